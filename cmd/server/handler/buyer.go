@@ -81,6 +81,71 @@ func (b *BuyerController) GetBuyerOrders() gin.HandlerFunc {
 	}
 }
 
+// Orders lists the purchase orders of a buyer
+// @Summary List buyer orders
+// @Description List the purchase orders of a buyer with their lines and statuses, oldest first
+// @Tags Buyers
+// @Param id path int true "Buyer ID"
+// @Produce json
+// @Success 200 {array} domain.BuyerOrder
+// @Failure 400 {string} string "Invalid ID"
+// @Failure 404 {string} string "Buyer not found"
+// @Failure 500 {string} string "Error listing orders"
+// @Router /api/v1/buyers/{id}/orders [get]
+func (b *BuyerController) Orders() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		buyerId, errId := strconv.Atoi(c.Param("id"))
+		if errId != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+		orders, err := b.buyerService.Orders(c, buyerId)
+		if err != nil {
+			b.writeSummaryError(c, err, "error listing orders")
+			return
+		}
+		web.Success(c, http.StatusOK, orders)
+	}
+}
+
+// Summary reports the lifetime value of a buyer
+// @Summary Get buyer summary
+// @Description Get the lifetime value, average order value, first and last order dates and favourite products of a buyer
+// @Tags Buyers
+// @Param id path int true "Buyer ID"
+// @Produce json
+// @Success 200 {object} domain.BuyerSummary
+// @Failure 400 {string} string "Invalid ID"
+// @Failure 404 {string} string "Buyer not found"
+// @Failure 500 {string} string "Error summarizing orders"
+// @Router /api/v1/buyers/{id}/summary [get]
+func (b *BuyerController) Summary() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		buyerId, errId := strconv.Atoi(c.Param("id"))
+		if errId != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+		summary, err := b.buyerService.Summary(c, buyerId)
+		if err != nil {
+			b.writeSummaryError(c, err, "error summarizing orders")
+			return
+		}
+		web.Success(c, http.StatusOK, summary)
+	}
+}
+
+func (b *BuyerController) writeSummaryError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, buyer.ErrNotFound):
+		web.Error(c, http.StatusNotFound, "buyer not found")
+	case errors.Is(err, buyer.ErrUnknownCurrency):
+		web.Error(c, http.StatusUnprocessableEntity, err.Error())
+	default:
+		web.Error(c, http.StatusInternalServerError, message)
+	}
+}
+
 // GetBuyersOrders gets the orders for all buyers
 // @Summary Get buyers orders
 // @Description Get the orders for all buyers
@@ -150,9 +215,14 @@ func (b *BuyerController) Create() gin.HandlerFunc {
 			CardNumberID: buyerInput.CardNumberID,
 			FirstName:    buyerInput.FirstName,
 			LastName:     buyerInput.LastName,
+			LocalityID:   buyerInput.LocalityID,
 		})
 		if err != nil {
-			web.Error(c, http.StatusConflict, err.Error())
+			if errors.Is(err, buyer.ErrExists) || errors.Is(err, buyer.ErrLocalityNotFound) {
+				web.Error(c, http.StatusConflict, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "error creating buyer")
 			return
 		}
 		web.Success(c, http.StatusCreated, buyerId)
@@ -164,13 +234,14 @@ func (b *BuyerController) Create() gin.HandlerFunc {
 // @Router /api/v1/buyers/{id} [patch]
 // @Param   id     path    int     true        "Buyer ID"
 // @Accept json
+// @Accept application/merge-patch+json
 // @Success 200 {object}  domain.Buyer
 // @Param buyer body domain.BuyerRequest true "Buyer Data"
 // @Tags Buyers
 func (b *BuyerController) Update() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var buyer domain.Buyer
-		if err := c.ShouldBindJSON(&buyer); err != nil {
+		document, err := web.MergePatch(c)
+		if err != nil {
 			web.Error(c, http.StatusUnprocessableEntity, "buyer not updated")
 			return
 		}
@@ -179,9 +250,61 @@ func (b *BuyerController) Update() gin.HandlerFunc {
 			web.Error(c, http.StatusBadRequest, "invalid ID")
 			return
 		}
-		buyerUpdated, err := b.buyerService.Update(c, buyer, id)
+		buyerUpdated, err := b.buyerService.Update(c, id, domain.Patch{Document: document})
 		if err != nil {
-			web.Error(c, http.StatusNotFound, "buyer not updated")
+			if errors.Is(err, buyer.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "buyer not updated")
+				return
+			}
+			if errors.Is(err, buyer.ErrInvalidBody) {
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+				return
+			}
+			if errors.Is(err, buyer.ErrLocalityNotFound) {
+				web.Error(c, http.StatusConflict, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "error updating buyer")
+			return
+		}
+		web.Success(c, http.StatusOK, buyerUpdated)
+	}
+}
+
+// @Produce json
+// PATCH /buyers/{id}/card-number @Summary Changes the card number of a buyer
+// @Router /api/v1/buyers/{id}/card-number [patch]
+// @Param   id     path    int     true        "Buyer ID"
+// @Accept json
+// @Success 200 {object}  domain.Buyer
+// @Failure 404 {string} string "Buyer not found"
+// @Failure 409 {string} string "Card number already in use"
+// @Param buyer body domain.BuyerCardNumberRequest true "New card number"
+// @Tags Buyers
+func (b *BuyerController) UpdateCardNumber() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid ID")
+			return
+		}
+		var req domain.BuyerCardNumberRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, buyer.ErrInvalidBody.Error())
+			return
+		}
+		buyerUpdated, err := b.buyerService.UpdateCardNumber(c, id, req.CardNumberID)
+		if err != nil {
+			switch {
+			case errors.Is(err, buyer.ErrNotFound):
+				web.Error(c, http.StatusNotFound, err.Error())
+			case errors.Is(err, buyer.ErrExists):
+				web.Error(c, http.StatusConflict, err.Error())
+			case errors.Is(err, buyer.ErrInvalidBody):
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, "error updating buyer")
+			}
 			return
 		}
 		web.Success(c, http.StatusOK, buyerUpdated)
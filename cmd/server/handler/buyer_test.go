@@ -9,6 +9,7 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/buyer"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/buyer"
 	"github.com/gin-gonic/gin"
@@ -389,6 +390,22 @@ func TestCreateBuyers(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, response.Code)
 	})
 
+	t.Run("Should return status 500 when the buyer cannot be saved", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetBuyers(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, Create, `{
+			"card_number_id":"1234",
+			"first_name":"Giu",
+			"last_name":"Oli"}`)
+
+		mockService.On("Create", mock.Anything, mock.AnythingOfType("domain.Buyer")).Return(domain.Buyer{}, errors.New("error"))
+
+		server.POST(Create, handler.Create())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+
 	t.Run("Should return status 409 when buyer already exists", func(t *testing.T) {
 		server, mockService, handler := InitServerWithGetBuyers(t)
 
@@ -404,6 +421,24 @@ func TestCreateBuyers(t *testing.T) {
 
 		assert.Equal(t, http.StatusConflict, response.Code)
 	})
+
+	t.Run("Should return status 409 when the delivery locality does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetBuyers(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, Create, `{
+			"card_number_id":"1234",
+			"first_name":"Giu",
+			"last_name":"Oli",
+			"locality_id":99}`)
+
+		expectedBuyer := domain.Buyer{CardNumberID: "1234", FirstName: "Giu", LastName: "Oli", LocalityID: testutil.Int(99)}
+		mockService.On("Create", mock.Anything, expectedBuyer).Return(domain.Buyer{}, buyer.ErrLocalityNotFound)
+
+		server.POST(Create, handler.Create())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
 }
 
 func TestUpdateBuyers(t *testing.T) {
@@ -415,7 +450,7 @@ func TestUpdateBuyers(t *testing.T) {
 			FirstName:    "Giulianna",
 			LastName:     "Oliveira",
 		}
-		mockService.On("Update", mock.Anything, mock.Anything, 8).Return(updatedBuyer, nil)
+		mockService.On("Update", mock.Anything, 8, mock.Anything).Return(updatedBuyer, nil)
 
 		request, response := testutil.MakeRequest(http.MethodPatch, "/buyers/8", `{
 			"first_name": "Giulianna",
@@ -441,7 +476,7 @@ func TestUpdateBuyers(t *testing.T) {
 			"last_name": "Goncalves"
 		  }`)
 
-		mockService.On("Update", mock.Anything, mock.Anything, 10).Return(domain.Buyer{}, buyer.ErrNotFound)
+		mockService.On("Update", mock.Anything, 10, mock.Anything).Return(domain.Buyer{}, buyer.ErrNotFound)
 
 		server.PATCH(Update, handler.Update())
 		server.ServeHTTP(response, request)
@@ -449,6 +484,19 @@ func TestUpdateBuyers(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, response.Code)
 	})
 
+	t.Run("Should return status 500 when the update fails", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetBuyers(t)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, "/buyers/10", `{"first_name": "Giulianna"}`)
+
+		mockService.On("Update", mock.Anything, 10, mock.Anything).Return(domain.Buyer{}, errors.New("error"))
+
+		server.PATCH(Update, handler.Update())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+
 	t.Run("Should return err 400 if id is invalid", func(t *testing.T) {
 		server, mockService, handler := InitServerWithGetBuyers(t)
 
@@ -482,6 +530,118 @@ func TestUpdateBuyers(t *testing.T) {
 
 }
 
+func TestUpdateBuyerCardNumber(t *testing.T) {
+	t.Run("Should return status 200 with the buyer updated", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetBuyers(t)
+		updatedBuyer := domain.Buyer{ID: 8, CardNumberID: "5436", FirstName: "Giulianna", LastName: "Oliveira"}
+		mockService.On("UpdateCardNumber", mock.Anything, 8, "5436").Return(updatedBuyer, nil)
+
+		server.PATCH("/buyers/:id/card-number", handler.UpdateCardNumber())
+		request, response := testutil.MakeRequest(http.MethodPatch, "/buyers/8/card-number", `{"card_number_id": "5436"}`)
+		server.ServeHTTP(response, request)
+
+		responseResult := domain.BuyerResponseID{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, updatedBuyer, responseResult.Data)
+	})
+
+	cases := []struct {
+		name   string
+		url    string
+		body   string
+		err    error
+		status int
+	}{
+		{"invalid id", "/buyers/invalid/card-number", `{"card_number_id": "5436"}`, nil, http.StatusBadRequest},
+		{"missing card number", "/buyers/8/card-number", `{}`, nil, http.StatusUnprocessableEntity},
+		{"buyer not found", "/buyers/8/card-number", `{"card_number_id": "5436"}`, buyer.ErrNotFound, http.StatusNotFound},
+		{"card number in use", "/buyers/8/card-number", `{"card_number_id": "5436"}`, buyer.ErrExists, http.StatusConflict},
+		{"internal error", "/buyers/8/card-number", `{"card_number_id": "5436"}`, errors.New("error"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run("Should return status "+http.StatusText(tc.status)+" on "+tc.name, func(t *testing.T) {
+			server, mockService, handler := InitServerWithGetBuyers(t)
+			mockService.On("UpdateCardNumber", mock.Anything, 8, "5436").Return(domain.Buyer{}, tc.err)
+
+			server.PATCH("/buyers/:id/card-number", handler.UpdateCardNumber())
+			request, response := testutil.MakeRequest(http.MethodPatch, tc.url, tc.body)
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.status, response.Code)
+		})
+	}
+}
+
+func TestBuyerOrdersAndSummary(t *testing.T) {
+	t.Run("Should return status 200 with the buyer orders", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetBuyers(t)
+		expectedOrders := []domain.BuyerOrder{
+			{ID: 1, OrderNumber: "PO001", Status: "Pending", Total: money.MustParse("30.00", "USD"), Lines: []domain.BuyerOrderLine{
+				{ID: 1, ProductID: 1, Quantity: 2, SalePrice: money.MustParse("15.00", "USD"), Total: money.MustParse("30.00", "USD")},
+			}},
+		}
+		mockService.On("Orders", mock.Anything, 1).Return(expectedOrders, nil)
+
+		server.GET("/buyers/:id/orders", handler.Orders())
+		request, response := testutil.MakeRequest(http.MethodGet, "/buyers/1/orders", "")
+		server.ServeHTTP(response, request)
+
+		responseResult := &domain.BuyerOrderListResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, expectedOrders, responseResult.Data)
+	})
+
+	t.Run("Should return status 200 with the buyer summary", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetBuyers(t)
+		expectedSummary := domain.BuyerSummary{
+			BuyerID:           1,
+			OrdersCount:       1,
+			LifetimeValue:     money.MustParse("30.00", "USD"),
+			AverageOrderValue: money.MustParse("30.00", "USD"),
+			FavouriteProducts: []domain.BuyerFavouriteProduct{{ProductID: 1, Quantity: 2, OrdersCount: 1}},
+		}
+		mockService.On("Summary", mock.Anything, 1).Return(expectedSummary, nil)
+
+		server.GET("/buyers/:id/summary", handler.Summary())
+		request, response := testutil.MakeRequest(http.MethodGet, "/buyers/1/summary", "")
+		server.ServeHTTP(response, request)
+
+		responseResult := &domain.BuyerSummaryResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, expectedSummary, responseResult.Data)
+	})
+
+	cases := []struct {
+		name   string
+		url    string
+		err    error
+		status int
+	}{
+		{"invalid id", "/buyers/invalid/summary", nil, http.StatusBadRequest},
+		{"buyer not found", "/buyers/1/summary", buyer.ErrNotFound, http.StatusNotFound},
+		{"unknown currency", "/buyers/1/summary", buyer.ErrUnknownCurrency, http.StatusUnprocessableEntity},
+		{"internal error", "/buyers/1/summary", errors.New("error"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run("Should return status "+http.StatusText(tc.status)+" on "+tc.name, func(t *testing.T) {
+			server, mockService, handler := InitServerWithGetBuyers(t)
+			mockService.On("Summary", mock.Anything, 1).Return(domain.BuyerSummary{}, tc.err)
+
+			server.GET("/buyers/:id/summary", handler.Summary())
+			request, response := testutil.MakeRequest(http.MethodGet, tc.url, "")
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.status, response.Code)
+		})
+	}
+}
+
 func InitServerWithGetBuyers(t *testing.T) (*gin.Engine, *mocks.BuyerServiceMock, *handler.BuyerController) {
 	t.Helper()
 	server := testutil.CreateServer()
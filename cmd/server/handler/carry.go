@@ -140,6 +140,7 @@ func (s *CarryController) Create() gin.HandlerFunc {
 				return
 			case carry.ErrConflictLocalityId:
 				web.Error(c, http.StatusConflict, err.Error())
+				return
 			default:
 				web.Error(c, http.StatusInternalServerError, carry.ErrTryAgain.Error(), err)
 				return
@@ -149,3 +150,234 @@ func (s *CarryController) Create() gin.HandlerFunc {
 		web.Success(c, http.StatusCreated, carryDomain)
 	}
 }
+
+// @Summary List Carriers
+// @Produce json
+// GET /carriers @Summary Returns a list of carriers
+// @Router /api/v1/carriers [get]
+// @Tags Carriers
+// @Accept json
+// @Success 200 {object} domain.CarryResponse
+// @Description List all Carriers
+func (s *CarryController) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		carriers, err := s.carryService.GetAll(c)
+		if err != nil {
+			web.Error(c, http.StatusInternalServerError, carry.ErrTryAgain.Error(), err)
+			return
+		}
+		web.Success(c, http.StatusOK, carriers)
+	}
+}
+
+// @Summary Update Carry
+// @Produce json
+// PATCH /carriers/:id @Summary Modifies an existing carry
+// @Router /api/v1/carriers/{id} [patch]
+// @Param id path int true "Carry ID"
+// @Param carry body domain.Carry true "Carry Data"
+// @Tags Carriers
+// @Accept json
+// @Success 200 {object} domain.CarryResponseId
+// @Description Update the non-empty fields of a Carry
+func (s *CarryController) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		carryId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, carry.ErrInvalidId.Error())
+			return
+		}
+
+		carryInput := &domain.Carry{}
+		if err := c.ShouldBindJSON(carryInput); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, carry.ErrInvalidJSON.Error())
+			return
+		}
+
+		carryDomain, err := s.carryService.Update(c, *carryInput, carryId)
+		if err != nil {
+			switch err {
+			case carry.ErrNotFound:
+				web.Error(c, http.StatusNotFound, err.Error())
+				return
+			case carry.ErrAlredyExists, carry.ErrConflictLocalityId:
+				web.Error(c, http.StatusConflict, err.Error())
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, carry.ErrTryAgain.Error(), err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusOK, carryDomain)
+	}
+}
+
+// @Summary Delete Carry
+// @Produce json
+// DELETE /carriers/:id @Summary Deletes a carry
+// @Router /api/v1/carriers/{id} [delete]
+// @Param id path int true "Carry ID"
+// @Tags Carriers
+// @Accept json
+// @Success 204
+// @Description Delete a Carry and its coverage, unless purchase orders use it
+func (s *CarryController) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		carryId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, carry.ErrInvalidId.Error())
+			return
+		}
+
+		err = s.carryService.Delete(c, carryId)
+		if err != nil {
+			switch err {
+			case carry.ErrNotFound:
+				web.Error(c, http.StatusNotFound, err.Error())
+				return
+			case carry.ErrInUse:
+				web.Error(c, http.StatusConflict, err.Error())
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, carry.ErrTryAgain.Error(), err)
+				return
+			}
+		}
+
+		web.Response(c, http.StatusNoContent, "")
+	}
+}
+
+// @Summary Get Carry coverage
+// @Produce json
+// GET /carriers/:id/coverage @Summary Returns the localities a carry delivers to
+// @Router /api/v1/carriers/{id}/coverage [get]
+// @Param id path int true "Carry ID"
+// @Tags Carriers
+// @Accept json
+// @Success 200 {object} domain.CarrierCoverageResponse
+// @Description List the localities a Carry delivers to, its own included
+func (s *CarryController) Coverage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		carryId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, carry.ErrInvalidId.Error())
+			return
+		}
+
+		coverage, err := s.carryService.Coverage(c, carryId)
+		if err != nil {
+			if errors.Is(err, carry.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, carry.ErrTryAgain.Error(), err)
+			return
+		}
+		web.Success(c, http.StatusOK, coverage)
+	}
+}
+
+// @Summary Replace Carry coverage
+// @Produce json
+// PUT /carriers/:id/coverage @Summary Replaces the localities a carry delivers to
+// @Router /api/v1/carriers/{id}/coverage [put]
+// @Param id path int true "Carry ID"
+// @Param coverage body domain.CarrierCoverageRequest true "Localities covered besides its own"
+// @Tags Carriers
+// @Accept json
+// @Success 200 {object} domain.CarrierCoverageResponse
+// @Description Replace the localities a Carry delivers to besides its own
+func (s *CarryController) SetCoverage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		carryId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, carry.ErrInvalidId.Error())
+			return
+		}
+
+		request := &domain.CarrierCoverageRequest{}
+		if err := c.ShouldBindJSON(request); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, carry.ErrInvalidJSON.Error())
+			return
+		}
+
+		coverage, err := s.carryService.SetCoverage(c, carryId, request.LocalityIDs)
+		if err != nil {
+			switch err {
+			case carry.ErrNotFound:
+				web.Error(c, http.StatusNotFound, err.Error())
+				return
+			case carry.ErrConflictLocalityId:
+				web.Error(c, http.StatusConflict, err.Error())
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, carry.ErrTryAgain.Error(), err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusOK, coverage)
+	}
+}
+
+// @Summary List Carriers delivering to a Locality
+// @Produce json
+// GET /localities/:id/carriers @Summary Returns the carriers able to deliver to a locality
+// @Router /api/v1/localities/{id}/carriers [get]
+// @Param id path int true "Locality ID"
+// @Tags Carriers
+// @Accept json
+// @Success 200 {object} domain.CarryResponse
+// @Description List the Carriers based in a Locality or covering it
+func (s *CarryController) ByLocality() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		localityID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, carry.ErrInvalidId.Error())
+			return
+		}
+
+		carriers, err := s.carryService.ByLocality(c, localityID)
+		if err != nil {
+			if errors.Is(err, carry.ErrNotFoundLocalityId) {
+				web.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, carry.ErrTryAgain.Error(), err)
+			return
+		}
+		web.Success(c, http.StatusOK, carriers)
+	}
+}
+
+// @Summary Issue Carry credential
+// @Produce json
+// POST /carriers/:id/credentials @Summary Issues a new API key for a carry
+// @Router /api/v1/carriers/{id}/credentials [post]
+// @Param id path int true "Carry ID"
+// @Tags Carriers
+// @Accept json
+// @Success 201 {object} domain.CarrierCredentialResponse
+// @Description Issue the API key a Carry pushes shipment events with, revoking the previous one. The key is only shown once
+func (s *CarryController) IssueCredential() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		carryId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, carry.ErrInvalidId.Error())
+			return
+		}
+
+		credential, err := s.carryService.IssueCredential(c, carryId)
+		if err != nil {
+			if errors.Is(err, carry.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, carry.ErrTryAgain.Error(), err)
+			return
+		}
+		web.Success(c, http.StatusCreated, credential)
+	}
+}
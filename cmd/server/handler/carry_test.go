@@ -304,6 +304,225 @@ func TestCreateCarriers(t *testing.T) {
 	})
 }
 
+func TestGetAllCarriers(t *testing.T) {
+	t.Run("Should return status 200 and the carriers", func(t *testing.T) {
+		server, mockService, handler := InitServerWithCarriers(t)
+
+		mockService.On("GetAll", mock.Anything).Return([]domain.Carry{expectedCarry}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointCarriers, "")
+
+		server.GET(BaseEndpointCarriers, handler.GetAll())
+		server.ServeHTTP(response, request)
+
+		responseResult := &domain.CarryResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, []domain.Carry{expectedCarry}, responseResult.Data)
+	})
+	t.Run("Should return status 500 when there is an internal error", func(t *testing.T) {
+		server, mockService, handler := InitServerWithCarriers(t)
+
+		mockService.On("GetAll", mock.Anything).Return([]domain.Carry{}, carry.ErrTryAgain)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointCarriers, "")
+
+		server.GET(BaseEndpointCarriers, handler.GetAll())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
+
+func TestUpdateCarriers(t *testing.T) {
+	body := `{"company_name":"Teste Livre"}`
+	cases := []struct {
+		name     string
+		path     string
+		body     string
+		err      error
+		expected int
+	}{
+		{"Should return status 200 and the updated carry", "/carriers/1", body, nil, http.StatusOK},
+		{"Should return status 400 when the carry id is invalid", "/carriers/invalid", body, nil, http.StatusBadRequest},
+		{"Should return status 422 when the json is invalid", "/carriers/1", `{"cid":1}`, nil, http.StatusUnprocessableEntity},
+		{"Should return status 404 when the carry does not exist", "/carriers/1", body, carry.ErrNotFound, http.StatusNotFound},
+		{"Should return status 409 when the cid already exists", "/carriers/1", body, carry.ErrAlredyExists, http.StatusConflict},
+		{"Should return status 409 when the locality does not exist", "/carriers/1", body, carry.ErrConflictLocalityId, http.StatusConflict},
+		{"Should return status 500 when there is an internal error", "/carriers/1", body, carry.ErrTryAgain, http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, mockService, handler := InitServerWithCarriers(t)
+
+			mockService.On("Update", mock.Anything, domain.Carry{CompanyName: "Teste Livre"}, 1).Return(expectedCarry, tc.err)
+
+			request, response := testutil.MakeRequest(http.MethodPatch, tc.path, tc.body)
+
+			server.PATCH(BaseEndpointWithIdCarriers, handler.Update())
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.expected, response.Code)
+		})
+	}
+}
+
+func TestDeleteCarriers(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		err      error
+		expected int
+	}{
+		{"Should return status 204 when the carry is deleted", "/carriers/1", nil, http.StatusNoContent},
+		{"Should return status 400 when the carry id is invalid", "/carriers/invalid", nil, http.StatusBadRequest},
+		{"Should return status 404 when the carry does not exist", "/carriers/1", carry.ErrNotFound, http.StatusNotFound},
+		{"Should return status 409 when purchase orders use the carry", "/carriers/1", carry.ErrInUse, http.StatusConflict},
+		{"Should return status 500 when there is an internal error", "/carriers/1", carry.ErrTryAgain, http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, mockService, handler := InitServerWithCarriers(t)
+
+			mockService.On("Delete", mock.Anything, 1).Return(tc.err)
+
+			request, response := testutil.MakeRequest(http.MethodDelete, tc.path, "")
+
+			server.DELETE(BaseEndpointWithIdCarriers, handler.Delete())
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.expected, response.Code)
+		})
+	}
+}
+
+func TestSetCoverageCarriers(t *testing.T) {
+	expectedCoverage := domain.CarrierCoverage{CarrierID: 1, LocalityIDs: []int{1, 2, 3}}
+	cases := []struct {
+		name     string
+		path     string
+		body     string
+		err      error
+		expected int
+	}{
+		{"Should return status 200 and the coverage", "/carriers/1/coverage", `{"locality_ids":[2,3]}`, nil, http.StatusOK},
+		{"Should return status 400 when the carry id is invalid", "/carriers/invalid/coverage", `{"locality_ids":[2,3]}`, nil, http.StatusBadRequest},
+		{"Should return status 422 when locality_ids is missing", "/carriers/1/coverage", `{}`, nil, http.StatusUnprocessableEntity},
+		{"Should return status 404 when the carry does not exist", "/carriers/1/coverage", `{"locality_ids":[2,3]}`, carry.ErrNotFound, http.StatusNotFound},
+		{"Should return status 409 when a locality does not exist", "/carriers/1/coverage", `{"locality_ids":[2,3]}`, carry.ErrConflictLocalityId, http.StatusConflict},
+		{"Should return status 500 when there is an internal error", "/carriers/1/coverage", `{"locality_ids":[2,3]}`, carry.ErrTryAgain, http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, mockService, handler := InitServerWithCarriers(t)
+
+			mockService.On("SetCoverage", mock.Anything, 1, []int{2, 3}).Return(expectedCoverage, tc.err)
+
+			request, response := testutil.MakeRequest(http.MethodPut, tc.path, tc.body)
+
+			server.PUT("/carriers/:id/coverage", handler.SetCoverage())
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.expected, response.Code)
+			if tc.expected == http.StatusOK {
+				responseResult := &domain.CarrierCoverageResponse{}
+				_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+				assert.Equal(t, expectedCoverage, responseResult.Data)
+			}
+		})
+	}
+}
+
+func TestCoverageCarriers(t *testing.T) {
+	t.Run("Should return status 200 and the coverage", func(t *testing.T) {
+		server, mockService, handler := InitServerWithCarriers(t)
+
+		mockService.On("Coverage", mock.Anything, 1).Return(domain.CarrierCoverage{CarrierID: 1, LocalityIDs: []int{1}}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/carriers/1/coverage", "")
+
+		server.GET("/carriers/:id/coverage", handler.Coverage())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("Should return status 404 when the carry does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithCarriers(t)
+
+		mockService.On("Coverage", mock.Anything, 1).Return(domain.CarrierCoverage{}, carry.ErrNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/carriers/1/coverage", "")
+
+		server.GET("/carriers/:id/coverage", handler.Coverage())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestByLocalityCarriers(t *testing.T) {
+	t.Run("Should return status 200 and the carriers delivering to the locality", func(t *testing.T) {
+		server, mockService, handler := InitServerWithCarriers(t)
+
+		mockService.On("ByLocality", mock.Anything, 1).Return([]domain.Carry{expectedCarry}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/localities/1/carriers", "")
+
+		server.GET("/localities/:id/carriers", handler.ByLocality())
+		server.ServeHTTP(response, request)
+
+		responseResult := &domain.CarryResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, []domain.Carry{expectedCarry}, responseResult.Data)
+	})
+	t.Run("Should return status 404 when the locality does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithCarriers(t)
+
+		mockService.On("ByLocality", mock.Anything, 1).Return([]domain.Carry{}, carry.ErrNotFoundLocalityId)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/localities/1/carriers", "")
+
+		server.GET("/localities/:id/carriers", handler.ByLocality())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestIssueCredentialCarriers(t *testing.T) {
+	t.Run("Should return status 201 and the new key", func(t *testing.T) {
+		server, mockService, handler := InitServerWithCarriers(t)
+
+		mockService.On("IssueCredential", mock.Anything, 1).Return(domain.CarrierCredential{CarrierID: 1, APIKey: "secret"}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/carriers/1/credentials", "")
+
+		server.POST("/carriers/:id/credentials", handler.IssueCredential())
+		server.ServeHTTP(response, request)
+
+		responseResult := &domain.CarrierCredentialResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Equal(t, "secret", responseResult.Data.APIKey)
+	})
+	t.Run("Should return status 404 when the carry does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithCarriers(t)
+
+		mockService.On("IssueCredential", mock.Anything, 1).Return(domain.CarrierCredential{}, carry.ErrNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/carriers/1/credentials", "")
+
+		server.POST("/carriers/:id/credentials", handler.IssueCredential())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
 func InitServerWithCarriers(t *testing.T) (*gin.Engine, *mocks.CarryServiceMock, *handler.CarryController) {
 	t.Helper()
 	server := testutil.CreateServer()
@@ -7,6 +7,7 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
 	"github.com/gin-gonic/gin"
 )
@@ -26,9 +27,12 @@ func NewEmployee(e employee.Service) *Employee {
 // GET /employee/:id @Summary Returns a employee per Id
 // @Router /api/v1/employees/{id} [get]
 // @Param id path int true "Employee ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Tags Employees
 // @Accept json
 // @Success 200 {object} domain.Employee
+// @Header 200 {string} ETag "Version of the employee"
+// @Success 304
 // @Description List one by Employee id
 func (e *Employee) Get() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -47,12 +51,18 @@ func (e *Employee) Get() gin.HandlerFunc {
 			web.Error(c, http.StatusInternalServerError, err.Error())
 			return
 		}
+		if web.NotModified(c, employeeGet.Version) {
+			return
+		}
 		web.Success(c, http.StatusOK, employeeGet)
 	}
 }
 
 // @Summary Get all Employees
 // @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // GET /employees @Summary Returns a list of employees
 // @Router /api/v1/employees [get]
 // @Tags Employees
@@ -61,6 +71,18 @@ func (e *Employee) Get() gin.HandlerFunc {
 // @Description List all Employees
 func (e *Employee) GetAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if format, ok := web.ExportFormat(c); ok {
+			err := web.Stream(c, format, domain.Employee{}, func(emit func(interface{}) error) error {
+				return e.employeeService.ForEach(c, func(d domain.Employee) error {
+					return emit(d)
+				})
+			})
+			if err != nil && !c.Writer.Written() {
+				web.Error(c, http.StatusInternalServerError, employee.ErrTryAgain.Error())
+			}
+			return
+		}
+
 		employees, err := e.employeeService.GetAll(c)
 		if err != nil {
 			web.Error(c, http.StatusInternalServerError, employee.ErrTryAgain.Error())
@@ -111,9 +133,14 @@ func (e *Employee) Create() gin.HandlerFunc {
 // PATCH /employees/:id @Summary Modifies an existing employee
 // @Router /api/v1/employees/{id} [patch]
 // @Accept json
+// @Accept application/merge-patch+json
 // @Tags Employees
 // @Success 200 {object} domain.Employee
+// @Header 200 {string} ETag "New version of the employee"
+// @Failure 412 {object} web.ErrorResponse
+// @Failure 428 {object} web.ErrorResponse
 // @Param id path int true "Employee ID"
+// @Param If-Match header string true "ETag of the employee being modified"
 // @Param employee body domain.Employee true "Employee Data"
 // @Description Update Employee
 func (e *Employee) Update() gin.HandlerFunc {
@@ -123,22 +150,37 @@ func (e *Employee) Update() gin.HandlerFunc {
 			web.Response(c, http.StatusBadRequest, employee.ErrInvalidId.Error())
 			return
 		}
-		domain := new(domain.Employee)
-		if err := c.ShouldBindJSON(domain); err != nil {
+		document, err := web.MergePatch(c)
+		if err != nil {
 			web.Error(c, http.StatusUnprocessableEntity, employee.ErrInvalidBody.Error())
 			return
 		}
-		result, err := e.employeeService.Update(c, *domain, employeeId)
+		version, err := web.IfMatch(c)
+		if err != nil {
+			web.PreconditionError(c, err)
+			return
+		}
+		result, err := e.employeeService.Update(c, employeeId, domain.Patch{Document: document, Version: version})
 		if err != nil {
 			switch err {
 			case employee.ErrNotFound:
 				web.Error(c, http.StatusNotFound, err.Error())
 				return
+			case employee.ErrVersionMismatch:
+				web.Error(c, http.StatusPreconditionFailed, err.Error())
+				return
+			case employee.ErrInvalidBody:
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+				return
+			case employee.ErrAlreadyExists:
+				web.Error(c, http.StatusConflict, err.Error())
+				return
 			default:
 				web.Error(c, http.StatusInternalServerError, employee.ErrTryAgain.Error(), err)
 				return
 			}
 		}
+		web.SetETag(c, result.Version)
 		web.Success(c, http.StatusOK, result)
 	}
 }
@@ -176,3 +218,114 @@ func (e *Employee) Delete() gin.HandlerFunc {
 		web.Response(c, http.StatusNoContent, "")
 	}
 }
+
+// @Summary Get Employee warehouse assignments
+// @Produce json
+// GET /employees/:id/assignments @Summary Returns the warehouse history of an employee
+// @Router /api/v1/employees/{id}/assignments [get]
+// @Param id path int true "Employee ID"
+// @Tags Employees
+// @Accept json
+// @Success 200 {object} []domain.EmployeeAssignment
+// @Description List the warehouses an employee has been assigned to, oldest first
+func (e *Employee) Assignments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		employeeId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, employee.ErrInvalidId.Error())
+			return
+		}
+
+		assignments, err := e.employeeService.Assignments(c, employeeId)
+		if err != nil {
+			if errors.Is(err, employee.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, employee.ErrNotFound.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, assignments)
+	}
+}
+
+// @Summary Create Employee shift
+// @Produce json
+// POST /employees/:id/shifts @Summary Schedules a shift for an employee
+// @Router /api/v1/employees/{id}/shifts [post]
+// @Param id path int true "Employee ID"
+// @Param shift body domain.ShiftRequest true "Shift Data"
+// @Tags Employees
+// @Accept json
+// @Success 201 {object} domain.Shift
+// @Description Schedule a shift, rejecting shifts that overlap another shift of the employee
+func (e *Employee) CreateShift() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		employeeId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, employee.ErrInvalidId.Error())
+			return
+		}
+
+		var req domain.ShiftRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			if errors.Is(err, datetime.ErrInvalid) {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			web.Error(c, http.StatusUnprocessableEntity, employee.ErrInvalidBody.Error())
+			return
+		}
+
+		shift, err := e.employeeService.CreateShift(c, employeeId, req)
+		if err != nil {
+			switch {
+			case errors.Is(err, employee.ErrNotFound):
+				web.Error(c, http.StatusNotFound, err.Error())
+			case errors.Is(err, employee.ErrInvalidShift):
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			case errors.Is(err, employee.ErrShiftOverlap):
+				web.Error(c, http.StatusConflict, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, employee.ErrTryAgain.Error(), err)
+			}
+			return
+		}
+		web.Success(c, http.StatusCreated, shift)
+	}
+}
+
+// @Summary Get Warehouse roster
+// @Produce json
+// GET /warehouses/:id/roster @Summary Returns the shifts of a warehouse on a day
+// @Router /api/v1/warehouses/{id}/roster [get]
+// @Param id path int true "Warehouse ID"
+// @Param date query string false "Day of the roster, defaults to today"
+// @Tags Employees
+// @Accept json
+// @Success 200 {object} domain.Roster
+// @Description List the shifts worked in a warehouse on a day
+func (e *Employee) Roster() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		warehouseId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, employee.ErrInvalidId.Error())
+			return
+		}
+
+		var date datetime.Time
+		if value := c.Query("date"); value != "" {
+			if date, err = datetime.Parse(value); err != nil {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		roster, err := e.employeeService.Roster(c, warehouseId, date)
+		if err != nil {
+			web.Error(c, http.StatusInternalServerError, employee.ErrTryAgain.Error(), err)
+			return
+		}
+		web.Success(c, http.StatusOK, roster)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/employee"
 	"github.com/gin-gonic/gin"
@@ -248,9 +249,10 @@ func TestUpdateEmployee(t *testing.T) {
 
 		server, mockService, handler := InitServerWithGetEmployees(t)
 		request, response := testutil.MakeRequest(http.MethodPatch, "/employees/1", employeeJson)
+		request.Header.Set("If-Match", `"1"`)
 		responseResult := domain.EmployeeResponseID{}
 
-		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(expectedEmployees, nil)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(expectedEmployees, nil)
 		server.PATCH("/employees/:id", handler.Update())
 
 		server.ServeHTTP(response, request)
@@ -266,22 +268,34 @@ func TestUpdateEmployee(t *testing.T) {
 		server, mockService, handler := InitServerWithGetEmployees(t)
 		server.PATCH("/employees/:id", handler.Update())
 		request, response := testutil.MakeRequest(http.MethodPatch, "/employees/1", employeeJson)
+		request.Header.Set("If-Match", `"1"`)
 		responseResult := domain.Employee{}
 		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
-		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(domain.Employee{}, employee.ErrNotFound)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Employee{}, employee.ErrNotFound)
 		server.ServeHTTP(response, request)
 		assert.Equal(t, http.StatusNotFound, response.Code)
 
 	})
 
+	t.Run("Should return 409 when the card number belongs to another employee", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetEmployees(t)
+		server.PATCH("/employees/:id", handler.Update())
+		request, response := testutil.MakeRequest(http.MethodPatch, "/employees/1", employeeJson)
+		request.Header.Set("If-Match", `"1"`)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Employee{}, employee.ErrAlreadyExists)
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
 	t.Run("Should return status 500 when an internal server error occurs.", func(t *testing.T) {
 		server, mockService, handler := InitServerWithGetEmployees(t)
 
 		server.PATCH("/employees/:id", handler.Update())
 
-		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(domain.Employee{}, employee.ErrTryAgain)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Employee{}, employee.ErrTryAgain)
 
 		request, response := testutil.MakeRequest(http.MethodPatch, "/employees/1", employeeJson)
+		request.Header.Set("If-Match", `"1"`)
 
 		server.ServeHTTP(response, request)
 		assert.Equal(t, http.StatusInternalServerError, response.Code)
@@ -294,9 +308,10 @@ func TestUpdateEmployee(t *testing.T) {
 
 		server.PATCH("/employees/:id", handler.Update())
 
-		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(domain.Employee{}, employee.ErrNotFound)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Employee{}, employee.ErrNotFound)
 
 		request, response := testutil.MakeRequest(http.MethodPatch, "/employees/1", employeeJson)
+		request.Header.Set("If-Match", `"1"`)
 
 		server.ServeHTTP(response, request)
 
@@ -329,6 +344,42 @@ func TestUpdateEmployee(t *testing.T) {
 	})
 }
 
+func TestConditionalRequestsEmployee(t *testing.T) {
+	t.Run("Should return status 304 when the cached copy is current", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetEmployees(t)
+		mockService.On("Get", mock.Anything, 1).Return(domain.Employee{ID: 1, Version: 2}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/employees/1", "")
+		request.Header.Set("If-None-Match", "*")
+		server.GET("/employees/:id", handler.Get())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotModified, response.Code)
+	})
+	t.Run("Should return status 428 without If-Match", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetEmployees(t)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, "/employees/1", `{"first_name":"Luciana"}`)
+		server.PATCH("/employees/:id", handler.Update())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusPreconditionRequired, response.Code)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Should return status 412 when the employee changed", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetEmployees(t)
+		mockService.On("Update", mock.Anything, 1, domain.Patch{Document: []byte(`{"first_name":"Luciana"}`), Version: 5}).Return(domain.Employee{}, employee.ErrVersionMismatch)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, "/employees/1", `{"first_name":"Luciana"}`)
+		request.Header.Set("If-Match", `"5"`)
+		server.PATCH("/employees/:id", handler.Update())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusPreconditionFailed, response.Code)
+		assert.Equal(t, "", response.Header().Get("ETag"))
+	})
+}
+
 func InitServerWithGetEmployees(t *testing.T) (*gin.Engine, *mocks.EmployeeServiceMock, *handler.Employee) {
 	t.Helper()
 	server := testutil.CreateServer()
@@ -336,3 +387,70 @@ func InitServerWithGetEmployees(t *testing.T) (*gin.Engine, *mocks.EmployeeServi
 	handler := handler.NewEmployee(mockService)
 	return server, mockService, handler
 }
+
+func TestCreateShift(t *testing.T) {
+	body := `{"starts_at":"2023-07-01T08:00:00Z","ends_at":"2023-07-01T16:00:00Z"}`
+	req := domain.ShiftRequest{
+		StartsAt: datetime.MustParse("2023-07-01T08:00:00Z"),
+		EndsAt:   datetime.MustParse("2023-07-01T16:00:00Z"),
+	}
+
+	t.Run("Should return status 201 with the shift", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetEmployees(t)
+		shift := domain.Shift{ID: 1, EmployeeID: 1, WarehouseID: 1, StartsAt: req.StartsAt, EndsAt: req.EndsAt}
+		mockService.On("CreateShift", mock.Anything, 1, req).Return(shift, nil)
+		server.POST("/employees/:id/shifts", handler.CreateShift())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/employees/1/shifts", body)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, `{"data":{"id":1,"employee_id":1,"warehouse_id":1,"starts_at":"2023-07-01T08:00:00Z","ends_at":"2023-07-01T16:00:00Z"}}`, response.Body.String())
+	})
+
+	t.Run("Should return status 409 when the shift overlaps another one", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetEmployees(t)
+		mockService.On("CreateShift", mock.Anything, 1, req).Return(domain.Shift{}, employee.ErrShiftOverlap)
+		server.POST("/employees/:id/shifts", handler.CreateShift())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/employees/1/shifts", body)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("Should return status 400 when a date is invalid", func(t *testing.T) {
+		server, _, handler := InitServerWithGetEmployees(t)
+		server.POST("/employees/:id/shifts", handler.CreateShift())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/employees/1/shifts", `{"starts_at":"tomorrow"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestRoster(t *testing.T) {
+	t.Run("Should return status 200 with the roster of the day", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetEmployees(t)
+		date := datetime.MustParse("2023-07-01")
+		mockService.On("Roster", mock.Anything, 2, date).Return(domain.Roster{WarehouseID: 2, Date: "2023-07-01", Shifts: []domain.RosterShift{}}, nil)
+		server.GET("/warehouses/:id/roster", handler.Roster())
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/warehouses/2/roster?date=2023-07-01", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":{"warehouse_id":2,"date":"2023-07-01","shifts":[]}}`, response.Body.String())
+	})
+
+	t.Run("Should return status 400 when the date is invalid", func(t *testing.T) {
+		server, _, handler := InitServerWithGetEmployees(t)
+		server.GET("/warehouses/:id/roster", handler.Roster())
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/warehouses/2/roster?date=01/07/2023", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
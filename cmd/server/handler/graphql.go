@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/graph"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+type GraphQLController struct {
+	executor *graph.Executor
+}
+
+func NewGraphQL(e *graph.Executor) *GraphQLController {
+	return &GraphQLController{
+		executor: e,
+	}
+}
+
+// @Summary Run a GraphQL query
+// @Produce json
+// POST /graphql @Summary Runs a GraphQL query over warehouses, sections, products, sellers, employees, buyers, orders, carriers and localities
+// @Router /api/graphql [post]
+// @Tags GraphQL
+// @Accept json
+// @Param query body domain.GraphQLRequest true "GraphQL query"
+// @Success 200
+// @Failure 400
+// @Description Run a query with its relations. Queries deeper or more complex than allowed are rejected
+func (g *GraphQLController) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req domain.GraphQLRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			graphQLError(c, err.Error())
+			return
+		}
+		g.execute(c, req)
+	}
+}
+
+// @Summary Run a GraphQL query
+// @Produce json
+// GET /graphql @Summary Runs a GraphQL query given in the query string
+// @Router /api/graphql [get]
+// @Tags GraphQL
+// @Param query query string true "GraphQL query"
+// @Param operationName query string false "Operation to run"
+// @Param variables query string false "Variables as a JSON object"
+// @Success 200
+// @Failure 400
+// @Description Run a query with its relations. Queries deeper or more complex than allowed are rejected
+func (g *GraphQLController) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := domain.GraphQLRequest{
+			Query:         c.Query("query"),
+			OperationName: c.Query("operationName"),
+		}
+		if req.Query == "" {
+			graphQLError(c, "query is required")
+			return
+		}
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				graphQLError(c, "invalid variables")
+				return
+			}
+		}
+		g.execute(c, req)
+	}
+}
+
+// execute writes the result as is, as GraphQL clients expect, with a 400
+// status when the query was rejected without running.
+func (g *GraphQLController) execute(c *gin.Context, req domain.GraphQLRequest) {
+	result := g.executor.Execute(c, req)
+	status := http.StatusOK
+	if result.Data == nil {
+		status = http.StatusBadRequest
+	}
+	web.Response(c, status, result)
+}
+
+func graphQLError(c *gin.Context, message string) {
+	web.Response(c, http.StatusBadRequest, graphql.Result{
+		Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(message)},
+	})
+}
@@ -0,0 +1,118 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/graph"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
+	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/warehouse"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const GraphQLEndpoint = "/api/graphql"
+
+type graphQLResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func InitGraphQLServer(t *testing.T) (*gin.Engine, *mocks.WarehouseServiceMock) {
+	t.Helper()
+	service := &mocks.WarehouseServiceMock{}
+	executor, err := graph.NewExecutor(graph.Services{Warehouses: service})
+	assert.NoError(t, err)
+	handler := handler.NewGraphQL(executor)
+	server := testutil.CreateServer()
+	server.POST(GraphQLEndpoint, handler.Post())
+	server.GET(GraphQLEndpoint, handler.Get())
+	return server, service
+}
+
+func decodeGraphQL(t *testing.T, body []byte) graphQLResponse {
+	t.Helper()
+	var response graphQLResponse
+	assert.NoError(t, json.Unmarshal(body, &response))
+	return response
+}
+
+func TestGraphQL(t *testing.T) {
+	t.Run("Should run a posted query with its variables", func(t *testing.T) {
+		server, service := InitGraphQLServer(t)
+		service.On("Get", mock.Anything, 4).Return(domain.Warehouse{ID: 4, WarehouseCode: "W4"}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, GraphQLEndpoint,
+			`{"query":"query($id: Int!) { warehouse(id: $id) { warehouse_code } }","variables":{"id":4}}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		body := decodeGraphQL(t, response.Body.Bytes())
+		assert.Empty(t, body.Errors)
+		assert.Equal(t, map[string]interface{}{"warehouse": map[string]interface{}{"warehouse_code": "W4"}}, body.Data)
+	})
+	t.Run("Should run a query given in the query string", func(t *testing.T) {
+		server, service := InitGraphQLServer(t)
+		service.On("Get", mock.Anything, 4).Return(domain.Warehouse{ID: 4, WarehouseCode: "W4"}, nil)
+
+		query := url.Values{
+			"query":     {"query($id: Int!) { warehouse(id: $id) { warehouse_code } }"},
+			"variables": {`{"id":4}`},
+		}
+		request, response := testutil.MakeRequest(http.MethodGet, GraphQLEndpoint+"?"+query.Encode(), "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, map[string]interface{}{"warehouse": map[string]interface{}{"warehouse_code": "W4"}}, decodeGraphQL(t, response.Body.Bytes()).Data)
+	})
+	t.Run("Should return status 200 with the errors of the fields that failed", func(t *testing.T) {
+		server, service := InitGraphQLServer(t)
+		service.On("Get", mock.Anything, 9).Return(domain.Warehouse{}, warehouse.ErrNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPost, GraphQLEndpoint, `{"query":"{ warehouse(id: 9) { id } }"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		body := decodeGraphQL(t, response.Body.Bytes())
+		assert.Equal(t, map[string]interface{}{"warehouse": nil}, body.Data)
+		assert.Equal(t, warehouse.ErrNotFound.Error(), body.Errors[0].Message)
+	})
+	t.Run("Should return status 400 when the query is rejected", func(t *testing.T) {
+		server, _ := InitGraphQLServer(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, GraphQLEndpoint, `{"query":"{ warehouse(id: 9) { unknown } }"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		body := decodeGraphQL(t, response.Body.Bytes())
+		assert.Nil(t, body.Data)
+		assert.NotEmpty(t, body.Errors)
+	})
+	t.Run("Should return status 400 without a query", func(t *testing.T) {
+		server, _ := InitGraphQLServer(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, GraphQLEndpoint, `{}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Len(t, decodeGraphQL(t, response.Body.Bytes()).Errors, 1)
+	})
+	t.Run("Should return status 400 on invalid variables", func(t *testing.T) {
+		server, _ := InitGraphQLServer(t)
+
+		query := url.Values{"query": {"{ warehouses { id } }"}, "variables": {"{"}}
+		request, response := testutil.MakeRequest(http.MethodGet, GraphQLEndpoint+"?"+query.Encode(), "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, "invalid variables", decodeGraphQL(t, response.Body.Bytes()).Errors[0].Message)
+	})
+}
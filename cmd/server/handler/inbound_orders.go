@@ -4,17 +4,15 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/inbound_order"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
 	"github.com/gin-gonic/gin"
 )
 
-const layout = "2006-01-02"
-
 type InboundOrdersController struct {
 	InboundOrdersService inbound_order.Service
 }
@@ -69,11 +67,14 @@ func (s *InboundOrdersController) Create() gin.HandlerFunc {
 		inboundOrdersInput := &domain.InboundOrders{}
 		err := c.ShouldBindJSON(inboundOrdersInput)
 		if err != nil {
+			if errors.Is(err, datetime.ErrInvalid) {
+				web.Error(c, http.StatusBadRequest, "invalid date")
+				return
+			}
 			web.Error(c, http.StatusUnprocessableEntity, inbound_order.ErrInvalidJSON.Error())
 			return
 		}
-		_, err = time.Parse(layout, inboundOrdersInput.OrderDate)
-		if err != nil {
+		if inboundOrdersInput.OrderDate.IsZero() {
 			web.Error(c, http.StatusBadRequest, "invalid date")
 			return
 		}
@@ -94,30 +95,112 @@ func (s *InboundOrdersController) Create() gin.HandlerFunc {
 
 		inboundOrdersDomain, err := s.InboundOrdersService.Create(c, *inboundOrdersInput)
 		if err != nil {
-			switch err {
-			case inbound_order.ErrAlredyExists:
+			switch {
+			case errors.Is(err, inbound_order.ErrAlredyExists):
 				web.Error(c, http.StatusConflict, err.Error())
+			case errors.Is(err, inbound_order.ErrEmployeeNotFound):
+				web.ErrorWithCode(c, http.StatusConflict, "employee_not_found", err.Error())
+			case errors.Is(err, inbound_order.ErrWarehouseNotFound):
+				web.ErrorWithCode(c, http.StatusConflict, "warehouse_not_found", err.Error())
+			case errors.Is(err, inbound_order.ErrBatchNotFound):
+				web.ErrorWithCode(c, http.StatusConflict, "product_batch_not_found", err.Error())
+			case errors.Is(err, inbound_order.ErrEmployeeWarehouse):
+				web.ErrorWithCode(c, http.StatusUnprocessableEntity, "employee_not_in_warehouse", err.Error())
+			case errors.Is(err, inbound_order.ErrBatchWarehouse):
+				web.ErrorWithCode(c, http.StatusUnprocessableEntity, "product_batch_not_in_warehouse", err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		web.Success(c, http.StatusCreated, inboundOrdersDomain)
+	}
+}
+
+// @Summary List Inbound Orders
+// @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// GET /inboundOrders @Summary Returns a list of inbound orders
+// @Router /api/v1/inboundOrders [get]
+// @Tags InboundOrders
+// @Accept json
+// @Param employee_id query int false "Employee ID"
+// @Param warehouse_id query int false "Warehouse ID"
+// @Param from query string false "Orders dated on or after this date"
+// @Param to query string false "Orders dated before this date"
+// @Success 200 {object} []domain.InboundOrders
+// @Description List Inbound Orders, optionally filtered by employee, warehouse and date
+func (s *InboundOrdersController) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filter domain.InboundOrderFilter
+		var err error
+		if param := c.Query("employee_id"); param != "" {
+			if filter.EmployeeID, err = strconv.Atoi(param); err != nil || filter.EmployeeID <= 0 {
+				web.Error(c, http.StatusBadRequest, "invalid employee_id")
 				return
 			}
-			web.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		if param := c.Query("warehouse_id"); param != "" {
+			if filter.WarehouseID, err = strconv.Atoi(param); err != nil || filter.WarehouseID <= 0 {
+				web.Error(c, http.StatusBadRequest, "invalid warehouse_id")
+				return
+			}
+		}
+		if param := c.Query("from"); param != "" {
+			if filter.From, err = datetime.Parse(param); err != nil {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if param := c.Query("to"); param != "" {
+			if filter.To, err = datetime.Parse(param); err != nil {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
 
+		orders, err := s.InboundOrdersService.GetAll(c, filter)
+		if err != nil {
+			if errors.Is(err, inbound_order.ErrInvalidRange) {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, inbound_order.ErrTryAgain.Error(), err)
+			return
 		}
-		web.Success(c, http.StatusCreated, inboundOrdersDomain)
+		web.Success(c, http.StatusOK, orders)
 	}
 }
 
 // @Summary Generate a report for all inbound orders
-// @Description Generates a report containing information for all inbound orders
+// @Description Generates a report of the inbound orders and units received by every employee, including those without orders
 // @Tags InboundOrders
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} domain.InboundOrdersReport
+// @Param warehouse_id query int false "Warehouse the employee was assigned to when receiving the orders"
+// @Param from query string false "Orders dated on or after this date"
+// @Param to query string false "Orders dated before this date"
+// @Param granularity query string false "Bucket size: day, week or month"
+// @Success 200 {object} []domain.InboundOrdersReport
+// @Failure 400 {string} ErrInvalidGranularity
 // @Failure 500 {string} ErrTryAgain
-// @Router /api/v1/reportInboundOrders [get]
+// @Router /api/v1/employees/reportInboundOrders [get]
 func (p *InboundOrdersController) ReportByAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		report, err := p.InboundOrdersService.ReportByAll(c)
+		filter, err := reportFilter(c)
 		if err != nil {
+			web.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		report, err := p.InboundOrdersService.ReportByAll(c, filter)
+		if err != nil {
+			if errors.Is(err, inbound_order.ErrInvalidGranularity) || errors.Is(err, inbound_order.ErrInvalidRange) {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
 			web.Error(c, http.StatusInternalServerError, inbound_order.ErrTryAgain.Error())
 
 			return
@@ -132,11 +215,15 @@ func (p *InboundOrdersController) ReportByAll() gin.HandlerFunc {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "ID of the employee"
+// @Param warehouse_id query int false "Warehouse the employee was assigned to when receiving the orders"
+// @Param from query string false "Orders dated on or after this date"
+// @Param to query string false "Orders dated before this date"
+// @Param granularity query string false "Bucket size: day, week or month"
 // @Success 200 {object} domain.InboundOrdersReport
 // @Failure 400 {string} ErrInvalidId
 // @Failure 404 {string} ErrNotFound
 // @Failure 500 {string} ErrTryAgain
-// @Router /api/v1/reportInboundOrders{id} [get]
+// @Router /api/v1/employees/reportInboundOrders/{id} [get]
 func (p *InboundOrdersController) ReportByOne() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		employeeId, err := strconv.Atoi(c.Param("id"))
@@ -144,14 +231,23 @@ func (p *InboundOrdersController) ReportByOne() gin.HandlerFunc {
 			web.Response(c, http.StatusBadRequest, employee.ErrInvalidId.Error())
 			return
 		}
+		filter, err := reportFilter(c)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
 
-		report, err := p.InboundOrdersService.ReportByOne(c, employeeId)
+		report, err := p.InboundOrdersService.ReportByOne(c, employeeId, filter)
 		if err != nil {
 			if errors.Is(err, inbound_order.ErrNotFound) {
 				web.Error(c, http.StatusNotFound, inbound_order.ErrNotFound.Error())
 
 				return
 			}
+			if errors.Is(err, inbound_order.ErrInvalidGranularity) || errors.Is(err, inbound_order.ErrInvalidRange) {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
 
 			web.Error(c, http.StatusInternalServerError, employee.ErrTryAgain.Error())
 
@@ -160,3 +256,25 @@ func (p *InboundOrdersController) ReportByOne() gin.HandlerFunc {
 		web.Success(c, http.StatusOK, report)
 	}
 }
+
+// reportFilter reads the inbound orders report filter from the query string.
+func reportFilter(c *gin.Context) (domain.InboundOrdersReportFilter, error) {
+	filter := domain.InboundOrdersReportFilter{Granularity: c.Query("granularity")}
+	var err error
+	if param := c.Query("warehouse_id"); param != "" {
+		if filter.WarehouseID, err = strconv.Atoi(param); err != nil || filter.WarehouseID <= 0 {
+			return domain.InboundOrdersReportFilter{}, errors.New("invalid warehouse_id")
+		}
+	}
+	if param := c.Query("from"); param != "" {
+		if filter.From, err = datetime.Parse(param); err != nil {
+			return domain.InboundOrdersReportFilter{}, err
+		}
+	}
+	if param := c.Query("to"); param != "" {
+		if filter.To, err = datetime.Parse(param); err != nil {
+			return domain.InboundOrdersReportFilter{}, err
+		}
+	}
+	return filter, nil
+}
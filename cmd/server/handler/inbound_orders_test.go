@@ -8,6 +8,7 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/inbound_order"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/inbound_order"
@@ -22,7 +23,7 @@ const (
 )
 
 var expectedInboundOrder = domain.InboundOrders{
-	OrderDate:      "01/01/01",
+	OrderDate:      datetime.MustParse("2001-01-01"),
 	OrderNumber:    "001",
 	EmployeeID:     1,
 	ProductBatchID: 1,
@@ -87,7 +88,7 @@ func TestGetInboundOrders(t *testing.T) {
 
 func TestCreateInboundOrders(t *testing.T) {
 	newInboudOrders := domain.InboundOrders{
-		OrderDate:      "2021-01-01",
+		OrderDate:      datetime.MustParse("2021-01-01"),
 		OrderNumber:    "002",
 		EmployeeID:     1,
 		ProductBatchID: 1,
@@ -123,16 +124,8 @@ func TestCreateInboundOrders(t *testing.T) {
 	})
 	t.Run("Should return status 400 when Order Date is invalid", func(t *testing.T) {
 		server, _, handler := InitServerWithInboundOrders(t)
-		newInboudOrdersInvalid := domain.InboundOrders{
-			ID:             1,
-			OrderDate:      "1",
-			OrderNumber:    "1",
-			EmployeeID:     1,
-			ProductBatchID: 1,
-			WarehouseID:    1,
-		}
-		jsonProductBatchInvalid, _ := json.Marshal(newInboudOrdersInvalid)
-		request, response := testutil.MakeRequest(http.MethodPost, BaseEndpointInboundOrders, string(jsonProductBatchInvalid))
+		jsonProductBatchInvalid := `{"id": 1, "order_date": "1", "order_number": "1", "employee_id": 1, "product_batch_id": 1, "warehouse_id": 1}`
+		request, response := testutil.MakeRequest(http.MethodPost, BaseEndpointInboundOrders, jsonProductBatchInvalid)
 
 		server.POST(BaseEndpointInboundOrders, handler.Create())
 		server.ServeHTTP(response, request)
@@ -143,7 +136,7 @@ func TestCreateInboundOrders(t *testing.T) {
 		server, _, handler := InitServerWithInboundOrders(t)
 		newInboudOrdersInvalid := &domain.InboundOrders{
 			ID:             1,
-			OrderDate:      "2020-01-02",
+			OrderDate:      datetime.MustParse("2020-01-02"),
 			OrderNumber:    "",
 			EmployeeID:     1,
 			ProductBatchID: 1,
@@ -162,7 +155,7 @@ func TestCreateInboundOrders(t *testing.T) {
 		server, _, handler := InitServerWithInboundOrders(t)
 		newInboudOrdersInvalid := &domain.InboundOrders{
 			ID:             1,
-			OrderDate:      "2020-01-02",
+			OrderDate:      datetime.MustParse("2020-01-02"),
 			OrderNumber:    "1",
 			EmployeeID:     0,
 			ProductBatchID: 1,
@@ -180,7 +173,7 @@ func TestCreateInboundOrders(t *testing.T) {
 		server, _, handler := InitServerWithInboundOrders(t)
 		newInboudOrdersInvalid := &domain.InboundOrders{
 			ID:             1,
-			OrderDate:      "2020-01-02",
+			OrderDate:      datetime.MustParse("2020-01-02"),
 			OrderNumber:    "1",
 			EmployeeID:     1,
 			ProductBatchID: 0,
@@ -199,7 +192,7 @@ func TestCreateInboundOrders(t *testing.T) {
 
 		newInboudOrdersInvalid := &domain.InboundOrders{
 			ID:             1,
-			OrderDate:      "2020-01-02",
+			OrderDate:      datetime.MustParse("2020-01-02"),
 			OrderNumber:    "1",
 			EmployeeID:     1,
 			ProductBatchID: 1,
@@ -266,7 +259,7 @@ func TestReportsByAllInboundOrders(t *testing.T) {
 		server.GET("/employees/reportInboundOrders", handler.ReportByAll())
 		request, response := testutil.MakeRequest(http.MethodGet, "/employees/reportInboundOrders", "")
 
-		mockService.On("ReportByAll", mock.Anything).Return(expectedInboundOrders, nil)
+		mockService.On("ReportByAll", mock.Anything, mock.Anything).Return(expectedInboundOrders, nil)
 		server.ServeHTTP(response, request)
 
 		responseResult := &domain.InboundOrdersReport{}
@@ -285,7 +278,7 @@ func TestReportsByAllInboundOrders(t *testing.T) {
 
 		request, response := testutil.MakeRequest(http.MethodGet, "/employees/reportInboundOrders", "")
 
-		mockService.On("ReportByAll", mock.Anything).Return(ExpectedEmptyReports, inbound_order.ErrTryAgain)
+		mockService.On("ReportByAll", mock.Anything, mock.Anything).Return(ExpectedEmptyReports, inbound_order.ErrTryAgain)
 
 		server.ServeHTTP(response, request)
 		assert.Equal(t, http.StatusInternalServerError, response.Code)
@@ -310,7 +303,7 @@ func TestReportsByOneInboundOrders(t *testing.T) {
 		server.GET("/employees/reportInboundOrders/:id", handler.ReportByOne())
 		request, response := testutil.MakeRequest(http.MethodGet, "/employees/reportInboundOrders/1", "")
 
-		mockService.On("ReportByOne", mock.Anything, mock.Anything).Return(expectedInboundOrders, nil)
+		mockService.On("ReportByOne", mock.Anything, mock.Anything, mock.Anything).Return(expectedInboundOrders, nil)
 
 		server.ServeHTTP(response, request)
 
@@ -329,7 +322,7 @@ func TestReportsByOneInboundOrders(t *testing.T) {
 		server, mockService, handler := InitServerWithInboundOrders(t)
 
 		server.GET("/employees/reportInboundOrders/:id", handler.ReportByOne())
-		mockService.On("ReportByOne", mock.Anything, mock.Anything).Return(ExpectedEmptyInboundOrder, inbound_order.ErrTryAgain)
+		mockService.On("ReportByOne", mock.Anything, mock.Anything, mock.Anything).Return(ExpectedEmptyInboundOrder, inbound_order.ErrTryAgain)
 
 		request, response := testutil.MakeRequest(http.MethodGet, "/employees/reportInboundOrders/1", "")
 
@@ -344,7 +337,7 @@ func TestReportsByOneInboundOrders(t *testing.T) {
 		server.GET("/employees/reportInboundOrders/:id", handler.ReportByOne())
 		request, response := testutil.MakeRequest(http.MethodGet, "/employees/reportInboundOrders/1", "")
 
-		mockService.On("ReportByOne", mock.Anything, mock.Anything).Return(domain.InboundOrdersReport{}, inbound_order.ErrNotFound)
+		mockService.On("ReportByOne", mock.Anything, mock.Anything, mock.Anything).Return(domain.InboundOrdersReport{}, inbound_order.ErrNotFound)
 
 		server.ServeHTTP(response, request)
 
@@ -356,7 +349,7 @@ func TestReportsByOneInboundOrders(t *testing.T) {
 		server, mockService, handler := InitServerWithInboundOrders(t)
 		server.GET("/employees/reportInboundOrders/:id", handler.ReportByOne())
 
-		mockService.On("ReportByOne", mock.Anything, mock.Anything).Return(domain.InboundOrdersReport{}, inbound_order.ErrInvalidId)
+		mockService.On("ReportByOne", mock.Anything, mock.Anything, mock.Anything).Return(domain.InboundOrdersReport{}, inbound_order.ErrInvalidId)
 
 		request, response := testutil.MakeRequest(http.MethodGet, "/employees/reportInboundOrders/invalidId", "")
 
@@ -366,6 +359,121 @@ func TestReportsByOneInboundOrders(t *testing.T) {
 	})
 }
 
+func TestReportsInboundOrdersFilter(t *testing.T) {
+	t.Run("Should pass the query filter to the service", func(t *testing.T) {
+		server, mockService, handler := InitServerWithInboundOrders(t)
+		filter := domain.InboundOrdersReportFilter{
+			WarehouseID: 1,
+			From:        datetime.MustParse("2023-01-01"),
+			To:          datetime.MustParse("2023-02-01"),
+			Granularity: domain.GranularityMonth,
+		}
+		expectedReport := []domain.InboundOrdersReport{
+			{ID: 1, WarehouseID: 1, InboundOrdersCount: 1, UnitsReceived: 10, Buckets: []domain.InboundOrdersBucket{
+				{Start: "2023-01-01", InboundOrdersCount: 1, UnitsReceived: 10},
+			}},
+			{ID: 2, WarehouseID: 1},
+		}
+		mockService.On("ReportByAll", mock.Anything, filter).Return(expectedReport, nil)
+
+		server.GET("/employees/reportInboundOrders", handler.ReportByAll())
+		request, response := testutil.MakeRequest(http.MethodGet, "/employees/reportInboundOrders?warehouse_id=1&from=2023-01-01&to=2023-02-01&granularity=month", "")
+		server.ServeHTTP(response, request)
+
+		responseResult := &domain.InboundOrdersReportsResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, expectedReport, responseResult.Data)
+	})
+	t.Run("Should return status 400 when the query is invalid", func(t *testing.T) {
+		for _, query := range []string{"warehouse_id=abc", "from=yesterday", "to=2023-13-01"} {
+			server, _, handler := InitServerWithInboundOrders(t)
+
+			server.GET("/employees/reportInboundOrders", handler.ReportByAll())
+			request, response := testutil.MakeRequest(http.MethodGet, "/employees/reportInboundOrders?"+query, "")
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, http.StatusBadRequest, response.Code, query)
+		}
+	})
+	t.Run("Should return status 400 when the granularity is not supported", func(t *testing.T) {
+		server, mockService, handler := InitServerWithInboundOrders(t)
+		mockService.On("ReportByOne", mock.Anything, 1, domain.InboundOrdersReportFilter{Granularity: "year"}).
+			Return(domain.InboundOrdersReport{}, inbound_order.ErrInvalidGranularity)
+
+		server.GET("/employees/reportInboundOrders/:id", handler.ReportByOne())
+		request, response := testutil.MakeRequest(http.MethodGet, "/employees/reportInboundOrders/1?granularity=year", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestCreateInboundOrdersReferences(t *testing.T) {
+	body, _ := json.Marshal(expectedInboundOrder)
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{inbound_order.ErrEmployeeNotFound, http.StatusConflict, "employee_not_found"},
+		{inbound_order.ErrWarehouseNotFound, http.StatusConflict, "warehouse_not_found"},
+		{inbound_order.ErrBatchNotFound, http.StatusConflict, "product_batch_not_found"},
+		{inbound_order.ErrEmployeeWarehouse, http.StatusUnprocessableEntity, "employee_not_in_warehouse"},
+		{inbound_order.ErrBatchWarehouse, http.StatusUnprocessableEntity, "product_batch_not_in_warehouse"},
+	}
+	for _, tc := range cases {
+		t.Run("Should return "+tc.code, func(t *testing.T) {
+			server, mockService, handler := InitServerWithInboundOrders(t)
+			mockService.On("Create", mock.Anything, mock.AnythingOfType("domain.InboundOrders")).Return(domain.InboundOrders{}, tc.err)
+			server.POST(BaseEndpointInboundOrders, handler.Create())
+
+			request, response := testutil.MakeRequest(http.MethodPost, BaseEndpointInboundOrders, string(body))
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.status, response.Code)
+			assert.Contains(t, response.Body.String(), `"code":"`+tc.code+`"`)
+		})
+	}
+}
+
+func TestGetAllInboundOrders(t *testing.T) {
+	t.Run("Should return status 200 with the filtered inbound orders", func(t *testing.T) {
+		server, mockService, handler := InitServerWithInboundOrders(t)
+		filter := domain.InboundOrderFilter{EmployeeID: 1, WarehouseID: 2, From: datetime.MustParse("2001-01-01"), To: datetime.MustParse("2001-02-01")}
+		mockService.On("GetAll", mock.Anything, filter).Return([]domain.InboundOrders{expectedInboundOrder}, nil)
+		server.GET(BaseEndpointInboundOrders, handler.GetAll())
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointInboundOrders+"?employee_id=1&warehouse_id=2&from=2001-01-01&to=2001-02-01", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"order_number":"001"`)
+	})
+
+	t.Run("Should return status 400 when a filter is invalid", func(t *testing.T) {
+		server, _, handler := InitServerWithInboundOrders(t)
+		server.GET(BaseEndpointInboundOrders, handler.GetAll())
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointInboundOrders+"?employee_id=abc", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Should return status 400 when the range is empty", func(t *testing.T) {
+		server, mockService, handler := InitServerWithInboundOrders(t)
+		mockService.On("GetAll", mock.Anything, mock.Anything).Return([]domain.InboundOrders(nil), inbound_order.ErrInvalidRange)
+		server.GET(BaseEndpointInboundOrders, handler.GetAll())
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointInboundOrders+"?from=2001-02-01&to=2001-01-01", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
 func InitServerWithInboundOrders(t *testing.T) (*gin.Engine, *mocks.InboundOrderServiceMock, *handler.InboundOrdersController) {
 	t.Helper()
 	server := testutil.CreateServer()
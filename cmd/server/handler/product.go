@@ -13,20 +13,32 @@ import (
 
 type ProductController struct {
 	productService product.Service
+	divisor        float64
 }
 
-func NewProduct(s product.Service) *ProductController {
+// NewProduct returns the product handlers, which respond with the measures of
+// the products, their volumetric weight computed with divisor.
+func NewProduct(s product.Service, divisor float64) *ProductController {
 	return &ProductController{
 		productService: s,
+		divisor:        divisor,
 	}
 }
 
+func (p *ProductController) measure(products []domain.Product) []domain.MeasuredProduct {
+	measured := make([]domain.MeasuredProduct, len(products))
+	for i, item := range products {
+		measured[i] = domain.Measure(item, p.divisor)
+	}
+	return measured
+}
+
 // @Summary Get All Products
 // @Produce json
 // @Router /api/v1/products [get]
 // @Tags Products
 // @Accept json
-// @Success 200 {object}  []domain.Product
+// @Success 200 {object}  []domain.MeasuredProduct
 // @Description List all Products
 func (p *ProductController) GetAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -36,7 +48,74 @@ func (p *ProductController) GetAll() gin.HandlerFunc {
 
 			return
 		}
-		web.Success(c, http.StatusOK, products)
+		web.Success(c, http.StatusOK, p.measure(products))
+	}
+}
+
+// @Summary Search Products
+// @Produce json
+// @Router /api/v1/products/search [get]
+// @Tags Products
+// @Accept json
+// @Param q query string false "Words matched against the description and product code"
+// @Param product_type_id query int false "Product type ID"
+// @Param seller_id query int false "Seller ID"
+// @Param min_weight query number false "Minimum net weight"
+// @Param max_weight query number false "Maximum net weight"
+// @Param freezable query bool false "Whether the product is kept at or below 0 degrees"
+// @Success 200 {object} domain.ProductSearchResult
+// @Failure 400 {string} ErrInvalidQuery
+// @Description Search products by relevance, with their counts by product type and seller and the matched words highlighted
+func (p *ProductController) Search() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		search := domain.ProductSearch{Query: c.Query("q")}
+		var err error
+		if param := c.Query("product_type_id"); param != "" {
+			if search.ProductTypeID, err = strconv.Atoi(param); err != nil || search.ProductTypeID <= 0 {
+				web.Error(c, http.StatusBadRequest, "invalid product_type_id")
+				return
+			}
+		}
+		if param := c.Query("seller_id"); param != "" {
+			if search.SellerID, err = strconv.Atoi(param); err != nil || search.SellerID <= 0 {
+				web.Error(c, http.StatusBadRequest, "invalid seller_id")
+				return
+			}
+		}
+		if param := c.Query("min_weight"); param != "" {
+			if search.MinWeight, err = strconv.ParseFloat(param, 64); err != nil || search.MinWeight < 0 {
+				web.Error(c, http.StatusBadRequest, "invalid min_weight")
+				return
+			}
+		}
+		if param := c.Query("max_weight"); param != "" {
+			if search.MaxWeight, err = strconv.ParseFloat(param, 64); err != nil || search.MaxWeight < 0 {
+				web.Error(c, http.StatusBadRequest, "invalid max_weight")
+				return
+			}
+		}
+		if param := c.Query("freezable"); param != "" {
+			freezable, err := strconv.ParseBool(param)
+			if err != nil {
+				web.Error(c, http.StatusBadRequest, "invalid freezable")
+				return
+			}
+			search.Freezable = &freezable
+		}
+
+		result, err := p.productService.Search(c, search)
+		if err != nil {
+			if errors.Is(err, product.ErrInvalidQuery) || errors.Is(err, product.ErrInvalidWeightRange) {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, product.ErrTryAgain.Error(), err)
+			return
+		}
+		for i, hit := range result.Products {
+			result.Products[i].MeasuredProduct = domain.Measure(hit.Product, p.divisor)
+		}
+		web.Success(c, http.StatusOK, result)
 	}
 }
 
@@ -46,7 +125,7 @@ func (p *ProductController) GetAll() gin.HandlerFunc {
 // @Param   id     path    int     true        "Product ID"
 // @Tags Products
 // @Accept json
-// @Success 200 {object}  domain.Product
+// @Success 200 {object}  domain.MeasuredProduct
 // @Description List one product by it's Product id
 func (p *ProductController) Get() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -67,7 +146,7 @@ func (p *ProductController) Get() gin.HandlerFunc {
 			web.Error(c, http.StatusInternalServerError, product.ErrTryAgain.Error())
 			return
 		}
-		web.Success(c, http.StatusOK, newProduct)
+		web.Success(c, http.StatusOK, domain.Measure(newProduct, p.divisor))
 	}
 }
 
@@ -77,7 +156,7 @@ func (p *ProductController) Get() gin.HandlerFunc {
 // @Tags Products
 // @Accept json
 // @Param product body domain.Product true "Product Data"
-// @Success 201 {object} domain.Product
+// @Success 201 {object} domain.MeasuredProduct
 // @Description Create Product
 func (p *ProductController) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -118,7 +197,7 @@ func (p *ProductController) Create() gin.HandlerFunc {
 			return
 		}
 		productItem.ID = productId
-		web.Success(c, http.StatusCreated, productItem)
+		web.Success(c, http.StatusCreated, domain.Measure(productItem, p.divisor))
 	}
 }
 
@@ -126,8 +205,9 @@ func (p *ProductController) Create() gin.HandlerFunc {
 // @Produce json
 // @Router /api/v1/products/{id} [patch]
 // @Accept json
+// @Accept application/merge-patch+json
 // @Tags Products
-// @Success 200 {object}  domain.Product
+// @Success 200 {object}  domain.MeasuredProduct
 // @Param id path int true "Product ID"
 // @Param product body domain.Product true "Product Data"
 // @Description Update Product
@@ -140,46 +220,30 @@ func (p *ProductController) Update() gin.HandlerFunc {
 			return
 		}
 
-		productImput := &domain.ProductRequest{}
-		err := c.ShouldBindJSON(productImput)
+		document, err := web.MergePatch(c)
 		if err != nil {
-
 			web.Error(c, http.StatusUnprocessableEntity, product.ErrInvalidJson.Error(), err)
 			return
 		}
 
-		if productImput.Description == "" || productImput.ExpirationRate == 0 || productImput.FreezingRate == 0 || productImput.Height == 0 || productImput.Length == 0 || productImput.Netweight == 0 || productImput.ProductCode == "" || productImput.RecomFreezTemp == 0 || productImput.SellerID == 0 {
-
-			web.Error(c, http.StatusBadRequest, product.ErrInvalidField.Error())
-			return
-		}
-
-		productItem := domain.Product{
-			ID:             productId,
-			Description:    productImput.Description,
-			ExpirationRate: productImput.ExpirationRate,
-			FreezingRate:   productImput.FreezingRate,
-			Height:         productImput.Height,
-			Length:         productImput.Length,
-			Netweight:      productImput.Netweight,
-			ProductCode:    productImput.ProductCode,
-			RecomFreezTemp: productImput.RecomFreezTemp,
-			Width:          productImput.Width,
-			ProductTypeID:  productImput.ProductTypeID,
-			SellerID:       productImput.SellerID,
-		}
-
-		err = p.productService.Update(c, productItem)
+		productItem, err := p.productService.Update(c, productId, domain.Patch{Document: document})
 
 		if err != nil {
-			if errors.Is(err, product.ErrNotFound) {
+			switch {
+			case errors.Is(err, product.ErrNotFound):
 				web.Error(c, http.StatusNotFound, product.ErrNotFound.Error())
-				return
+			case errors.Is(err, product.ErrInvalidBody):
+				web.Error(c, http.StatusUnprocessableEntity, product.ErrInvalidJson.Error())
+			case errors.Is(err, product.ErrInvalidField):
+				web.Error(c, http.StatusBadRequest, product.ErrInvalidField.Error())
+			case errors.Is(err, product.ErrProductAlreadyExists):
+				web.Error(c, http.StatusConflict, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
 			}
-			web.Error(c, http.StatusInternalServerError, err.Error())
 			return
 		}
-		web.Success(c, http.StatusOK, productItem)
+		web.Success(c, http.StatusOK, domain.Measure(productItem, p.divisor))
 
 	}
 }
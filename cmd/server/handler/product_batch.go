@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
@@ -67,3 +68,54 @@ func (s *ProductBatchController) Create() gin.HandlerFunc {
 		web.Success(c, http.StatusCreated, productBatch)
 	}
 }
+
+// @Summary List Product Batches
+// @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// GET /productBatches @Summary Returns a list of Product Batches
+// @Router /api/v1/productBatches [get]
+// @Tags ProductBatch
+// @Accept json
+// @Param section_id query int false "Section ID"
+// @Param product_id query int false "Product ID"
+// @Success 200 {object} []domain.ProductBatch
+// @Description List Product Batches, optionally filtered by section and product
+func (s *ProductBatchController) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filter domain.ProductBatchFilter
+		var err error
+		if param := c.Query("section_id"); param != "" {
+			if filter.SectionID, err = strconv.Atoi(param); err != nil || filter.SectionID <= 0 {
+				web.Error(c, http.StatusBadRequest, "invalid section_id")
+				return
+			}
+		}
+		if param := c.Query("product_id"); param != "" {
+			if filter.ProductID, err = strconv.Atoi(param); err != nil || filter.ProductID <= 0 {
+				web.Error(c, http.StatusBadRequest, "invalid product_id")
+				return
+			}
+		}
+
+		if format, ok := web.ExportFormat(c); ok {
+			err := web.Stream(c, format, domain.ProductBatch{}, func(emit func(interface{}) error) error {
+				return s.productBatchService.ForEach(c, filter, func(p domain.ProductBatch) error {
+					return emit(p)
+				})
+			})
+			if err != nil && !c.Writer.Written() {
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		productBatches, err := s.productBatchService.GetAll(c, filter)
+		if err != nil {
+			web.Error(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, productBatches)
+	}
+}
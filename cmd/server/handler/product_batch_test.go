@@ -8,6 +8,7 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
 	mocksProduct "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product_batch"
@@ -32,10 +33,10 @@ func TestCreateProductBatch(t *testing.T) {
 		BatchNumber:        1,
 		CurrentQuantity:    1,
 		InitialQuantity:    1,
-		ManufacturingDate:  "2021-01-01",
+		ManufacturingDate:  datetime.MustParse("2021-01-01"),
 		CurrentTemperature: 1,
 		MinimumTemperature: 1,
-		DueDate:            "2021-01-01",
+		DueDate:            datetime.MustParse("2021-01-01"),
 		ManufacturingHour:  1,
 	}
 
@@ -122,30 +123,98 @@ func TestCreateProductBatch(t *testing.T) {
 		server, handler, mocks := InitServerWithProductBatch(t)
 		server.POST("/productBatches", handler.Create())
 
-		jsonProductBatch, _ := json.Marshal(&domain.ProductBatch{
+		jsonProductBatch := `{
+			"id": 1,
+			"product_id": 1,
+			"section_id": 1,
+			"batch_number": 1,
+			"current_quantity": 1,
+			"initial_quantity": 1,
+			"manufacturing_date": "2021-01-01",
+			"current_temperature": 1,
+			"minimum_temperature": 1,
+			"due_date": "INVALID DATE",
+			"manufacturing_hour": 1
+		}`
+		request, response := testutil.MakeRequest("POST", "/productBatches", jsonProductBatch)
+
+		mocks.ProductBatchServiceMock.On("Save", mock.Anything, mock.Anything).Return(0, nil)
+		mocks.ProductServiceMock.On("ExistsById", newProductBatch.ProductID).Return(nil)
+		mocks.SectionServiceMock.On("ExistsById", newProductBatch.SectionID).Return(nil)
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+}
+
+func TestGetAllProductBatches(t *testing.T) {
+	productBatches := []domain.ProductBatch{
+		{
 			ID:                 1,
 			ProductID:          1,
-			SectionID:          1,
+			SectionID:          2,
 			BatchNumber:        1,
 			CurrentQuantity:    1,
 			InitialQuantity:    1,
-			ManufacturingDate:  "2021-01-01",
+			ManufacturingDate:  datetime.MustParse("2021-01-01"),
 			CurrentTemperature: 1,
 			MinimumTemperature: 1,
-			DueDate:            "INVALID DATE",
+			DueDate:            datetime.MustParse("2021-01-01"),
 			ManufacturingHour:  1,
-		})
-		request, response := testutil.MakeRequest("POST", "/productBatches", string(jsonProductBatch))
+		},
+	}
+	t.Run("Should list product batches filtered by section", func(t *testing.T) {
+		server, handler, mocks := InitServerWithProductBatch(t)
+		server.GET("/productBatches", handler.GetAll())
 
-		mocks.ProductBatchServiceMock.On("Save", mock.Anything, mock.Anything).Return(0, nil)
-		mocks.ProductServiceMock.On("ExistsById", newProductBatch.ProductID).Return(nil)
-		mocks.SectionServiceMock.On("ExistsById", newProductBatch.SectionID).Return(nil)
+		request, response := testutil.MakeRequest(http.MethodGet, "/productBatches?section_id=2", "")
+		mocks.ProductBatchServiceMock.On("GetAll", mock.Anything, domain.ProductBatchFilter{SectionID: 2}).Return(productBatches, nil)
 
 		server.ServeHTTP(response, request)
 
+		var result struct {
+			Data []domain.ProductBatch `json:"data"`
+		}
+		_ = json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, productBatches, result.Data)
+	})
+	t.Run("Should export product batches with the same filter", func(t *testing.T) {
+		server, handler, mocks := InitServerWithProductBatch(t)
+		server.GET("/productBatches", handler.GetAll())
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/productBatches?section_id=2&product_id=1", "")
+		request.Header.Set("Accept", "text/csv")
+		mocks.ProductBatchServiceMock.On("ForEach", mock.Anything, domain.ProductBatchFilter{SectionID: 2, ProductID: 1}).Return(productBatches, nil)
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "id,batch_number,current_quantity,current_temperature,due_date,initial_quantity,manufacturing_date,manufacturing_hour,minimum_temperature,product_id,section_id\n1,1,1,1,2021-01-01T00:00:00Z,1,2021-01-01T00:00:00Z,1,1,1,2\n", response.Body.String())
+		mocks.ProductBatchServiceMock.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+	})
+	t.Run("Should return status 400 when the filter is invalid", func(t *testing.T) {
+		server, handler, _ := InitServerWithProductBatch(t)
+		server.GET("/productBatches", handler.GetAll())
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/productBatches?section_id=abc", "")
+		server.ServeHTTP(response, request)
+
 		assert.Equal(t, http.StatusBadRequest, response.Code)
 	})
+	t.Run("Should return status 500 when the listing fails", func(t *testing.T) {
+		server, handler, mocks := InitServerWithProductBatch(t)
+		server.GET("/productBatches", handler.GetAll())
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/productBatches", "")
+		mocks.ProductBatchServiceMock.On("GetAll", mock.Anything, domain.ProductBatchFilter{}).Return([]domain.ProductBatch{}, errors.New("error"))
 
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
 }
 
 func InitServerWithProductBatch(t *testing.T) (*gin.Engine, *handler.ProductBatchController, ProductBatchServiceMocks) {
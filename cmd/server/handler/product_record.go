@@ -4,11 +4,13 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
 	productrecord "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_record"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
 	"github.com/gin-gonic/gin"
 )
@@ -40,22 +42,21 @@ func (p *ProductRecordController) Create() gin.HandlerFunc {
 		err := c.ShouldBindJSON(productRecordImput)
 
 		if err != nil {
+			if errors.Is(err, datetime.ErrInvalid) {
+				web.Error(c, http.StatusBadRequest, productrecord.ErrInvalidField.Error())
+				return
+			}
 			web.Error(c, http.StatusUnprocessableEntity, productrecord.ErrInvalidJson.Error()) //422
 			return
 		}
 
-		const layout = "2006-01-02" // modelo da data
-		currentDate := time.Now()   //obtem a data atual
-
-		//transforma data de tipo string para tipo data
-		imputDate, err := time.Parse(layout, productRecordImput.LastUpdateDate)
-		if err != nil {
+		if productRecordImput.LastUpdateDate.IsZero() {
 			web.Error(c, http.StatusBadRequest, productrecord.ErrInvalidField.Error())
 			return
 		}
 
 		// se a lastUpdateDate for menor que a data do sistema, não poderá ser criado
-		if imputDate.Before(currentDate) {
+		if productRecordImput.LastUpdateDate.Before(time.Now()) {
 			// alterar pra conflict
 			web.Error(c, http.StatusConflict, productrecord.ErrInvalidDate.Error())
 			return
@@ -68,15 +69,20 @@ func (p *ProductRecordController) Create() gin.HandlerFunc {
 		}
 
 		productRecordItem := domain.ProductRecord{
-			LastUpdateDate: productRecordImput.LastUpdateDate,
-			PurchasePrice:  productRecordImput.PurchasePrice,
-			SalePrice:      productRecordImput.SalePrice,
-			ProductID:      productRecordImput.ProductID,
+			LastUpdateDate:      productRecordImput.LastUpdateDate,
+			PurchasePrice:       productRecordImput.PurchasePrice,
+			SalePrice:           productRecordImput.SalePrice,
+			ProductID:           productRecordImput.ProductID,
+			AllowNegativeMargin: productRecordImput.AllowNegativeMargin,
 		}
 
 		productRecordId, err := p.productRecordService.Save(c, productRecordItem)
 
 		if err != nil {
+			if errors.Is(err, productrecord.ErrNegativeMargin) || errors.Is(err, productrecord.ErrCurrencyMismatch) {
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+				return
+			}
 			web.Error(c, http.StatusInternalServerError, productrecord.ErrTryAgain.Error())
 			return
 		}
@@ -135,3 +141,87 @@ func (p *ProductRecordController) RecordsByOneProductReport() gin.HandlerFunc {
 		web.Success(c, http.StatusOK, productRecord)
 	}
 }
+
+// @Summary Get the price history of a product
+// @Produce json
+// @Router /api/v1/products/{id}/prices [get]
+// @Param   id     path    int     true        "Product ID"
+// @Param   currency query string  false       "Currency code to convert prices to"
+// @Tags ProductRecord
+// @Accept json
+// @Success 200 {object}  []domain.ProductRecord
+// @Description List the price records of one product, oldest first
+func (p *ProductRecordController) PriceHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		productId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, product.ErrInvalidId.Error())
+			return
+		}
+
+		if err := p.productService.ExistsById(productId); err != nil {
+			web.Error(c, http.StatusNotFound, productrecord.ErrNotFound.Error())
+			return
+		}
+
+		records, err := p.productRecordService.PriceHistory(c, productId, strings.ToUpper(c.Query("currency")))
+		if err != nil {
+			if errors.Is(err, productrecord.ErrUnknownCurrency) {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, productrecord.ErrTryAgain.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, records)
+	}
+}
+
+// @Summary Get the price of a product at a given time
+// @Produce json
+// @Router /api/v1/products/{id}/price [get]
+// @Param   id     path    int     true        "Product ID"
+// @Param   at     query   string  false       "Timestamp (RFC3339, 2006-01-02 15:04:05 or 2006-01-02), defaults to now"
+// @Param   currency query string  false       "Currency code to convert prices to"
+// @Tags ProductRecord
+// @Accept json
+// @Success 200 {object}  domain.ProductPrice
+// @Description Resolve the price in effect at a given time with its margin
+func (p *ProductRecordController) PriceAt() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		productId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, product.ErrInvalidId.Error())
+			return
+		}
+
+		at := datetime.Now()
+		if value := c.Query("at"); value != "" {
+			at, err = datetime.Parse(value)
+			if err != nil {
+				web.Error(c, http.StatusBadRequest, productrecord.ErrInvalidDate.Error())
+				return
+			}
+		}
+
+		if err := p.productService.ExistsById(productId); err != nil {
+			web.Error(c, http.StatusNotFound, productrecord.ErrNotFound.Error())
+			return
+		}
+
+		price, err := p.productRecordService.PriceAt(c, productId, at, strings.ToUpper(c.Query("currency")))
+		if err != nil {
+			if errors.Is(err, productrecord.ErrUnknownCurrency) {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			if errors.Is(err, productrecord.ErrNoPrice) {
+				web.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, productrecord.ErrTryAgain.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, price)
+	}
+}
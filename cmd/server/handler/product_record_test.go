@@ -10,6 +10,8 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
 	productrecord "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_record"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
 	productmocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product_record"
@@ -153,9 +155,9 @@ func TestSave(t *testing.T) {
 	}`
 	expectedProductRecord := domain.ProductRecord{
 		ID:             1,
-		LastUpdateDate: "2021-04-04",
-		PurchasePrice:  10,
-		SalePrice:      15,
+		LastUpdateDate: datetime.MustParse("2021-04-04"),
+		PurchasePrice:  money.MustParse("10", "USD"),
+		SalePrice:      money.MustParse("15", "USD"),
 		ProductID:      1,
 	}
 	t.Run("Should return 201 when a product record is created", func(t *testing.T) {
@@ -216,6 +218,38 @@ func TestSave(t *testing.T) {
 
 	})
 
+	t.Run("Should return 422 when the sale price is below the purchase price", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProductRecords(t)
+		server.POST("/productRecords", handler.Create())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/productRecords", `{
+			"last_update_date": "5050-08-04",
+			"purchase_price": {"amount": "15.25", "currency": "USD"},
+			"sale_price": 10.75,
+			"product_id": 1
+		}`)
+
+		mockService.MockProductService.On("ExistsById", 1).Return(nil)
+		mockService.MockProductRecordService.On("Save", mock.MatchedBy(func(p domain.ProductRecord) bool {
+			return p.PurchasePrice == money.MustParse("15.25", "USD") && p.SalePrice == money.MustParse("10.75", "USD")
+		})).Return(0, productrecord.ErrNegativeMargin)
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Should return 422 when a price has more than two decimals", func(t *testing.T) {
+		server, _, handler := InitServerWithProductRecords(t)
+		server.POST("/productRecords", handler.Create())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/productRecords", `{"last_update_date": "5050-08-04", "sale_price": 10.755, "product_id": 1}`)
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
 	t.Run("Should not save if product lastUpdateDate is invalid", func(t *testing.T) {
 
 		server, _, handler := InitServerWithProductRecords(t)
@@ -243,6 +277,88 @@ func TestSave(t *testing.T) {
 	})
 }
 
+func TestPriceHistory(t *testing.T) {
+	t.Run("Should return 200 with the price records of a product", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProductRecords(t)
+		server.GET("/products/:id/prices", handler.PriceHistory())
+
+		records := []domain.ProductRecord{{ID: 1, LastUpdateDate: datetime.MustParse("2023-01-01 00:00:00"), PurchasePrice: money.MustParse("10", "USD"), SalePrice: money.MustParse("15", "USD"), ProductID: 1}}
+		mockService.MockProductService.On("ExistsById", 1).Return(nil)
+		mockService.MockProductRecordService.On("PriceHistory", 1, "").Return(records, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/products/1/prices", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":[{"id":1,"last_update_date":"2023-01-01T00:00:00Z","purchase_price":{"amount":"10.00","currency":"USD"},"sale_price":{"amount":"15.00","currency":"USD"},"product_id":1}]}`, response.Body.String())
+	})
+
+	t.Run("Should return 404 when the product does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProductRecords(t)
+		server.GET("/products/:id/prices", handler.PriceHistory())
+
+		mockService.MockProductService.On("ExistsById", 1).Return(product.ErrNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/products/1/prices", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestPriceAt(t *testing.T) {
+	t.Run("Should return 200 with the price in effect at the given time", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProductRecords(t)
+		server.GET("/products/:id/price", handler.PriceAt())
+
+		at := datetime.MustParse("2023-01-15")
+		price := domain.ProductPrice{ProductID: 1, RecordID: 3, PurchasePrice: money.MustParse("20", "USD"), SalePrice: money.MustParse("30", "USD"), Margin: money.MustParse("10", "USD"), MarginPercent: 33.33}
+		mockService.MockProductService.On("ExistsById", 1).Return(nil)
+		mockService.MockProductRecordService.On("PriceAt", 1, at, "").Return(price, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/products/1/price?at=2023-01-15", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("Should return 400 when the currency cannot be converted to", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProductRecords(t)
+		server.GET("/products/:id/price", handler.PriceAt())
+
+		mockService.MockProductService.On("ExistsById", 1).Return(nil)
+		mockService.MockProductRecordService.On("PriceAt", 1, mock.Anything, "XYZ").Return(domain.ProductPrice{}, productrecord.ErrUnknownCurrency)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/products/1/price?currency=xyz", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Should return 400 when the timestamp is invalid", func(t *testing.T) {
+		server, _, handler := InitServerWithProductRecords(t)
+		server.GET("/products/:id/price", handler.PriceAt())
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/products/1/price?at=yesterday", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Should return 404 when no price is in effect", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProductRecords(t)
+		server.GET("/products/:id/price", handler.PriceAt())
+
+		mockService.MockProductService.On("ExistsById", 1).Return(nil)
+		mockService.MockProductRecordService.On("PriceAt", 1, mock.Anything, "").Return(domain.ProductPrice{}, productrecord.ErrNoPrice)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/products/1/price?at=2000-01-01T00:00:00Z", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
 func InitServerWithProductRecords(t *testing.T) (*gin.Engine, ProductRecordServiceMocks, *handler.ProductRecordController) {
 	t.Helper()
 	server := testutil.CreateServer()
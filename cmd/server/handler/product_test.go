@@ -46,6 +46,15 @@ var expectedProduct = domain.Product{
 	SellerID:       1,
 }
 
+// measuredProduct is expectedProduct with its measures, the volumetric weight
+// computed with the default divisor.
+var measuredProduct = domain.MeasuredProduct{
+	Product:          expectedProduct,
+	Volume:           34.56,
+	VolumetricWeight: 0.006912,
+	Density:          0.09838,
+}
+
 const (
 	GetAllProducts = "/products"
 )
@@ -138,6 +147,40 @@ func TestGetProductById(t *testing.T) {
 
 	})
 
+	t.Run("Should return the measures of the product", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProducts(t)
+		server.GET("/products/:id", handler.Get())
+		mockService.On("Get", 1).Return(expectedProduct, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/products/1", "")
+		server.ServeHTTP(response, request)
+
+		var body struct {
+			Data domain.MeasuredProduct `json:"data"`
+		}
+		_ = json.Unmarshal(response.Body.Bytes(), &body)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, measuredProduct, body.Data)
+	})
+
+	t.Run("Should return zero measures when the product has no dimensions", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProducts(t)
+		server.GET("/products/:id", handler.Get())
+		flat := expectedProduct
+		flat.Width = 0
+		mockService.On("Get", 1).Return(flat, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/products/1", "")
+		server.ServeHTTP(response, request)
+
+		var body struct {
+			Data domain.MeasuredProduct `json:"data"`
+		}
+		_ = json.Unmarshal(response.Body.Bytes(), &body)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, domain.MeasuredProduct{Product: flat}, body.Data)
+	})
+
 	// case find_by_id_non_existent
 
 	t.Run("Should return status 404 when the product is not found", func(t *testing.T) {
@@ -310,7 +353,7 @@ func TestUpdateProduct(t *testing.T) {
 
 		responseResult := domain.ProductResponseById{}
 
-		mockService.On("Update", mock.Anything, mock.Anything).Return(nil)
+		mockService.On("Update", mock.Anything, 1, domain.Patch{Document: []byte(productJson)}).Return(expectedProduct, nil)
 
 		server.ServeHTTP(response, request)
 		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
@@ -326,7 +369,7 @@ func TestUpdateProduct(t *testing.T) {
 		request, response := testutil.MakeRequest(http.MethodPatch, "/products/1", productJson)
 		responseResult := domain.Product{}
 		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
-		mockService.On("Update", mock.Anything, mock.Anything).Return(product.ErrNotFound)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Product{}, product.ErrNotFound)
 		server.ServeHTTP(response, request)
 		assert.Equal(t, http.StatusNotFound, response.Code)
 
@@ -337,7 +380,7 @@ func TestUpdateProduct(t *testing.T) {
 
 		server.PATCH("/products/:id", handler.Update())
 
-		mockService.On("Update", mock.Anything, mock.Anything).Return(product.ErrTryAgain)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Product{}, product.ErrTryAgain)
 
 		request, response := testutil.MakeRequest(http.MethodPatch, "/products/1", productJson)
 
@@ -352,7 +395,7 @@ func TestUpdateProduct(t *testing.T) {
 
 		server.PATCH("/products/:id", handler.Update())
 
-		mockService.On("Update", mock.Anything, mock.Anything).Return(product.ErrInvalidId)
+		mockService.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(domain.Product{}, product.ErrInvalidId)
 
 		request, response := testutil.MakeRequest(http.MethodPatch, "/products/invalidId", productJson)
 
@@ -362,17 +405,64 @@ func TestUpdateProduct(t *testing.T) {
 	})
 
 	t.Run("Should return 400 when field is invalid", func(t *testing.T) {
-		server, _, handler := InitServerWithProducts(t)
+		server, mockService, handler := InitServerWithProducts(t)
 
 		server.PATCH("/products/:id", handler.Update())
 
-		request, response := testutil.MakeRequest(http.MethodPatch, "/products/1", string(`{"ExpirationRate": 0}`))
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Product{}, product.ErrInvalidField)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, "/products/1", string(`{"description": ""}`))
 
 		server.ServeHTTP(response, request)
 
 		assert.Equal(t, http.StatusBadRequest, response.Code)
 	})
 
+	t.Run("Should return 422 when a required field is cleared", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProducts(t)
+
+		server.PATCH("/products/:id", handler.Update())
+
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Product{}, product.ErrInvalidBody)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, "/products/1", string(`{"description": null}`))
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Should pass explicit zero values on to the service", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProducts(t)
+
+		server.PATCH("/products/:id", handler.Update())
+
+		updatedProduct := expectedProduct
+		updatedProduct.RecomFreezTemp = 0
+		mockService.On("Update", mock.Anything, 1, domain.Patch{Document: []byte(`{"recommended_freezing_temperature": 0}`)}).Return(updatedProduct, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, "/products/1", string(`{"recommended_freezing_temperature": 0}`))
+
+		server.ServeHTTP(response, request)
+
+		responseResult := domain.ProductResponseById{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, updatedProduct, responseResult.Data)
+	})
+
+	t.Run("Should return 422 when the body is not an object", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProducts(t)
+
+		server.PATCH("/products/:id", handler.Update())
+
+		request, response := testutil.MakeRequest(http.MethodPatch, "/products/1", string(`[{"description": "milk"}]`))
+
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("Should return 422 when Json is invalid", func(t *testing.T) {
 		server, _, handler := InitServerWithProducts(t)
 
@@ -385,11 +475,77 @@ func TestUpdateProduct(t *testing.T) {
 	})
 }
 
+func TestSearchProducts(t *testing.T) {
+	t.Run("Should return 200 with the products found", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProducts(t)
+		server.GET("/products/search", handler.Search())
+
+		freezable := true
+		search := domain.ProductSearch{Query: "frozen yogurt", ProductTypeID: 2, SellerID: 3, MinWeight: 0.5, MaxWeight: 2, Freezable: &freezable}
+		found := domain.ProductSearchResult{
+			Total:    1,
+			Products: []domain.ProductHit{{MeasuredProduct: domain.MeasuredProduct{Product: expectedProduct}, Score: 1.5, Highlights: map[string]string{"description": "<em>frozen</em>"}}},
+			Facets: domain.ProductFacets{
+				ProductTypes: []domain.ProductFacet{{ID: 2, Count: 1}},
+				Sellers:      []domain.ProductFacet{{ID: 3, Count: 1}},
+			},
+		}
+		mockService.On("Search", mock.Anything, search).Return(found, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/products/search?q=frozen+yogurt&product_type_id=2&seller_id=3&min_weight=0.5&max_weight=2&freezable=true", "")
+		server.ServeHTTP(response, request)
+
+		var body struct {
+			Data domain.ProductSearchResult `json:"data"`
+		}
+		_ = json.Unmarshal(response.Body.Bytes(), &body)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, found.Total, body.Data.Total)
+		assert.Equal(t, found.Facets, body.Data.Facets)
+		assert.Equal(t, []domain.ProductHit{{MeasuredProduct: measuredProduct, Score: 1.5, Highlights: map[string]string{"description": "<em>frozen</em>"}}}, body.Data.Products)
+	})
+
+	t.Run("Should return 400 when a filter is invalid", func(t *testing.T) {
+		for _, query := range []string{"product_type_id=x", "seller_id=0", "min_weight=-1", "max_weight=heavy", "freezable=maybe"} {
+			server, mockService, handler := InitServerWithProducts(t)
+			server.GET("/products/search", handler.Search())
+
+			request, response := testutil.MakeRequest(http.MethodGet, "/products/search?"+query, "")
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, http.StatusBadRequest, response.Code, query)
+			mockService.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("Should return 400 when the search is invalid", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProducts(t)
+		server.GET("/products/search", handler.Search())
+		mockService.On("Search", mock.Anything, mock.Anything).Return(domain.ProductSearchResult{}, product.ErrInvalidWeightRange)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/products/search?min_weight=5&max_weight=1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Should return 500 when the search fails", func(t *testing.T) {
+		server, mockService, handler := InitServerWithProducts(t)
+		server.GET("/products/search", handler.Search())
+		mockService.On("Search", mock.Anything, mock.Anything).Return(domain.ProductSearchResult{}, product.ErrTryAgain)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/products/search?q=milk", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
+
 // iniciar o servidor de testes
 func InitServerWithProducts(t *testing.T) (*gin.Engine, *mocks.ProductServiceMock, *handler.ProductController) {
 	t.Helper()
 	server := testutil.CreateServer() //chama o servidor dos testes
 	mockService := new(mocks.ProductServiceMock)
-	handler := handler.NewProduct(mockService)
+	handler := handler.NewProduct(mockService, product.DefaultDivisor)
 	return server, mockService, handler
 }
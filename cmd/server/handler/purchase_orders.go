@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/buyer"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
@@ -47,7 +49,7 @@ func (po *PurchaseOrdersController) CreateOrders() gin.HandlerFunc {
 			web.Error(c, http.StatusConflict, err.Error())
 			return
 		}
-		if orderRequest.OrderNumber == "" || orderRequest.OrderDate == "" || orderRequest.TrackingCode == "" || orderRequest.BuyerID == 0 || orderRequest.ProductRecordID == 0 {
+		if orderRequest.OrderNumber == "" || orderRequest.OrderDate.IsZero() || orderRequest.TrackingCode == "" || orderRequest.BuyerID == 0 || orderRequest.ProductRecordID == 0 {
 			web.Error(c, http.StatusUnprocessableEntity, "invalid body")
 			return
 		}
@@ -59,8 +61,14 @@ func (po *PurchaseOrdersController) CreateOrders() gin.HandlerFunc {
 			BuyerID:         orderRequest.BuyerID,
 			ProductRecordID: orderRequest.ProductRecordID,
 			OrderStatusID:   orderRequest.OrderStatusID,
+			CarrierID:       orderRequest.CarrierID,
+			WarehouseID:     orderRequest.WarehouseID,
 		})
 		if err != nil {
+			if errors.Is(err, purchase_orders.ErrInvalidReference) || errors.Is(err, purchase_orders.ErrTrackingCodeExists) {
+				web.Error(c, http.StatusConflict, err.Error())
+				return
+			}
 			web.Error(c, http.StatusInternalServerError, err.Error())
 			return
 		}
@@ -68,3 +76,58 @@ func (po *PurchaseOrdersController) CreateOrders() gin.HandlerFunc {
 		web.Success(c, http.StatusCreated, order)
 	}
 }
+
+// Assign picks the warehouse and carrier of a purchase order
+// @Summary Assign a warehouse and a carrier to a purchase order
+// @Description Picks a warehouse holding enough stock and a carrier able to deliver to the buyer locality
+// @Description through a strategy (same-locality, round-robin or least-loaded). A warehouse_id or
+// @Description carrier_id in the body overrides the strategy choice.
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Router /api/v1/purchaseOrders/{id}/assign [post]
+// @Param id path int true "Purchase Order ID"
+// @Param assignment body domain.PurchaseOrderAssignmentRequest false "Strategy and overrides"
+// @Success 200 {object} domain.PurchaseOrderAssignmentResponse
+// @Failure 400 {string} string "Invalid ID or unknown strategy"
+// @Failure 404 {string} string "Order not found"
+// @Failure 409 {string} string "No warehouse or carrier can serve the order"
+// @Failure 422 {string} string "Buyer without delivery locality"
+func (po *PurchaseOrdersController) Assign() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, purchase_orders.ErrInvalidID.Error())
+			return
+		}
+		request := domain.PurchaseOrderAssignmentRequest{}
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&request); err != nil {
+				web.Error(c, http.StatusUnprocessableEntity, "invalid body")
+				return
+			}
+		}
+
+		assignment, err := po.purchaseordersService.Assign(c, id, request)
+		if err != nil {
+			switch {
+			case errors.Is(err, purchase_orders.ErrUnknownStrategy):
+				web.Error(c, http.StatusBadRequest, err.Error())
+			case errors.Is(err, purchase_orders.ErrNotFound):
+				web.Error(c, http.StatusNotFound, err.Error())
+			case errors.Is(err, purchase_orders.ErrNoWarehouse),
+				errors.Is(err, purchase_orders.ErrInsufficientStock),
+				errors.Is(err, purchase_orders.ErrNoCarrier),
+				errors.Is(err, purchase_orders.ErrCarrierNotFound):
+				web.Error(c, http.StatusConflict, err.Error())
+			case errors.Is(err, purchase_orders.ErrNoDestination):
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, "error assigning order")
+			}
+			return
+		}
+
+		web.Success(c, http.StatusOK, assignment)
+	}
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
 	mocksBuyer "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/buyer"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/purchase_orders"
@@ -32,7 +34,7 @@ func TestCreateOrders(t *testing.T) {
 
 		purchaseOrders := domain.PurchaseOrders{
 			OrderNumber:     "order#1000",
-			OrderDate:       "2021-04-04",
+			OrderDate:       datetime.MustParse("2021-04-04"),
 			TrackingCode:    "abscf123",
 			BuyerID:         1,
 			ProductRecordID: 1,
@@ -90,7 +92,7 @@ func TestCreateOrders(t *testing.T) {
 
 		purchaseOrders := domain.PurchaseOrders{
 			OrderNumber:     "order#1000",
-			OrderDate:       "2021-04-04",
+			OrderDate:       datetime.MustParse("2021-04-04"),
 			TrackingCode:    "abscf123",
 			BuyerID:         100,
 			ProductRecordID: 1,
@@ -113,7 +115,7 @@ func TestCreateOrders(t *testing.T) {
 
 		purchaseOrders := domain.PurchaseOrders{
 			OrderNumber:     "order#1000",
-			OrderDate:       "2021-04-04",
+			OrderDate:       datetime.MustParse("2021-04-04"),
 			TrackingCode:    "abscf123",
 			BuyerID:         1,
 			ProductRecordID: 1,
@@ -132,6 +134,57 @@ func TestCreateOrders(t *testing.T) {
 	})
 }
 
+func TestAssignOrders(t *testing.T) {
+	assignment := domain.PurchaseOrderAssignment{OrderID: 1, WarehouseID: 2, CarrierID: 3, Strategy: "same-locality"}
+	cases := []struct {
+		name     string
+		path     string
+		body     string
+		err      error
+		expected int
+	}{
+		{"Should return status 200 and the assignment", "/purchaseOrders/1/assign", "", nil, http.StatusOK},
+		{"Should return status 400 when the id is invalid", "/purchaseOrders/invalid/assign", "", nil, http.StatusBadRequest},
+		{"Should return status 422 when the body is invalid", "/purchaseOrders/1/assign", `{"carrier_id":"x"}`, nil, http.StatusUnprocessableEntity},
+		{"Should return status 400 when the strategy is unknown", "/purchaseOrders/1/assign", "", purchase_orders.ErrUnknownStrategy, http.StatusBadRequest},
+		{"Should return status 404 when the order does not exist", "/purchaseOrders/1/assign", "", purchase_orders.ErrNotFound, http.StatusNotFound},
+		{"Should return status 409 when no warehouse holds the stock", "/purchaseOrders/1/assign", "", purchase_orders.ErrNoWarehouse, http.StatusConflict},
+		{"Should return status 409 when no carrier reaches the buyer", "/purchaseOrders/1/assign", "", purchase_orders.ErrNoCarrier, http.StatusConflict},
+		{"Should return status 422 when the buyer has no locality", "/purchaseOrders/1/assign", "", purchase_orders.ErrNoDestination, http.StatusUnprocessableEntity},
+		{"Should return status 500 when internal error", "/purchaseOrders/1/assign", "", errors.New("error"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, handler, mocks := InitServerWithGetPurchaseOrders(t)
+			server.POST("/purchaseOrders/:id/assign", handler.Assign())
+
+			mocks.PurchaseOrdersServiceMock.On("Assign", mock.Anything, 1, domain.PurchaseOrderAssignmentRequest{}).Return(assignment, tc.err)
+
+			request, response := testutil.MakeRequest(http.MethodPost, tc.path, tc.body)
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.expected, response.Code)
+			if tc.expected == http.StatusOK {
+				responseResult := domain.PurchaseOrderAssignmentResponse{}
+				_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+				assert.Equal(t, assignment, responseResult.Data)
+			}
+		})
+	}
+	t.Run("Should pass the strategy and overrides to the service", func(t *testing.T) {
+		server, handler, mocks := InitServerWithGetPurchaseOrders(t)
+		server.POST("/purchaseOrders/:id/assign", handler.Assign())
+
+		request := domain.PurchaseOrderAssignmentRequest{Strategy: "round-robin", WarehouseID: 2}
+		mocks.PurchaseOrdersServiceMock.On("Assign", mock.Anything, 1, request).Return(assignment, nil)
+
+		req, response := testutil.MakeRequest(http.MethodPost, "/purchaseOrders/1/assign", `{"strategy":"round-robin","warehouse_id":2}`)
+		server.ServeHTTP(response, req)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
 func InitServerWithGetPurchaseOrders(t *testing.T) (*gin.Engine, *handler.PurchaseOrdersController, BuyerServiceMocks) {
 	t.Helper()
 	server := testutil.CreateServer()
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/receipt"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type ReceiptController struct {
+	receiptService receipt.Service
+}
+
+func NewReceipt(s receipt.Service) *ReceiptController {
+	return &ReceiptController{
+		receiptService: s,
+	}
+}
+
+// @Summary Create Receipt
+// @Produce json
+// POST /warehouses/:id/receipts @Summary Receives goods into a warehouse
+// @Router /api/v1/warehouses/{id}/receipts [post]
+// @Param id path int true "Warehouse ID"
+// @Param receipt body domain.ReceiptRequest true "Product batch and inbound order data"
+// @Tags Warehouses
+// @Accept json
+// @Success 201 {object} domain.Receipt
+// @Description Create a product batch and the inbound order that records it in one transaction
+func (r *ReceiptController) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		warehouseId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, receipt.ErrInvalidId.Error())
+			return
+		}
+
+		var req domain.ReceiptRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			if errors.Is(err, datetime.ErrInvalid) {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		result, err := r.receiptService.Create(c, warehouseId, req)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrInvalidManufacturingDate):
+				web.Error(c, http.StatusBadRequest, err.Error())
+			case errors.Is(err, receipt.ErrWarehouseNotFound):
+				web.Error(c, http.StatusNotFound, err.Error())
+			case errors.Is(err, receipt.ErrAlreadyExists):
+				web.Error(c, http.StatusConflict, err.Error())
+			case errors.Is(err, receipt.ErrEmployeeNotFound):
+				web.ErrorWithCode(c, http.StatusConflict, "employee_not_found", err.Error())
+			case errors.Is(err, receipt.ErrProductNotFound):
+				web.ErrorWithCode(c, http.StatusConflict, "product_not_found", err.Error())
+			case errors.Is(err, receipt.ErrSectionNotFound):
+				web.ErrorWithCode(c, http.StatusConflict, "section_not_found", err.Error())
+			case errors.Is(err, receipt.ErrEmployeeWarehouse):
+				web.ErrorWithCode(c, http.StatusUnprocessableEntity, "employee_not_in_warehouse", err.Error())
+			case errors.Is(err, receipt.ErrSectionWarehouse):
+				web.ErrorWithCode(c, http.StatusUnprocessableEntity, "section_not_in_warehouse", err.Error())
+			case errors.Is(err, receipt.ErrCapacityExceeded):
+				web.ErrorWithCode(c, http.StatusUnprocessableEntity, "section_capacity_exceeded", err.Error())
+			case errors.Is(err, receipt.ErrNoDimensions):
+				web.ErrorWithCode(c, http.StatusUnprocessableEntity, "product_without_dimensions", err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, receipt.ErrTryAgain.Error(), err)
+			}
+			return
+		}
+		web.Success(c, http.StatusCreated, result)
+	}
+}
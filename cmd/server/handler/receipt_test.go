@@ -0,0 +1,90 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/receipt"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
+	receiptmocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/receipt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const receiptJson = `{"order_number":"INB100","order_date":"2023-07-05T14:00:00Z","employee_id":1,"product_batch":{
+	"batch_number":10,"current_quantity":20,"current_temperature":-18,"due_date":"2023-08-01","initial_quantity":20,
+	"manufacturing_date":"2023-07-01","manufacturing_hour":8,"minimum_temperature":-20,"product_id":1,"section_id":1}}`
+
+func TestCreateReceipt(t *testing.T) {
+	t.Run("Should return status 201 with the batch and the inbound order", func(t *testing.T) {
+		server, mockService, handler := InitServerWithReceipts(t)
+		result := domain.Receipt{
+			InboundOrder: domain.InboundOrders{ID: 7, OrderNumber: "INB100", EmployeeID: 1, ProductBatchID: 5, WarehouseID: 1},
+			ProductBatch: domain.ProductBatch{ID: 5, BatchNumber: 10, SectionID: 1},
+		}
+		mockService.On("Create", mock.Anything, 1, mock.AnythingOfType("domain.ReceiptRequest")).Return(result, nil)
+		server.POST("/warehouses/:id/receipts", handler.Create())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/warehouses/1/receipts", receiptJson)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Contains(t, response.Body.String(), `"product_batch_id":5`)
+	})
+
+	t.Run("Should return status 422 when the batch is incomplete", func(t *testing.T) {
+		server, _, handler := InitServerWithReceipts(t)
+		server.POST("/warehouses/:id/receipts", handler.Create())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/warehouses/1/receipts", `{"order_number":"INB100","employee_id":1,"product_batch":{"batch_number":10}}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Should return status 404 when the warehouse does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithReceipts(t)
+		mockService.On("Create", mock.Anything, 1, mock.Anything).Return(domain.Receipt{}, receipt.ErrWarehouseNotFound)
+		server.POST("/warehouses/:id/receipts", handler.Create())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/warehouses/1/receipts", receiptJson)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Should return status 422 when the section is full", func(t *testing.T) {
+		server, mockService, handler := InitServerWithReceipts(t)
+		mockService.On("Create", mock.Anything, 1, mock.Anything).Return(domain.Receipt{}, receipt.ErrCapacityExceeded)
+		server.POST("/warehouses/:id/receipts", handler.Create())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/warehouses/1/receipts", receiptJson)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		assert.Contains(t, response.Body.String(), `"code":"section_capacity_exceeded"`)
+	})
+
+	t.Run("Should return status 422 when the product has no dimensions", func(t *testing.T) {
+		server, mockService, handler := InitServerWithReceipts(t)
+		mockService.On("Create", mock.Anything, 1, mock.Anything).Return(domain.Receipt{}, receipt.ErrNoDimensions)
+		server.POST("/warehouses/:id/receipts", handler.Create())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/warehouses/1/receipts", receiptJson)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		assert.Contains(t, response.Body.String(), `"code":"product_without_dimensions"`)
+	})
+}
+
+func InitServerWithReceipts(t *testing.T) (*gin.Engine, *receiptmocks.ReceiptServiceMock, *handler.ReceiptController) {
+	t.Helper()
+	server := testutil.CreateServer()
+	mockService := new(receiptmocks.ReceiptServiceMock)
+	handler := handler.NewReceipt(mockService)
+	return server, mockService, handler
+}
@@ -23,6 +23,9 @@ func NewSection(s section.Service) *SectionController {
 
 // @Summary Get All Sections
 // @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // GET /sections @Summary Returns a list of Sections
 // @Router /api/v1/sections [get]
 // @Tags Section
@@ -31,6 +34,18 @@ func NewSection(s section.Service) *SectionController {
 // @Description List All Sections
 func (s *SectionController) GetAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if format, ok := web.ExportFormat(c); ok {
+			err := web.Stream(c, format, domain.Section{}, func(emit func(interface{}) error) error {
+				return s.sectionService.ForEach(c, func(d domain.Section) error {
+					return emit(d)
+				})
+			})
+			if err != nil && !c.Writer.Written() {
+				web.Error(c, http.StatusInternalServerError, "error listing sections")
+			}
+			return
+		}
+
 		sections, err := s.sectionService.GetAll(c)
 		if err != nil {
 			web.Error(c, http.StatusInternalServerError, "error listing sections")
@@ -98,9 +113,6 @@ func (s *SectionController) Create() gin.HandlerFunc {
 		case sectionInput.MinimumTemperature == 0:
 			web.Error(c, http.StatusUnprocessableEntity, "invalid minimum_temperature field")
 			return
-		case sectionInput.CurrentCapacity == 0:
-			web.Error(c, http.StatusUnprocessableEntity, "invalid current_capacity field")
-			return
 		case sectionInput.MinimumCapacity == 0:
 			web.Error(c, http.StatusUnprocessableEntity, "invalid minimum_capacity field")
 			return
@@ -125,6 +137,7 @@ func (s *SectionController) Create() gin.HandlerFunc {
 			return
 		}
 		sectionInput.ID = sectionID
+		sectionInput.CurrentCapacity = 0
 		web.Success(c, http.StatusCreated, sectionInput)
 	}
 }
@@ -134,6 +147,7 @@ func (s *SectionController) Create() gin.HandlerFunc {
 // PATCH /sections/:id @Summary Update an existing Section
 // @Router /api/v1/sections/{id} [patch]
 // @Accept json
+// @Accept application/merge-patch+json
 // @Tags Section
 // @Success 200 {object} domain.Section
 // @Param id path int true "Section ID"
@@ -147,56 +161,22 @@ func (s *SectionController) Update() gin.HandlerFunc {
 			return
 		}
 
-		sectionInput := &domain.SectionRequest{}
-		err = c.ShouldBindJSON(sectionInput)
+		document, err := web.MergePatch(c)
 		if err != nil {
 			web.Error(c, http.StatusBadRequest, domain.ErrTryAgain.Error(), err)
 			return
 		}
-		sectionUpdated := domain.Section{
-			ID:                 id,
-			SectionNumber:      sectionInput.SectionNumber,
-			CurrentTemperature: sectionInput.CurrentTemperature,
-			MinimumTemperature: sectionInput.MinimumTemperature,
-			CurrentCapacity:    sectionInput.CurrentCapacity,
-			MinimumCapacity:    sectionInput.MinimumCapacity,
-			MaximumCapacity:    sectionInput.MaximumCapacity,
-			WarehouseID:        sectionInput.WarehouseID,
-			ProductTypeID:      sectionInput.ProductTypeID,
-		}
-		switch {
-		case sectionInput.SectionNumber == 0:
-			web.Error(c, http.StatusUnprocessableEntity, "invalid section_number field")
-			return
-		case sectionInput.CurrentTemperature == 0:
-			web.Error(c, http.StatusUnprocessableEntity, "invalid current_temperature field")
-			return
-		case sectionInput.MinimumTemperature == 0:
-			web.Error(c, http.StatusUnprocessableEntity, "invalid minimum_temperature field")
-			return
-		case sectionInput.CurrentCapacity == 0:
-			web.Error(c, http.StatusUnprocessableEntity, "invalid current_capacity field")
-			return
-		case sectionInput.MinimumCapacity == 0:
-			web.Error(c, http.StatusUnprocessableEntity, "invalid minimum_capacity field")
-			return
-		case sectionInput.MaximumCapacity == 0:
-			web.Error(c, http.StatusUnprocessableEntity, "invalid maximum_capacity field")
-			return
-		case sectionInput.WarehouseID == 0:
-			web.Error(c, http.StatusUnprocessableEntity, "invalid warehouse_id field")
-			return
-		case sectionInput.ProductTypeID == 0:
-			web.Error(c, http.StatusUnprocessableEntity, "invalid product_type_id field")
-			return
-		}
 
-		err = s.sectionService.Update(c, sectionUpdated)
+		sectionUpdated, err := s.sectionService.Update(c, id, domain.Patch{Document: document})
 		if err != nil {
 			if errors.Is(err, section.ErrNotFound) {
 				web.Error(c, http.StatusNotFound, section.ErrNotFound.Error())
 				return
 			}
+			if errors.Is(err, section.ErrInvalidBody) {
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+				return
+			}
 			web.Error(c, http.StatusInternalServerError, err.Error())
 			return
 		}
@@ -206,6 +186,52 @@ func (s *SectionController) Update() gin.HandlerFunc {
 	}
 }
 
+// @Summary Fit Product into Section
+// @Produce json
+// @Router /api/v1/sections/{id}/fit [post]
+// @Accept json
+// @Tags Section
+// @Param id path int true "Section ID"
+// @Param product body domain.SectionFitRequest true "Product to fit"
+// @Success 200 {object} domain.SectionFit
+// @Failure 404 {string} ErrNotFound
+// @Failure 422 {string} ErrNoDimensions
+// @Description Count how many units of a product fit into the capacity the section has left
+func (s *SectionController) Fit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, domain.ErrInvalidId.Error())
+			return
+		}
+
+		var req domain.SectionFitRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusBadRequest, domain.ErrTryAgain.Error(), err)
+			return
+		}
+		if req.ProductID <= 0 {
+			web.Error(c, http.StatusUnprocessableEntity, "invalid product_id field")
+			return
+		}
+
+		fit, err := s.sectionService.Fit(c, id, req.ProductID)
+		if err != nil {
+			switch {
+			case errors.Is(err, section.ErrNotFound), errors.Is(err, section.ErrProductNotFound):
+				web.Error(c, http.StatusNotFound, err.Error())
+			case errors.Is(err, section.ErrNoDimensions), errors.Is(err, section.ErrProductType):
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		web.Success(c, http.StatusOK, fit)
+	}
+}
+
 // @Summary Delete Section
 // @Produce json
 // DELETE /sections/:id @Summary Delete a specific Section
@@ -68,24 +68,6 @@ var BodyTestCases = []struct {
 			"message": "invalid minimum_temperature field",
 		},
 	},
-	{
-		name: "invalid current_capacity field",
-		sectionInput: domain.Section{
-			SectionNumber:      1,
-			CurrentTemperature: 10,
-			MinimumTemperature: 5,
-			CurrentCapacity:    0,
-			MinimumCapacity:    50,
-			MaximumCapacity:    200,
-			WarehouseID:        1,
-			ProductTypeID:      1,
-		},
-		expectedCode: http.StatusUnprocessableEntity,
-		expectedBody: gin.H{
-			"code":    "unprocessable_entity",
-			"message": "invalid current_capacity field",
-		},
-	},
 	{
 		name: "invalid minimum_capacity field",
 		sectionInput: domain.Section{
@@ -328,8 +310,10 @@ func TestCreate(t *testing.T) {
 		assert.Equal(t, http.StatusCreated, response.Code)
 
 		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
-		newSection.ID = 1
-		assert.EqualValues(t, newSection, responseResult.Data)
+		expected := newSection
+		expected.ID = 1
+		expected.CurrentCapacity = 0
+		assert.EqualValues(t, expected, responseResult.Data)
 	})
 	t.Run("Should return 409 when section already exists", func(t *testing.T) {
 		server, mockService, handler := InitServerWithGetSections(t)
@@ -395,30 +379,44 @@ func TestUpdate(t *testing.T) {
 		server.PATCH(BaseRouteWithID, handler.Update())
 		jsonSection, _ := json.Marshal(newSection)
 		request, response := testutil.MakeRequest(http.MethodPatch, "/sections/1", string(jsonSection))
-		mockService.On("Update", mock.Anything, mock.Anything).Return(nil)
+		updatedSection := newSection
+		updatedSection.ID = 1
+		mockService.On("Update", mock.Anything, 1, domain.Patch{Document: jsonSection}).Return(updatedSection, nil)
 		server.ServeHTTP(response, request)
 		assert.Equal(t, http.StatusOK, response.Code)
 		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
-		newSection.ID = 1
-		assert.EqualValues(t, newSection, responseResult.Data)
+		assert.EqualValues(t, updatedSection, responseResult.Data)
 	})
 	t.Run("Should return 404 when section not exists", func(t *testing.T) {
 		server, mockService, handler := InitServerWithGetSections(t)
 		server.PATCH(BaseRouteWithID, handler.Update())
 		jsonSection, _ := json.Marshal(newSection)
 		request, response := testutil.MakeRequest(http.MethodPatch, "/sections/1", string(jsonSection))
-		mockService.On("Update", mock.Anything, mock.Anything).Return(section.ErrNotFound)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Section{}, section.ErrNotFound)
 		server.ServeHTTP(response, request)
 		assert.Equal(t, http.StatusNotFound, response.Code)
 	})
 	t.Run("Should return 422 when any of fields is invalid", func(t *testing.T) {
-		server, _, handler := InitServerWithGetSections(t)
+		server, mockService, handler := InitServerWithGetSections(t)
 		server.PATCH(BaseRouteWithID, handler.Update())
-		jsonSection, _ := json.Marshal(domain.Section{})
-		request, response := testutil.MakeRequest(http.MethodPatch, "/sections/1", string(jsonSection))
+		request, response := testutil.MakeRequest(http.MethodPatch, "/sections/1", `{"section_number":null}`)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Section{}, section.ErrInvalidBody)
 		server.ServeHTTP(response, request)
 		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
 	})
+	t.Run("Should pass explicit zero values on to the service", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetSections(t)
+		server.PATCH(BaseRouteWithID, handler.Update())
+		updatedSection := newSection
+		updatedSection.ID = 1
+		updatedSection.CurrentTemperature = 0
+		request, response := testutil.MakeRequest(http.MethodPatch, "/sections/1", `{"current_temperature":0}`)
+		mockService.On("Update", mock.Anything, 1, domain.Patch{Document: []byte(`{"current_temperature":0}`)}).Return(updatedSection, nil)
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+		assert.EqualValues(t, updatedSection, responseResult.Data)
+	})
 	t.Run("Should return 400 when id is invalid", func(t *testing.T) {
 		server, _, handler := InitServerWithGetSections(t)
 		server.PATCH(BaseRouteWithID, handler.Update())
@@ -432,29 +430,20 @@ func TestUpdate(t *testing.T) {
 		server.PATCH(BaseRouteWithID, handler.Update())
 		jsonSection, _ := json.Marshal(newSection)
 		request, response := testutil.MakeRequest(http.MethodPatch, "/sections/1", string(jsonSection))
-		mockService.On("Update", mock.Anything, mock.Anything).Return(errors.New("error"))
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Section{}, errors.New("error"))
 		server.ServeHTTP(response, request)
 		assert.Equal(t, http.StatusInternalServerError, response.Code)
 	})
 	t.Run("Should return 400 when body is invalid", func(t *testing.T) {
-		server, _, handler := InitServerWithGetSections(t)
+		server, mockService, handler := InitServerWithGetSections(t)
 		server.PATCH(BaseRouteWithID, handler.Update())
 
-		for _, tc := range BodyTestCases {
-			jsonSection, _ := json.Marshal(tc.sectionInput)
-			request, response := testutil.MakeRequest(http.MethodPatch, "/sections/1", string(jsonSection))
+		for _, body := range []string{"", "[]", `{"section_number":`} {
+			request, response := testutil.MakeRequest(http.MethodPatch, "/sections/1", body)
 			server.ServeHTTP(response, request)
-			var body gin.H
-			err := json.Unmarshal(response.Body.Bytes(), &body)
-			assert.NoError(t, err)
-			code := response.Code
-			assert.Equal(t, tc.expectedCode, code)
-			assert.Equal(t, tc.expectedBody, body)
+			assert.Equal(t, http.StatusBadRequest, response.Code, body)
 		}
-		request, response := testutil.MakeRequest(http.MethodPatch, "/sections/1", "")
-
-		server.ServeHTTP(response, request)
-		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
 	})
 }
 
@@ -523,6 +512,55 @@ func TestProductBySection(t *testing.T) {
 	})
 }
 
+func TestFit(t *testing.T) {
+	t.Run("Should return 200 with the units that fit", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetSections(t)
+		server.POST("/sections/:id/fit", handler.Fit())
+		expected := domain.SectionFit{SectionID: 1, ProductID: 7, RemainingCapacity: 60, ProductVolume: 12, Units: 5}
+		mockService.On("Fit", 1, 7).Return(expected, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/sections/1/fit", `{"product_id": 7}`)
+		server.ServeHTTP(response, request)
+
+		var body struct {
+			Data domain.SectionFit `json:"data"`
+		}
+		_ = json.Unmarshal(response.Body.Bytes(), &body)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, expected, body.Data)
+	})
+
+	t.Run("Should return 422 when the product is missing", func(t *testing.T) {
+		server, mockService, handler := InitServerWithGetSections(t)
+		server.POST("/sections/:id/fit", handler.Fit())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/sections/1/fit", `{}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		mockService.AssertNotCalled(t, "Fit", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should map the errors of the service", func(t *testing.T) {
+		for err, code := range map[error]int{
+			section.ErrNotFound:        http.StatusNotFound,
+			section.ErrProductNotFound: http.StatusNotFound,
+			section.ErrNoDimensions:    http.StatusUnprocessableEntity,
+			section.ErrProductType:     http.StatusUnprocessableEntity,
+			errors.New("error"):        http.StatusInternalServerError,
+		} {
+			server, mockService, handler := InitServerWithGetSections(t)
+			server.POST("/sections/:id/fit", handler.Fit())
+			mockService.On("Fit", 1, 7).Return(domain.SectionFit{}, err)
+
+			request, response := testutil.MakeRequest(http.MethodPost, "/sections/1/fit", `{"product_id": 7}`)
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, code, response.Code, err.Error())
+		}
+	})
+}
+
 func InitServerWithGetSections(t *testing.T) (*gin.Engine, *mocks.SectionServiceMock, *handler.SectionController) {
 	t.Helper()
 	server := testutil.CreateServer()
@@ -8,6 +8,7 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/locality"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
 	"github.com/gin-gonic/gin"
 )
@@ -51,9 +52,12 @@ func (s *SellerController) GetAll() gin.HandlerFunc {
 // GET /sellers/:id @Summary Returns a seller per Id
 // @Router /api/v1/sellers/{id} [get]
 // @Param   id     path    int     true        "Seller ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Tags Sellers
 // @Accept json
 // @Success 200 {object}  domain.Seller
+// @Header 200 {string} ETag "Version of the seller"
+// @Success 304
 // @Description List one by Seller id
 func (s *SellerController) Get() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -72,6 +76,9 @@ func (s *SellerController) Get() gin.HandlerFunc {
 			web.Error(c, http.StatusInternalServerError, err.Error())
 			return
 		}
+		if web.NotModified(c, sellerGet.Version) {
+			return
+		}
 		web.Success(c, http.StatusOK, sellerGet)
 	}
 }
@@ -125,6 +132,7 @@ func (s *SellerController) Create() gin.HandlerFunc {
 				return
 			}
 			web.Error(c, http.StatusInternalServerError, seller.ErrSaveSeller.Error())
+			return
 		}
 		web.Success(c, http.StatusCreated, sellerSaved)
 	}
@@ -135,9 +143,14 @@ func (s *SellerController) Create() gin.HandlerFunc {
 // PATCH /sellers/:id @Summary Modifies an existing seller
 // @Router /api/v1/sellers/{id} [patch]
 // @Accept json
+// @Accept application/merge-patch+json
 // @Tags Sellers
 // @Success 200 {object}  domain.Seller
+// @Header 200 {string} ETag "New version of the seller"
+// @Failure 412 {object} web.ErrorResponse
+// @Failure 428 {object} web.ErrorResponse
 // @Param id path int true "Seller ID"
+// @Param If-Match header string true "ETag of the seller being modified"
 // @Param seller body domain.Seller true "Seller Data"
 // @Description Update Seller
 func (s *SellerController) Update() gin.HandlerFunc {
@@ -147,22 +160,37 @@ func (s *SellerController) Update() gin.HandlerFunc {
 			web.Error(c, http.StatusBadRequest, err.Error())
 			return
 		}
-		domain := new(domain.Seller)
-		if err := c.ShouldBindJSON(domain); err != nil {
+		document, err := web.MergePatch(c)
+		if err != nil {
 			web.Error(c, http.StatusUnprocessableEntity, seller.ErrInvalidBody.Error())
 			return
 		}
-		sellerUpdated, err := s.sellerService.Update(c, id, *domain)
+		version, err := web.IfMatch(c)
+		if err != nil {
+			web.PreconditionError(c, err)
+			return
+		}
+		sellerUpdated, err := s.sellerService.Update(c, id, domain.Patch{Document: document, Version: version})
 		if err != nil {
 			switch err {
 			case seller.ErrNotFound:
 				web.Error(c, http.StatusNotFound, err.Error())
 				return
+			case seller.ErrVersionMismatch:
+				web.Error(c, http.StatusPreconditionFailed, err.Error())
+				return
+			case seller.ErrInvalidBody:
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+				return
+			case seller.ErrCidAlreadyExists:
+				web.Error(c, http.StatusConflict, err.Error())
+				return
 			default:
 				web.Error(c, http.StatusInternalServerError, err.Error())
 				return
 			}
 		}
+		web.SetETag(c, sellerUpdated.Version)
 		web.Success(c, http.StatusOK, sellerUpdated)
 	}
 }
@@ -200,3 +228,51 @@ func (s *SellerController) Delete() gin.HandlerFunc {
 		web.Response(c, http.StatusNoContent, "")
 	}
 }
+
+// @Summary Get Seller dashboard
+// @Produce json
+// @Router /api/v1/sellers/{id}/dashboard [get]
+// @Param   id     path    int     true        "Seller ID"
+// @Param   from   query   string  false       "Count orders placed from this date (inclusive)"
+// @Param   to     query   string  false       "Count orders placed before this date (exclusive)"
+// @Tags Sellers
+// @Accept json
+// @Success 200 {object}  domain.SellerDashboard
+// @Description Products by type, stock, stock value, units sold and revenue of a seller
+func (s *SellerController) Dashboard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sellerId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, seller.ErrInvalidId.Error())
+			return
+		}
+
+		var from, to datetime.Time
+		if value := c.Query("from"); value != "" {
+			if from, err = datetime.Parse(value); err != nil {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if value := c.Query("to"); value != "" {
+			if to, err = datetime.Parse(value); err != nil {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		dashboard, err := s.sellerService.Dashboard(c, sellerId, from, to)
+		if err != nil {
+			switch {
+			case errors.Is(err, seller.ErrNotFound):
+				web.Error(c, http.StatusNotFound, err.Error())
+			case errors.Is(err, seller.ErrInvalidRange):
+				web.Error(c, http.StatusBadRequest, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		web.Success(c, http.StatusOK, dashboard)
+	}
+}
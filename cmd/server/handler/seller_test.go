@@ -9,6 +9,8 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/seller"
@@ -417,9 +419,10 @@ func TestUpdateSeller(t *testing.T) {
 			LocalityId:  1,
 		}
 
-		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(updatedSeller, nil)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(updatedSeller, nil)
 
 		request, response := testutil.MakeRequest(http.MethodPatch, BaseRouteWithIDSeller, `{"address":"Address","telephone":"88748585"}`)
+		request.Header.Set("If-Match", `"1"`)
 
 		server.PATCH("/sellers/:id", handler.Update())
 		server.ServeHTTP(response, request)
@@ -460,8 +463,9 @@ func TestUpdateSeller(t *testing.T) {
 		server, mockService, _, handler := InitServer(t)
 
 		request, response := testutil.MakeRequest(http.MethodPatch, BaseRouteWithIDSeller, `{"address":"Address","telephone":"88748585"}`)
+		request.Header.Set("If-Match", `"1"`)
 
-		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(domain.Seller{}, seller.ErrNotFound)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Seller{}, seller.ErrNotFound)
 
 		server.PATCH("/sellers/:id", handler.Update())
 		server.ServeHTTP(response, request)
@@ -472,8 +476,9 @@ func TestUpdateSeller(t *testing.T) {
 		server, mockService, _, handler := InitServer(t)
 
 		request, response := testutil.MakeRequest(http.MethodPatch, BaseRouteWithIDSeller, `{"telephone":"88748585"}`)
+		request.Header.Set("If-Match", `"1"`)
 
-		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(domain.Seller{}, seller.ErrTryAgain)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Seller{}, seller.ErrTryAgain)
 
 		server.PATCH("/sellers/:id", handler.Update())
 		server.ServeHTTP(response, request)
@@ -481,6 +486,92 @@ func TestUpdateSeller(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, response.Code)
 	})
 }
+func TestDashboardSeller(t *testing.T) {
+	t.Run("Should return status 200 with the seller dashboard", func(t *testing.T) {
+		server, mockService, _, handler := InitServer(t)
+		from := datetime.MustParse("2023-01-01")
+		expectedDashboard := domain.SellerDashboard{
+			SellerID:       1,
+			From:           from,
+			ProductsByType: []domain.SellerProductTypeCount{{ProductTypeID: 1, Description: "frozen", ProductsCount: 2}},
+			TotalStock:     30,
+			StockValue:     []money.Money{money.MustParse("300", "USD")},
+			UnitsSold:      4,
+			Revenue:        []money.Money{money.MustParse("60.50", "USD")},
+		}
+		mockService.On("Dashboard", mock.Anything, 1, from, datetime.Time{}).Return(expectedDashboard, nil)
+		server.GET("/sellers/:id/dashboard", handler.Dashboard())
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/sellers/1/dashboard?from=2023-01-01", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":{
+			"seller_id":1,"from":"2023-01-01T00:00:00Z","to":null,
+			"products_by_type":[{"product_type_id":1,"description":"frozen","products_count":2}],
+			"total_stock":30,"stock_value":[{"amount":"300.00","currency":"USD"}],
+			"units_sold":4,"revenue":[{"amount":"60.50","currency":"USD"}]
+		}}`, response.Body.String())
+	})
+
+	t.Run("Should return status 400 when a date is invalid", func(t *testing.T) {
+		server, _, _, handler := InitServer(t)
+		server.GET("/sellers/:id/dashboard", handler.Dashboard())
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/sellers/1/dashboard?to=31/01/2023", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Should return status 404 when the seller does not exist", func(t *testing.T) {
+		server, mockService, _, handler := InitServer(t)
+		mockService.On("Dashboard", mock.Anything, 1, mock.Anything, mock.Anything).Return(domain.SellerDashboard{}, seller.ErrNotFound)
+		server.GET("/sellers/:id/dashboard", handler.Dashboard())
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/sellers/1/dashboard", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestConditionalRequestsSeller(t *testing.T) {
+	t.Run("Should return status 304 when the cached copy is current", func(t *testing.T) {
+		server, mockService, _, handler := InitServer(t)
+		mockService.On("Get", mock.Anything, 1).Return(domain.Seller{ID: 1, Version: 2}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseRouteWithIDSeller, "")
+		request.Header.Set("If-None-Match", `"2"`)
+		server.GET("/sellers/:id", handler.Get())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotModified, response.Code)
+		assert.Equal(t, `"2"`, response.Header().Get("ETag"))
+	})
+	t.Run("Should return status 428 without If-Match", func(t *testing.T) {
+		server, mockService, _, handler := InitServer(t)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, BaseRouteWithIDSeller, `{"address":"Address"}`)
+		server.PATCH("/sellers/:id", handler.Update())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusPreconditionRequired, response.Code)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Should return status 412 when the seller changed", func(t *testing.T) {
+		server, mockService, _, handler := InitServer(t)
+		mockService.On("Update", mock.Anything, 1, domain.Patch{Document: []byte(`{"address":"Address"}`), Version: 1}).Return(domain.Seller{}, seller.ErrVersionMismatch)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, BaseRouteWithIDSeller, `{"address":"Address"}`)
+		request.Header.Set("If-Match", `"1"`)
+		server.PATCH("/sellers/:id", handler.Update())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusPreconditionFailed, response.Code)
+	})
+}
+
 func InitServer(t *testing.T) (*gin.Engine, *mocks.SellerServiceMock, *mocks.LocalityServiceMock, *handler.SellerController) {
 	t.Helper()
 	server := testutil.CreateServer()
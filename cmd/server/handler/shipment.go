@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/shipment"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type ShipmentController struct {
+	shipmentService shipment.Service
+}
+
+func NewShipment(s shipment.Service) *ShipmentController {
+	return &ShipmentController{
+		shipmentService: s,
+	}
+}
+
+// @Summary Get Shipment by tracking code
+// @Produce json
+// GET /shipments/:tracking_code @Summary Returns the timeline of a shipment
+// @Router /api/v1/shipments/{tracking_code} [get]
+// @Param tracking_code path string true "Tracking code of the purchase order"
+// @Tags Shipments
+// @Accept json
+// @Success 200 {object} domain.ShipmentResponse
+// @Description Shipment of a purchase order with its events, oldest first
+func (s *ShipmentController) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := s.shipmentService.Get(c, c.Param("tracking_code"))
+		if err != nil {
+			s.writeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, result)
+	}
+}
+
+// @Summary Add Shipment event
+// @Produce json
+// POST /shipments/:tracking_code/events @Summary Records an event reported by the carrier
+// @Router /api/v1/shipments/{tracking_code}/events [post]
+// @Param tracking_code path string true "Tracking code of the purchase order"
+// @Param Authorization header string true "Bearer API key of the carrier"
+// @Param event body domain.ShipmentEventRequest true "Event Data"
+// @Tags Shipments
+// @Accept json
+// @Success 201 {object} domain.ShipmentEventResponse
+// @Description Record a picked, dispatched, in_transit, delivered or exception event. Only the carrier assigned to the order can report them
+func (s *ShipmentController) AddEvent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req domain.ShipmentEventRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, shipment.ErrInvalidEvent.Error())
+			return
+		}
+
+		result, err := s.shipmentService.AddEvent(c, c.Param("tracking_code"), apiKey(c), req)
+		if err != nil {
+			s.writeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusCreated, result)
+	}
+}
+
+// apiKey reads the carrier credential from an "Authorization: Bearer" header.
+func apiKey(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+}
+
+func (s *ShipmentController) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shipment.ErrUnauthorized):
+		web.Error(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, shipment.ErrForbidden):
+		web.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, shipment.ErrNotFound):
+		web.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, shipment.ErrInvalidEvent):
+		web.Error(c, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, shipment.ErrInvalidTransition), errors.Is(err, shipment.ErrDelivered):
+		web.Error(c, http.StatusConflict, err.Error())
+	default:
+		web.Error(c, http.StatusInternalServerError, "error, try again %s", err)
+	}
+}
@@ -0,0 +1,119 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/shipment"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
+	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/shipment"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	ShipmentEndpoint       = "/shipments/:tracking_code"
+	ShipmentEventsEndpoint = "/shipments/:tracking_code/events"
+)
+
+var shipmentEventBody = `{"type":"delivered","location":"São Paulo","occurred_at":"2023-07-03 10:00:00"}`
+
+var shipmentEventRequest = domain.ShipmentEventRequest{
+	Type:       domain.ShipmentDelivered,
+	Location:   "São Paulo",
+	OccurredAt: datetime.MustParse("2023-07-03 10:00:00"),
+}
+
+func TestGetShipment(t *testing.T) {
+	t.Run("Should return status 200 and the shipment", func(t *testing.T) {
+		server, mockService, handler := InitServerWithShipments(t)
+		expected := domain.Shipment{TrackingCode: "TRACK001", OrderID: 1, Status: domain.ShipmentPicked, Events: []domain.ShipmentEvent{{ID: 1, Type: domain.ShipmentPicked}}}
+		mockService.On("Get", mock.Anything, "TRACK001").Return(expected, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/shipments/TRACK001", "")
+		server.GET(ShipmentEndpoint, handler.Get())
+		server.ServeHTTP(response, request)
+
+		responseResult := domain.ShipmentResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, expected.Events[0].ID, responseResult.Data.Events[0].ID)
+		assert.Equal(t, expected.Status, responseResult.Data.Status)
+	})
+	t.Run("Should return status 404 when the tracking code is unknown", func(t *testing.T) {
+		server, mockService, handler := InitServerWithShipments(t)
+		mockService.On("Get", mock.Anything, "nope").Return(domain.Shipment{}, shipment.ErrNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/shipments/nope", "")
+		server.GET(ShipmentEndpoint, handler.Get())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestAddShipmentEvent(t *testing.T) {
+	t.Run("Should return status 201 and pass the bearer key to the service", func(t *testing.T) {
+		server, mockService, handler := InitServerWithShipments(t)
+		mockService.On("AddEvent", mock.Anything, "TRACK001", "secret", shipmentEventRequest).Return(domain.ShipmentEvent{ID: 4, Type: domain.ShipmentDelivered}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/shipments/TRACK001/events", shipmentEventBody)
+		request.Header.Set("Authorization", "Bearer secret")
+		server.POST(ShipmentEventsEndpoint, handler.AddEvent())
+		server.ServeHTTP(response, request)
+
+		responseResult := domain.ShipmentEventResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Equal(t, 4, responseResult.Data.ID)
+	})
+	t.Run("Should return status 422 when the body is invalid", func(t *testing.T) {
+		server, _, handler := InitServerWithShipments(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/shipments/TRACK001/events", `{"location":"São Paulo"}`)
+		server.POST(ShipmentEventsEndpoint, handler.AddEvent())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{shipment.ErrUnauthorized, http.StatusUnauthorized},
+		{shipment.ErrForbidden, http.StatusForbidden},
+		{shipment.ErrNotFound, http.StatusNotFound},
+		{shipment.ErrInvalidEvent, http.StatusUnprocessableEntity},
+		{shipment.ErrInvalidTransition, http.StatusConflict},
+		{shipment.ErrDelivered, http.StatusConflict},
+		{errors.New("error"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run("Should return status "+http.StatusText(tc.status)+" on "+tc.err.Error(), func(t *testing.T) {
+			server, mockService, handler := InitServerWithShipments(t)
+			mockService.On("AddEvent", mock.Anything, "TRACK001", "", shipmentEventRequest).Return(domain.ShipmentEvent{}, tc.err)
+
+			request, response := testutil.MakeRequest(http.MethodPost, "/shipments/TRACK001/events", shipmentEventBody)
+			server.POST(ShipmentEventsEndpoint, handler.AddEvent())
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.status, response.Code)
+		})
+	}
+}
+
+func InitServerWithShipments(t *testing.T) (*gin.Engine, *mocks.ShipmentServiceMock, *handler.ShipmentController) {
+	t.Helper()
+	server := testutil.CreateServer()
+	mockService := new(mocks.ShipmentServiceMock)
+	return server, mockService, handler.NewShipment(mockService)
+}
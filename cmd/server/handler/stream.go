@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/stream"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeat is how often an idle stream sends a comment, so proxies
+// keep the connection open and dead clients are noticed.
+const streamHeartbeat = 15 * time.Second
+
+type StreamController struct {
+	hub       *stream.Hub
+	heartbeat time.Duration
+}
+
+func NewStream(h *stream.Hub) *StreamController {
+	return &StreamController{
+		hub:       h,
+		heartbeat: streamHeartbeat,
+	}
+}
+
+// @Summary Stream warehouse activity
+// @Produce text/event-stream
+// GET /stream @Summary Pushes live warehouse activity as Server-Sent Events
+// @Router /api/v1/stream [get]
+// @Param warehouse_id query int false "Warehouse ID, all warehouses when omitted"
+// @Param Last-Event-ID header int false "Id of the last event received, to resume after it"
+// @Tags Stream
+// @Success 200
+// @Description Push inbound orders, batch creations, transfers and temperature alerts as they happen. A client that falls behind is disconnected and can resume with Last-Event-ID
+func (s *StreamController) Stream() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var warehouseID int
+		var err error
+		if param := c.Query("warehouse_id"); param != "" {
+			if warehouseID, err = strconv.Atoi(param); err != nil || warehouseID <= 0 {
+				web.Error(c, http.StatusBadRequest, "invalid warehouse_id")
+				return
+			}
+		}
+		var lastID int64
+		if header := c.GetHeader("Last-Event-ID"); header != "" {
+			if lastID, err = strconv.ParseInt(header, 10, 64); err != nil || lastID < 0 {
+				web.Error(c, http.StatusBadRequest, "invalid Last-Event-ID")
+				return
+			}
+		}
+
+		client := s.hub.Subscribe(warehouseID, lastID)
+		defer s.hub.Unsubscribe(client)
+
+		c.Header("Content-Type", sse.ContentType)
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(s.heartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case m, ok := <-client.Messages():
+				if !ok {
+					return
+				}
+				c.Render(-1, sse.Event{Id: strconv.FormatInt(m.ID, 10), Event: m.Event, Data: m.Data})
+			case <-heartbeat.C:
+				if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+					return
+				}
+			case <-c.Request.Context().Done():
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/stream"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const StreamEndpoint = "/stream"
+
+func TestStream(t *testing.T) {
+	t.Run("Should push the messages of the warehouse as events", func(t *testing.T) {
+		hub, url := InitStreamServer(t)
+		body := openStream(t, url+"?warehouse_id=1", "")
+		waitForClients(t, hub, 1)
+
+		hub.Broadcast(stream.Message{EventID: 1, Event: domain.StreamInboundOrder, Data: []byte(`{"id":1}`), Warehouses: []int{2}})
+		hub.Broadcast(stream.Message{EventID: 2, Event: domain.StreamTransfer, Data: []byte(`{"id":3}`), Warehouses: []int{1}})
+
+		assert.Equal(t, []string{"id:2", "event:transfer", `data:{"id":3}`}, readEvent(t, body))
+	})
+	t.Run("Should resume after the Last-Event-ID", func(t *testing.T) {
+		hub, url := InitStreamServer(t)
+		for i := int64(1); i <= 3; i++ {
+			hub.Broadcast(stream.Message{EventID: i, Event: domain.StreamInboundOrder, Data: []byte(`{}`), Warehouses: []int{1}})
+		}
+
+		body := openStream(t, url, "2")
+
+		assert.Equal(t, "id:3", readEvent(t, body)[0])
+	})
+	t.Run("Should return status 400 on an invalid warehouse_id", func(t *testing.T) {
+		hub, _ := InitStreamServer(t)
+		server := testutil.CreateServer()
+		server.GET(StreamEndpoint, handler.NewStream(hub).Stream())
+
+		request, response := testutil.MakeRequest(http.MethodGet, StreamEndpoint+"?warehouse_id=abc", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+	t.Run("Should return status 400 on an invalid Last-Event-ID", func(t *testing.T) {
+		hub, _ := InitStreamServer(t)
+		server := testutil.CreateServer()
+		server.GET(StreamEndpoint, handler.NewStream(hub).Stream())
+
+		request, response := testutil.MakeRequest(http.MethodGet, StreamEndpoint, "")
+		request.Header.Set("Last-Event-ID", "last")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+// InitStreamServer serves the stream on a real listener, since it writes to
+// the connection until the client goes away.
+func InitStreamServer(t *testing.T) (*stream.Hub, string) {
+	t.Helper()
+	hub := stream.NewHub(10, 10)
+	server := testutil.CreateServer()
+	server.GET(StreamEndpoint, handler.NewStream(hub).Stream())
+	s := httptest.NewServer(server)
+	t.Cleanup(s.Close)
+	return hub, s.URL + StreamEndpoint
+}
+
+func openStream(t *testing.T, url, lastEventID string) *bufio.Reader {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if lastEventID != "" {
+		request.Header.Set("Last-Event-ID", lastEventID)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { response.Body.Close() })
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+	return bufio.NewReader(response.Body)
+}
+
+// readEvent returns the lines of the next event, skipping heartbeats.
+func readEvent(t *testing.T, body *bufio.Reader) []string {
+	t.Helper()
+	lines := []string{}
+	for {
+		line, err := body.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && len(lines) > 0:
+			return lines
+		case line != "" && !strings.HasPrefix(line, ":"):
+			lines = append(lines, line)
+		}
+	}
+}
+
+func waitForClients(t *testing.T, hub *stream.Hub, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); hub.Clients() < n; {
+		if time.Now().After(deadline) {
+			t.Fatal("client did not subscribe")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
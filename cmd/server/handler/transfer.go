@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/transfer"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type TransferController struct {
+	transferService transfer.Service
+}
+
+func NewTransfer(s transfer.Service) *TransferController {
+	return &TransferController{
+		transferService: s,
+	}
+}
+
+// @Summary Create Transfer
+// @Produce json
+// POST /transfers @Summary Create a draft stock transfer between sections
+// @Router /api/v1/transfers [post]
+// @Tags Transfers
+// @Accept json
+// @Param transfer body domain.TransferRequest true "Transfer Data"
+// @Success 201 {object} domain.Transfer
+// @Description Create a draft transfer of a product batch to another section
+func (t *TransferController) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req domain.TransferRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		result, err := t.transferService.Create(c, req)
+		if err != nil {
+			t.writeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusCreated, result)
+	}
+}
+
+// @Summary Get Transfer by ID
+// @Produce json
+// GET /transfers/:id @Summary Returns a transfer per Id
+// @Router /api/v1/transfers/{id} [get]
+// @Param id path int true "Transfer ID"
+// @Tags Transfers
+// @Accept json
+// @Success 200 {object} domain.Transfer
+// @Description List one by Transfer id
+func (t *TransferController) Get() gin.HandlerFunc {
+	return t.byID(t.transferService.Get)
+}
+
+// @Summary Dispatch Transfer
+// @Produce json
+// POST /transfers/:id/dispatch @Summary Takes the stock out of the source section
+// @Router /api/v1/transfers/{id}/dispatch [post]
+// @Param id path int true "Transfer ID"
+// @Tags Transfers
+// @Accept json
+// @Success 200 {object} domain.Transfer
+// @Description Move a draft transfer to in_transit, splitting the batch when only part of it is moved
+func (t *TransferController) Dispatch() gin.HandlerFunc {
+	return t.byID(t.transferService.Dispatch)
+}
+
+// @Summary Receive Transfer
+// @Produce json
+// POST /transfers/:id/receive @Summary Places the stock in the target section
+// @Router /api/v1/transfers/{id}/receive [post]
+// @Param id path int true "Transfer ID"
+// @Tags Transfers
+// @Accept json
+// @Success 200 {object} domain.Transfer
+// @Description Move an in_transit transfer to received
+func (t *TransferController) Receive() gin.HandlerFunc {
+	return t.byID(t.transferService.Receive)
+}
+
+func (t *TransferController) byID(fn func(ctx context.Context, id int) (domain.Transfer, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, transfer.ErrInvalidId.Error())
+			return
+		}
+
+		result, err := fn(c, id)
+		if err != nil {
+			t.writeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, result)
+	}
+}
+
+func (t *TransferController) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, transfer.ErrNotFound):
+		web.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, transfer.ErrBatchNotFound), errors.Is(err, transfer.ErrSectionNotFound):
+		web.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, transfer.ErrInvalidQuantity), errors.Is(err, transfer.ErrSameSection):
+		web.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, transfer.ErrInvalidTransition):
+		web.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, transfer.ErrWrongSection), errors.Is(err, transfer.ErrInsufficientStock),
+		errors.Is(err, transfer.ErrProductTypeMismatch), errors.Is(err, transfer.ErrCapacityExceeded),
+		errors.Is(err, transfer.ErrNoDimensions):
+		web.Error(c, http.StatusUnprocessableEntity, err.Error())
+	default:
+		web.Error(c, http.StatusInternalServerError, transfer.ErrTryAgain.Error(), err)
+	}
+}
@@ -0,0 +1,133 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/transfer"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
+	transfermocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/transfer"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateTransfer(t *testing.T) {
+	body := `{"product_batch_id":1,"quantity":40,"source_section_id":1,"target_section_id":2}`
+	req := domain.TransferRequest{ProductBatchID: 1, Quantity: 40, SourceSectionID: 1, TargetSectionID: 2}
+
+	t.Run("Should return status 201 with the draft transfer", func(t *testing.T) {
+		server, mockService, handler := InitServerWithTransfers(t)
+		created := domain.Transfer{
+			ID:              1,
+			ProductBatchID:  1,
+			Quantity:        40,
+			SourceSectionID: 1,
+			TargetSectionID: 2,
+			Status:          domain.TransferDraft,
+			CreatedAt:       datetime.MustParse("2023-07-01T10:00:00Z"),
+		}
+		mockService.On("Create", mock.Anything, req).Return(created, nil)
+		server.POST("/transfers", handler.Create())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/transfers", body)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, `{"data":{
+			"id":1,"product_batch_id":1,"quantity":40,"source_section_id":1,"target_section_id":2,
+			"status":"draft","created_at":"2023-07-01T10:00:00Z","dispatched_at":null,"received_at":null
+		}}`, response.Body.String())
+	})
+
+	t.Run("Should return status 422 when a field is missing", func(t *testing.T) {
+		server, _, handler := InitServerWithTransfers(t)
+		server.POST("/transfers", handler.Create())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/transfers", `{"product_batch_id":1}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Should return status 422 when the target section is full", func(t *testing.T) {
+		server, mockService, handler := InitServerWithTransfers(t)
+		mockService.On("Create", mock.Anything, req).Return(domain.Transfer{}, transfer.ErrCapacityExceeded)
+		server.POST("/transfers", handler.Create())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/transfers", body)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Should return status 409 when the batch does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithTransfers(t)
+		mockService.On("Create", mock.Anything, req).Return(domain.Transfer{}, transfer.ErrBatchNotFound)
+		server.POST("/transfers", handler.Create())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/transfers", body)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestGetTransfer(t *testing.T) {
+	t.Run("Should return status 404 when the transfer does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithTransfers(t)
+		mockService.On("Get", mock.Anything, 9).Return(domain.Transfer{}, transfer.ErrNotFound)
+		server.GET("/transfers/:id", handler.Get())
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/transfers/9", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Should return status 400 when the id is invalid", func(t *testing.T) {
+		server, _, handler := InitServerWithTransfers(t)
+		server.GET("/transfers/:id", handler.Get())
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/transfers/abc", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestDispatchAndReceiveTransfer(t *testing.T) {
+	t.Run("Should return status 200 when the transfer is dispatched", func(t *testing.T) {
+		server, mockService, handler := InitServerWithTransfers(t)
+		mockService.On("Dispatch", mock.Anything, 1).Return(domain.Transfer{ID: 1, MovedBatchID: 5, Status: domain.TransferInTransit}, nil)
+		server.POST("/transfers/:id/dispatch", handler.Dispatch())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/transfers/1/dispatch", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"status":"in_transit"`)
+	})
+
+	t.Run("Should return status 409 when the transfer cannot be received", func(t *testing.T) {
+		server, mockService, handler := InitServerWithTransfers(t)
+		mockService.On("Receive", mock.Anything, 1).Return(domain.Transfer{}, transfer.ErrInvalidTransition)
+		server.POST("/transfers/:id/receive", handler.Receive())
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/transfers/1/receive", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func InitServerWithTransfers(t *testing.T) (*gin.Engine, *transfermocks.TransferServiceMock, *handler.TransferController) {
+	t.Helper()
+	server := testutil.CreateServer()
+	mockService := new(transfermocks.TransferServiceMock)
+	handler := handler.NewTransfer(mockService)
+	return server, mockService, handler
+}
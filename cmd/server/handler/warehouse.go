@@ -7,6 +7,7 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
 	"github.com/gin-gonic/gin"
 )
@@ -26,9 +27,12 @@ func NewWarehouse(w warehouse.Service) *WarehouseController {
 // GET /warehouses/:id @Summary Returns a warehouse per Id
 // @Router /api/v1/warehouses/{id} [get]
 // @Param id path int true "Warehouse ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Tags Warehouses
 // @Accept json
 // @Success 200 {object} domain.Warehouse
+// @Header 200 {string} ETag "Version of the warehouse"
+// @Success 304
 // @Description List one by Warehouse id
 func (w *WarehouseController) Get() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -48,12 +52,18 @@ func (w *WarehouseController) Get() gin.HandlerFunc {
 			web.Error(c, http.StatusInternalServerError, warehouse.ErrTryAgain.Error(), err)
 			return
 		}
+		if web.NotModified(c, warehouseGet.Version) {
+			return
+		}
 		web.Success(c, http.StatusOK, warehouseGet)
 	}
 }
 
 // @Summary Get all Warehouses
 // @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // GET /warehouses @Summary Returns a list of warehouses
 // @Router /api/v1/warehouses [get]
 // @Tags Warehouses
@@ -62,6 +72,18 @@ func (w *WarehouseController) Get() gin.HandlerFunc {
 // @Description List all Warehouses
 func (w *WarehouseController) GetAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if format, ok := web.ExportFormat(c); ok {
+			err := web.Stream(c, format, domain.Warehouse{}, func(emit func(interface{}) error) error {
+				return w.warehouseService.ForEach(c, func(d domain.Warehouse) error {
+					return emit(d)
+				})
+			})
+			if err != nil && !c.Writer.Written() {
+				web.Error(c, http.StatusInternalServerError, warehouse.ErrTryAgain.Error(), err)
+			}
+			return
+		}
+
 		warehouses, err := w.warehouseService.GetAll(c)
 		if err != nil {
 			web.Error(c, http.StatusInternalServerError, warehouse.ErrTryAgain.Error(), err)
@@ -99,7 +121,7 @@ func (w *WarehouseController) Create() gin.HandlerFunc {
 		case warehouseInput.Address == "":
 			web.Error(c, http.StatusBadRequest, "invalid address field")
 			return
-		case warehouseInput.MinimumCapacity <= 0:
+		case warehouseInput.MinimumCapacity != nil && *warehouseInput.MinimumCapacity <= 0:
 			web.Error(c, http.StatusBadRequest, "invalid minimum_capacity field")
 			return
 		case warehouseInput.Telephone == "":
@@ -164,9 +186,14 @@ func (w *WarehouseController) Delete() gin.HandlerFunc {
 // PATCH /warehouses/:id @Summary Modifies an existing warehouse
 // @Router /api/v1/warehouses/{id} [patch]
 // @Accept json
+// @Accept application/merge-patch+json
 // @Tags Warehouses
 // @Success 200 {object} domain.Warehouse
+// @Header 200 {string} ETag "New version of the warehouse"
+// @Failure 412 {object} web.ErrorResponse
+// @Failure 428 {object} web.ErrorResponse
 // @Param id path int true "Warehouse ID"
+// @Param If-Match header string true "ETag of the warehouse being modified"
 // @Param warehouse body domain.Warehouse true "Warehouse Data"
 // @Description Update Warehouse
 func (w *WarehouseController) Update() gin.HandlerFunc {
@@ -177,24 +204,81 @@ func (w *WarehouseController) Update() gin.HandlerFunc {
 			return
 		}
 
-		domain := new(domain.Warehouse)
-		if err := c.ShouldBindJSON(domain); err != nil {
+		document, err := web.MergePatch(c)
+		if err != nil {
 			web.Error(c, http.StatusUnprocessableEntity, warehouse.ErrInvalidBody.Error())
 			return
 		}
+		version, err := web.IfMatch(c)
+		if err != nil {
+			web.PreconditionError(c, err)
+			return
+		}
 
-		result, err := w.warehouseService.Update(c, *domain, warehouseId)
+		result, err := w.warehouseService.Update(c, warehouseId, domain.Patch{Document: document, Version: version})
 		if err != nil {
 			switch err {
 			case warehouse.ErrNotFound:
 				web.Error(c, http.StatusNotFound, err.Error())
 				return
+			case warehouse.ErrVersionMismatch:
+				web.Error(c, http.StatusPreconditionFailed, err.Error())
+				return
+			case warehouse.ErrInvalidBody:
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+				return
+			case warehouse.ErrAlredyExists:
+				web.Error(c, http.StatusConflict, err.Error())
+				return
 			default:
 				web.Error(c, http.StatusInternalServerError, warehouse.ErrTryAgain.Error(), err)
 				return
 			}
 		}
 
+		web.SetETag(c, result.Version)
 		web.Success(c, http.StatusOK, result)
 	}
 }
+
+// @Summary Get Warehouse inventory
+// @Produce json
+// GET /warehouses/:id/inventory @Summary Returns the stock of a warehouse
+// @Router /api/v1/warehouses/{id}/inventory [get]
+// @Param id path int true "Warehouse ID"
+// @Param as_of query string false "Rebuild the stock at this date instead of now"
+// @Tags Warehouses
+// @Accept json
+// @Success 200 {object} domain.WarehouseInventory
+// @Description Per product and per section stock of a warehouse, with batch ages
+func (w *WarehouseController) Inventory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		warehouseId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, warehouse.ErrInvalidId.Error())
+			return
+		}
+
+		var asOf datetime.Time
+		if value := c.Query("as_of"); value != "" {
+			if asOf, err = datetime.Parse(value); err != nil {
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		inventory, err := w.warehouseService.Inventory(c, warehouseId, asOf)
+		if err != nil {
+			switch {
+			case errors.Is(err, warehouse.ErrNotFound):
+				web.Error(c, http.StatusNotFound, warehouse.ErrNotFound.Error())
+			case errors.Is(err, warehouse.ErrFutureAsOf):
+				web.Error(c, http.StatusBadRequest, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, warehouse.ErrTryAgain.Error(), err)
+			}
+			return
+		}
+		web.Success(c, http.StatusOK, inventory)
+	}
+}
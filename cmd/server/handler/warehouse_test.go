@@ -1,13 +1,17 @@
 package handler_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/warehouse"
 	"github.com/gin-gonic/gin"
@@ -31,16 +35,16 @@ func TestGetAllWarehouses(t *testing.T) {
 				Address:            "Rua Pedro Dias",
 				Telephone:          "3712291281",
 				WarehouseCode:      "DAE",
-				MinimumCapacity:    10,
-				MinimumTemperature: 10,
+				MinimumCapacity:    testutil.Int(10),
+				MinimumTemperature: testutil.Float64(10),
 			},
 			{
 				ID:                 2,
 				Address:            "Rua Maria das Dores",
 				Telephone:          "1722919394",
 				WarehouseCode:      "EWQ",
-				MinimumCapacity:    10,
-				MinimumTemperature: 10,
+				MinimumCapacity:    testutil.Int(10),
+				MinimumTemperature: testutil.Float64(10),
 			},
 		}
 
@@ -92,6 +96,148 @@ func TestGetAllWarehouses(t *testing.T) {
 	})
 }
 
+func TestExportWarehouses(t *testing.T) {
+	expectedWarehouses := []domain.Warehouse{
+		{
+			ID:                 1,
+			Address:            "Rua Pedro Dias",
+			Telephone:          "3712291281",
+			WarehouseCode:      "DAE",
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10.5),
+			LocalityId:         1,
+		},
+		{
+			ID:                 2,
+			Address:            "Rua Maria, das Dores",
+			Telephone:          "1722919394",
+			WarehouseCode:      "EWQ",
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(-2),
+			LocalityId:         1,
+		},
+	}
+	t.Run("Should stream warehouses as CSV", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("ForEach", mock.Anything, mock.Anything).Return(expectedWarehouses, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointWarehouse, "")
+		request.Header.Set("Accept", "text/csv")
+
+		server.GET(BaseEndpointWarehouse, handler.GetAll())
+		server.ServeHTTP(response, request)
+
+		expected := "id,address,telephone,warehouse_code,minimum_capacity,minimum_temperature,locality_id\n" +
+			"1,Rua Pedro Dias,3712291281,DAE,10,10.5,1\n" +
+			"2,\"Rua Maria, das Dores\",1722919394,EWQ,10,-2,1\n"
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "text/csv", response.Header().Get("Content-Type"))
+		assert.Contains(t, response.Header().Get("Content-Disposition"), "warehouses.csv")
+		assert.Equal(t, expected, response.Body.String())
+		mockService.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+	t.Run("Should stream warehouses as NDJSON", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("ForEach", mock.Anything, mock.Anything).Return(expectedWarehouses, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointWarehouse, "")
+		request.Header.Set("Accept", "application/x-ndjson")
+
+		server.GET(BaseEndpointWarehouse, handler.GetAll())
+		server.ServeHTTP(response, request)
+
+		lines := strings.Split(strings.TrimSpace(response.Body.String()), "\n")
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Len(t, lines, 2)
+		for i, line := range lines {
+			var result domain.Warehouse
+			assert.NoError(t, json.Unmarshal([]byte(line), &result))
+			assert.Equal(t, expectedWarehouses[i], result)
+		}
+	})
+	t.Run("Should stream warehouses as XLSX", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("ForEach", mock.Anything, mock.Anything).Return(expectedWarehouses, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointWarehouse, "")
+		request.Header.Set("Accept", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+		server.GET(BaseEndpointWarehouse, handler.GetAll())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		reader, err := zip.NewReader(bytes.NewReader(response.Body.Bytes()), int64(response.Body.Len()))
+		assert.NoError(t, err)
+
+		var sheet bytes.Buffer
+		for _, file := range reader.File {
+			if file.Name == "xl/worksheets/sheet1.xml" {
+				content, _ := file.Open()
+				_, _ = sheet.ReadFrom(content)
+			}
+		}
+		assert.Contains(t, sheet.String(), `<row r="3">`)
+		assert.Contains(t, sheet.String(), "Rua Maria, das Dores")
+		assert.Contains(t, sheet.String(), `<c t="n"><v>-2</v></c>`)
+	})
+	t.Run("Should return status 500 when the export fails before any row", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("ForEach", mock.Anything, mock.Anything).Return([]domain.Warehouse{}, warehouse.ErrTryAgain)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointWarehouse, "")
+		request.Header.Set("Accept", "text/csv")
+
+		server.GET(BaseEndpointWarehouse, handler.GetAll())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+	t.Run("Should export a single warehouse through the JSON handler", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("Get", mock.Anything, 1).Return(expectedWarehouses[0], nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointWithIdNumberWarehouse, "")
+		request.Header.Set("Accept", "text/csv;q=0.9, application/json;q=0.5")
+
+		server.GET(BaseEndpointWithIdWarehouse, handler.Get())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "1,Rua Pedro Dias,3712291281,DAE,10,10.5,1", strings.Split(response.Body.String(), "\n")[1])
+	})
+	t.Run("Should answer JSON when the client weighs it above an export format", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("Get", mock.Anything, 1).Return(expectedWarehouses[0], nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointWithIdNumberWarehouse, "")
+		request.Header.Set("Accept", "application/json;q=1, text/csv;q=0.1")
+
+		server.GET(BaseEndpointWithIdWarehouse, handler.Get())
+		server.ServeHTTP(response, request)
+
+		responseResult := &domain.WarehouseResponseId{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Header().Get("Content-Type"), "application/json")
+		assert.Equal(t, expectedWarehouses[0], responseResult.Data)
+	})
+	t.Run("Should export the format the client weighs highest", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("ForEach", mock.Anything, mock.Anything).Return(expectedWarehouses, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointWarehouse, "")
+		request.Header.Set("Accept", "text/csv;q=0.2, application/x-ndjson;q=0.8, */*;q=0.1")
+
+		server.GET(BaseEndpointWarehouse, handler.GetAll())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "application/x-ndjson", response.Header().Get("Content-Type"))
+	})
+}
+
 func TestGetByIdWarehouses(t *testing.T) {
 	t.Run("Should return status 200 and warehouse with id", func(t *testing.T) {
 		server, mockService, handler := InitServerWithWarehouses(t)
@@ -100,8 +246,8 @@ func TestGetByIdWarehouses(t *testing.T) {
 			Address:            "Rua Pedro Dias",
 			Telephone:          "3712291281",
 			WarehouseCode:      "DAE",
-			MinimumCapacity:    10,
-			MinimumTemperature: 10,
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10),
 		}
 		mockService.On("Get", mock.Anything, 1).Return(expectedWarehouse, nil)
 
@@ -164,8 +310,8 @@ func TestCreateWarehouses(t *testing.T) {
 			Address:            "Rua Pedro Dias",
 			Telephone:          "3712291281",
 			WarehouseCode:      "DAE",
-			MinimumCapacity:    10,
-			MinimumTemperature: 10,
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10),
 		}
 
 		mockService.On("Save", mock.Anything, mock.Anything).Return(expectedWarehouse, nil)
@@ -318,13 +464,14 @@ func TestUpdateWarehouse(t *testing.T) {
 			Address:            "Rua Pedro Dias",
 			Telephone:          "3712291281",
 			WarehouseCode:      "DAE",
-			MinimumCapacity:    10,
-			MinimumTemperature: 10,
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10),
 		}
 
-		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(updatedWarehouse, nil)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(updatedWarehouse, nil)
 
 		request, response := testutil.MakeRequest(http.MethodPatch, BaseEndpointWithIdNumberWarehouse, `{"address":"Rua Pedro Dias","telephone":"371928"}`)
+		request.Header.Set("If-Match", `"1"`)
 
 		server.PATCH(BaseEndpointWithIdWarehouse, handler.Update())
 		server.ServeHTTP(response, request)
@@ -363,8 +510,9 @@ func TestUpdateWarehouse(t *testing.T) {
 		server, mockService, handler := InitServerWithWarehouses(t)
 
 		request, response := testutil.MakeRequest(http.MethodPatch, BaseEndpointWithIdNumberWarehouse, `{"address":"Rua Pedro Dias","telephone":"371928"}`)
+		request.Header.Set("If-Match", `"1"`)
 
-		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(domain.Warehouse{}, warehouse.ErrNotFound)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Warehouse{}, warehouse.ErrNotFound)
 
 		server.PATCH(BaseEndpointWithIdWarehouse, handler.Update())
 		server.ServeHTTP(response, request)
@@ -375,8 +523,9 @@ func TestUpdateWarehouse(t *testing.T) {
 		server, mockService, handler := InitServerWithWarehouses(t)
 
 		request, response := testutil.MakeRequest(http.MethodPatch, BaseEndpointWithIdNumberWarehouse, `{"telephone":"371928"}`)
+		request.Header.Set("If-Match", `"1"`)
 
-		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(domain.Warehouse{}, warehouse.ErrTryAgain)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Warehouse{}, warehouse.ErrTryAgain)
 
 		server.PATCH(BaseEndpointWithIdWarehouse, handler.Update())
 		server.ServeHTTP(response, request)
@@ -385,6 +534,160 @@ func TestUpdateWarehouse(t *testing.T) {
 	})
 }
 
+func TestInventoryWarehouse(t *testing.T) {
+	t.Run("Should return status 200 with the inventory at as_of", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		asOf := datetime.MustParse("2023-02-15")
+		expectedInventory := domain.WarehouseInventory{
+			WarehouseID:     1,
+			WarehouseCode:   "W001",
+			AsOf:            asOf,
+			TotalQuantity:   5,
+			MinimumCapacity: testutil.Int(50),
+			BelowMinimum:    true,
+			Products:        []domain.InventoryProduct{{ProductID: 1, Description: "apple", Quantity: 5}},
+			Sections:        []domain.InventorySection{},
+		}
+		mockService.On("Inventory", mock.Anything, 1, asOf).Return(expectedInventory, nil)
+
+		server.GET("/warehouses/:id/inventory", handler.Inventory())
+		request, response := testutil.MakeRequest(http.MethodGet, "/warehouses/1/inventory?as_of=2023-02-15", "")
+		server.ServeHTTP(response, request)
+
+		responseResult := struct {
+			Data domain.WarehouseInventory `json:"data"`
+		}{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, expectedInventory, responseResult.Data)
+	})
+
+	t.Run("Should return status 400 when as_of is invalid", func(t *testing.T) {
+		server, _, handler := InitServerWithWarehouses(t)
+
+		server.GET("/warehouses/:id/inventory", handler.Inventory())
+		request, response := testutil.MakeRequest(http.MethodGet, "/warehouses/1/inventory?as_of=someday", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Should return status 404 when the warehouse does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("Inventory", mock.Anything, 1, datetime.Time{}).Return(domain.WarehouseInventory{}, warehouse.ErrNotFound)
+
+		server.GET("/warehouses/:id/inventory", handler.Inventory())
+		request, response := testutil.MakeRequest(http.MethodGet, "/warehouses/1/inventory", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestConditionalRequestsWarehouse(t *testing.T) {
+	storedWarehouse := domain.Warehouse{
+		ID:            1,
+		Address:       "Rua Pedro Dias",
+		WarehouseCode: "DAE",
+		Version:       3,
+	}
+
+	t.Run("Should return the ETag of the warehouse", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("Get", mock.Anything, 1).Return(storedWarehouse, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointWithIdNumberWarehouse, "")
+		server.GET(BaseEndpointWithIdWarehouse, handler.Get())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `"3"`, response.Header().Get("ETag"))
+	})
+	t.Run("Should return status 304 when the cached copy is current", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("Get", mock.Anything, 1).Return(storedWarehouse, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointWithIdNumberWarehouse, "")
+		request.Header.Set("If-None-Match", `"2", W/"3"`)
+		server.GET(BaseEndpointWithIdWarehouse, handler.Get())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotModified, response.Code)
+		assert.Empty(t, response.Body.String())
+	})
+	t.Run("Should return status 200 when the cached copy is stale", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("Get", mock.Anything, 1).Return(storedWarehouse, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, BaseEndpointWithIdNumberWarehouse, "")
+		request.Header.Set("If-None-Match", `"2"`)
+		server.GET(BaseEndpointWithIdWarehouse, handler.Get())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("Should update the expected version and return the new ETag", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		updatedWarehouse := storedWarehouse
+		updatedWarehouse.Version = 4
+		mockService.On("Update", mock.Anything, 1, domain.Patch{Document: []byte(`{"address":"Rua Maria"}`), Version: 3}).Return(updatedWarehouse, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, BaseEndpointWithIdNumberWarehouse, `{"address":"Rua Maria"}`)
+		request.Header.Set("If-Match", `"3"`)
+		server.PATCH(BaseEndpointWithIdWarehouse, handler.Update())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `"4"`, response.Header().Get("ETag"))
+	})
+	t.Run("Should accept any version with a wildcard", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("Update", mock.Anything, 1, domain.Patch{Document: []byte(`{"address":"Rua Maria"}`)}).Return(storedWarehouse, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, BaseEndpointWithIdNumberWarehouse, `{"address":"Rua Maria"}`)
+		request.Header.Set("If-Match", "*")
+		server.PATCH(BaseEndpointWithIdWarehouse, handler.Update())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("Should return status 428 without If-Match", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, BaseEndpointWithIdNumberWarehouse, `{"address":"Rua Maria"}`)
+		server.PATCH(BaseEndpointWithIdWarehouse, handler.Update())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusPreconditionRequired, response.Code)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Should return status 412 with a weak or malformed If-Match", func(t *testing.T) {
+		for _, header := range []string{`W/"3"`, "3", `"abc"`} {
+			server, mockService, handler := InitServerWithWarehouses(t)
+
+			request, response := testutil.MakeRequest(http.MethodPatch, BaseEndpointWithIdNumberWarehouse, `{"address":"Rua Maria"}`)
+			request.Header.Set("If-Match", header)
+			server.PATCH(BaseEndpointWithIdWarehouse, handler.Update())
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, http.StatusPreconditionFailed, response.Code, header)
+			mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+	t.Run("Should return status 412 when the warehouse changed", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWarehouses(t)
+		mockService.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Warehouse{}, warehouse.ErrVersionMismatch)
+
+		request, response := testutil.MakeRequest(http.MethodPatch, BaseEndpointWithIdNumberWarehouse, `{"address":"Rua Maria"}`)
+		request.Header.Set("If-Match", `"2"`)
+		server.PATCH(BaseEndpointWithIdWarehouse, handler.Update())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusPreconditionFailed, response.Code)
+	})
+}
+
 func InitServerWithWarehouses(t *testing.T) (*gin.Engine, *mocks.WarehouseServiceMock, *handler.WarehouseController) {
 	t.Helper()
 	server := testutil.CreateServer()
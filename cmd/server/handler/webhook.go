@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/webhook"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidWebhookID = errors.New("invalid id")
+
+type WebhookController struct {
+	webhookService webhook.Service
+}
+
+func NewWebhook(s webhook.Service) *WebhookController {
+	return &WebhookController{
+		webhookService: s,
+	}
+}
+
+// @Summary Create Webhook
+// @Produce json
+// POST /webhooks @Summary Subscribes a URL to domain events
+// @Router /api/v1/webhooks [post]
+// @Tags Webhooks
+// @Accept json
+// @Param webhook body domain.WebhookRequest true "Webhook Data"
+// @Success 201 {object} domain.WebhookResponse
+// @Description Subscribe a URL to event types. The response carries the secret deliveries are signed with; it is not shown again
+func (w *WebhookController) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req domain.WebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		result, err := w.webhookService.Create(c, req)
+		if err != nil {
+			w.writeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusCreated, result)
+	}
+}
+
+// @Summary List Webhooks
+// @Produce json
+// GET /webhooks @Summary Returns all webhooks
+// @Router /api/v1/webhooks [get]
+// @Tags Webhooks
+// @Success 200 {object} domain.WebhooksResponse
+// @Description List all webhooks and the events they are subscribed to
+func (w *WebhookController) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := w.webhookService.GetAll(c)
+		if err != nil {
+			w.writeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, result)
+	}
+}
+
+// @Summary Get Webhook by ID
+// @Produce json
+// GET /webhooks/:id @Summary Returns a webhook per Id
+// @Router /api/v1/webhooks/{id} [get]
+// @Param id path int true "Webhook ID"
+// @Tags Webhooks
+// @Success 200 {object} domain.WebhookResponse
+// @Description List one by Webhook id
+func (w *WebhookController) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := w.id(c)
+		if !ok {
+			return
+		}
+		result, err := w.webhookService.Get(c, id)
+		if err != nil {
+			w.writeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, result)
+	}
+}
+
+// @Summary Delete Webhook
+// @Produce json
+// DELETE /webhooks/:id @Summary Removes a webhook and its queued deliveries
+// @Router /api/v1/webhooks/{id} [delete]
+// @Param id path int true "Webhook ID"
+// @Tags Webhooks
+// @Success 204
+// @Description Delete a webhook by id
+func (w *WebhookController) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := w.id(c)
+		if !ok {
+			return
+		}
+		if err := w.webhookService.Delete(c, id); err != nil {
+			w.writeError(c, err)
+			return
+		}
+		web.Response(c, http.StatusNoContent, "")
+	}
+}
+
+// @Summary Test Webhook
+// @Produce json
+// POST /webhooks/:id/test @Summary Sends a ping to a webhook
+// @Router /api/v1/webhooks/{id}/test [post]
+// @Param id path int true "Webhook ID"
+// @Tags Webhooks
+// @Success 200 {object} domain.WebhookTestResponse
+// @Description Send a signed ping event right away and report how the receiver answered
+func (w *WebhookController) Test() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := w.id(c)
+		if !ok {
+			return
+		}
+		result, err := w.webhookService.Test(c, id)
+		if err != nil {
+			w.writeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, result)
+	}
+}
+
+// @Summary List dead deliveries
+// @Produce json
+// GET /webhooks/dead-letters @Summary Returns the deliveries that ran out of attempts
+// @Router /api/v1/webhooks/dead-letters [get]
+// @Tags Webhooks
+// @Success 200 {object} domain.WebhookDeliveriesResponse
+// @Description List the deliveries that were dead-lettered, with their last error
+func (w *WebhookController) DeadLetters() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := w.webhookService.DeadLetters(c)
+		if err != nil {
+			w.writeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, result)
+	}
+}
+
+// @Summary Retry dead delivery
+// @Produce json
+// POST /webhooks/deliveries/:id/retry @Summary Queues a dead delivery again
+// @Router /api/v1/webhooks/deliveries/{id}/retry [post]
+// @Param id path int true "Delivery ID"
+// @Tags Webhooks
+// @Success 204
+// @Description Queue a dead-lettered delivery again with a fresh set of attempts
+func (w *WebhookController) Retry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := w.id(c)
+		if !ok {
+			return
+		}
+		if err := w.webhookService.Retry(c, id); err != nil {
+			w.writeError(c, err)
+			return
+		}
+		web.Response(c, http.StatusNoContent, "")
+	}
+}
+
+func (w *WebhookController) id(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		web.Error(c, http.StatusBadRequest, errInvalidWebhookID.Error())
+		return 0, false
+	}
+	return id, true
+}
+
+func (w *WebhookController) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrNotFound), errors.Is(err, webhook.ErrDeliveryNotFound):
+		web.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, webhook.ErrInvalidURL), errors.Is(err, webhook.ErrInvalidEvents):
+		web.Error(c, http.StatusUnprocessableEntity, err.Error())
+	default:
+		web.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
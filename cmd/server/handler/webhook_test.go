@@ -0,0 +1,212 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/webhook"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
+	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	WebhooksEndpoint     = "/webhooks"
+	WebhookEndpoint      = "/webhooks/:id"
+	WebhookTestEndpoint  = "/webhooks/:id/test"
+	WebhookRetryEndpoint = "/webhooks/deliveries/:id/retry"
+)
+
+var webhookRequest = domain.WebhookRequest{
+	URL:    "https://erp.example.com/hooks",
+	Events: []string{domain.EventPurchaseOrderCreated},
+}
+
+func TestCreateWebhook(t *testing.T) {
+	t.Run("Should return status 201 and the webhook with its secret", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		expected := domain.Webhook{ID: 1, URL: webhookRequest.URL, Events: webhookRequest.Events, Secret: "secret"}
+		mockService.On("Create", mock.Anything, webhookRequest).Return(expected, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, WebhooksEndpoint, `{"url":"https://erp.example.com/hooks","events":["purchase_order.created"]}`)
+		server.POST(WebhooksEndpoint, handler.Create())
+		server.ServeHTTP(response, request)
+
+		responseResult := domain.WebhookResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Equal(t, expected, responseResult.Data)
+	})
+	t.Run("Should return status 422 when the url is missing", func(t *testing.T) {
+		server, _, handler := InitServerWithWebhooks(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, WebhooksEndpoint, `{"events":["purchase_order.created"]}`)
+		server.POST(WebhooksEndpoint, handler.Create())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+	t.Run("Should return status 422 on an unknown event", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		mockService.On("Create", mock.Anything, mock.Anything).Return(domain.Webhook{}, webhook.ErrInvalidEvents)
+
+		request, response := testutil.MakeRequest(http.MethodPost, WebhooksEndpoint, `{"url":"https://erp.example.com/hooks","events":["order.lost"]}`)
+		server.POST(WebhooksEndpoint, handler.Create())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func TestGetWebhook(t *testing.T) {
+	t.Run("Should return status 200 and the webhooks", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		expected := []domain.Webhook{{ID: 1, URL: webhookRequest.URL, Events: webhookRequest.Events}}
+		mockService.On("GetAll", mock.Anything).Return(expected, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, WebhooksEndpoint, "")
+		server.GET(WebhooksEndpoint, handler.GetAll())
+		server.ServeHTTP(response, request)
+
+		responseResult := domain.WebhooksResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, expected, responseResult.Data)
+	})
+	t.Run("Should return status 404 when the webhook does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		mockService.On("Get", mock.Anything, 9).Return(domain.Webhook{}, webhook.ErrNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/webhooks/9", "")
+		server.GET(WebhookEndpoint, handler.Get())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+	t.Run("Should return status 400 on an invalid id", func(t *testing.T) {
+		server, _, handler := InitServerWithWebhooks(t)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/webhooks/abc", "")
+		server.GET(WebhookEndpoint, handler.Get())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	t.Run("Should return status 204", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		mockService.On("Delete", mock.Anything, 1).Return(nil)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, "/webhooks/1", "")
+		server.DELETE(WebhookEndpoint, handler.Delete())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+	t.Run("Should return status 404 when the webhook does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		mockService.On("Delete", mock.Anything, 1).Return(webhook.ErrNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, "/webhooks/1", "")
+		server.DELETE(WebhookEndpoint, handler.Delete())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestTestWebhook(t *testing.T) {
+	t.Run("Should return status 200 and how the receiver answered", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		expected := domain.WebhookTest{WebhookID: 1, ResponseStatus: 500, Error: "unexpected response status: 500"}
+		mockService.On("Test", mock.Anything, 1).Return(expected, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/webhooks/1/test", "")
+		server.POST(WebhookTestEndpoint, handler.Test())
+		server.ServeHTTP(response, request)
+
+		responseResult := domain.WebhookTestResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, expected, responseResult.Data)
+	})
+	t.Run("Should return status 404 when the webhook does not exist", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		mockService.On("Test", mock.Anything, 1).Return(domain.WebhookTest{}, webhook.ErrNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/webhooks/1/test", "")
+		server.POST(WebhookTestEndpoint, handler.Test())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestDeadLettersWebhook(t *testing.T) {
+	t.Run("Should return status 200 and the dead deliveries", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		expected := []domain.WebhookDelivery{{ID: 3, WebhookID: 1, Event: domain.EventSellerUpdated, Status: domain.DeliveryDead, Attempts: 10}}
+		mockService.On("DeadLetters", mock.Anything).Return(expected, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/webhooks/dead-letters", "")
+		server.GET("/webhooks/dead-letters", handler.DeadLetters())
+		server.ServeHTTP(response, request)
+
+		responseResult := domain.WebhookDeliveriesResponse{}
+		_ = json.Unmarshal(response.Body.Bytes(), &responseResult)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, 3, responseResult.Data[0].ID)
+	})
+	t.Run("Should return status 500 when the queue cannot be read", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		mockService.On("DeadLetters", mock.Anything).Return([]domain.WebhookDelivery{}, errors.New("error"))
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/webhooks/dead-letters", "")
+		server.GET("/webhooks/dead-letters", handler.DeadLetters())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
+
+func TestRetryWebhookDelivery(t *testing.T) {
+	t.Run("Should return status 204", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		mockService.On("Retry", mock.Anything, 3).Return(nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/webhooks/deliveries/3/retry", "")
+		server.POST(WebhookRetryEndpoint, handler.Retry())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+	t.Run("Should return status 404 when the delivery is not dead", func(t *testing.T) {
+		server, mockService, handler := InitServerWithWebhooks(t)
+		mockService.On("Retry", mock.Anything, 3).Return(webhook.ErrDeliveryNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPost, "/webhooks/deliveries/3/retry", "")
+		server.POST(WebhookRetryEndpoint, handler.Retry())
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func InitServerWithWebhooks(t *testing.T) (*gin.Engine, *mocks.WebhookServiceMock, *handler.WebhookController) {
+	t.Helper()
+	server := testutil.CreateServer()
+	mockService := new(mocks.WebhookServiceMock)
+	return server, mockService, handler.NewWebhook(mockService)
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"database/sql"
+	"net"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/routes"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/rpc"
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -20,6 +22,17 @@ func main() {
 	router := routes.NewRouter(eng, db)
 	router.MapRoutes()
 
+	listener, err := net.Listen("tcp", rpc.AddressFromEnv())
+	if err != nil {
+		panic(err)
+	}
+	server := rpc.NewServer(rpc.NewServices(db))
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			panic(err)
+		}
+	}()
+
 	if err := eng.Run(); err != nil {
 		panic(err)
 	}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/docs"
@@ -8,15 +9,23 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/buyer"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/carry"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/graph"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/inbound_order"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/locality"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
 	productbatch "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_batch"
 	productrecord "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_record"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/receipt"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/shipment"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/stream"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/transfer"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/webhook"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
 
 	"github.com/gin-gonic/gin"
 	swaggerfiles "github.com/swaggo/files"
@@ -31,6 +40,9 @@ type router struct {
 	eng *gin.Engine
 	rg  *gin.RouterGroup
 	db  *sql.DB
+
+	webhooks webhook.Service
+	hub      *stream.Hub
 }
 
 func NewRouter(eng *gin.Engine, db *sql.DB) Router {
@@ -39,6 +51,9 @@ func NewRouter(eng *gin.Engine, db *sql.DB) Router {
 
 func (r *router) MapRoutes() {
 	r.setGroup()
+	r.setWebhooks()
+	r.setStream()
+	r.setOutbox()
 
 	r.buildSellerRoutes()
 	r.buildProductRoutes()
@@ -53,16 +68,56 @@ func (r *router) MapRoutes() {
 	r.buildLocalityRoutes()
 	r.buildInboundOrderRoutes()
 	r.buildProductRecordRoutes()
+	r.buildTransferRoutes()
+	r.buildReceiptRoutes()
+	r.buildShipmentRoutes()
+	r.buildWebhookRoutes()
+	r.buildStreamRoutes()
+	r.buildGraphQLRoutes()
 }
 
 func (r *router) setGroup() {
 	r.rg = r.eng.Group("/api/v1")
 }
 
+// setWebhooks builds the webhook service and starts sending the queued
+// deliveries in the background.
+func (r *router) setWebhooks() {
+	repo := webhook.NewRepository(r.db)
+	r.webhooks = webhook.NewService(repo, nil)
+
+	dispatcher := webhook.NewDispatcher(repo, nil)
+	go dispatcher.Run(context.Background(), webhook.DefaultInterval)
+}
+
+// setStream builds the hub the live warehouse activity is broadcast through.
+func (r *router) setStream() {
+	r.hub = stream.NewHub(stream.DefaultHistory, stream.DefaultBuffer)
+}
+
+// setOutbox starts relaying the domain events the repositories store to the
+// stream, the webhooks and the publisher chosen with OUTBOX_PUBLISHER. The
+// stream goes first so live screens are not held back by webhook storage.
+func (r *router) setOutbox() {
+	publisher, err := outbox.PublisherFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	live := stream.NewPublisher(stream.NewRepository(r.db), r.hub)
+	relay := outbox.NewRelay(outbox.NewRepository(r.db), outbox.Multi(live, r.webhooks, publisher))
+	go relay.Run(context.Background(), outbox.DefaultInterval)
+}
+
 func (r *router) buildInboundOrderRoutes() {
 	repoInboundOrder := inbound_order.NewRepository(r.db)
-	service := inbound_order.NewService(repoInboundOrder)
+	employeeRepo := employee.NewRepository(r.db)
+	warehouseRepo := warehouse.NewRepository(r.db)
+	batchRepo := productbatch.NewRepository(r.db, productbatch.Querys{})
+	sectionRepo := section.NewRepository(r.db)
+	service := inbound_order.NewService(repoInboundOrder, employeeRepo, warehouseRepo, batchRepo, sectionRepo)
 	handler := handler.NewInboundOrders(service)
+	r.rg.GET("/inboundOrders", handler.GetAll())
 	r.rg.GET("/inboundOrders/:id", handler.Get())
 	r.rg.POST("/inboundOrders", handler.Create())
 	r.rg.GET("/employees/reportInboundOrders", handler.ReportByAll())
@@ -83,6 +138,7 @@ func (r *router) buildSellerRoutes() {
 	r.rg.POST("/sellers", handler.Create())
 	r.rg.DELETE("/sellers/:id", handler.Delete())
 	r.rg.PATCH("/sellers/:id", handler.Update())
+	r.rg.GET("/sellers/:id/dashboard", handler.Dashboard())
 }
 
 func (r *router) buildLocalityRoutes() {
@@ -94,11 +150,17 @@ func (r *router) buildLocalityRoutes() {
 }
 
 func (r *router) buildProductRoutes() {
+	divisor, err := product.DivisorFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
 	repo := product.NewRepository(r.db)
 	service := product.NewService(repo)
-	handler := handler.NewProduct(service)
+	handler := handler.NewProduct(service, divisor)
 	r.rg.POST("/products", handler.Create())
 	r.rg.GET("/products", handler.GetAll())
+	r.rg.GET("/products/search", handler.Search())
 	r.rg.DELETE("/products/:id", handler.Delete())
 	r.rg.GET("/products/:id", handler.Get())
 	r.rg.PATCH("/products/:id", handler.Update())
@@ -106,7 +168,7 @@ func (r *router) buildProductRoutes() {
 
 func (r *router) buildSectionRoutes() {
 	repo := section.NewRepository(r.db)
-	service := section.NewService(repo)
+	service := section.NewService(repo, product.NewRepository(r.db))
 	handler := handler.NewSection(service)
 
 	r.rg.GET("/sections", handler.GetAll())
@@ -114,18 +176,22 @@ func (r *router) buildSectionRoutes() {
 	r.rg.POST("/sections", handler.Create())
 	r.rg.DELETE("/sections/:id", handler.Delete())
 	r.rg.PATCH("/sections/:id", handler.Update())
+	r.rg.POST("/sections/:id/fit", handler.Fit())
 	r.rg.GET("/sections/reportProducts", handler.ReportProducts())
 }
 
 func (r *router) buildWarehouseRoutes() {
 	repo := warehouse.NewRepository(r.db)
-	service := warehouse.NewService(repo)
+	sectionRepo := section.NewRepository(r.db)
+	batchRepo := productbatch.NewRepository(r.db, productbatch.Querys{})
+	service := warehouse.NewService(repo, sectionRepo, batchRepo)
 	handler := handler.NewWarehouse(service)
 	r.rg.GET("/warehouses", handler.GetAll())
 	r.rg.GET("/warehouses/:id", handler.Get())
 	r.rg.POST("/warehouses", handler.Create())
 	r.rg.DELETE("/warehouses/:id", handler.Delete())
 	r.rg.PATCH("/warehouses/:id", handler.Update())
+	r.rg.GET("/warehouses/:id/inventory", handler.Inventory())
 }
 
 func (r *router) buildEmployeeRoutes() {
@@ -138,29 +204,41 @@ func (r *router) buildEmployeeRoutes() {
 	r.rg.GET("/employees/:id", handler.Get())
 	r.rg.DELETE("/employees/:id", handler.Delete())
 	r.rg.PATCH("/employees/:id", handler.Update())
+	r.rg.GET("/employees/:id/assignments", handler.Assignments())
+	r.rg.POST("/employees/:id/shifts", handler.CreateShift())
+	r.rg.GET("/warehouses/:id/roster", handler.Roster())
 }
 
 func (r *router) buildBuyerRoutes() {
+	rates, err := money.RatesFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
 	repo := buyer.NewRepository(r.db)
-	service := buyer.NewService(repo)
+	service := buyer.NewService(repo, rates)
 	handler := handler.NewBuyer(service)
 	r.rg.GET("/buyers", handler.GetAll())
 	r.rg.GET("/buyers/:id", handler.Get())
+	r.rg.GET("/buyers/:id/orders", handler.Orders())
+	r.rg.GET("/buyers/:id/summary", handler.Summary())
 	r.rg.GET("/buyers/reportPurchaseOrders", handler.GetBuyersOrders())
 	r.rg.GET("/buyers/reportPurchaseOrders/:id", handler.GetBuyerOrders())
 	r.rg.POST("/buyers", handler.Create())
 	r.rg.PATCH("/buyers/:id", handler.Update())
+	r.rg.PATCH("/buyers/:id/card-number", handler.UpdateCardNumber())
 	r.rg.DELETE("/buyers/:id", handler.Delete())
 }
 
 func (r *router) buildPurchaseOrdersRoutes() {
 	buyerRepo := buyer.NewRepository(r.db)
-	buyerService := buyer.NewService(buyerRepo)
+	buyerService := buyer.NewService(buyerRepo, nil)
 
 	repo := purchase_orders.NewRepository(r.db)
 	service := purchase_orders.NewService(repo)
 	handler := handler.NewPurchaseOrders(service, buyerService)
 	r.rg.POST("/purchaseOrders", handler.CreateOrders())
+	r.rg.POST("/purchaseOrders/:id/assign", handler.Assign())
 }
 
 func (r *router) buildProductBatchRoutes() {
@@ -168,13 +246,14 @@ func (r *router) buildProductBatchRoutes() {
 	productService := product.NewService(productRrepo)
 
 	sectionRepo := section.NewRepository(r.db)
-	sectionService := section.NewService(sectionRepo)
+	sectionService := section.NewService(sectionRepo, productRrepo)
 
 	repo := productbatch.NewRepository(r.db, productbatch.Querys{})
 	service := productbatch.NewService(repo)
 	handler := handler.NewProductBatch(service, productService, sectionService)
 
 	r.rg.POST("/productBatches", handler.Create())
+	r.rg.GET("/productBatches", handler.GetAll())
 }
 
 func (r *router) buildCarryRoutes() {
@@ -185,26 +264,124 @@ func (r *router) buildCarryRoutes() {
 	r.rg.GET("/carriers/:id", handler.Get())
 	r.rg.GET("/localities/reportCarries", handler.Read())
 	r.rg.POST("/carriers", handler.Create())
+	r.rg.GET("/carriers", handler.GetAll())
+	r.rg.PATCH("/carriers/:id", handler.Update())
+	r.rg.DELETE("/carriers/:id", handler.Delete())
+	r.rg.GET("/carriers/:id/coverage", handler.Coverage())
+	r.rg.PUT("/carriers/:id/coverage", handler.SetCoverage())
+	r.rg.POST("/carriers/:id/credentials", handler.IssueCredential())
+	r.rg.GET("/localities/:id/carriers", handler.ByLocality())
 }
 
 func (r *router) buildProductRecordRoutes() {
 	productRepo := product.NewRepository(r.db)
 	productService := product.NewService(productRepo)
 
+	rates, err := money.RatesFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
 	repo := productrecord.NewRepository(r.db)
-	service := productrecord.NewService(repo)
+	service := productrecord.NewService(repo, rates)
 	handler := handler.NewProductRecord(service, productService)
 
 	r.rg.POST("/productRecords", handler.Create())
 	r.rg.GET("/products/reportRecords", handler.RecordsByAllProductsReport())
 	r.rg.GET("/products/reportRecords/:id", handler.RecordsByOneProductReport())
+	r.rg.GET("/products/:id/prices", handler.PriceHistory())
+	r.rg.GET("/products/:id/price", handler.PriceAt())
+}
+
+func (r *router) buildTransferRoutes() {
+	repo := transfer.NewRepository(r.db)
+	service := transfer.NewService(repo)
+	handler := handler.NewTransfer(service)
+
+	r.rg.POST("/transfers", handler.Create())
+	r.rg.GET("/transfers/:id", handler.Get())
+	r.rg.POST("/transfers/:id/dispatch", handler.Dispatch())
+	r.rg.POST("/transfers/:id/receive", handler.Receive())
+}
+
+func (r *router) buildReceiptRoutes() {
+	repo := receipt.NewRepository(r.db)
+	warehouseRepo := warehouse.NewRepository(r.db)
+	employeeRepo := employee.NewRepository(r.db)
+	productRepo := product.NewRepository(r.db)
+	sectionRepo := section.NewRepository(r.db)
+	orderRepo := inbound_order.NewRepository(r.db)
+	service := receipt.NewService(repo, warehouseRepo, employeeRepo, productRepo, sectionRepo, orderRepo)
+	handler := handler.NewReceipt(service)
+
+	r.rg.POST("/warehouses/:id/receipts", handler.Create())
+}
+
+func (r *router) buildShipmentRoutes() {
+	repo := shipment.NewRepository(r.db)
+	service := shipment.NewService(repo)
+	handler := handler.NewShipment(service)
+
+	r.rg.GET("/shipments/:tracking_code", handler.Get())
+	r.rg.POST("/shipments/:tracking_code/events", handler.AddEvent())
+}
+
+func (r *router) buildWebhookRoutes() {
+	handler := handler.NewWebhook(r.webhooks)
+
+	r.rg.POST("/webhooks", handler.Create())
+	r.rg.GET("/webhooks", handler.GetAll())
+	r.rg.GET("/webhooks/dead-letters", handler.DeadLetters())
+	r.rg.GET("/webhooks/:id", handler.Get())
+	r.rg.DELETE("/webhooks/:id", handler.Delete())
+	r.rg.POST("/webhooks/:id/test", handler.Test())
+	r.rg.POST("/webhooks/deliveries/:id/retry", handler.Retry())
+}
+
+func (r *router) buildStreamRoutes() {
+	handler := handler.NewStream(r.hub)
+
+	r.rg.GET("/stream", handler.Stream())
+}
+
+// buildGraphQLRoutes serves the domain model over GraphQL next to the REST
+// API, outside of its versioned group.
+func (r *router) buildGraphQLRoutes() {
+	rates, err := money.RatesFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	localityRepo := locality.NewRepository(r.db)
+	sectionRepo := section.NewRepository(r.db)
+	batchRepo := productbatch.NewRepository(r.db, productbatch.Querys{})
+	productRepo := product.NewRepository(r.db)
+	executor, err := graph.NewExecutor(graph.Services{
+		Warehouses:     warehouse.NewService(warehouse.NewRepository(r.db), sectionRepo, batchRepo),
+		Sections:       section.NewService(sectionRepo, productRepo),
+		Batches:        productbatch.NewService(batchRepo),
+		Products:       product.NewService(productRepo),
+		Sellers:        seller.NewService(seller.NewRepository(r.db)),
+		Employees:      employee.NewService(employee.NewRepository(r.db)),
+		Buyers:         buyer.NewService(buyer.NewRepository(r.db), rates),
+		PurchaseOrders: purchase_orders.NewService(purchase_orders.NewRepository(r.db)),
+		Carriers:       carry.NewService(carry.NewRepository(r.db), localityRepo),
+		Localities:     locality.NewService(localityRepo),
+	})
+	if err != nil {
+		panic(err)
+	}
+	handler := handler.NewGraphQL(executor)
+
+	r.eng.POST("/api/graphql", handler.Post())
+	r.eng.GET("/api/graphql", handler.Get())
 }
 
 func (r *router) buildSwagger() {
 	docs.SwaggerInfo.BasePath = "/"
 	r.rg.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 	repo := buyer.NewRepository(r.db)
-	service := buyer.NewService(repo)
+	service := buyer.NewService(repo, nil)
 	handler := handler.NewBuyer(service)
 	r.rg.GET("/teste", handler.GetAll())
 }
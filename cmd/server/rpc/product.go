@@ -0,0 +1,140 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var productCodes = errorCodes{
+	product.ErrNotFound:             codes.NotFound,
+	product.ErrProductAlreadyExists: codes.AlreadyExists,
+	product.ErrInvalidBody:          codes.InvalidArgument,
+	product.ErrInvalidField:         codes.InvalidArgument,
+}
+
+type ProductServer struct {
+	pb.UnimplementedProductServiceServer
+	productService product.Service
+}
+
+func NewProduct(s product.Service) *ProductServer {
+	return &ProductServer{
+		productService: s,
+	}
+}
+
+func (p *ProductServer) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.Product, error) {
+	if req.Id <= 0 {
+		return nil, invalidID(req.Id)
+	}
+	result, err := p.productService.Get(ctx, int(req.Id))
+	if err != nil {
+		return nil, productCodes.status(err)
+	}
+	return productToPB(result), nil
+}
+
+func (p *ProductServer) ListProducts(ctx context.Context, _ *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+	products, err := p.productService.GetAll(ctx)
+	if err != nil {
+		return nil, productCodes.status(err)
+	}
+	res := &pb.ListProductsResponse{Products: make([]*pb.Product, 0, len(products))}
+	for _, result := range products {
+		res.Products = append(res.Products, productToPB(result))
+	}
+	return res, nil
+}
+
+func (p *ProductServer) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.Product, error) {
+	input := productFromPB(req.Product)
+	if err := validateProduct(input); err != nil {
+		return nil, err
+	}
+	input.ID = 0
+
+	id, err := p.productService.Save(ctx, input)
+	if err != nil {
+		return nil, productCodes.status(err)
+	}
+	input.ID = id
+	return productToPB(input), nil
+}
+
+func (p *ProductServer) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.Product, error) {
+	input := productFromPB(req.Product)
+	if input.ID <= 0 {
+		return nil, invalidID(int64(input.ID))
+	}
+	if err := validateProduct(input); err != nil {
+		return nil, err
+	}
+
+	patch, err := replacement(input)
+	if err != nil {
+		return nil, productCodes.status(err)
+	}
+	updated, err := p.productService.Update(ctx, input.ID, patch)
+	if err != nil {
+		return nil, productCodes.status(err)
+	}
+	return productToPB(updated), nil
+}
+
+func (p *ProductServer) DeleteProduct(ctx context.Context, req *pb.DeleteProductRequest) (*emptypb.Empty, error) {
+	if req.Id <= 0 {
+		return nil, invalidID(req.Id)
+	}
+	if err := p.productService.Delete(ctx, int(req.Id)); err != nil {
+		return nil, productCodes.status(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// validateProduct requires the fields the REST API requires.
+func validateProduct(p domain.Product) error {
+	if p.Description == "" || p.ExpirationRate == 0 || p.FreezingRate == 0 || p.Height == 0 || p.Length == 0 || p.Netweight == 0 || p.ProductCode == "" || p.RecomFreezTemp == 0 || p.SellerID == 0 {
+		return status.Error(codes.InvalidArgument, product.ErrInvalidField.Error())
+	}
+	return nil
+}
+
+func productToPB(p domain.Product) *pb.Product {
+	return &pb.Product{
+		Id:                             int64(p.ID),
+		Description:                    p.Description,
+		ExpirationRate:                 p.ExpirationRate,
+		FreezingRate:                   p.FreezingRate,
+		Height:                         p.Height,
+		Length:                         p.Length,
+		Netweight:                      p.Netweight,
+		ProductCode:                    p.ProductCode,
+		RecommendedFreezingTemperature: p.RecomFreezTemp,
+		Width:                          p.Width,
+		ProductTypeId:                  int64(p.ProductTypeID),
+		SellerId:                       int64(p.SellerID),
+	}
+}
+
+func productFromPB(p *pb.Product) domain.Product {
+	return domain.Product{
+		ID:             int(p.GetId()),
+		Description:    p.GetDescription(),
+		ExpirationRate: p.GetExpirationRate(),
+		FreezingRate:   p.GetFreezingRate(),
+		Height:         p.GetHeight(),
+		Length:         p.GetLength(),
+		Netweight:      p.GetNetweight(),
+		ProductCode:    p.GetProductCode(),
+		RecomFreezTemp: p.GetRecommendedFreezingTemperature(),
+		Width:          p.GetWidth(),
+		ProductTypeID:  int(p.GetProductTypeId()),
+		SellerID:       int(p.GetSellerId()),
+	}
+}
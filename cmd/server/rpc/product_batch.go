@@ -0,0 +1,117 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
+	productbatch "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var productBatchCodes = errorCodes{
+	productbatch.ErrNotFound: codes.NotFound,
+}
+
+type ProductBatchServer struct {
+	pb.UnimplementedProductBatchServiceServer
+	productBatchService productbatch.Service
+	productService      product.Service
+	sectionService      section.Service
+}
+
+func NewProductBatch(s productbatch.Service, ps product.Service, ss section.Service) *ProductBatchServer {
+	return &ProductBatchServer{
+		productBatchService: s,
+		productService:      ps,
+		sectionService:      ss,
+	}
+}
+
+func (p *ProductBatchServer) ListProductBatches(ctx context.Context, req *pb.ListProductBatchesRequest) (*pb.ListProductBatchesResponse, error) {
+	if req.SectionId < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid section_id")
+	}
+	if req.ProductId < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid product_id")
+	}
+	filter := domain.ProductBatchFilter{SectionID: int(req.SectionId), ProductID: int(req.ProductId)}
+
+	batches, err := p.productBatchService.GetAll(ctx, filter)
+	if err != nil {
+		return nil, productBatchCodes.status(err)
+	}
+	res := &pb.ListProductBatchesResponse{ProductBatches: make([]*pb.ProductBatch, 0, len(batches))}
+	for _, result := range batches {
+		res.ProductBatches = append(res.ProductBatches, productBatchToPB(result))
+	}
+	return res, nil
+}
+
+func (p *ProductBatchServer) CreateProductBatch(ctx context.Context, req *pb.CreateProductBatchRequest) (*pb.ProductBatch, error) {
+	input, err := productBatchFromPB(req.ProductBatch)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := input.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	input.ID = 0
+
+	if err := p.productService.ExistsById(input.ProductID); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err := p.sectionService.ExistsById(input.SectionID); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	id, err := p.productBatchService.Save(ctx, input)
+	if err != nil {
+		return nil, productBatchCodes.status(err)
+	}
+	input.ID = id
+	return productBatchToPB(input), nil
+}
+
+func productBatchToPB(b domain.ProductBatch) *pb.ProductBatch {
+	return &pb.ProductBatch{
+		Id:                 int64(b.ID),
+		BatchNumber:        int64(b.BatchNumber),
+		CurrentQuantity:    int64(b.CurrentQuantity),
+		CurrentTemperature: int64(b.CurrentTemperature),
+		DueDate:            date(b.DueDate),
+		InitialQuantity:    int64(b.InitialQuantity),
+		ManufacturingDate:  date(b.ManufacturingDate),
+		ManufacturingHour:  int64(b.ManufacturingHour),
+		MinimumTemperature: int64(b.MinimumTemperature),
+		ProductId:          int64(b.ProductID),
+		SectionId:          int64(b.SectionID),
+	}
+}
+
+func productBatchFromPB(b *pb.ProductBatch) (domain.ProductBatch, error) {
+	dueDate, err := parseDate("due_date", b.GetDueDate())
+	if err != nil {
+		return domain.ProductBatch{}, err
+	}
+	manufacturingDate, err := parseDate("manufacturing_date", b.GetManufacturingDate())
+	if err != nil {
+		return domain.ProductBatch{}, err
+	}
+	return domain.ProductBatch{
+		ID:                 int(b.GetId()),
+		BatchNumber:        int(b.GetBatchNumber()),
+		CurrentQuantity:    int(b.GetCurrentQuantity()),
+		CurrentTemperature: int(b.GetCurrentTemperature()),
+		DueDate:            dueDate,
+		InitialQuantity:    int(b.GetInitialQuantity()),
+		ManufacturingDate:  manufacturingDate,
+		ManufacturingHour:  int(b.GetManufacturingHour()),
+		MinimumTemperature: int(b.GetMinimumTemperature()),
+		ProductID:          int(b.GetProductId()),
+		SectionID:          int(b.GetSectionId()),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+)
+
+var productBatchExpected = domain.ProductBatch{
+	BatchNumber:        1,
+	CurrentQuantity:    10,
+	CurrentTemperature: 5,
+	DueDate:            datetime.MustParse("2024-01-10"),
+	InitialQuantity:    10,
+	ManufacturingDate:  datetime.MustParse("2024-01-01"),
+	ManufacturingHour:  8,
+	MinimumTemperature: 2,
+	ProductID:          3,
+	SectionID:          4,
+}
+
+func productBatchMessage() *pb.ProductBatch {
+	return &pb.ProductBatch{
+		BatchNumber:        1,
+		CurrentQuantity:    10,
+		CurrentTemperature: 5,
+		DueDate:            "2024-01-10",
+		InitialQuantity:    10,
+		ManufacturingDate:  "2024-01-01",
+		ManufacturingHour:  8,
+		MinimumTemperature: 2,
+		ProductId:          3,
+		SectionId:          4,
+	}
+}
+
+func TestProductBatchServer(t *testing.T) {
+	t.Run("Should list the batches of a section", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.batches.On("GetAll", mock.Anything, domain.ProductBatchFilter{SectionID: 4}).Return([]domain.ProductBatch{productBatchExpected}, nil)
+
+		res, err := pb.NewProductBatchServiceClient(conn).ListProductBatches(context.TODO(), &pb.ListProductBatchesRequest{SectionId: 4})
+
+		assert.NoError(t, err)
+		assert.Len(t, res.ProductBatches, 1)
+		assert.Equal(t, "2024-01-10", res.ProductBatches[0].DueDate)
+	})
+	t.Run("Should create a batch of an existing product and section", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.products.On("ExistsById", 3).Return(nil)
+		s.sections.On("ExistsById", 4).Return(nil)
+		s.batches.On("Save", mock.Anything, productBatchExpected).Return(8, nil)
+
+		res, err := pb.NewProductBatchServiceClient(conn).CreateProductBatch(context.TODO(), &pb.CreateProductBatchRequest{ProductBatch: productBatchMessage()})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8), res.Id)
+		assert.Equal(t, "2024-01-01", res.ManufacturingDate)
+	})
+	t.Run("Should fail the precondition when the section does not exist", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.products.On("ExistsById", 3).Return(nil)
+		s.sections.On("ExistsById", 4).Return(errors.New("section not exists"))
+
+		_, err := pb.NewProductBatchServiceClient(conn).CreateProductBatch(context.TODO(), &pb.CreateProductBatchRequest{ProductBatch: productBatchMessage()})
+
+		assertCode(t, codes.FailedPrecondition, err)
+		s.batches.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+	t.Run("Should reject an invalid date", func(t *testing.T) {
+		conn, _ := InitServer(t)
+		message := productBatchMessage()
+		message.DueDate = "tomorrow"
+
+		_, err := pb.NewProductBatchServiceClient(conn).CreateProductBatch(context.TODO(), &pb.CreateProductBatchRequest{ProductBatch: message})
+
+		assertCode(t, codes.InvalidArgument, err)
+	})
+	t.Run("Should reject a batch without dates", func(t *testing.T) {
+		conn, _ := InitServer(t)
+		message := productBatchMessage()
+		message.ManufacturingDate = ""
+
+		_, err := pb.NewProductBatchServiceClient(conn).CreateProductBatch(context.TODO(), &pb.CreateProductBatchRequest{ProductBatch: message})
+
+		assertCode(t, codes.InvalidArgument, err)
+	})
+}
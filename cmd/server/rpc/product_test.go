@@ -0,0 +1,128 @@
+package rpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+)
+
+var productExpected = domain.Product{
+	ID:             1,
+	Description:    "Yogurt",
+	ExpirationRate: 1,
+	FreezingRate:   2,
+	Height:         6.4,
+	Length:         4.5,
+	Netweight:      3.4,
+	ProductCode:    "PROD01",
+	RecomFreezTemp: 1.3,
+	Width:          1.2,
+	ProductTypeID:  1,
+	SellerID:       1,
+}
+
+func TestProductServer(t *testing.T) {
+	t.Run("Should get a product", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.products.On("Get", 1).Return(productExpected, nil)
+
+		res, err := pb.NewProductServiceClient(conn).GetProduct(context.TODO(), &pb.GetProductRequest{Id: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PROD01", res.ProductCode)
+		assert.Equal(t, float32(1.3), res.RecommendedFreezingTemperature)
+	})
+	t.Run("Should return not found", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.products.On("Get", 9).Return(domain.Product{}, product.ErrNotFound)
+
+		_, err := pb.NewProductServiceClient(conn).GetProduct(context.TODO(), &pb.GetProductRequest{Id: 9})
+
+		assertCode(t, codes.NotFound, err)
+	})
+	t.Run("Should reject an invalid id", func(t *testing.T) {
+		conn, s := InitServer(t)
+
+		_, err := pb.NewProductServiceClient(conn).DeleteProduct(context.TODO(), &pb.DeleteProductRequest{Id: 0})
+
+		assertCode(t, codes.InvalidArgument, err)
+		s.products.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+	t.Run("Should list the products", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.products.On("GetAll").Return([]domain.Product{productExpected, {ID: 2}}, nil)
+
+		res, err := pb.NewProductServiceClient(conn).ListProducts(context.TODO(), &pb.ListProductsRequest{})
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Products, 2)
+	})
+	t.Run("Should create a product", func(t *testing.T) {
+		conn, s := InitServer(t)
+		input := productExpected
+		input.ID = 0
+		s.products.On("Save", input).Return(7, nil)
+
+		res, err := pb.NewProductServiceClient(conn).CreateProduct(context.TODO(), &pb.CreateProductRequest{Product: &pb.Product{
+			Id:                             99,
+			Description:                    "Yogurt",
+			ExpirationRate:                 1,
+			FreezingRate:                   2,
+			Height:                         6.4,
+			Length:                         4.5,
+			Netweight:                      3.4,
+			ProductCode:                    "PROD01",
+			RecommendedFreezingTemperature: 1.3,
+			Width:                          1.2,
+			ProductTypeId:                  1,
+			SellerId:                       1,
+		}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), res.Id)
+	})
+	t.Run("Should return already exists on a repeated product code", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.products.On("Save", mock.Anything).Return(0, product.ErrProductAlreadyExists)
+
+		req := &pb.CreateProductRequest{Product: &pb.Product{Description: "Yogurt", ExpirationRate: 1, FreezingRate: 2, Height: 1, Length: 1, Netweight: 1, ProductCode: "PROD01", RecommendedFreezingTemperature: 1, SellerId: 1}}
+		_, err := pb.NewProductServiceClient(conn).CreateProduct(context.TODO(), req)
+
+		assertCode(t, codes.AlreadyExists, err)
+	})
+	t.Run("Should reject a product without the required fields", func(t *testing.T) {
+		conn, s := InitServer(t)
+
+		_, err := pb.NewProductServiceClient(conn).CreateProduct(context.TODO(), &pb.CreateProductRequest{})
+
+		assertCode(t, codes.InvalidArgument, err)
+		s.products.AssertNotCalled(t, "Save", mock.Anything)
+	})
+	t.Run("Should return not found when updating a missing product", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.products.On("Update", mock.Anything, 1, replacementOf(t, productExpected)).Return(domain.Product{}, product.ErrNotFound)
+
+		_, err := pb.NewProductServiceClient(conn).UpdateProduct(context.TODO(), &pb.UpdateProductRequest{Product: &pb.Product{
+			Id:                             1,
+			Description:                    "Yogurt",
+			ExpirationRate:                 1,
+			FreezingRate:                   2,
+			Height:                         6.4,
+			Length:                         4.5,
+			Netweight:                      3.4,
+			ProductCode:                    "PROD01",
+			RecommendedFreezingTemperature: 1.3,
+			Width:                          1.2,
+			ProductTypeId:                  1,
+			SellerId:                       1,
+		}})
+
+		assertCode(t, codes.NotFound, err)
+	})
+}
@@ -0,0 +1,132 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/buyer"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var purchaseOrderCodes = errorCodes{
+	purchase_orders.ErrNotFound:           codes.NotFound,
+	purchase_orders.ErrInvalidReference:   codes.FailedPrecondition,
+	purchase_orders.ErrTrackingCodeExists: codes.AlreadyExists,
+	purchase_orders.ErrUnknownStrategy:    codes.InvalidArgument,
+	purchase_orders.ErrNoDestination:      codes.FailedPrecondition,
+	purchase_orders.ErrNoWarehouse:        codes.FailedPrecondition,
+	purchase_orders.ErrInsufficientStock:  codes.FailedPrecondition,
+	purchase_orders.ErrNoCarrier:          codes.FailedPrecondition,
+	purchase_orders.ErrCarrierNotFound:    codes.FailedPrecondition,
+}
+
+type PurchaseOrderServer struct {
+	pb.UnimplementedPurchaseOrderServiceServer
+	purchaseOrdersService purchase_orders.Service
+	buyerService          buyer.Service
+}
+
+func NewPurchaseOrder(s purchase_orders.Service, bs buyer.Service) *PurchaseOrderServer {
+	return &PurchaseOrderServer{
+		purchaseOrdersService: s,
+		buyerService:          bs,
+	}
+}
+
+func (po *PurchaseOrderServer) GetPurchaseOrder(ctx context.Context, req *pb.GetPurchaseOrderRequest) (*pb.PurchaseOrder, error) {
+	if req.Id <= 0 {
+		return nil, invalidID(req.Id)
+	}
+	result, err := po.purchaseOrdersService.Get(ctx, int(req.Id))
+	if err != nil {
+		return nil, purchaseOrderCodes.status(err)
+	}
+	return purchaseOrderToPB(result), nil
+}
+
+func (po *PurchaseOrderServer) ListPurchaseOrders(ctx context.Context, _ *pb.ListPurchaseOrdersRequest) (*pb.ListPurchaseOrdersResponse, error) {
+	orders, err := po.purchaseOrdersService.GetAll(ctx)
+	if err != nil {
+		return nil, purchaseOrderCodes.status(err)
+	}
+	res := &pb.ListPurchaseOrdersResponse{PurchaseOrders: make([]*pb.PurchaseOrder, 0, len(orders))}
+	for _, result := range orders {
+		res.PurchaseOrders = append(res.PurchaseOrders, purchaseOrderToPB(result))
+	}
+	return res, nil
+}
+
+func (po *PurchaseOrderServer) CreatePurchaseOrder(ctx context.Context, req *pb.CreatePurchaseOrderRequest) (*pb.PurchaseOrder, error) {
+	input, err := purchaseOrderFromPB(req.PurchaseOrder)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	input.ID = 0
+	if input.OrderNumber == "" || input.OrderDate.IsZero() || input.TrackingCode == "" || input.BuyerID == 0 || input.ProductRecordID == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid body")
+	}
+	if err := po.buyerService.ExistsID(ctx, input.BuyerID); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	order, err := po.purchaseOrdersService.Create(ctx, input)
+	if err != nil {
+		return nil, purchaseOrderCodes.status(err)
+	}
+	return purchaseOrderToPB(order), nil
+}
+
+func (po *PurchaseOrderServer) AssignPurchaseOrder(ctx context.Context, req *pb.AssignPurchaseOrderRequest) (*pb.PurchaseOrderAssignment, error) {
+	if req.Id <= 0 {
+		return nil, invalidID(req.Id)
+	}
+	assignment, err := po.purchaseOrdersService.Assign(ctx, int(req.Id), domain.PurchaseOrderAssignmentRequest{
+		Strategy:    req.Strategy,
+		WarehouseID: int(req.WarehouseId),
+		CarrierID:   int(req.CarrierId),
+	})
+	if err != nil {
+		return nil, purchaseOrderCodes.status(err)
+	}
+	return &pb.PurchaseOrderAssignment{
+		OrderId:     int64(assignment.OrderID),
+		WarehouseId: int64(assignment.WarehouseID),
+		CarrierId:   int64(assignment.CarrierID),
+		Strategy:    assignment.Strategy,
+	}, nil
+}
+
+func purchaseOrderToPB(o domain.PurchaseOrders) *pb.PurchaseOrder {
+	return &pb.PurchaseOrder{
+		Id:              int64(o.ID),
+		OrderNumber:     o.OrderNumber,
+		OrderDate:       date(o.OrderDate),
+		TrackingCode:    o.TrackingCode,
+		BuyerId:         int64(o.BuyerID),
+		ProductRecordId: int64(o.ProductRecordID),
+		OrderStatusId:   int64(o.OrderStatusID),
+		CarrierId:       int64(o.CarrierID),
+		WarehouseId:     int64(o.WarehouseID),
+	}
+}
+
+func purchaseOrderFromPB(o *pb.PurchaseOrder) (domain.PurchaseOrders, error) {
+	orderDate, err := parseDate("order_date", o.GetOrderDate())
+	if err != nil {
+		return domain.PurchaseOrders{}, err
+	}
+	return domain.PurchaseOrders{
+		ID:              int(o.GetId()),
+		OrderNumber:     o.GetOrderNumber(),
+		OrderDate:       orderDate,
+		TrackingCode:    o.GetTrackingCode(),
+		BuyerID:         int(o.GetBuyerId()),
+		ProductRecordID: int(o.GetProductRecordId()),
+		OrderStatusID:   int(o.GetOrderStatusId()),
+		CarrierID:       int(o.GetCarrierId()),
+		WarehouseID:     int(o.GetWarehouseId()),
+	}, nil
+}
@@ -0,0 +1,106 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+)
+
+var purchaseOrderExpected = domain.PurchaseOrders{
+	OrderNumber:     "ORD-1",
+	OrderDate:       datetime.MustParse("2024-02-01"),
+	TrackingCode:    "TRK-1",
+	BuyerID:         1,
+	ProductRecordID: 2,
+	OrderStatusID:   1,
+}
+
+func purchaseOrderMessage() *pb.PurchaseOrder {
+	return &pb.PurchaseOrder{
+		OrderNumber:     "ORD-1",
+		OrderDate:       "2024-02-01",
+		TrackingCode:    "TRK-1",
+		BuyerId:         1,
+		ProductRecordId: 2,
+		OrderStatusId:   1,
+	}
+}
+
+func TestPurchaseOrderServer(t *testing.T) {
+	t.Run("Should get an order", func(t *testing.T) {
+		conn, s := InitServer(t)
+		order := purchaseOrderExpected
+		order.ID, order.CarrierID = 5, 3
+		s.purchaseOrders.On("Get", mock.Anything, 5).Return(order, nil)
+
+		res, err := pb.NewPurchaseOrderServiceClient(conn).GetPurchaseOrder(context.TODO(), &pb.GetPurchaseOrderRequest{Id: 5})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-02-01", res.OrderDate)
+		assert.Equal(t, int64(3), res.CarrierId)
+	})
+	t.Run("Should create an order of an existing buyer", func(t *testing.T) {
+		conn, s := InitServer(t)
+		created := purchaseOrderExpected
+		created.ID = 5
+		s.buyers.On("ExistsID", mock.Anything, 1).Return(nil)
+		s.purchaseOrders.On("Create", mock.Anything, purchaseOrderExpected).Return(created, nil)
+
+		res, err := pb.NewPurchaseOrderServiceClient(conn).CreatePurchaseOrder(context.TODO(), &pb.CreatePurchaseOrderRequest{PurchaseOrder: purchaseOrderMessage()})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), res.Id)
+	})
+	t.Run("Should fail the precondition when the buyer does not exist", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.buyers.On("ExistsID", mock.Anything, 1).Return(errors.New("buyer not exists"))
+
+		_, err := pb.NewPurchaseOrderServiceClient(conn).CreatePurchaseOrder(context.TODO(), &pb.CreatePurchaseOrderRequest{PurchaseOrder: purchaseOrderMessage()})
+
+		assertCode(t, codes.FailedPrecondition, err)
+	})
+	t.Run("Should return already exists on a repeated tracking code", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.buyers.On("ExistsID", mock.Anything, 1).Return(nil)
+		s.purchaseOrders.On("Create", mock.Anything, mock.Anything).Return(domain.PurchaseOrders{}, purchase_orders.ErrTrackingCodeExists)
+
+		_, err := pb.NewPurchaseOrderServiceClient(conn).CreatePurchaseOrder(context.TODO(), &pb.CreatePurchaseOrderRequest{PurchaseOrder: purchaseOrderMessage()})
+
+		assertCode(t, codes.AlreadyExists, err)
+	})
+	t.Run("Should assign an order", func(t *testing.T) {
+		conn, s := InitServer(t)
+		req := domain.PurchaseOrderAssignmentRequest{Strategy: purchase_orders.StrategyRoundRobin, CarrierID: 2}
+		s.purchaseOrders.On("Assign", mock.Anything, 5, req).Return(domain.PurchaseOrderAssignment{OrderID: 5, WarehouseID: 1, CarrierID: 2, Strategy: purchase_orders.StrategyRoundRobin}, nil)
+
+		res, err := pb.NewPurchaseOrderServiceClient(conn).AssignPurchaseOrder(context.TODO(), &pb.AssignPurchaseOrderRequest{Id: 5, Strategy: purchase_orders.StrategyRoundRobin, CarrierId: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), res.WarehouseId)
+		assert.Equal(t, purchase_orders.StrategyRoundRobin, res.Strategy)
+	})
+	t.Run("Should map the assignment errors", func(t *testing.T) {
+		tests := map[error]codes.Code{
+			purchase_orders.ErrNotFound:        codes.NotFound,
+			purchase_orders.ErrUnknownStrategy: codes.InvalidArgument,
+			purchase_orders.ErrNoWarehouse:     codes.FailedPrecondition,
+			purchase_orders.ErrNoDestination:   codes.FailedPrecondition,
+		}
+		for serviceErr, code := range tests {
+			conn, s := InitServer(t)
+			s.purchaseOrders.On("Assign", mock.Anything, 5, mock.Anything).Return(domain.PurchaseOrderAssignment{}, serviceErr)
+
+			_, err := pb.NewPurchaseOrderServiceClient(conn).AssignPurchaseOrder(context.TODO(), &pb.AssignPurchaseOrderRequest{Id: 5})
+
+			assertCode(t, code, err)
+		}
+	})
+}
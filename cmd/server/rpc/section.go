@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Sections are reported missing with the error of either package.
+var sectionCodes = errorCodes{
+	section.ErrNotFound:     codes.NotFound,
+	section.ErrInvalidBody:  codes.InvalidArgument,
+	domain.ErrNotFound:      codes.NotFound,
+	domain.ErrAlreadyExists: codes.AlreadyExists,
+}
+
+type SectionServer struct {
+	pb.UnimplementedSectionServiceServer
+	sectionService section.Service
+}
+
+func NewSection(s section.Service) *SectionServer {
+	return &SectionServer{
+		sectionService: s,
+	}
+}
+
+func (s *SectionServer) GetSection(ctx context.Context, req *pb.GetSectionRequest) (*pb.Section, error) {
+	if req.Id <= 0 {
+		return nil, invalidID(req.Id)
+	}
+	result, err := s.sectionService.Get(ctx, int(req.Id))
+	if err != nil {
+		return nil, sectionCodes.status(err)
+	}
+	return sectionToPB(result), nil
+}
+
+func (s *SectionServer) ListSections(ctx context.Context, _ *pb.ListSectionsRequest) (*pb.ListSectionsResponse, error) {
+	sections, err := s.sectionService.GetAll(ctx)
+	if err != nil {
+		return nil, sectionCodes.status(err)
+	}
+	res := &pb.ListSectionsResponse{Sections: make([]*pb.Section, 0, len(sections))}
+	for _, result := range sections {
+		res.Sections = append(res.Sections, sectionToPB(result))
+	}
+	return res, nil
+}
+
+func (s *SectionServer) CreateSection(ctx context.Context, req *pb.CreateSectionRequest) (*pb.Section, error) {
+	input := sectionFromPB(req.Section)
+	if err := validateSection(input); err != nil {
+		return nil, err
+	}
+	input.ID = 0
+
+	id, err := s.sectionService.Save(ctx, input)
+	if err != nil {
+		return nil, sectionCodes.status(err)
+	}
+	input.ID = id
+	input.CurrentCapacity = 0
+	return sectionToPB(input), nil
+}
+
+func (s *SectionServer) UpdateSection(ctx context.Context, req *pb.UpdateSectionRequest) (*pb.Section, error) {
+	input := sectionFromPB(req.Section)
+	if input.ID <= 0 {
+		return nil, invalidID(int64(input.ID))
+	}
+	if err := validateSection(input); err != nil {
+		return nil, err
+	}
+
+	patch, err := replacement(input)
+	if err != nil {
+		return nil, sectionCodes.status(err)
+	}
+	updated, err := s.sectionService.Update(ctx, input.ID, patch)
+	if err != nil {
+		return nil, sectionCodes.status(err)
+	}
+	return sectionToPB(updated), nil
+}
+
+func (s *SectionServer) DeleteSection(ctx context.Context, req *pb.DeleteSectionRequest) (*emptypb.Empty, error) {
+	if req.Id <= 0 {
+		return nil, invalidID(req.Id)
+	}
+	if err := s.sectionService.Delete(ctx, int(req.Id)); err != nil {
+		return nil, sectionCodes.status(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// validateSection requires the fields the REST API requires.
+func validateSection(s domain.Section) error {
+	switch {
+	case s.SectionNumber == 0:
+		return status.Error(codes.InvalidArgument, "invalid section_number field")
+	case s.CurrentTemperature == 0:
+		return status.Error(codes.InvalidArgument, "invalid current_temperature field")
+	case s.MinimumTemperature == 0:
+		return status.Error(codes.InvalidArgument, "invalid minimum_temperature field")
+	case s.MinimumCapacity == 0:
+		return status.Error(codes.InvalidArgument, "invalid minimum_capacity field")
+	case s.MaximumCapacity == 0:
+		return status.Error(codes.InvalidArgument, "invalid maximum_capacity field")
+	case s.WarehouseID == 0:
+		return status.Error(codes.InvalidArgument, "invalid warehouse_id field")
+	case s.ProductTypeID == 0:
+		return status.Error(codes.InvalidArgument, "invalid product_type_id field")
+	}
+	return nil
+}
+
+func sectionToPB(s domain.Section) *pb.Section {
+	return &pb.Section{
+		Id:                 int64(s.ID),
+		SectionNumber:      int64(s.SectionNumber),
+		CurrentTemperature: int64(s.CurrentTemperature),
+		MinimumTemperature: int64(s.MinimumTemperature),
+		CurrentCapacity:    int64(s.CurrentCapacity),
+		MinimumCapacity:    int64(s.MinimumCapacity),
+		MaximumCapacity:    int64(s.MaximumCapacity),
+		WarehouseId:        int64(s.WarehouseID),
+		ProductTypeId:      int64(s.ProductTypeID),
+	}
+}
+
+func sectionFromPB(s *pb.Section) domain.Section {
+	return domain.Section{
+		ID:                 int(s.GetId()),
+		SectionNumber:      int(s.GetSectionNumber()),
+		CurrentTemperature: int(s.GetCurrentTemperature()),
+		MinimumTemperature: int(s.GetMinimumTemperature()),
+		CurrentCapacity:    int(s.GetCurrentCapacity()),
+		MinimumCapacity:    int(s.GetMinimumCapacity()),
+		MaximumCapacity:    int(s.GetMaximumCapacity()),
+		WarehouseID:        int(s.GetWarehouseId()),
+		ProductTypeID:      int(s.GetProductTypeId()),
+	}
+}
@@ -0,0 +1,101 @@
+package rpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+var sectionExpected = domain.Section{
+	ID:                 1,
+	SectionNumber:      10,
+	CurrentTemperature: 4,
+	MinimumTemperature: 2,
+	CurrentCapacity:    20,
+	MinimumCapacity:    5,
+	MaximumCapacity:    50,
+	WarehouseID:        1,
+	ProductTypeID:      1,
+}
+
+func sectionMessage(id int64) *pb.Section {
+	return &pb.Section{
+		Id:                 id,
+		SectionNumber:      10,
+		CurrentTemperature: 4,
+		MinimumTemperature: 2,
+		CurrentCapacity:    20,
+		MinimumCapacity:    5,
+		MaximumCapacity:    50,
+		WarehouseId:        1,
+		ProductTypeId:      1,
+	}
+}
+
+func TestSectionServer(t *testing.T) {
+	t.Run("Should get a section", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sections.On("Get", 1).Return(sectionExpected, nil)
+
+		res, err := pb.NewSectionServiceClient(conn).GetSection(context.TODO(), &pb.GetSectionRequest{Id: 1})
+
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(sectionMessage(1), res), res)
+	})
+	t.Run("Should return not found with the error of either package", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sections.On("Get", 9).Return(domain.Section{}, domain.ErrNotFound)
+		s.sections.On("Delete", 9).Return(section.ErrNotFound)
+
+		_, err := pb.NewSectionServiceClient(conn).GetSection(context.TODO(), &pb.GetSectionRequest{Id: 9})
+		assertCode(t, codes.NotFound, err)
+
+		_, err = pb.NewSectionServiceClient(conn).DeleteSection(context.TODO(), &pb.DeleteSectionRequest{Id: 9})
+		assertCode(t, codes.NotFound, err)
+	})
+	t.Run("Should create a section", func(t *testing.T) {
+		conn, s := InitServer(t)
+		input := sectionExpected
+		input.ID = 0
+		s.sections.On("Save", input).Return(5, nil)
+
+		res, err := pb.NewSectionServiceClient(conn).CreateSection(context.TODO(), &pb.CreateSectionRequest{Section: sectionMessage(0)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), res.Id)
+	})
+	t.Run("Should return already exists", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sections.On("Save", mock.Anything).Return(0, domain.ErrAlreadyExists)
+
+		_, err := pb.NewSectionServiceClient(conn).CreateSection(context.TODO(), &pb.CreateSectionRequest{Section: sectionMessage(0)})
+
+		assertCode(t, codes.AlreadyExists, err)
+	})
+	t.Run("Should reject a section without the required fields", func(t *testing.T) {
+		conn, s := InitServer(t)
+		message := sectionMessage(1)
+		message.WarehouseId = 0
+
+		_, err := pb.NewSectionServiceClient(conn).UpdateSection(context.TODO(), &pb.UpdateSectionRequest{Section: message})
+
+		assertCode(t, codes.InvalidArgument, err)
+		s.sections.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Should update a section", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sections.On("Update", mock.Anything, 1, replacementOf(t, sectionExpected)).Return(sectionExpected, nil)
+
+		res, err := pb.NewSectionServiceClient(conn).UpdateSection(context.TODO(), &pb.UpdateSectionRequest{Section: sectionMessage(1)})
+
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(sectionMessage(1), res))
+	})
+}
@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/locality"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var sellerCodes = errorCodes{
+	seller.ErrNotFound:         codes.NotFound,
+	seller.ErrCidAlreadyExists: codes.AlreadyExists,
+	seller.ErrInvalidBody:      codes.InvalidArgument,
+	seller.ErrVersionMismatch:  codes.Aborted,
+}
+
+type SellerServer struct {
+	pb.UnimplementedSellerServiceServer
+	sellerService   seller.Service
+	localityService locality.Service
+}
+
+func NewSeller(s seller.Service, l locality.Service) *SellerServer {
+	return &SellerServer{
+		sellerService:   s,
+		localityService: l,
+	}
+}
+
+func (s *SellerServer) GetSeller(ctx context.Context, req *pb.GetSellerRequest) (*pb.Seller, error) {
+	if req.Id <= 0 {
+		return nil, invalidID(req.Id)
+	}
+	result, err := s.sellerService.Get(ctx, int(req.Id))
+	if err != nil {
+		return nil, sellerCodes.status(err)
+	}
+	return sellerToPB(result), nil
+}
+
+func (s *SellerServer) ListSellers(ctx context.Context, _ *pb.ListSellersRequest) (*pb.ListSellersResponse, error) {
+	sellers, err := s.sellerService.GetAll(ctx)
+	if err != nil {
+		return nil, sellerCodes.status(err)
+	}
+	res := &pb.ListSellersResponse{Sellers: make([]*pb.Seller, 0, len(sellers))}
+	for _, result := range sellers {
+		res.Sellers = append(res.Sellers, sellerToPB(result))
+	}
+	return res, nil
+}
+
+func (s *SellerServer) CreateSeller(ctx context.Context, req *pb.CreateSellerRequest) (*pb.Seller, error) {
+	input := sellerFromPB(req.Seller)
+	input.ID = 0
+	switch {
+	case input.CID == 0:
+		return nil, status.Error(codes.InvalidArgument, "cid is required")
+	case input.CompanyName == "":
+		return nil, status.Error(codes.InvalidArgument, "company name is required")
+	case input.Address == "":
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	case input.Telephone == "":
+		return nil, status.Error(codes.InvalidArgument, "phone is required")
+	case input.LocalityId == 0:
+		return nil, status.Error(codes.InvalidArgument, "locality id is required")
+	}
+	if err := s.localityService.ExistsById(ctx, input.LocalityId); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	saved, err := s.sellerService.Save(ctx, input)
+	if err != nil {
+		return nil, sellerCodes.status(err)
+	}
+	return sellerToPB(saved), nil
+}
+
+func (s *SellerServer) UpdateSeller(ctx context.Context, req *pb.UpdateSellerRequest) (*pb.Seller, error) {
+	input := sellerFromPB(req.Seller)
+	if input.ID <= 0 {
+		return nil, invalidID(int64(input.ID))
+	}
+
+	patch, err := replacement(input)
+	if err != nil {
+		return nil, sellerCodes.status(err)
+	}
+	updated, err := s.sellerService.Update(ctx, input.ID, patch)
+	if err != nil {
+		return nil, sellerCodes.status(err)
+	}
+	return sellerToPB(updated), nil
+}
+
+func (s *SellerServer) DeleteSeller(ctx context.Context, req *pb.DeleteSellerRequest) (*emptypb.Empty, error) {
+	if req.Id <= 0 {
+		return nil, invalidID(req.Id)
+	}
+	if err := s.sellerService.Delete(ctx, int(req.Id)); err != nil {
+		return nil, sellerCodes.status(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func sellerToPB(s domain.Seller) *pb.Seller {
+	return &pb.Seller{
+		Id:          int64(s.ID),
+		Cid:         int64(s.CID),
+		CompanyName: s.CompanyName,
+		Address:     s.Address,
+		Telephone:   s.Telephone,
+		LocalityId:  int64(s.LocalityId),
+	}
+}
+
+func sellerFromPB(s *pb.Seller) domain.Seller {
+	return domain.Seller{
+		ID:          int(s.GetId()),
+		CID:         int(s.GetCid()),
+		CompanyName: s.GetCompanyName(),
+		Address:     s.GetAddress(),
+		Telephone:   s.GetTelephone(),
+		LocalityId:  int(s.GetLocalityId()),
+	}
+}
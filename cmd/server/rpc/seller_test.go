@@ -0,0 +1,109 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/locality"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+)
+
+var sellerExpected = domain.Seller{
+	ID:          1,
+	CID:         10,
+	CompanyName: "Meli",
+	Address:     "Rua Feliciano",
+	Telephone:   "48999999",
+	LocalityId:  2,
+}
+
+func TestSellerServer(t *testing.T) {
+	t.Run("Should get a seller", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sellers.On("Get", mock.Anything, 1).Return(sellerExpected, nil)
+
+		res, err := pb.NewSellerServiceClient(conn).GetSeller(context.TODO(), &pb.GetSellerRequest{Id: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Meli", res.CompanyName)
+		assert.Equal(t, int64(2), res.LocalityId)
+	})
+	t.Run("Should return not found", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sellers.On("Get", mock.Anything, 9).Return(domain.Seller{}, seller.ErrNotFound)
+
+		_, err := pb.NewSellerServiceClient(conn).GetSeller(context.TODO(), &pb.GetSellerRequest{Id: 9})
+
+		assertCode(t, codes.NotFound, err)
+	})
+	t.Run("Should create a seller in an existing locality", func(t *testing.T) {
+		conn, s := InitServer(t)
+		input := sellerExpected
+		input.ID = 0
+		s.localities.On("ExistsById", mock.Anything, 2).Return(nil)
+		s.sellers.On("Save", mock.Anything, input).Return(sellerExpected, nil)
+
+		res, err := pb.NewSellerServiceClient(conn).CreateSeller(context.TODO(), &pb.CreateSellerRequest{Seller: &pb.Seller{
+			Cid: 10, CompanyName: "Meli", Address: "Rua Feliciano", Telephone: "48999999", LocalityId: 2,
+		}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), res.Id)
+	})
+	t.Run("Should fail the precondition when the locality does not exist", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.localities.On("ExistsById", mock.Anything, 2).Return(locality.ErrLocalityNotExists)
+
+		_, err := pb.NewSellerServiceClient(conn).CreateSeller(context.TODO(), &pb.CreateSellerRequest{Seller: &pb.Seller{
+			Cid: 10, CompanyName: "Meli", Address: "Rua Feliciano", Telephone: "48999999", LocalityId: 2,
+		}})
+
+		assertCode(t, codes.FailedPrecondition, err)
+		s.sellers.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+	t.Run("Should reject a seller without cid", func(t *testing.T) {
+		conn, _ := InitServer(t)
+
+		_, err := pb.NewSellerServiceClient(conn).CreateSeller(context.TODO(), &pb.CreateSellerRequest{Seller: &pb.Seller{CompanyName: "Meli"}})
+
+		assertCode(t, codes.InvalidArgument, err)
+	})
+	t.Run("Should return already exists on a repeated cid", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sellers.On("Update", mock.Anything, 1, replacementOf(t, domain.Seller{ID: 1, CID: 11})).Return(domain.Seller{}, seller.ErrCidAlreadyExists)
+
+		_, err := pb.NewSellerServiceClient(conn).UpdateSeller(context.TODO(), &pb.UpdateSellerRequest{Seller: &pb.Seller{Id: 1, Cid: 11}})
+
+		assertCode(t, codes.AlreadyExists, err)
+	})
+	t.Run("Should abort an update that lost a concurrent write", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sellers.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Seller{}, seller.ErrVersionMismatch)
+
+		_, err := pb.NewSellerServiceClient(conn).UpdateSeller(context.TODO(), &pb.UpdateSellerRequest{Seller: &pb.Seller{Id: 1, Cid: 11}})
+
+		assertCode(t, codes.Aborted, err)
+	})
+	t.Run("Should delete a seller", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sellers.On("Delete", mock.Anything, 1).Return(nil)
+
+		_, err := pb.NewSellerServiceClient(conn).DeleteSeller(context.TODO(), &pb.DeleteSellerRequest{Id: 1})
+
+		assert.NoError(t, err)
+	})
+	t.Run("Should return internal on an unknown error", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sellers.On("Delete", mock.Anything, 1).Return(errors.New("deadlock"))
+
+		_, err := pb.NewSellerServiceClient(conn).DeleteSeller(context.TODO(), &pb.DeleteSellerRequest{Id: 1})
+
+		assertCode(t, codes.Internal, err)
+	})
+}
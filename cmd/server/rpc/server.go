@@ -0,0 +1,148 @@
+// Package rpc serves the services of the internal packages over gRPC, next
+// to the REST API of the handler package. The messages and services are
+// defined in the proto directory and generated into pkg/pb.
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/buyer"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/locality"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
+	productbatch "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultAddress is the address the server listens on when GRPC_ADDRESS is
+// not set.
+const DefaultAddress = ":9090"
+
+// errInternal is sent in place of the errors no code is known for, as their
+// message may reveal details of the database.
+const errInternal = "internal error"
+
+// AddressFromEnv returns the address to listen on, from GRPC_ADDRESS.
+func AddressFromEnv() string {
+	if address := os.Getenv("GRPC_ADDRESS"); address != "" {
+		return address
+	}
+	return DefaultAddress
+}
+
+// Services are the services the server calls. Buyers and localities are
+// only used to check the references of new orders and sellers.
+type Services struct {
+	Products       product.Service
+	Sellers        seller.Service
+	Localities     locality.Service
+	Warehouses     warehouse.Service
+	Sections       section.Service
+	Batches        productbatch.Service
+	PurchaseOrders purchase_orders.Service
+	Buyers         buyer.Service
+}
+
+// NewServices builds the services on top of the database, as the routes of
+// the REST API do.
+func NewServices(db *sql.DB) Services {
+	sectionRepo := section.NewRepository(db)
+	batchRepo := productbatch.NewRepository(db, productbatch.Querys{})
+	productRepo := product.NewRepository(db)
+	return Services{
+		Products:       product.NewService(productRepo),
+		Sellers:        seller.NewService(seller.NewRepository(db)),
+		Localities:     locality.NewService(locality.NewRepository(db)),
+		Warehouses:     warehouse.NewService(warehouse.NewRepository(db), sectionRepo, batchRepo),
+		Sections:       section.NewService(sectionRepo, productRepo),
+		Batches:        productbatch.NewService(batchRepo),
+		PurchaseOrders: purchase_orders.NewService(purchase_orders.NewRepository(db)),
+		Buyers:         buyer.NewService(buyer.NewRepository(db), nil),
+	}
+}
+
+// NewServer returns a server with every service registered, along with the
+// health and reflection services.
+func NewServer(s Services) *grpc.Server {
+	server := grpc.NewServer()
+	pb.RegisterProductServiceServer(server, NewProduct(s.Products))
+	pb.RegisterSellerServiceServer(server, NewSeller(s.Sellers, s.Localities))
+	pb.RegisterWarehouseServiceServer(server, NewWarehouse(s.Warehouses))
+	pb.RegisterSectionServiceServer(server, NewSection(s.Sections))
+	pb.RegisterProductBatchServiceServer(server, NewProductBatch(s.Batches, s.Products, s.Sections))
+	pb.RegisterPurchaseOrderServiceServer(server, NewPurchaseOrder(s.PurchaseOrders, s.Buyers))
+
+	healthServer := health.NewServer()
+	for name := range server.GetServiceInfo() {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	return server
+}
+
+// errorCodes maps the errors of a package to the gRPC codes they are sent with.
+type errorCodes map[error]codes.Code
+
+// status returns err as a gRPC status error: with its code when it wraps an
+// error of the map or a context error, and as an internal error otherwise.
+func (m errorCodes) status(err error) error {
+	for target, code := range m {
+		if errors.Is(err, target) {
+			return status.Error(code, err.Error())
+		}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, errInternal)
+}
+
+// replacement is a merge patch setting every field of a resource to the one
+// of v. Update requests carry whole messages, so fields left unset in them are
+// stored as zero instead of being left unchanged.
+func replacement(v interface{}) (domain.Patch, error) {
+	document, err := json.Marshal(v)
+	return domain.Patch{Document: document}, err
+}
+
+// invalidID is returned for ids that cannot belong to any row.
+func invalidID(id int64) error {
+	return status.Errorf(codes.InvalidArgument, "invalid id %d", id)
+}
+
+// date formats t as the messages carry dates, empty when unset.
+func date(t datetime.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Date()
+}
+
+// parseDate parses a date of a message, leaving it unset when empty.
+func parseDate(field, value string) (datetime.Time, error) {
+	if value == "" {
+		return datetime.Time{}, nil
+	}
+	t, err := datetime.Parse(value)
+	if err != nil {
+		return datetime.Time{}, fmt.Errorf("invalid %s", field)
+	}
+	return t, nil
+}
@@ -0,0 +1,145 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/cmd/server/rpc"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	buyerMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/buyer"
+	productMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product"
+	batchMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product_batch"
+	orderMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/purchase_orders"
+	sectionMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/section"
+	sellerMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/seller"
+	warehouseMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/warehouse"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type services struct {
+	products       *productMocks.ProductServiceMock
+	sellers        *sellerMocks.SellerServiceMock
+	localities     *sellerMocks.LocalityServiceMock
+	warehouses     *warehouseMocks.WarehouseServiceMock
+	sections       *sectionMocks.SectionServiceMock
+	batches        *batchMocks.ProductBatchServiceMock
+	purchaseOrders *orderMocks.PurchaseOrdersServiceMock
+	buyers         *buyerMocks.BuyerServiceMock
+}
+
+// InitServer serves the mocked services on an in-memory connection and
+// returns a client connection to it.
+func InitServer(t *testing.T) (*grpc.ClientConn, services) {
+	t.Helper()
+	s := services{
+		products:       &productMocks.ProductServiceMock{},
+		sellers:        &sellerMocks.SellerServiceMock{},
+		localities:     &sellerMocks.LocalityServiceMock{},
+		warehouses:     &warehouseMocks.WarehouseServiceMock{},
+		sections:       &sectionMocks.SectionServiceMock{},
+		batches:        &batchMocks.ProductBatchServiceMock{},
+		purchaseOrders: &orderMocks.PurchaseOrdersServiceMock{},
+		buyers:         &buyerMocks.BuyerServiceMock{},
+	}
+	server := rpc.NewServer(rpc.Services{
+		Products:       s.products,
+		Sellers:        s.sellers,
+		Localities:     s.localities,
+		Warehouses:     s.warehouses,
+		Sections:       s.sections,
+		Batches:        s.batches,
+		PurchaseOrders: s.purchaseOrders,
+		Buyers:         s.buyers,
+	})
+
+	listener := bufconn.Listen(1024 * 1024)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, s
+}
+
+func assertCode(t *testing.T, expected codes.Code, err error) {
+	t.Helper()
+	assert.Error(t, err)
+	assert.Equal(t, expected, status.Code(err), err)
+}
+
+// replacementOf is the patch an update request carrying v is applied with.
+func replacementOf(t *testing.T, v interface{}) domain.Patch {
+	t.Helper()
+	document, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return domain.Patch{Document: document}
+}
+
+func TestServer(t *testing.T) {
+	t.Run("Should report every service as serving", func(t *testing.T) {
+		conn, _ := InitServer(t)
+		client := healthpb.NewHealthClient(conn)
+
+		for _, service := range []string{"", "melisprint.v1.ProductService", "melisprint.v1.PurchaseOrderService"} {
+			res, err := client.Check(context.TODO(), &healthpb.HealthCheckRequest{Service: service})
+			assert.NoError(t, err)
+			assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+		}
+	})
+	t.Run("Should list the services through reflection", func(t *testing.T) {
+		conn, _ := InitServer(t)
+		stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.TODO())
+		assert.NoError(t, err)
+
+		err = stream.Send(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{}})
+		assert.NoError(t, err)
+		res, err := stream.Recv()
+		assert.NoError(t, err)
+
+		var names []string
+		for _, service := range res.GetListServicesResponse().Service {
+			names = append(names, service.Name)
+		}
+		assert.Subset(t, names, []string{
+			"melisprint.v1.ProductService",
+			"melisprint.v1.SellerService",
+			"melisprint.v1.WarehouseService",
+			"melisprint.v1.SectionService",
+			"melisprint.v1.ProductBatchService",
+			"melisprint.v1.PurchaseOrderService",
+			"grpc.health.v1.Health",
+		})
+	})
+	t.Run("Should hide the message of unknown errors", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sections.On("GetAll").Return([]domain.Section{}, errors.New("dial tcp 10.0.0.1:3306: connection refused"))
+
+		_, err := pb.NewSectionServiceClient(conn).ListSections(context.TODO(), &pb.ListSectionsRequest{})
+
+		assertCode(t, codes.Internal, err)
+		assert.Equal(t, "internal error", status.Convert(err).Message())
+	})
+	t.Run("Should report context errors with their code", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.sections.On("GetAll").Return([]domain.Section{}, context.DeadlineExceeded)
+
+		_, err := pb.NewSectionServiceClient(conn).ListSections(context.TODO(), &pb.ListSectionsRequest{})
+
+		assertCode(t, codes.DeadlineExceeded, err)
+	})
+}
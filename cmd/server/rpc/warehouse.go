@@ -0,0 +1,135 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var warehouseCodes = errorCodes{
+	warehouse.ErrNotFound:        codes.NotFound,
+	warehouse.ErrAlredyExists:    codes.AlreadyExists,
+	warehouse.ErrInvalidBody:     codes.InvalidArgument,
+	warehouse.ErrVersionMismatch: codes.Aborted,
+}
+
+type WarehouseServer struct {
+	pb.UnimplementedWarehouseServiceServer
+	warehouseService warehouse.Service
+}
+
+func NewWarehouse(s warehouse.Service) *WarehouseServer {
+	return &WarehouseServer{
+		warehouseService: s,
+	}
+}
+
+func (w *WarehouseServer) GetWarehouse(ctx context.Context, req *pb.GetWarehouseRequest) (*pb.Warehouse, error) {
+	if req.Id <= 0 {
+		return nil, invalidID(req.Id)
+	}
+	result, err := w.warehouseService.Get(ctx, int(req.Id))
+	if err != nil {
+		return nil, warehouseCodes.status(err)
+	}
+	return warehouseToPB(result), nil
+}
+
+func (w *WarehouseServer) ListWarehouses(ctx context.Context, _ *pb.ListWarehousesRequest) (*pb.ListWarehousesResponse, error) {
+	warehouses, err := w.warehouseService.GetAll(ctx)
+	if err != nil {
+		return nil, warehouseCodes.status(err)
+	}
+	res := &pb.ListWarehousesResponse{Warehouses: make([]*pb.Warehouse, 0, len(warehouses))}
+	for _, result := range warehouses {
+		res.Warehouses = append(res.Warehouses, warehouseToPB(result))
+	}
+	return res, nil
+}
+
+func (w *WarehouseServer) CreateWarehouse(ctx context.Context, req *pb.CreateWarehouseRequest) (*pb.Warehouse, error) {
+	input := warehouseFromPB(req.Warehouse)
+	input.ID = 0
+	switch {
+	case input.Address == "":
+		return nil, status.Error(codes.InvalidArgument, "invalid address field")
+	case *input.MinimumCapacity <= 0:
+		return nil, status.Error(codes.InvalidArgument, "invalid minimum_capacity field")
+	case input.Telephone == "":
+		return nil, status.Error(codes.InvalidArgument, "invalid telephone field")
+	case input.WarehouseCode == "":
+		return nil, status.Error(codes.InvalidArgument, "invalid warehouse_code field")
+	}
+
+	saved, err := w.warehouseService.Save(ctx, input)
+	if err != nil {
+		return nil, warehouseCodes.status(err)
+	}
+	return warehouseToPB(saved), nil
+}
+
+func (w *WarehouseServer) UpdateWarehouse(ctx context.Context, req *pb.UpdateWarehouseRequest) (*pb.Warehouse, error) {
+	input := warehouseFromPB(req.Warehouse)
+	if input.ID <= 0 {
+		return nil, invalidID(int64(input.ID))
+	}
+
+	patch, err := replacement(input)
+	if err != nil {
+		return nil, warehouseCodes.status(err)
+	}
+	updated, err := w.warehouseService.Update(ctx, input.ID, patch)
+	if err != nil {
+		return nil, warehouseCodes.status(err)
+	}
+	return warehouseToPB(updated), nil
+}
+
+func (w *WarehouseServer) DeleteWarehouse(ctx context.Context, req *pb.DeleteWarehouseRequest) (*emptypb.Empty, error) {
+	if req.Id <= 0 {
+		return nil, invalidID(req.Id)
+	}
+	if err := w.warehouseService.Delete(ctx, int(req.Id)); err != nil {
+		return nil, warehouseCodes.status(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// warehouseToPB converts a warehouse to its message. Scalar fields cannot be
+// null in proto3, so unset minimums are sent as 0.
+func warehouseToPB(w domain.Warehouse) *pb.Warehouse {
+	res := &pb.Warehouse{
+		Id:            int64(w.ID),
+		Address:       w.Address,
+		Telephone:     w.Telephone,
+		WarehouseCode: w.WarehouseCode,
+		LocalityId:    int64(w.LocalityId),
+	}
+	if w.MinimumCapacity != nil {
+		res.MinimumCapacity = int64(*w.MinimumCapacity)
+	}
+	if w.MinimumTemperature != nil {
+		res.MinimumTemperature = *w.MinimumTemperature
+	}
+	return res
+}
+
+// warehouseFromPB converts a message to a warehouse. Its minimums are always
+// set, since a message cannot tell 0 from unset.
+func warehouseFromPB(w *pb.Warehouse) domain.Warehouse {
+	minimumCapacity, minimumTemperature := int(w.GetMinimumCapacity()), w.GetMinimumTemperature()
+	return domain.Warehouse{
+		ID:                 int(w.GetId()),
+		Address:            w.GetAddress(),
+		Telephone:          w.GetTelephone(),
+		WarehouseCode:      w.GetWarehouseCode(),
+		MinimumCapacity:    &minimumCapacity,
+		MinimumTemperature: &minimumTemperature,
+		LocalityId:         int(w.GetLocalityId()),
+	}
+}
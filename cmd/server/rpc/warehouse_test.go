@@ -0,0 +1,81 @@
+package rpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/pb"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+)
+
+var warehouseExpected = domain.Warehouse{
+	ID:                 1,
+	Address:            "Rua Pedro Dias",
+	Telephone:          "3551-3553",
+	WarehouseCode:      "W1",
+	MinimumCapacity:    testutil.Int(10),
+	MinimumTemperature: testutil.Float64(-2.5),
+	LocalityId:         3,
+}
+
+func TestWarehouseServer(t *testing.T) {
+	t.Run("Should get a warehouse", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.warehouses.On("Get", mock.Anything, 1).Return(warehouseExpected, nil)
+
+		res, err := pb.NewWarehouseServiceClient(conn).GetWarehouse(context.TODO(), &pb.GetWarehouseRequest{Id: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "W1", res.WarehouseCode)
+		assert.Equal(t, -2.5, res.MinimumTemperature)
+	})
+	t.Run("Should return not found", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.warehouses.On("Delete", mock.Anything, 9).Return(warehouse.ErrNotFound)
+
+		_, err := pb.NewWarehouseServiceClient(conn).DeleteWarehouse(context.TODO(), &pb.DeleteWarehouseRequest{Id: 9})
+
+		assertCode(t, codes.NotFound, err)
+	})
+	t.Run("Should create a warehouse", func(t *testing.T) {
+		conn, s := InitServer(t)
+		input := warehouseExpected
+		input.ID = 0
+		s.warehouses.On("Save", mock.Anything, input).Return(warehouseExpected, nil)
+
+		res, err := pb.NewWarehouseServiceClient(conn).CreateWarehouse(context.TODO(), &pb.CreateWarehouseRequest{Warehouse: &pb.Warehouse{
+			Address: "Rua Pedro Dias", Telephone: "3551-3553", WarehouseCode: "W1", MinimumCapacity: 10, MinimumTemperature: -2.5, LocalityId: 3,
+		}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), res.Id)
+	})
+	t.Run("Should reject a warehouse without address", func(t *testing.T) {
+		conn, _ := InitServer(t)
+
+		_, err := pb.NewWarehouseServiceClient(conn).CreateWarehouse(context.TODO(), &pb.CreateWarehouseRequest{Warehouse: &pb.Warehouse{WarehouseCode: "W1"}})
+
+		assertCode(t, codes.InvalidArgument, err)
+	})
+	t.Run("Should return already exists on a repeated code", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.warehouses.On("Update", mock.Anything, 1, replacementOf(t, domain.Warehouse{ID: 1, WarehouseCode: "W2", MinimumCapacity: testutil.Int(0), MinimumTemperature: testutil.Float64(0)})).Return(domain.Warehouse{}, warehouse.ErrAlredyExists)
+
+		_, err := pb.NewWarehouseServiceClient(conn).UpdateWarehouse(context.TODO(), &pb.UpdateWarehouseRequest{Warehouse: &pb.Warehouse{Id: 1, WarehouseCode: "W2"}})
+
+		assertCode(t, codes.AlreadyExists, err)
+	})
+	t.Run("Should abort an update that lost a concurrent write", func(t *testing.T) {
+		conn, s := InitServer(t)
+		s.warehouses.On("Update", mock.Anything, 1, mock.Anything).Return(domain.Warehouse{}, warehouse.ErrVersionMismatch)
+
+		_, err := pb.NewWarehouseServiceClient(conn).UpdateWarehouse(context.TODO(), &pb.UpdateWarehouseRequest{Warehouse: &pb.Warehouse{Id: 1, WarehouseCode: "W2"}})
+
+		assertCode(t, codes.Aborted, err)
+	})
+}
@@ -11,15 +11,17 @@ CREATE TABLE products(
   lenght FLOAT NOT NULL, netweight FLOAT NOT NULL, 
   product_code TEXT NOT NULL, recommended_freezing_temperature FLOAT NOT NULL, 
   width FLOAT NOT NULL, id_product_type INT NOT NULL, 
-  id_seller INT NOT NULL
+  id_seller INT NOT NULL, 
+  FULLTEXT (`description`, `product_code`)
 );
 
 DROP 
   TABLE IF EXISTS employees;
 CREATE TABLE employees(
   `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
-  card_number_id TEXT NOT NULL, first_name TEXT NOT NULL, 
-  last_name TEXT NOT NULL, warehouse_id INT NOT NULL
+  card_number_id VARCHAR(255) NOT NULL UNIQUE, first_name TEXT NOT NULL, 
+  last_name TEXT NOT NULL, warehouse_id INT NOT NULL, 
+  version INT NOT NULL DEFAULT 1
 );
 
 DROP 
@@ -57,6 +59,7 @@ CREATE TABLE warehouses(
   minimum_capacity INT NULL,
   minimum_temperature INT NULL,
   locality_id INT NOT NULL,
+  version INT NOT NULL DEFAULT 1,
   FOREIGN KEY(locality_id) REFERENCES `melisprint`.`localities` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 );
 
@@ -66,7 +69,7 @@ CREATE TABLE sections(
   `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
   section_number INT NOT NULL, current_temperature INT NOT NULL, 
   minimum_temperature INT NOT NULL, 
-  current_capacity INT NOT NULL, minimum_capacity INT NOT NULL, 
+  minimum_capacity INT NOT NULL, 
   maximum_capacity INT NOT NULL, warehouse_id INT NOT NULL, 
   id_product_type INT NOT NULL
 );
@@ -80,6 +83,7 @@ CREATE TABLE sellers(
   `address` TEXT NOT NULL, 
   telephone TEXT(15) NOT NULL,
   `locality_id` INT NOT NULL, 
+  version INT NOT NULL DEFAULT 1, 
   FOREIGN KEY (`locality_id`) REFERENCES `melisprint`.`localities` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 );
 
@@ -87,8 +91,10 @@ DROP
   TABLE IF EXISTS buyers;
 CREATE TABLE buyers(
   `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
-  card_number_id TEXT NOT NULL, first_name TEXT NOT NULL, 
-  last_name TEXT NOT NULL
+  card_number_id VARCHAR(255) NOT NULL UNIQUE, first_name TEXT NOT NULL, 
+  last_name TEXT NOT NULL, 
+  `locality_id` INT NULL, 
+  FOREIGN KEY (`locality_id`) REFERENCES `melisprint`.`localities` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 );
 
 DROP 
@@ -143,6 +149,7 @@ CREATE TABLE product_records(
   `last_update_date` DATETIME NOT NULL, 
   `purchase_price` DECIMAL(19, 2) NOT NULL, 
   `sale_price` DECIMAL(19, 2) NOT NULL, 
+  `currency` CHAR(3) NOT NULL DEFAULT 'USD', 
   `product_id` INT NOT NULL, 
   FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE NO ACTION ON UPDATE NO ACTION
 );
@@ -156,6 +163,17 @@ CREATE TABLE carriers(
   `address` VARCHAR(255) NOT NULL, 
   `telephone` VARCHAR(255) NOT NULL, 
   `locality_id` INT NOT NULL, 
+  `api_key_hash` CHAR(64) NULL UNIQUE, 
+  FOREIGN KEY(locality_id) REFERENCES localities(id) ON DELETE NO ACTION ON UPDATE NO ACTION
+);
+
+DROP 
+  TABLE IF EXISTS carrier_localities;
+CREATE TABLE carrier_localities(
+  `carrier_id` INT NOT NULL, 
+  `locality_id` INT NOT NULL, 
+  PRIMARY KEY (carrier_id, locality_id), 
+  FOREIGN KEY(carrier_id) REFERENCES carriers(id) ON DELETE CASCADE ON UPDATE NO ACTION, 
   FOREIGN KEY(locality_id) REFERENCES localities(id) ON DELETE NO ACTION ON UPDATE NO ACTION
 );
 
@@ -172,7 +190,7 @@ CREATE TABLE IF NOT EXISTS melisprint.purchase_orders (
   `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
   `order_number` VARCHAR(255) NOT NULL, 
   `order_date` DATETIME(6) NOT NULL, 
-  `tracking_code` VARCHAR(255) NOT NULL, 
+  `tracking_code` VARCHAR(255) NOT NULL UNIQUE, 
   `buyer_id` INT NOT NULL, 
   `carrier_id` INT NULL, 
   `order_status_id` INT NOT NULL, 
@@ -198,6 +216,78 @@ CREATE TABLE IF NOT EXISTS melisprint.order_details (
   FOREIGN KEY(product_record_id) REFERENCES product_records(id) ON DELETE NO ACTION ON UPDATE NO ACTION
 );
 
+DROP 
+  TABLE IF EXISTS shipment_events;
+CREATE TABLE IF NOT EXISTS `melisprint`.`shipment_events` (
+  `id` INT NOT NULL AUTO_INCREMENT, 
+  `purchase_order_id` INT NOT NULL, 
+  `carrier_id` INT NOT NULL, 
+  `event_type` VARCHAR(20) NOT NULL, 
+  `location` VARCHAR(255) NOT NULL, 
+  `occurred_at` DATETIME(6) NOT NULL, 
+  `recorded_at` DATETIME(6) NOT NULL, 
+  PRIMARY KEY (`id`), 
+  INDEX (`purchase_order_id`, `occurred_at`), 
+  FOREIGN KEY (`purchase_order_id`) REFERENCES `melisprint`.`purchase_orders` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION, 
+  FOREIGN KEY (`carrier_id`) REFERENCES `melisprint`.`carriers` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+);
+
+DROP 
+  TABLE IF EXISTS outbox_events;
+CREATE TABLE IF NOT EXISTS `melisprint`.`outbox_events` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT, 
+  `aggregate_type` VARCHAR(64) NOT NULL, 
+  `aggregate_id` INT NOT NULL, 
+  `event_type` VARCHAR(64) NOT NULL, 
+  `payload` TEXT NOT NULL, 
+  `occurred_at` DATETIME(6) NOT NULL, 
+  `published_at` DATETIME(6) NULL, 
+  `attempts` INT NOT NULL DEFAULT 0, 
+  `next_attempt_at` DATETIME(6) NULL, 
+  `last_error` VARCHAR(255) NULL, 
+  `dead_at` DATETIME(6) NULL, 
+  PRIMARY KEY (`id`), 
+  INDEX (`published_at`, `id`), 
+  INDEX (`aggregate_type`, `aggregate_id`, `id`)
+);
+
+DROP 
+  TABLE IF EXISTS webhook_deliveries;
+DROP 
+  TABLE IF EXISTS webhook_events;
+DROP 
+  TABLE IF EXISTS webhooks;
+CREATE TABLE IF NOT EXISTS `melisprint`.`webhooks` (
+  `id` INT NOT NULL AUTO_INCREMENT, 
+  `url` VARCHAR(2048) NOT NULL, 
+  `secret` CHAR(64) NOT NULL, 
+  `created_at` DATETIME(6) NOT NULL, 
+  PRIMARY KEY (`id`)
+);
+CREATE TABLE IF NOT EXISTS `melisprint`.`webhook_events` (
+  `webhook_id` INT NOT NULL, 
+  `event_type` VARCHAR(64) NOT NULL, 
+  PRIMARY KEY (`webhook_id`, `event_type`), 
+  INDEX (`event_type`), 
+  FOREIGN KEY (`webhook_id`) REFERENCES `melisprint`.`webhooks` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+CREATE TABLE IF NOT EXISTS `melisprint`.`webhook_deliveries` (
+  `id` INT NOT NULL AUTO_INCREMENT, 
+  `webhook_id` INT NOT NULL, 
+  `event_type` VARCHAR(64) NOT NULL, 
+  `payload` TEXT NOT NULL, 
+  `status` VARCHAR(20) NOT NULL, 
+  `attempts` INT NOT NULL DEFAULT 0, 
+  `next_attempt_at` DATETIME(6) NOT NULL, 
+  `last_error` VARCHAR(255) NULL, 
+  `response_status` INT NULL, 
+  `created_at` DATETIME(6) NOT NULL, 
+  `delivered_at` DATETIME(6) NULL, 
+  PRIMARY KEY (`id`), 
+  INDEX (`status`, `next_attempt_at`), 
+  FOREIGN KEY (`webhook_id`) REFERENCES `melisprint`.`webhooks` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
 DROP 
   TABLE IF EXISTS inbound_orders;
 CREATE TABLE IF NOT EXISTS `melisprint`.`inbound_orders` (
@@ -213,6 +303,55 @@ CREATE TABLE IF NOT EXISTS `melisprint`.`inbound_orders` (
   FOREIGN KEY (`warehouse_id`) REFERENCES `melisprint`.`warehouses` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 );
 
+DROP 
+  TABLE IF EXISTS transfers;
+CREATE TABLE IF NOT EXISTS `melisprint`.`transfers` (
+  `id` INT NOT NULL AUTO_INCREMENT, 
+  `product_batch_id` INT NOT NULL, 
+  `moved_batch_id` INT NULL, 
+  `quantity` INT NOT NULL, 
+  `source_section_id` INT NOT NULL, 
+  `target_section_id` INT NOT NULL, 
+  `status` VARCHAR(20) NOT NULL DEFAULT 'draft', 
+  `created_at` DATETIME(6) NOT NULL, 
+  `dispatched_at` DATETIME(6) NULL, 
+  `received_at` DATETIME(6) NULL, 
+  PRIMARY KEY (`id`), 
+  FOREIGN KEY (`product_batch_id`) REFERENCES `melisprint`.`product_batches` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION, 
+  FOREIGN KEY (`moved_batch_id`) REFERENCES `melisprint`.`product_batches` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION, 
+  FOREIGN KEY (`source_section_id`) REFERENCES `melisprint`.`sections` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION, 
+  FOREIGN KEY (`target_section_id`) REFERENCES `melisprint`.`sections` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+);
+
+DROP 
+  TABLE IF EXISTS employee_assignments;
+CREATE TABLE IF NOT EXISTS `melisprint`.`employee_assignments` (
+  `id` INT NOT NULL AUTO_INCREMENT, 
+  `employee_id` INT NOT NULL, 
+  `warehouse_id` INT NOT NULL, 
+  `started_at` DATETIME(6) NOT NULL, 
+  `ended_at` DATETIME(6) NULL, 
+  PRIMARY KEY (`id`), 
+  INDEX (`employee_id`, `started_at`), 
+  FOREIGN KEY (`employee_id`) REFERENCES `melisprint`.`employees` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION, 
+  FOREIGN KEY (`warehouse_id`) REFERENCES `melisprint`.`warehouses` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+);
+
+DROP 
+  TABLE IF EXISTS employee_shifts;
+CREATE TABLE IF NOT EXISTS `melisprint`.`employee_shifts` (
+  `id` INT NOT NULL AUTO_INCREMENT, 
+  `employee_id` INT NOT NULL, 
+  `warehouse_id` INT NOT NULL, 
+  `starts_at` DATETIME(6) NOT NULL, 
+  `ends_at` DATETIME(6) NOT NULL, 
+  PRIMARY KEY (`id`), 
+  INDEX (`employee_id`, `starts_at`), 
+  INDEX (`warehouse_id`, `starts_at`), 
+  FOREIGN KEY (`employee_id`) REFERENCES `melisprint`.`employees` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION, 
+  FOREIGN KEY (`warehouse_id`) REFERENCES `melisprint`.`warehouses` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+);
+
 
 INSERT INTO `melisprint`.`countries` (`country_name`) VALUES ('Brazil');
 INSERT INTO `melisprint`.`countries` (`country_name`) VALUES ('United States');
@@ -235,8 +374,8 @@ INSERT INTO `melisprint`.`products` (`product_code`, `description`, `width`, `he
 INSERT INTO `melisprint`.`warehouses` (`address`, `telephone`, `warehouse_code`, `minimum_capacity`, `minimum_temperature`, `locality_id`) VALUES ('Warehouse 1 Address', '111111111', 'W001', 100, -20, 1);
 INSERT INTO `melisprint`.`warehouses` (`address`, `telephone`, `warehouse_code`, `minimum_capacity`, `minimum_temperature`, `locality_id`) VALUES ('Warehouse 2 Address', '222222222', 'W002', 150, -18, 2);
 
-INSERT INTO `melisprint`.`sections` (`section_number`, `current_temperature`, `minimum_temperature`, `current_capacity`, `minimum_capacity`, `maximum_capacity`, `warehouse_id`, `id_product_type`) VALUES (1, -18, -20, 50, 20, 100, 1, 1);
-INSERT INTO `melisprint`.`sections` (`section_number`, `current_temperature`, `minimum_temperature`, `current_capacity`, `minimum_capacity`, `maximum_capacity`, `warehouse_id`, `id_product_type`) VALUES (2, -15, -18, 60, 30, 150, 2, 2);
+INSERT INTO `melisprint`.`sections` (`section_number`, `current_temperature`, `minimum_temperature`, `minimum_capacity`, `maximum_capacity`, `warehouse_id`, `id_product_type`) VALUES (1, -18, -20, 20000, 200000, 1, 1);
+INSERT INTO `melisprint`.`sections` (`section_number`, `current_temperature`, `minimum_temperature`, `minimum_capacity`, `maximum_capacity`, `warehouse_id`, `id_product_type`) VALUES (2, -15, -18, 10000, 100000, 2, 2);
 
 INSERT INTO `melisprint`.`product_batches` (`batch_number`, `current_quantity`, `current_temperature`, `due_date`, `initial_quantity`, `manufacturing_date`, `manufacturing_hour`, `minimum_temperature`, `product_id`, `section_id`) VALUES (1, 200, -18, '2023-07-31 00:00:00', 300, '2023-07-01 00:00:00', 8, -20, 1, 1);
 INSERT INTO `melisprint`.`product_batches` (`batch_number`, `current_quantity`, `current_temperature`, `due_date`, `initial_quantity`, `manufacturing_date`, `manufacturing_hour`, `minimum_temperature`, `product_id`, `section_id`) VALUES (2, 150, -15, '2023-08-15 00:00:00', 200, '2023-07-10 00:00:00', 9, -18, 2, 2);
@@ -244,14 +383,16 @@ INSERT INTO `melisprint`.`product_batches` (`batch_number`, `current_quantity`,
 INSERT INTO `melisprint`.`product_records` (`last_update_date`, `purchase_price`, `sale_price`, `product_id`) VALUES ('2023-07-05 10:00:00', 10.50, 15.00, 1);
 INSERT INTO `melisprint`.`product_records` (`last_update_date`, `purchase_price`, `sale_price`, `product_id`) VALUES ('2023-07-05 10:00:00', 8.75, 12.50, 2);
 
-INSERT INTO `melisprint`.`buyers` (`card_number_id`, `first_name`, `last_name`) VALUES ('987654321', 'John', 'Doe');
-INSERT INTO `melisprint`.`buyers` (`card_number_id`, `first_name`, `last_name`) VALUES ('123456789', 'Jane', 'Smith');
+INSERT INTO `melisprint`.`buyers` (`card_number_id`, `first_name`, `last_name`, `locality_id`) VALUES ('987654321', 'John', 'Doe', 1);
+INSERT INTO `melisprint`.`buyers` (`card_number_id`, `first_name`, `last_name`, `locality_id`) VALUES ('123456789', 'Jane', 'Smith', 2);
 
 INSERT INTO `melisprint`.`carriers` (`cid`, `company_name`, `address`, `telephone`, `locality_id`) VALUES ('111111', 'Carrier 1', 'Carrier Address 1', '111111111', 1);
 INSERT INTO `melisprint`.`carriers` (`cid`, `company_name`, `address`, `telephone`, `locality_id`) VALUES ('222222', 'Carrier 2', 'Carrier Address 2', '222222222', 2);
 
 INSERT INTO `melisprint`.`order_status` (`description`) VALUES ('Pending');
 INSERT INTO `melisprint`.`order_status` (`description`) VALUES ('Processing');
+INSERT INTO `melisprint`.`order_status` (`description`) VALUES ('Delivered');
+INSERT INTO `melisprint`.`order_status` (`description`) VALUES ('Exception');
 
 INSERT INTO `melisprint`.`purchase_orders` (`order_number`, `order_date`, `tracking_code`, `buyer_id`, `carrier_id`, `order_status_id`, `warehouse_id`, `product_record_id`) VALUES ('PO001', '2023-07-01 10:00:00', 'TRACK001', 1, 1, 1, 1, 1);
 INSERT INTO `melisprint`.`purchase_orders` (`order_number`, `order_date`, `tracking_code`, `buyer_id`, `carrier_id`, `order_status_id`, `warehouse_id`, `product_record_id`) VALUES ('PO002', '2023-07-02 11:00:00', 'TRACK002', 2, 2, 2, 2, 2);
@@ -262,4 +403,7 @@ INSERT INTO `melisprint`.`order_details` (`clean_liness_status`, `quantity`, `te
 INSERT INTO `melisprint`.`employees` (`card_number_id`, `first_name`, `last_name`, `warehouse_id`) VALUES ('123456', 'John', 'Smith', 1);
 INSERT INTO `melisprint`.`employees` (`card_number_id`, `first_name`, `last_name`, `warehouse_id`) VALUES ('654321', 'Jane', 'Doe', 2);
 
+INSERT INTO `melisprint`.`employee_assignments` (`employee_id`, `warehouse_id`, `started_at`) VALUES (1, 1, '2023-01-01 00:00:00');
+INSERT INTO `melisprint`.`employee_assignments` (`employee_id`, `warehouse_id`, `started_at`) VALUES (2, 2, '2023-01-01 00:00:00');
+
 INSERT INTO `melisprint`.`inbound_orders` (`order_date`, `order_number`, `employee_id`, `product_batch_id`, `warehouse_id`) VALUES ('2023-07-05 14:00:00', 'INB001', 1, 1, 1);
\ No newline at end of file
@@ -5,21 +5,41 @@ import (
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
 )
 
 // Repository encapsulates the storage of a buyer.
 type Repository interface {
 	GetAll(ctx context.Context) ([]domain.Buyer, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Buyer, error)
 	Get(ctx context.Context, id int) (domain.Buyer, error)
 	ExistsBuyer(ctx context.Context, cardNumberID string) bool
 	ExistsID(ctx context.Context, buyerID int) bool
 	Save(ctx context.Context, b domain.Buyer) (int, error)
 	Update(ctx context.Context, b domain.Buyer) error
+	UpdateCardNumber(ctx context.Context, id int, cardNumberID string) error
 	Delete(ctx context.Context, id int) error
 	GetBuyerOrders(ctx context.Context, id int) (domain.BuyerOrders, error)
 	GetBuyersOrders(ctx context.Context) ([]domain.BuyerOrders, error)
+	Orders(ctx context.Context, id int) ([]domain.BuyerOrder, error)
 }
 
+const (
+	GetAllQuery           = "SELECT id, card_number_id, first_name, last_name, locality_id FROM buyers"
+	GetQuery              = "SELECT id, card_number_id, first_name, last_name, locality_id FROM buyers WHERE id = ?"
+	SaveQuery             = "INSERT INTO buyers(card_number_id,first_name,last_name,locality_id) VALUES (?,?,?,?)"
+	UpdateQuery           = "UPDATE buyers SET first_name=?, last_name=?, locality_id=? WHERE id=?"
+	UpdateCardNumberQuery = "UPDATE buyers SET card_number_id=? WHERE id=?"
+	// OrdersQuery reads one row per order line, oldest order first. Orders
+	// without lines come back as a single row with NULL line columns.
+	OrdersQuery = "SELECT po.id, po.order_number, po.order_date, po.tracking_code, os.description, " +
+		"od.id, od.product_record_id, pr.product_id, p.description, od.quantity, pr.sale_price, pr.currency, od.clean_liness_status, od.temperature " +
+		"FROM purchase_orders po JOIN order_status os ON po.order_status_id = os.id " +
+		"LEFT JOIN order_details od ON od.purchase_order_id = po.id LEFT JOIN product_records pr ON od.product_record_id = pr.id " +
+		"LEFT JOIN products p ON pr.product_id = p.id WHERE po.buyer_id = ? ORDER BY po.order_date, po.id, od.id"
+)
+
 type repository struct {
 	db *sql.DB
 }
@@ -60,6 +80,48 @@ func (r *repository) GetBuyersOrders(ctx context.Context) ([]domain.BuyerOrders,
 	return buyers, nil
 }
 
+// Orders lists the orders of a buyer with their lines.
+func (r *repository) Orders(ctx context.Context, id int) ([]domain.BuyerOrder, error) {
+	rows, err := r.db.QueryContext(ctx, OrdersQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []domain.BuyerOrder{}
+	for rows.Next() {
+		o := domain.BuyerOrder{}
+		var lineID, recordID, productID, quantity sql.NullInt64
+		var description, currency, cleanLiness sql.NullString
+		var temperature sql.NullFloat64
+		var salePrice money.Money
+		err := rows.Scan(&o.ID, &o.OrderNumber, &o.OrderDate, &o.TrackingCode, &o.Status,
+			&lineID, &recordID, &productID, &description, &quantity, &salePrice, &currency, &cleanLiness, &temperature)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(orders); n == 0 || orders[n-1].ID != o.ID {
+			o.Lines = []domain.BuyerOrderLine{}
+			orders = append(orders, o)
+		}
+		if !lineID.Valid {
+			continue
+		}
+		last := &orders[len(orders)-1]
+		last.Lines = append(last.Lines, domain.BuyerOrderLine{
+			ID:                int(lineID.Int64),
+			ProductRecordID:   int(recordID.Int64),
+			ProductID:         int(productID.Int64),
+			Description:       description.String,
+			Quantity:          int(quantity.Int64),
+			SalePrice:         salePrice.In(currency.String),
+			CleanLinessStatus: cleanLiness.String,
+			Temperature:       temperature.Float64,
+		})
+	}
+	return orders, rows.Err()
+}
+
 func (r *repository) ExistsID(ctx context.Context, buyerID int) bool {
 	query := "SELECT COUNT(*) FROM buyers WHERE id = ?"
 	var count int
@@ -72,8 +134,7 @@ func (r *repository) ExistsID(ctx context.Context, buyerID int) bool {
 }
 
 func (r *repository) GetAll(ctx context.Context) ([]domain.Buyer, error) {
-	query := "SELECT * FROM buyers"
-	rows, err := r.db.Query(query)
+	rows, err := r.db.Query(GetAllQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -82,18 +143,45 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Buyer, error) {
 
 	for rows.Next() {
 		b := domain.Buyer{}
-		_ = rows.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName)
+		_ = rows.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName, &b.LocalityID)
 		buyers = append(buyers, b)
 	}
 
 	return buyers, nil
 }
 
+// GetByIDs returns the buyers with the given ids.
+func (r *repository) GetByIDs(ctx context.Context, ids []int) ([]domain.Buyer, error) {
+	return r.getIn(ctx, "id", ids)
+}
+
+// getIn returns the buyers whose column holds one of values, in one query.
+func (r *repository) getIn(ctx context.Context, column string, values []int) ([]domain.Buyer, error) {
+	buyers := []domain.Buyer{}
+	if len(values) == 0 {
+		return buyers, nil
+	}
+	in, args := mysqlutil.In(values)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, card_number_id, first_name, last_name, locality_id FROM buyers WHERE "+column+" IN "+in+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		b := domain.Buyer{}
+		if err := rows.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName, &b.LocalityID); err != nil {
+			return nil, err
+		}
+		buyers = append(buyers, b)
+	}
+	return buyers, rows.Err()
+}
+
 func (r *repository) Get(ctx context.Context, id int) (domain.Buyer, error) {
-	query := "SELECT * FROM buyers WHERE id = ?;"
-	row := r.db.QueryRow(query, id)
+	row := r.db.QueryRow(GetQuery, id)
 	b := domain.Buyer{}
-	err := row.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName)
+	err := row.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName, &b.LocalityID)
 	if err != nil {
 		return domain.Buyer{}, err
 	}
@@ -109,14 +197,19 @@ func (r *repository) ExistsBuyer(ctx context.Context, cardNumberID string) bool
 }
 
 func (r *repository) Save(ctx context.Context, b domain.Buyer) (int, error) {
-	query := "INSERT INTO buyers(card_number_id,first_name,last_name) VALUES (?,?,?)"
-	stmt, err := r.db.Prepare(query)
+	stmt, err := r.db.Prepare(SaveQuery)
 	if err != nil {
 		return 0, err
 	}
 
-	res, err := stmt.Exec(&b.CardNumberID, &b.FirstName, &b.LastName)
+	res, err := stmt.Exec(&b.CardNumberID, &b.FirstName, &b.LastName, b.LocalityID)
 	if err != nil {
+		if mysqlutil.IsDuplicateEntry(err) {
+			return 0, ErrExists
+		}
+		if mysqlutil.IsNoReferencedRow(err) {
+			return 0, ErrLocalityNotFound
+		}
 		return 0, err
 	}
 
@@ -129,22 +222,47 @@ func (r *repository) Save(ctx context.Context, b domain.Buyer) (int, error) {
 }
 
 func (r *repository) Update(ctx context.Context, b domain.Buyer) error {
-	query := "UPDATE buyers SET first_name=?, last_name=?  WHERE id=?"
-	stmt, err := r.db.Prepare(query)
+	stmt, err := r.db.Prepare(UpdateQuery)
 	if err != nil {
 		return err
 	}
 
-	res, err := stmt.Exec(&b.FirstName, &b.LastName, &b.ID)
+	res, err := stmt.Exec(&b.FirstName, &b.LastName, b.LocalityID, &b.ID)
 	if err != nil {
+		if mysqlutil.IsNoReferencedRow(err) {
+			return ErrLocalityNotFound
+		}
 		return err
 	}
 
-	_, err = res.RowsAffected()
+	return r.checkUpdated(ctx, res, b.ID)
+}
+
+// UpdateCardNumber changes the card number of a buyer. The unique index on
+// card_number_id rejects a number taken by another buyer, even one saved
+// concurrently, which is reported as ErrExists.
+func (r *repository) UpdateCardNumber(ctx context.Context, id int, cardNumberID string) error {
+	res, err := r.db.ExecContext(ctx, UpdateCardNumberQuery, cardNumberID, id)
 	if err != nil {
+		if mysqlutil.IsDuplicateEntry(err) {
+			return ErrExists
+		}
 		return err
 	}
+	return r.checkUpdated(ctx, res, id)
+}
 
+// checkUpdated returns ErrNotFound when an update matched no buyer. MySQL
+// reports unchanged rows as not affected, so a buyer updated with its own
+// values is told apart from a missing one by looking it up.
+func (r *repository) checkUpdated(ctx context.Context, res sql.Result, id int) error {
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect < 1 && !r.ExistsID(ctx, id) {
+		return ErrNotFound
+	}
 	return nil
 }
 
@@ -10,6 +10,8 @@ import (
 	buyers "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/buyer"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -43,14 +45,14 @@ func TestGetBuyersOrdersRepository(t *testing.T) {
 
 		expectedOrder := domain.PurchaseOrders{
 			OrderNumber:     "9423i",
-			OrderDate:       "2021-04-04",
-			TrackingCode:    "afijaehn",
+			OrderDate:       datetime.MustParse("2021-04-04"),
+			TrackingCode:    "buyer-track-1",
 			BuyerID:         1,
 			ProductRecordID: 1,
 			OrderStatusID:   1,
 		}
 
-		err = repositoryPurchaseOrders.Save(ctx, expectedOrder)
+		_, err = repositoryPurchaseOrders.Save(ctx, expectedOrder)
 		assert.NoError(t, err)
 
 		buyer, err := repositoryBuyer.GetBuyersOrders(ctx)
@@ -78,14 +80,14 @@ func TestGetBuyerOrdersRepository(t *testing.T) {
 
 		expectedOrder := domain.PurchaseOrders{
 			OrderNumber:     "9423i",
-			OrderDate:       "2021-04-04",
-			TrackingCode:    "afijaehn",
+			OrderDate:       datetime.MustParse("2021-04-04"),
+			TrackingCode:    "buyer-track-2",
 			BuyerID:         1,
 			ProductRecordID: 1,
 			OrderStatusID:   1,
 		}
 
-		err = repositoryPurchaseOrders.Save(ctx, expectedOrder)
+		_, err = repositoryPurchaseOrders.Save(ctx, expectedOrder)
 		assert.NoError(t, err)
 
 		buyer, err := repositoryBuyer.GetBuyerOrders(ctx, buyerID)
@@ -105,13 +107,107 @@ func TestGetBuyerOrdersRepository(t *testing.T) {
 
 }
 
+func TestBuyerOrdersRepository(t *testing.T) {
+	t.Run("Should return the orders of the buyer with their lines", func(t *testing.T) {
+		repository := buyers.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		orders, err := repository.Orders(ctx, 1)
+		assert.NoError(t, err)
+		if assert.NotEmpty(t, orders) && assert.NotEmpty(t, orders[0].Lines) {
+			assert.Equal(t, "PO001", orders[0].OrderNumber)
+			assert.Equal(t, "Pending", orders[0].Status)
+			assert.Equal(t, 10, orders[0].Lines[0].Quantity)
+		}
+	})
+	t.Run("Should return no orders for a buyer without them", func(t *testing.T) {
+		repository := buyers.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		orders, err := repository.Orders(ctx, 50000000)
+		assert.NoError(t, err)
+		assert.Empty(t, orders)
+	})
+}
+
+func TestBuyerCardNumberUniqueness(t *testing.T) {
+	t.Run("Should return conflict when the card number is already saved", func(t *testing.T) {
+		repository := buyers.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		expectedBuyer := domain.Buyer{CardNumberID: "7700", FirstName: "Giulianna", LastName: "Oliveira"}
+		_, err := repository.Save(ctx, expectedBuyer)
+		assert.NoError(t, err)
+
+		_, err = repository.Save(ctx, expectedBuyer)
+		assert.ErrorIs(t, err, buyers.ErrExists)
+	})
+	t.Run("Should return conflict when changing to a card number in use", func(t *testing.T) {
+		repository := buyers.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		_, err := repository.Save(ctx, domain.Buyer{CardNumberID: "7701", FirstName: "Giulianna", LastName: "Oliveira"})
+		assert.NoError(t, err)
+		buyerID, err := repository.Save(ctx, domain.Buyer{CardNumberID: "7702", FirstName: "Giulianna", LastName: "Oliveira"})
+		assert.NoError(t, err)
+
+		err = repository.UpdateCardNumber(ctx, buyerID, "7701")
+		assert.ErrorIs(t, err, buyers.ErrExists)
+	})
+	t.Run("Should return not found when updating a missing buyer", func(t *testing.T) {
+		repository := buyers.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		err := repository.Update(ctx, domain.Buyer{ID: 50000000, FirstName: "Giulianna", LastName: "Oliveira"})
+		assert.ErrorIs(t, err, buyers.ErrNotFound)
+
+		err = repository.UpdateCardNumber(ctx, 50000000, "7703")
+		assert.ErrorIs(t, err, buyers.ErrNotFound)
+	})
+}
+
+func TestBuyerLocalityRepository(t *testing.T) {
+	t.Run("Should store the delivery locality", func(t *testing.T) {
+		repository := buyers.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		buyerID, err := repository.Save(ctx, domain.Buyer{CardNumberID: "7801", FirstName: "Giulianna", LastName: "Oliveira", LocalityID: testutil.Int(1)})
+		assert.NoError(t, err)
+
+		buyer, err := repository.Get(ctx, buyerID)
+		assert.NoError(t, err)
+		assert.Equal(t, testutil.Int(1), buyer.LocalityID)
+	})
+	t.Run("Should return locality not found for a missing locality", func(t *testing.T) {
+		repository := buyers.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		_, err := repository.Save(ctx, domain.Buyer{CardNumberID: "7802", FirstName: "Giulianna", LastName: "Oliveira", LocalityID: testutil.Int(50000000)})
+		assert.ErrorIs(t, err, buyers.ErrLocalityNotFound)
+	})
+}
+
 func TestDeleteBuyer(t *testing.T) {
 
 	t.Run("Should delete buyer", func(t *testing.T) {
 		repository := buyers.NewRepository(db)
 
 		expectedBuyer := domain.Buyer{
-			CardNumberID: "138935",
+			CardNumberID: "138936",
 			FirstName:    "Giulianna",
 			LastName:     "Oliveira",
 		}
@@ -188,7 +284,7 @@ func TestExistsBuyer(t *testing.T) {
 		defer cancel()
 
 		expectedBuyer := domain.Buyer{
-			CardNumberID: "138935",
+			CardNumberID: "138937",
 			FirstName:    "Giulianna",
 			LastName:     "Oliveira",
 		}
@@ -219,7 +315,7 @@ func TestExistsBuyerID(t *testing.T) {
 		defer cancel()
 
 		expectedBuyer := domain.Buyer{
-			CardNumberID: "138935",
+			CardNumberID: "138938",
 			FirstName:    "Giulianna",
 			LastName:     "Oliveira",
 		}
@@ -247,7 +343,7 @@ func TestSaveBuyer(t *testing.T) {
 		repository := buyers.NewRepository(db)
 
 		expectedBuyer := domain.Buyer{
-			CardNumberID: "138935",
+			CardNumberID: "138939",
 			FirstName:    "Giulianna",
 			LastName:     "Oliveira",
 		}
@@ -267,7 +363,7 @@ func TestGetBuyer(t *testing.T) {
 
 		expectedBuyer := domain.Buyer{
 			ID:           9,
-			CardNumberID: "2556",
+			CardNumberID: "2557",
 			FirstName:    "Giulianna",
 			LastName:     "Oliveira",
 		}
@@ -303,7 +399,7 @@ func TestGetAllBuyers(t *testing.T) {
 
 		expectedBuyers := domain.Buyer{
 			ID:           9,
-			CardNumberID: "2556",
+			CardNumberID: "2558",
 			FirstName:    "Giulianna",
 			LastName:     "Oliveira",
 		}
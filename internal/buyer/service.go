@@ -3,8 +3,11 @@ package buyer
 import (
 	"context"
 	"errors"
+	"sort"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mergepatch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
 )
 
 // Errors
@@ -13,27 +16,161 @@ var (
 	ErrExists      = errors.New("buyer already exists")
 	ErrInvalidID   = errors.New("invalid ID")
 	ErrInvalidBody = errors.New("invalid body")
+	// ErrLocalityNotFound is returned when the delivery locality of a buyer
+	// does not exist.
+	ErrLocalityNotFound = errors.New("locality not found")
+	// ErrUnknownCurrency is returned when an order line is priced in a
+	// currency that cannot be converted to the reporting currency.
+	ErrUnknownCurrency = errors.New("no conversion rate for currency")
 )
 
+// FavouriteProductsLimit is the number of products listed in a summary.
+const FavouriteProductsLimit = 3
+
 type Service interface {
 	GetAll(ctx context.Context) ([]domain.Buyer, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Buyer, error)
 	Get(ctx context.Context, id int) (domain.Buyer, error)
 	ExistsID(ctx context.Context, id int) error
 	Create(ctx context.Context, b domain.Buyer) (domain.Buyer, error)
-	Update(ctx context.Context, b domain.Buyer, id int) (domain.Buyer, error)
+	Update(ctx context.Context, id int, p domain.Patch) (domain.Buyer, error)
+	UpdateCardNumber(ctx context.Context, id int, cardNumberID string) (domain.Buyer, error)
 	Delete(ctx context.Context, id int) error
 	GetBuyerOrders(ctx context.Context, id int) (domain.BuyerOrders, error)
 	GetBuyersOrders(ctx context.Context) ([]domain.BuyerOrders, error)
+	Orders(ctx context.Context, id int) ([]domain.BuyerOrder, error)
+	Summary(ctx context.Context, id int) (domain.BuyerSummary, error)
 }
 
 type buyerService struct {
 	repository Repository
+	rates      *money.Rates
 }
 
-func NewService(r Repository) Service {
+// NewService returns a service that reports order amounts in the base
+// currency of rates. With nil rates, amounts are reported in
+// money.DefaultCurrency and lines in other currencies cannot be added up.
+func NewService(r Repository, rates *money.Rates) Service {
 	return &buyerService{
 		repository: r,
+		rates:      rates,
+	}
+}
+
+// Orders lists the orders of a buyer, oldest first, pricing each line at the
+// sale price of its product record.
+func (b *buyerService) Orders(ctx context.Context, id int) ([]domain.BuyerOrder, error) {
+	if !b.repository.ExistsID(ctx, id) {
+		return nil, ErrNotFound
+	}
+	orders, err := b.repository.Orders(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		total := money.New(0, b.currency())
+		for j := range orders[i].Lines {
+			line := &orders[i].Lines[j]
+			line.Total = money.New(line.SalePrice.Cents()*int64(line.Quantity), line.SalePrice.Currency())
+			converted, err := b.convert(line.Total)
+			if err != nil {
+				return nil, err
+			}
+			if total, err = total.Add(converted); err != nil {
+				return nil, err
+			}
+		}
+		orders[i].Total = total
+	}
+	return orders, nil
+}
+
+// Summary adds up the orders of a buyer. The favourite products are the ones
+// with the most units bought, ties broken by product id.
+func (b *buyerService) Summary(ctx context.Context, id int) (domain.BuyerSummary, error) {
+	orders, err := b.Orders(ctx, id)
+	if err != nil {
+		return domain.BuyerSummary{}, err
+	}
+
+	summary := domain.BuyerSummary{
+		BuyerID:           id,
+		OrdersCount:       len(orders),
+		LifetimeValue:     money.New(0, b.currency()),
+		AverageOrderValue: money.New(0, b.currency()),
+		FavouriteProducts: []domain.BuyerFavouriteProduct{},
+	}
+	if len(orders) == 0 {
+		return summary, nil
+	}
+
+	products := map[int]*domain.BuyerFavouriteProduct{}
+	for _, o := range orders {
+		if summary.LifetimeValue, err = summary.LifetimeValue.Add(o.Total); err != nil {
+			return domain.BuyerSummary{}, err
+		}
+		if summary.FirstOrderDate.IsZero() || o.OrderDate.Before(summary.FirstOrderDate.Time) {
+			summary.FirstOrderDate = o.OrderDate
+		}
+		if o.OrderDate.After(summary.LastOrderDate.Time) {
+			summary.LastOrderDate = o.OrderDate
+		}
+		counted := map[int]bool{}
+		for _, line := range o.Lines {
+			p, ok := products[line.ProductID]
+			if !ok {
+				p = &domain.BuyerFavouriteProduct{ProductID: line.ProductID, Description: line.Description}
+				products[line.ProductID] = p
+			}
+			p.Quantity += line.Quantity
+			if !counted[line.ProductID] {
+				counted[line.ProductID] = true
+				p.OrdersCount++
+			}
+		}
+	}
+	n := int64(len(orders))
+	summary.AverageOrderValue = money.New((summary.LifetimeValue.Cents()+n/2)/n, b.currency())
+
+	for _, p := range products {
+		summary.FavouriteProducts = append(summary.FavouriteProducts, *p)
+	}
+	sort.Slice(summary.FavouriteProducts, func(i, j int) bool {
+		pi, pj := summary.FavouriteProducts[i], summary.FavouriteProducts[j]
+		if pi.Quantity != pj.Quantity {
+			return pi.Quantity > pj.Quantity
+		}
+		return pi.ProductID < pj.ProductID
+	})
+	if len(summary.FavouriteProducts) > FavouriteProductsLimit {
+		summary.FavouriteProducts = summary.FavouriteProducts[:FavouriteProductsLimit]
+	}
+	return summary, nil
+}
+
+// currency is the currency order totals are reported in.
+func (b *buyerService) currency() string {
+	if b.rates == nil {
+		return money.DefaultCurrency
 	}
+	return b.rates.Base()
+}
+
+func (b *buyerService) convert(m money.Money) (money.Money, error) {
+	if b.rates == nil {
+		if m.Currency() != money.DefaultCurrency {
+			return money.Money{}, ErrUnknownCurrency
+		}
+		return m, nil
+	}
+	converted, err := b.rates.Convert(m, b.rates.Base())
+	if err != nil {
+		if errors.Is(err, money.ErrUnknownRate) {
+			return money.Money{}, ErrUnknownCurrency
+		}
+		return money.Money{}, err
+	}
+	return converted, nil
 }
 
 func (b *buyerService) GetBuyerOrders(ctx context.Context, id int) (domain.BuyerOrders, error) {
@@ -70,6 +207,11 @@ func (b *buyerService) GetAll(ctx context.Context) ([]domain.Buyer, error) {
 	return buyers, nil
 }
 
+// GetByIDs returns the buyers with the given ids.
+func (b *buyerService) GetByIDs(ctx context.Context, ids []int) ([]domain.Buyer, error) {
+	return b.repository.GetByIDs(ctx, ids)
+}
+
 func (b *buyerService) Get(ctx context.Context, id int) (domain.Buyer, error) {
 	buyer, err := b.repository.Get(ctx, id)
 	if err != nil {
@@ -88,17 +230,25 @@ func (b *buyerService) Create(ctx context.Context, d domain.Buyer) (domain.Buyer
 	return d, err
 }
 
-func (b *buyerService) Update(ctx context.Context, d domain.Buyer, id int) (domain.Buyer, error) {
+// Update applies a merge patch to the buyer. Its names cannot be cleared,
+// while clearing its locality leaves it without a delivery address. The card
+// number is only changed through UpdateCardNumber.
+func (b *buyerService) Update(ctx context.Context, id int, p domain.Patch) (domain.Buyer, error) {
 	buyer, err := b.repository.Get(ctx, id)
 	if err != nil {
 		return domain.Buyer{}, ErrNotFound
 	}
-	if d.FirstName != "" {
-		buyer.FirstName = d.FirstName
+
+	cardNumberID := buyer.CardNumberID
+	if err := mergepatch.Apply(&buyer, p.Document); err != nil {
+		return domain.Buyer{}, ErrInvalidBody
 	}
-	if d.LastName != "" {
-		buyer.LastName = d.LastName
+	buyer.ID = id
+	buyer.CardNumberID = cardNumberID
+	if buyer.FirstName == "" || buyer.LastName == "" {
+		return domain.Buyer{}, ErrInvalidBody
 	}
+
 	err = b.repository.Update(ctx, buyer)
 	if err != nil {
 		return domain.Buyer{}, err
@@ -108,6 +258,30 @@ func (b *buyerService) Update(ctx context.Context, d domain.Buyer, id int) (doma
 
 }
 
+// UpdateCardNumber moves a buyer to another card number, unless another buyer
+// already holds it. Numbers taken concurrently are caught by the unique index
+// and reported as ErrExists as well.
+func (b *buyerService) UpdateCardNumber(ctx context.Context, id int, cardNumberID string) (domain.Buyer, error) {
+	if cardNumberID == "" {
+		return domain.Buyer{}, ErrInvalidBody
+	}
+	buyer, err := b.repository.Get(ctx, id)
+	if err != nil {
+		return domain.Buyer{}, ErrNotFound
+	}
+	if buyer.CardNumberID == cardNumberID {
+		return buyer, nil
+	}
+	if b.repository.ExistsBuyer(ctx, cardNumberID) {
+		return domain.Buyer{}, ErrExists
+	}
+	if err := b.repository.UpdateCardNumber(ctx, id, cardNumberID); err != nil {
+		return domain.Buyer{}, err
+	}
+	buyer.CardNumberID = cardNumberID
+	return buyer, nil
+}
+
 func (b *buyerService) Delete(ctx context.Context, id int) error {
 	err := b.repository.Delete(ctx, id)
 	return err
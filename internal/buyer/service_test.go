@@ -7,6 +7,9 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/buyer"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/buyer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -136,19 +139,15 @@ func TestDelete(t *testing.T) {
 
 func TestUpdate(t *testing.T) {
 	t.Run("Should update the buyer", func(t *testing.T) {
-		expectedBuyer := domain.Buyer{
-			ID:           9,
-			CardNumberID: "2556",
-			FirstName:    "Giulianna",
-			LastName:     "Oliveira",
-		}
+		stored := domain.Buyer{ID: 9, CardNumberID: "2556", FirstName: "Giulia", LastName: "Oliveira"}
+		expectedBuyer := stored
+		expectedBuyer.FirstName = "Giulianna"
 
 		repository, service := InitServerWithBuyersRepository(t)
-		repository.On("Get", mock.Anything, expectedBuyer.ID).Return(expectedBuyer, nil)
-		repository.On("ExistsID", mock.Anything, expectedBuyer.ID).Return(false)
+		repository.On("Get", mock.Anything, 9).Return(stored, nil)
 		repository.On("Update", mock.Anything, expectedBuyer).Return(nil)
 
-		updatedBuyer, err := service.Update(context.TODO(), expectedBuyer, expectedBuyer.ID)
+		updatedBuyer, err := service.Update(context.TODO(), 9, domain.Patch{Document: []byte(`{"first_name":"Giulianna"}`)})
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedBuyer, updatedBuyer)
@@ -156,37 +155,87 @@ func TestUpdate(t *testing.T) {
 	t.Run("should not update buyer if not exists", func(t *testing.T) {
 		repository, service := InitServerWithBuyersRepository(t)
 
-		expectedBuyer := domain.Buyer{
-			CardNumberID: "2556",
-			FirstName:    "Giulianna",
-			LastName:     "Oliveira",
-		}
-
 		repository.On("Get", mock.Anything, 100).Return(domain.Buyer{}, errors.New("error"))
-		repository.On("Update", mock.Anything, mock.Anything).Return(errors.New("error"))
-		_, err := service.Update(context.TODO(), expectedBuyer, 100)
-		assert.Error(t, err)
+		_, err := service.Update(context.TODO(), 100, domain.Patch{Document: []byte(`{"first_name":"Giulianna"}`)})
+
+		assert.Equal(t, buyer.ErrNotFound, err)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
 	})
 	t.Run("Should return err", func(t *testing.T) {
-
-		expectedBuyer := domain.Buyer{
-			ID:           50,
-			CardNumberID: "2556",
-			FirstName:    "Giulianna",
-			LastName:     "Oliveira",
-		}
+		stored := domain.Buyer{ID: 50, CardNumberID: "2556", FirstName: "Giulianna", LastName: "Oliveira"}
 
 		repository, service := InitServerWithBuyersRepository(t)
 		expectedError := errors.New("error")
 
-		repository.On("Get", mock.Anything, mock.Anything).Return(domain.Buyer{}, nil)
+		repository.On("Get", mock.Anything, 50).Return(stored, nil)
 		repository.On("Update", mock.Anything, mock.Anything).Return(expectedError)
 
-		_, err := service.Update(context.TODO(), expectedBuyer, expectedBuyer.ID)
+		_, err := service.Update(context.TODO(), 50, domain.Patch{Document: []byte(`{}`)})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
 	})
+	t.Run("Should change the delivery locality", func(t *testing.T) {
+		stored := domain.Buyer{ID: 9, CardNumberID: "2556", FirstName: "Giulianna", LastName: "Oliveira", LocalityID: testutil.Int(1)}
+		expectedBuyer := stored
+		expectedBuyer.LocalityID = testutil.Int(2)
+
+		repository, service := InitServerWithBuyersRepository(t)
+		repository.On("Get", mock.Anything, 9).Return(stored, nil)
+		repository.On("Update", mock.Anything, expectedBuyer).Return(nil)
+
+		updatedBuyer, err := service.Update(context.TODO(), 9, domain.Patch{Document: []byte(`{"locality_id":2}`)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBuyer, updatedBuyer)
+	})
+	t.Run("Should clear the delivery locality on null", func(t *testing.T) {
+		stored := domain.Buyer{ID: 9, CardNumberID: "2556", FirstName: "Giulianna", LastName: "Oliveira", LocalityID: testutil.Int(1)}
+		expectedBuyer := stored
+		expectedBuyer.LocalityID = nil
+
+		repository, service := InitServerWithBuyersRepository(t)
+		repository.On("Get", mock.Anything, 9).Return(stored, nil)
+		repository.On("Update", mock.Anything, expectedBuyer).Return(nil)
+
+		updatedBuyer, err := service.Update(context.TODO(), 9, domain.Patch{Document: []byte(`{"locality_id":null}`)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBuyer, updatedBuyer)
+	})
+	t.Run("Should keep the card number", func(t *testing.T) {
+		stored := domain.Buyer{ID: 9, CardNumberID: "2556", FirstName: "Giulianna", LastName: "Oliveira"}
+
+		repository, service := InitServerWithBuyersRepository(t)
+		repository.On("Get", mock.Anything, 9).Return(stored, nil)
+		repository.On("Update", mock.Anything, stored).Return(nil)
+
+		updatedBuyer, err := service.Update(context.TODO(), 9, domain.Patch{Document: []byte(`{"card_number_id":"9999"}`)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, stored, updatedBuyer)
+	})
+	t.Run("Should not clear the names", func(t *testing.T) {
+		stored := domain.Buyer{ID: 9, CardNumberID: "2556", FirstName: "Giulianna", LastName: "Oliveira"}
+
+		repository, service := InitServerWithBuyersRepository(t)
+		repository.On("Get", mock.Anything, 9).Return(stored, nil)
+
+		_, err := service.Update(context.TODO(), 9, domain.Patch{Document: []byte(`{"last_name":null}`)})
+
+		assert.Equal(t, buyer.ErrInvalidBody, err)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+	t.Run("Should reject values of the wrong type", func(t *testing.T) {
+		stored := domain.Buyer{ID: 9, CardNumberID: "2556", FirstName: "Giulianna", LastName: "Oliveira"}
+
+		repository, service := InitServerWithBuyersRepository(t)
+		repository.On("Get", mock.Anything, 9).Return(stored, nil)
+
+		_, err := service.Update(context.TODO(), 9, domain.Patch{Document: []byte(`{"locality_id":"two"}`)})
+
+		assert.Equal(t, buyer.ErrInvalidBody, err)
+	})
 }
 
 func TestExistsID(t *testing.T) {
@@ -265,9 +314,202 @@ func TestGetBuyersOrders(t *testing.T) {
 	})
 }
 
+func TestUpdateCardNumber(t *testing.T) {
+	current := domain.Buyer{ID: 1, CardNumberID: "1234", FirstName: "Giu", LastName: "Oli"}
+
+	t.Run("Should change the card number", func(t *testing.T) {
+		mockRepository, service := InitServerWithBuyersRepository(t)
+		mockRepository.On("Get", mock.Anything, 1).Return(current, nil)
+		mockRepository.On("ExistsBuyer", mock.Anything, "5678").Return(false)
+		mockRepository.On("UpdateCardNumber", mock.Anything, 1, "5678").Return(nil)
+
+		updated, err := service.UpdateCardNumber(context.Background(), 1, "5678")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "5678", updated.CardNumberID)
+		assert.Equal(t, current.FirstName, updated.FirstName)
+	})
+
+	t.Run("Should not store anything when the card number does not change", func(t *testing.T) {
+		mockRepository, service := InitServerWithBuyersRepository(t)
+		mockRepository.On("Get", mock.Anything, 1).Return(current, nil)
+
+		updated, err := service.UpdateCardNumber(context.Background(), 1, "1234")
+
+		assert.NoError(t, err)
+		assert.Equal(t, current, updated)
+		mockRepository.AssertNotCalled(t, "UpdateCardNumber", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return conflict when another buyer holds the card number", func(t *testing.T) {
+		mockRepository, service := InitServerWithBuyersRepository(t)
+		mockRepository.On("Get", mock.Anything, 1).Return(current, nil)
+		mockRepository.On("ExistsBuyer", mock.Anything, "5678").Return(true)
+
+		_, err := service.UpdateCardNumber(context.Background(), 1, "5678")
+
+		assert.ErrorIs(t, err, buyer.ErrExists)
+	})
+
+	t.Run("Should return conflict when the card number is taken concurrently", func(t *testing.T) {
+		mockRepository, service := InitServerWithBuyersRepository(t)
+		mockRepository.On("Get", mock.Anything, 1).Return(current, nil)
+		mockRepository.On("ExistsBuyer", mock.Anything, "5678").Return(false)
+		mockRepository.On("UpdateCardNumber", mock.Anything, 1, "5678").Return(buyer.ErrExists)
+
+		_, err := service.UpdateCardNumber(context.Background(), 1, "5678")
+
+		assert.ErrorIs(t, err, buyer.ErrExists)
+	})
+
+	t.Run("Should return not found when the buyer does not exist", func(t *testing.T) {
+		mockRepository, service := InitServerWithBuyersRepository(t)
+		mockRepository.On("Get", mock.Anything, 50).Return(domain.Buyer{}, errors.New("sql: no rows in result set"))
+
+		_, err := service.UpdateCardNumber(context.Background(), 50, "5678")
+
+		assert.ErrorIs(t, err, buyer.ErrNotFound)
+	})
+
+	t.Run("Should reject an empty card number", func(t *testing.T) {
+		_, service := InitServerWithBuyersRepository(t)
+
+		_, err := service.UpdateCardNumber(context.Background(), 1, "")
+
+		assert.ErrorIs(t, err, buyer.ErrInvalidBody)
+	})
+}
+
+func buyerOrdersFixture() []domain.BuyerOrder {
+	return []domain.BuyerOrder{
+		{ID: 1, OrderNumber: "PO001", OrderDate: datetime.MustParse("2023-07-01"), Status: "Pending", Lines: []domain.BuyerOrderLine{
+			{ID: 1, ProductID: 1, Description: "Apple", Quantity: 10, SalePrice: money.MustParse("15.00", "USD")},
+			{ID: 2, ProductID: 2, Description: "Pear", Quantity: 2, SalePrice: money.MustParse("12.50", "USD")},
+		}},
+		{ID: 2, OrderNumber: "PO002", OrderDate: datetime.MustParse("2023-07-05"), Status: "Processing", Lines: []domain.BuyerOrderLine{
+			{ID: 3, ProductID: 2, Description: "Pear", Quantity: 3, SalePrice: money.MustParse("12.50", "USD")},
+		}},
+		{ID: 3, OrderNumber: "PO003", OrderDate: datetime.MustParse("2023-07-09"), Status: "Pending", Lines: []domain.BuyerOrderLine{}},
+	}
+}
+
+func TestBuyerOrders(t *testing.T) {
+	t.Run("Should price every line and order", func(t *testing.T) {
+		mockRepository, service := InitServerWithBuyersRepository(t)
+		mockRepository.On("ExistsID", 1).Return(true)
+		mockRepository.On("Orders", mock.Anything, 1).Return(buyerOrdersFixture(), nil)
+
+		orders, err := service.Orders(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, orders, 3)
+		assert.Equal(t, money.MustParse("150.00", "USD"), orders[0].Lines[0].Total)
+		assert.Equal(t, money.MustParse("175.00", "USD"), orders[0].Total)
+		assert.Equal(t, money.MustParse("37.50", "USD"), orders[1].Total)
+		assert.Equal(t, money.MustParse("0", "USD"), orders[2].Total)
+	})
+
+	t.Run("Should convert lines to the base currency", func(t *testing.T) {
+		mockRepository := &mocks.BuyerRepositoryMock{}
+		rates, err := money.NewRates("USD", map[string]string{"EUR": "1.10"})
+		assert.NoError(t, err)
+		service := buyer.NewService(mockRepository, rates)
+		mockRepository.On("ExistsID", 1).Return(true)
+		mockRepository.On("Orders", mock.Anything, 1).Return([]domain.BuyerOrder{
+			{ID: 1, Lines: []domain.BuyerOrderLine{
+				{ID: 1, ProductID: 1, Quantity: 2, SalePrice: money.MustParse("10.00", "EUR")},
+				{ID: 2, ProductID: 2, Quantity: 1, SalePrice: money.MustParse("5.00", "USD")},
+			}},
+		}, nil)
+
+		orders, err := service.Orders(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("20.00", "EUR"), orders[0].Lines[0].Total)
+		assert.Equal(t, money.MustParse("27.00", "USD"), orders[0].Total)
+	})
+
+	t.Run("Should return error when a line currency cannot be converted", func(t *testing.T) {
+		mockRepository, service := InitServerWithBuyersRepository(t)
+		mockRepository.On("ExistsID", 1).Return(true)
+		mockRepository.On("Orders", mock.Anything, 1).Return([]domain.BuyerOrder{
+			{ID: 1, Lines: []domain.BuyerOrderLine{{ID: 1, Quantity: 1, SalePrice: money.MustParse("10.00", "EUR")}}},
+		}, nil)
+
+		_, err := service.Orders(context.Background(), 1)
+
+		assert.ErrorIs(t, err, buyer.ErrUnknownCurrency)
+	})
+
+	t.Run("Should return buyer not found", func(t *testing.T) {
+		mockRepository, service := InitServerWithBuyersRepository(t)
+		mockRepository.On("ExistsID", 50).Return(false)
+
+		_, err := service.Orders(context.Background(), 50)
+
+		assert.ErrorIs(t, err, buyer.ErrNotFound)
+		mockRepository.AssertNotCalled(t, "Orders", mock.Anything, mock.Anything)
+	})
+}
+
+func TestBuyerSummary(t *testing.T) {
+	t.Run("Should add up every order of the buyer", func(t *testing.T) {
+		mockRepository, service := InitServerWithBuyersRepository(t)
+		mockRepository.On("ExistsID", 1).Return(true)
+		mockRepository.On("Orders", mock.Anything, 1).Return(buyerOrdersFixture(), nil)
+
+		summary, err := service.Summary(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.BuyerSummary{
+			BuyerID:           1,
+			OrdersCount:       3,
+			LifetimeValue:     money.MustParse("212.50", "USD"),
+			AverageOrderValue: money.MustParse("70.83", "USD"),
+			FirstOrderDate:    datetime.MustParse("2023-07-01"),
+			LastOrderDate:     datetime.MustParse("2023-07-09"),
+			FavouriteProducts: []domain.BuyerFavouriteProduct{
+				{ProductID: 1, Description: "Apple", Quantity: 10, OrdersCount: 1},
+				{ProductID: 2, Description: "Pear", Quantity: 5, OrdersCount: 2},
+			},
+		}, summary)
+	})
+
+	t.Run("Should return an empty summary when the buyer has no orders", func(t *testing.T) {
+		mockRepository, service := InitServerWithBuyersRepository(t)
+		mockRepository.On("ExistsID", 1).Return(true)
+		mockRepository.On("Orders", mock.Anything, 1).Return([]domain.BuyerOrder{}, nil)
+
+		summary, err := service.Summary(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, summary.OrdersCount)
+		assert.True(t, summary.LifetimeValue.IsZero())
+		assert.True(t, summary.FirstOrderDate.IsZero())
+		assert.Empty(t, summary.FavouriteProducts)
+	})
+
+	t.Run("Should keep only the top favourite products", func(t *testing.T) {
+		mockRepository, service := InitServerWithBuyersRepository(t)
+		mockRepository.On("ExistsID", 1).Return(true)
+		mockRepository.On("Orders", mock.Anything, 1).Return([]domain.BuyerOrder{
+			{ID: 1, Lines: []domain.BuyerOrderLine{
+				{ID: 1, ProductID: 4, Quantity: 1}, {ID: 2, ProductID: 3, Quantity: 2},
+				{ID: 3, ProductID: 2, Quantity: 2}, {ID: 4, ProductID: 1, Quantity: 5},
+			}},
+		}, nil)
+
+		summary, err := service.Summary(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, summary.FavouriteProducts, buyer.FavouriteProductsLimit)
+		assert.Equal(t, []int{1, 2, 3}, []int{summary.FavouriteProducts[0].ProductID, summary.FavouriteProducts[1].ProductID, summary.FavouriteProducts[2].ProductID})
+	})
+}
+
 func InitServerWithBuyersRepository(t *testing.T) (*mocks.BuyerRepositoryMock, buyer.Service) {
 	t.Helper()
 	mockRepository := &mocks.BuyerRepositoryMock{}
-	mockService := buyer.NewService(mockRepository)
+	mockService := buyer.NewService(mockRepository, nil)
 	return mockRepository, mockService
 }
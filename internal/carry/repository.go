@@ -5,7 +5,8 @@ import (
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
 )
 
 // Repository encapsulates the storage of a carry.
@@ -15,6 +16,15 @@ type Repository interface {
 	ExistsByCidCarry(ctx context.Context, cid string) bool
 	ReadAllCarriers(ctx context.Context) ([]domain.LocalityCarriersReport, error)
 	ReadCarriersWithLocalityId(ctx context.Context, localityID int) (domain.LocalityCarriersReport, error)
+	GetAll(ctx context.Context) ([]domain.Carry, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Carry, error)
+	GetBasedIn(ctx context.Context, localityIDs []int) ([]domain.Carry, error)
+	Update(ctx context.Context, c domain.Carry) error
+	Delete(ctx context.Context, id int) error
+	Coverage(ctx context.Context, id int) ([]int, error)
+	SetCoverage(ctx context.Context, id int, localityIDs []int) error
+	GetByLocality(ctx context.Context, localityID int) ([]domain.Carry, error)
+	SetAPIKeyHash(ctx context.Context, id int, hash string) error
 }
 
 type repository struct {
@@ -38,6 +48,16 @@ const (
 	ReadAllCarriers = "SELECT L.id, L.locality_name, COUNT(C.id) AS carriers_count " +
 		"FROM localities L LEFT JOIN carriers C ON L.id = C.locality_id " +
 		"GROUP BY L.id, L.locality_name"
+	GetAllCarriers   = "SELECT id, cid, company_name, address, telephone, locality_id FROM carriers ORDER BY id"
+	UpdateCarry      = "UPDATE carriers SET cid = ?, company_name = ?, address = ?, telephone = ?, locality_id = ? WHERE id = ?"
+	DeleteCarry      = "DELETE FROM carriers WHERE id = ?"
+	LockCarry        = "SELECT id FROM carriers WHERE id = ? FOR UPDATE"
+	CoverageQuery    = "SELECT locality_id FROM carrier_localities WHERE carrier_id = ? ORDER BY locality_id"
+	ClearCoverage    = "DELETE FROM carrier_localities WHERE carrier_id = ?"
+	AddCoverage      = "INSERT INTO carrier_localities (carrier_id, locality_id) VALUES (?, ?)"
+	SetAPIKeyHash    = "UPDATE carriers SET api_key_hash = ? WHERE id = ?"
+	CarriersDelivery = "SELECT c.id, c.cid, c.company_name, c.address, c.telephone, c.locality_id FROM carriers c " +
+		"WHERE c.locality_id = ? OR EXISTS (SELECT 1 FROM carrier_localities cl WHERE cl.carrier_id = c.id AND cl.locality_id = ?) ORDER BY c.id"
 )
 
 func (r *repository) Create(ctx context.Context, c domain.Carry) (int, error) {
@@ -123,3 +143,147 @@ func (r *repository) ReadCarriersWithLocalityId(ctx context.Context, localityID
 
 	return l, nil
 }
+
+func (r *repository) GetAll(ctx context.Context) ([]domain.Carry, error) {
+	return r.list(ctx, GetAllCarriers)
+}
+
+// GetByIDs returns the carriers with the given ids.
+func (r *repository) GetByIDs(ctx context.Context, ids []int) ([]domain.Carry, error) {
+	return r.getIn(ctx, "id", ids)
+}
+
+// GetBasedIn returns the carriers based in the given localities.
+func (r *repository) GetBasedIn(ctx context.Context, localityIDs []int) ([]domain.Carry, error) {
+	return r.getIn(ctx, "locality_id", localityIDs)
+}
+
+// getIn returns the carriers whose column holds one of values, in one query.
+func (r *repository) getIn(ctx context.Context, column string, values []int) ([]domain.Carry, error) {
+	carriers := []domain.Carry{}
+	if len(values) == 0 {
+		return carriers, nil
+	}
+	in, args := mysqlutil.In(values)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, cid, company_name, address, telephone, locality_id FROM carriers WHERE "+column+" IN "+in+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c := domain.Carry{}
+		if err := rows.Scan(&c.ID, &c.Cid, &c.CompanyName, &c.Address, &c.Telephone, &c.LocalityId); err != nil {
+			return nil, err
+		}
+		carriers = append(carriers, c)
+	}
+	return carriers, rows.Err()
+}
+
+// GetByLocality lists the carriers based in the locality or covering it.
+func (r *repository) GetByLocality(ctx context.Context, localityID int) ([]domain.Carry, error) {
+	return r.list(ctx, CarriersDelivery, localityID, localityID)
+}
+
+func (r *repository) list(ctx context.Context, query string, args ...interface{}) ([]domain.Carry, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	carriers := []domain.Carry{}
+	for rows.Next() {
+		c := domain.Carry{}
+		if err := rows.Scan(&c.ID, &c.Cid, &c.CompanyName, &c.Address, &c.Telephone, &c.LocalityId); err != nil {
+			return nil, err
+		}
+		carriers = append(carriers, c)
+	}
+	return carriers, rows.Err()
+}
+
+func (r *repository) Update(ctx context.Context, c domain.Carry) error {
+	_, err := r.db.ExecContext(ctx, UpdateCarry, c.Cid, c.CompanyName, c.Address, c.Telephone, c.LocalityId, c.ID)
+	return err
+}
+
+// Delete removes the carrier and its coverage. Carriers still referenced by
+// purchase orders are kept and ErrInUse is returned.
+func (r *repository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, DeleteCarry, id)
+	if err != nil {
+		if mysqlutil.IsRowReferenced(err) {
+			return ErrInUse
+		}
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect < 1 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Coverage lists the localities added to the coverage of the carrier, without
+// its own.
+func (r *repository) Coverage(ctx context.Context, id int) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, CoverageQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	localityIDs := []int{}
+	for rows.Next() {
+		var localityID int
+		if err := rows.Scan(&localityID); err != nil {
+			return nil, err
+		}
+		localityIDs = append(localityIDs, localityID)
+	}
+	return localityIDs, rows.Err()
+}
+
+// SetCoverage replaces the coverage of the carrier in a single transaction.
+// The carrier row is locked so concurrent replacements do not interleave.
+func (r *repository) SetCoverage(ctx context.Context, id int, localityIDs []int) error {
+	return sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, LockCarry, id).Scan(&id); err != nil {
+			if err.Error() == "sql: no rows in result set" {
+				return ErrNotFound
+			}
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, ClearCoverage, id); err != nil {
+			return err
+		}
+		for _, localityID := range localityIDs {
+			if _, err := tx.ExecContext(ctx, AddCoverage, id, localityID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetAPIKeyHash replaces the credential of the carrier, revoking the previous
+// one.
+func (r *repository) SetAPIKeyHash(ctx context.Context, id int, hash string) error {
+	res, err := r.db.ExecContext(ctx, SetAPIKeyHash, hash, id)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect < 1 {
+		return ErrNotFound
+	}
+	return nil
+}
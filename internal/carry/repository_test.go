@@ -149,6 +149,77 @@ func TestReadAllCarriersRepository(t *testing.T) {
 	})
 }
 
+func TestCoverageCarriersRepository(t *testing.T) {
+	t.Run("Should list the carriers based in or covering a locality", func(t *testing.T) {
+		repository := carry.NewRepository(db)
+		repositoryLocality := locality.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		covered, err := repositoryLocality.Save(ctx, localityExpected)
+		assert.NoError(t, err)
+
+		carrier := carryExpected
+		carrier.Cid = "coverage-1"
+		carrier.LocalityId = 1
+		carrierID, err := repository.Create(ctx, carrier)
+		assert.NoError(t, err)
+
+		assert.NoError(t, repository.SetCoverage(ctx, carrierID, []int{covered}))
+		coverage, err := repository.Coverage(ctx, carrierID)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{covered}, coverage)
+
+		carriers, err := repository.GetByLocality(ctx, covered)
+		assert.NoError(t, err)
+		if assert.Len(t, carriers, 1) {
+			assert.Equal(t, carrierID, carriers[0].ID)
+		}
+
+		assert.NoError(t, repository.SetCoverage(ctx, carrierID, []int{}))
+		carriers, err = repository.GetByLocality(ctx, covered)
+		assert.NoError(t, err)
+		assert.Empty(t, carriers)
+	})
+	t.Run("Should return error when the carry does not exist", func(t *testing.T) {
+		repository := carry.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+		defer cancel()
+
+		err := repository.SetCoverage(ctx, 200000, []int{1})
+		assert.ErrorIs(t, err, carry.ErrNotFound)
+	})
+}
+
+func TestDeleteCarriersRepository(t *testing.T) {
+	t.Run("Should delete the carry", func(t *testing.T) {
+		repository := carry.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+		defer cancel()
+
+		carrier := carryExpected
+		carrier.Cid = "delete-1"
+		carrier.LocalityId = 1
+		carrierID, err := repository.Create(ctx, carrier)
+		assert.NoError(t, err)
+
+		assert.NoError(t, repository.Delete(ctx, carrierID))
+		_, err = repository.Get(ctx, carrierID)
+		assert.ErrorIs(t, err, carry.ErrNotFound)
+	})
+	t.Run("Should return error when the carry does not exist", func(t *testing.T) {
+		repository := carry.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+		defer cancel()
+
+		assert.ErrorIs(t, repository.Delete(ctx, 200000), carry.ErrNotFound)
+	})
+}
+
 func TestAllEndpointsRepositoryWithErrorDatabaseClosed(t *testing.T) {
 	db.Close()
 	t.Run("Should return error when there is an ReadAllCarriers database error", func(t *testing.T) {
@@ -2,7 +2,11 @@ package carry
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"sort"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/locality"
@@ -16,12 +20,22 @@ var (
 	ErrInvalidJSON        = errors.New("invalid json")
 	ErrConflictLocalityId = errors.New("locality_id not found")
 	ErrNotFoundLocalityId = errors.New("locality_id not found")
+	ErrInUse              = errors.New("carry is referenced by purchase orders")
 )
 
 type Service interface {
 	Create(ctx context.Context, d domain.Carry) (domain.Carry, error)
 	Get(ctx context.Context, id int) (domain.Carry, error)
 	Read(ctx context.Context, id int) ([]domain.LocalityCarriersReport, error)
+	GetAll(ctx context.Context) ([]domain.Carry, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Carry, error)
+	GetBasedIn(ctx context.Context, localityIDs []int) ([]domain.Carry, error)
+	Update(ctx context.Context, d domain.Carry, id int) (domain.Carry, error)
+	Delete(ctx context.Context, id int) error
+	Coverage(ctx context.Context, id int) (domain.CarrierCoverage, error)
+	SetCoverage(ctx context.Context, id int, localityIDs []int) (domain.CarrierCoverage, error)
+	ByLocality(ctx context.Context, localityID int) ([]domain.Carry, error)
+	IssueCredential(ctx context.Context, id int) (domain.CarrierCredential, error)
 }
 
 type CarryService struct {
@@ -79,3 +93,132 @@ func (c *CarryService) Get(ctx context.Context, id int) (domain.Carry, error) {
 	carry, err := c.repository.Get(ctx, id)
 	return carry, err
 }
+
+func (c *CarryService) GetAll(ctx context.Context) ([]domain.Carry, error) {
+	return c.repository.GetAll(ctx)
+}
+
+// GetByIDs returns the carriers with the given ids.
+func (c *CarryService) GetByIDs(ctx context.Context, ids []int) ([]domain.Carry, error) {
+	return c.repository.GetByIDs(ctx, ids)
+}
+
+// GetBasedIn returns the carriers based in the given localities.
+func (c *CarryService) GetBasedIn(ctx context.Context, localityIDs []int) ([]domain.Carry, error) {
+	return c.repository.GetBasedIn(ctx, localityIDs)
+}
+
+// Update merges the non-empty fields of d into the stored carrier.
+func (c *CarryService) Update(ctx context.Context, d domain.Carry, id int) (domain.Carry, error) {
+	carry, err := c.repository.Get(ctx, id)
+	if err != nil {
+		return domain.Carry{}, err
+	}
+
+	if d.Cid != "" && d.Cid != carry.Cid {
+		if c.repository.ExistsByCidCarry(ctx, d.Cid) {
+			return domain.Carry{}, ErrAlredyExists
+		}
+		carry.Cid = d.Cid
+	}
+	if d.CompanyName != "" {
+		carry.CompanyName = d.CompanyName
+	}
+	if d.Address != "" {
+		carry.Address = d.Address
+	}
+	if d.Telephone != "" {
+		carry.Telephone = d.Telephone
+	}
+	if d.LocalityId != 0 && d.LocalityId != carry.LocalityId {
+		if !c.repositoryLocality.ExistsById(ctx, d.LocalityId) {
+			return domain.Carry{}, ErrConflictLocalityId
+		}
+		carry.LocalityId = d.LocalityId
+	}
+
+	if err := c.repository.Update(ctx, carry); err != nil {
+		return domain.Carry{}, err
+	}
+	return carry, nil
+}
+
+func (c *CarryService) Delete(ctx context.Context, id int) error {
+	return c.repository.Delete(ctx, id)
+}
+
+// Coverage lists the localities the carrier delivers to, its own included.
+func (c *CarryService) Coverage(ctx context.Context, id int) (domain.CarrierCoverage, error) {
+	carry, err := c.repository.Get(ctx, id)
+	if err != nil {
+		return domain.CarrierCoverage{}, err
+	}
+
+	localityIDs, err := c.repository.Coverage(ctx, id)
+	if err != nil {
+		return domain.CarrierCoverage{}, err
+	}
+	return newCoverage(carry, localityIDs), nil
+}
+
+// SetCoverage replaces the localities the carrier delivers to besides its own.
+// Every locality must exist; duplicates and the carrier's own locality are
+// not stored.
+func (c *CarryService) SetCoverage(ctx context.Context, id int, localityIDs []int) (domain.CarrierCoverage, error) {
+	carry, err := c.repository.Get(ctx, id)
+	if err != nil {
+		return domain.CarrierCoverage{}, err
+	}
+
+	seen := map[int]bool{carry.LocalityId: true}
+	coverage := []int{}
+	for _, localityID := range localityIDs {
+		if seen[localityID] {
+			continue
+		}
+		if !c.repositoryLocality.ExistsById(ctx, localityID) {
+			return domain.CarrierCoverage{}, ErrConflictLocalityId
+		}
+		seen[localityID] = true
+		coverage = append(coverage, localityID)
+	}
+
+	if err := c.repository.SetCoverage(ctx, id, coverage); err != nil {
+		return domain.CarrierCoverage{}, err
+	}
+	return newCoverage(carry, coverage), nil
+}
+
+func (c *CarryService) ByLocality(ctx context.Context, localityID int) ([]domain.Carry, error) {
+	if !c.repositoryLocality.ExistsById(ctx, localityID) {
+		return nil, ErrNotFoundLocalityId
+	}
+	return c.repository.GetByLocality(ctx, localityID)
+}
+
+func newCoverage(carry domain.Carry, localityIDs []int) domain.CarrierCoverage {
+	ids := append([]int{carry.LocalityId}, localityIDs...)
+	sort.Ints(ids)
+	return domain.CarrierCoverage{CarrierID: carry.ID, LocalityIDs: ids}
+}
+
+// IssueCredential generates a new API key for the carrier, revoking the
+// previous one. Only its hash is stored, so the key cannot be shown again.
+func (c *CarryService) IssueCredential(ctx context.Context, id int) (domain.CarrierCredential, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return domain.CarrierCredential{}, err
+	}
+	apiKey := hex.EncodeToString(key)
+
+	if err := c.repository.SetAPIKeyHash(ctx, id, HashAPIKey(apiKey)); err != nil {
+		return domain.CarrierCredential{}, err
+	}
+	return domain.CarrierCredential{CarrierID: id, APIKey: apiKey}, nil
+}
+
+// HashAPIKey returns the hash a carrier API key is stored and looked up by.
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
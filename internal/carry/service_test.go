@@ -194,6 +194,148 @@ func TestGetCarriers(t *testing.T) {
 	})
 }
 
+func TestUpdateCarriers(t *testing.T) {
+	stored := domain.Carry{ID: 2, Cid: "1111110", CompanyName: "Teste Livre", Address: "Rua Pedro Dias", Telephone: "3712291281", LocalityId: 1}
+
+	t.Run("Should update only the non-empty fields", func(t *testing.T) {
+		repository, repositoryLocality, service := InitServerWithCarriersRepository(t)
+		updated := stored
+		updated.Cid = "2222220"
+		updated.LocalityId = 3
+		repository.On("Get", mock.Anything, 2).Return(stored, nil)
+		repository.On("ExistsByCidCarry", mock.Anything, "2222220").Return(false)
+		repositoryLocality.On("ExistsById", mock.Anything, 3).Return(true)
+		repository.On("Update", mock.Anything, updated).Return(nil)
+
+		carry, err := service.Update(context.TODO(), domain.Carry{Cid: "2222220", LocalityId: 3}, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, updated, carry)
+	})
+	t.Run("Should return err carry already exists when the new cid is taken", func(t *testing.T) {
+		repository, _, service := InitServerWithCarriersRepository(t)
+		repository.On("Get", mock.Anything, 2).Return(stored, nil)
+		repository.On("ExistsByCidCarry", mock.Anything, "2222220").Return(true)
+
+		_, err := service.Update(context.TODO(), domain.Carry{Cid: "2222220"}, 2)
+
+		assert.ErrorIs(t, err, carry.ErrAlredyExists)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+	t.Run("Should return err locality_id not found when the new locality does not exist", func(t *testing.T) {
+		repository, repositoryLocality, service := InitServerWithCarriersRepository(t)
+		repository.On("Get", mock.Anything, 2).Return(stored, nil)
+		repositoryLocality.On("ExistsById", mock.Anything, 9).Return(false)
+
+		_, err := service.Update(context.TODO(), domain.Carry{LocalityId: 9}, 2)
+
+		assert.ErrorIs(t, err, carry.ErrConflictLocalityId)
+	})
+	t.Run("Should return err not found when the carry does not exist", func(t *testing.T) {
+		repository, _, service := InitServerWithCarriersRepository(t)
+		repository.On("Get", mock.Anything, 2).Return(domain.Carry{}, carry.ErrNotFound)
+
+		_, err := service.Update(context.TODO(), domain.Carry{}, 2)
+
+		assert.ErrorIs(t, err, carry.ErrNotFound)
+	})
+}
+
+func TestSetCoverageCarriers(t *testing.T) {
+	stored := domain.Carry{ID: 2, Cid: "1111110", LocalityId: 3}
+
+	t.Run("Should store the coverage without duplicates nor the own locality", func(t *testing.T) {
+		repository, repositoryLocality, service := InitServerWithCarriersRepository(t)
+		repository.On("Get", mock.Anything, 2).Return(stored, nil)
+		repositoryLocality.On("ExistsById", mock.Anything, mock.Anything).Return(true)
+		repository.On("SetCoverage", mock.Anything, 2, []int{5, 1}).Return(nil)
+
+		coverage, err := service.SetCoverage(context.TODO(), 2, []int{5, 3, 1, 5})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CarrierCoverage{CarrierID: 2, LocalityIDs: []int{1, 3, 5}}, coverage)
+	})
+	t.Run("Should return err locality_id not found when a locality does not exist", func(t *testing.T) {
+		repository, repositoryLocality, service := InitServerWithCarriersRepository(t)
+		repository.On("Get", mock.Anything, 2).Return(stored, nil)
+		repositoryLocality.On("ExistsById", mock.Anything, 5).Return(true)
+		repositoryLocality.On("ExistsById", mock.Anything, 9).Return(false)
+
+		_, err := service.SetCoverage(context.TODO(), 2, []int{5, 9})
+
+		assert.ErrorIs(t, err, carry.ErrConflictLocalityId)
+		repository.AssertNotCalled(t, "SetCoverage", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Should return err not found when the carry does not exist", func(t *testing.T) {
+		repository, _, service := InitServerWithCarriersRepository(t)
+		repository.On("Get", mock.Anything, 2).Return(domain.Carry{}, carry.ErrNotFound)
+
+		_, err := service.SetCoverage(context.TODO(), 2, []int{})
+
+		assert.ErrorIs(t, err, carry.ErrNotFound)
+	})
+}
+
+func TestCoverageCarriers(t *testing.T) {
+	t.Run("Should include the own locality in the coverage", func(t *testing.T) {
+		repository, _, service := InitServerWithCarriersRepository(t)
+		repository.On("Get", mock.Anything, 2).Return(domain.Carry{ID: 2, LocalityId: 4}, nil)
+		repository.On("Coverage", mock.Anything, 2).Return([]int{1, 7}, nil)
+
+		coverage, err := service.Coverage(context.TODO(), 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 4, 7}, coverage.LocalityIDs)
+	})
+}
+
+func TestByLocalityCarriers(t *testing.T) {
+	t.Run("Should list the carriers delivering to the locality", func(t *testing.T) {
+		repository, repositoryLocality, service := InitServerWithCarriersRepository(t)
+		repositoryLocality.On("ExistsById", mock.Anything, 1).Return(true)
+		repository.On("GetByLocality", mock.Anything, 1).Return([]domain.Carry{expectedCarry}, nil)
+
+		carriers, err := service.ByLocality(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Carry{expectedCarry}, carriers)
+	})
+	t.Run("Should return err locality_id not found when the locality does not exist", func(t *testing.T) {
+		_, repositoryLocality, service := InitServerWithCarriersRepository(t)
+		repositoryLocality.On("ExistsById", mock.Anything, 1).Return(false)
+
+		_, err := service.ByLocality(context.TODO(), 1)
+
+		assert.ErrorIs(t, err, carry.ErrNotFoundLocalityId)
+	})
+}
+
+func TestIssueCredentialCarriers(t *testing.T) {
+	t.Run("Should store the hash of a new key", func(t *testing.T) {
+		repository, _, service := InitServerWithCarriersRepository(t)
+		var storedHash string
+		repository.On("SetAPIKeyHash", mock.Anything, 2, mock.Anything).Run(func(args mock.Arguments) {
+			storedHash = args.String(2)
+		}).Return(nil)
+
+		credential, err := service.IssueCredential(context.TODO(), 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, credential.CarrierID)
+		assert.Len(t, credential.APIKey, 64)
+		assert.Equal(t, carry.HashAPIKey(credential.APIKey), storedHash)
+		assert.NotEqual(t, credential.APIKey, storedHash)
+	})
+	t.Run("Should return err not found when the carry does not exist", func(t *testing.T) {
+		repository, _, service := InitServerWithCarriersRepository(t)
+		repository.On("SetAPIKeyHash", mock.Anything, 2, mock.Anything).Return(carry.ErrNotFound)
+
+		_, err := service.IssueCredential(context.TODO(), 2)
+
+		assert.ErrorIs(t, err, carry.ErrNotFound)
+	})
+}
+
 func InitServerWithCarriersRepository(t *testing.T) (*mocks.CarryRepositoryMock, *mocksLocality.LocalityRepositoryMock, carry.Service) {
 	t.Helper()
 	mockRepositoryCarriers := &mocks.CarryRepositoryMock{}
@@ -1,16 +1,29 @@
 package domain
 
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
+)
+
+// Buyer is a customer of the warehouses. LocalityID is where its orders are
+// delivered and is nil for buyers registered without one.
 type Buyer struct {
 	ID           int    `json:"id"`
 	CardNumberID string `json:"card_number_id"`
 	FirstName    string `json:"first_name"`
 	LastName     string `json:"last_name"`
+	LocalityID   *int   `json:"locality_id,omitempty"`
 }
 
 type BuyerRequest struct {
 	CardNumberID string `json:"card_number_id"`
 	FirstName    string `json:"first_name"`
 	LastName     string `json:"last_name"`
+	LocalityID   *int   `json:"locality_id"`
+}
+
+type BuyerCardNumberRequest struct {
+	CardNumberID string `json:"card_number_id" binding:"required"`
 }
 
 type BuyerOrders struct {
@@ -21,6 +34,53 @@ type BuyerOrders struct {
 	PurchaseOrdersCount int    `json:"purchase_orders_count"`
 }
 
+// BuyerOrder is a purchase order of a buyer with its lines. Total is the sum
+// of the lines converted to the reporting currency.
+type BuyerOrder struct {
+	ID           int              `json:"id"`
+	OrderNumber  string           `json:"order_number"`
+	OrderDate    datetime.Time    `json:"order_date"`
+	TrackingCode string           `json:"tracking_code"`
+	Status       string           `json:"status"`
+	Total        money.Money      `json:"total"`
+	Lines        []BuyerOrderLine `json:"lines"`
+}
+
+// BuyerOrderLine is an order detail priced at the sale price of its product
+// record, in the currency of the record.
+type BuyerOrderLine struct {
+	ID                int         `json:"id"`
+	ProductRecordID   int         `json:"product_record_id"`
+	ProductID         int         `json:"product_id"`
+	Description       string      `json:"description"`
+	Quantity          int         `json:"quantity"`
+	SalePrice         money.Money `json:"sale_price"`
+	Total             money.Money `json:"total"`
+	CleanLinessStatus string      `json:"clean_liness_status"`
+	Temperature       float64     `json:"temperature"`
+}
+
+// BuyerSummary adds up every order of a buyer. Amounts are in the reporting
+// currency and dates are unset when the buyer has no orders.
+type BuyerSummary struct {
+	BuyerID           int                     `json:"buyer_id"`
+	OrdersCount       int                     `json:"orders_count"`
+	LifetimeValue     money.Money             `json:"lifetime_value"`
+	AverageOrderValue money.Money             `json:"average_order_value"`
+	FirstOrderDate    datetime.Time           `json:"first_order_date"`
+	LastOrderDate     datetime.Time           `json:"last_order_date"`
+	FavouriteProducts []BuyerFavouriteProduct `json:"favourite_products"`
+}
+
+// BuyerFavouriteProduct is one of the products a buyer bought the most units
+// of.
+type BuyerFavouriteProduct struct {
+	ProductID   int    `json:"product_id"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	OrdersCount int    `json:"orders_count"`
+}
+
 type BuyerResponse struct {
 	Data []Buyer `json:"data"`
 }
@@ -36,3 +96,11 @@ type BuyerOrdersResponseID struct {
 type BuyerOrdersResponse struct {
 	Data []BuyerOrders `json:"data"`
 }
+
+type BuyerOrderListResponse struct {
+	Data []BuyerOrder `json:"data"`
+}
+
+type BuyerSummaryResponse struct {
+	Data BuyerSummary `json:"data"`
+}
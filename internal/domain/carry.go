@@ -9,6 +9,26 @@ type Carry struct {
 	LocalityId  int    `json:"locality_id"`
 }
 
+// CarrierCoverageRequest replaces the localities a carrier delivers to besides
+// its own. An empty list leaves it delivering to its own locality only.
+type CarrierCoverageRequest struct {
+	LocalityIDs []int `json:"locality_ids" binding:"required"`
+}
+
+// CarrierCoverage lists every locality a carrier delivers to, its own
+// included, in ascending order.
+type CarrierCoverage struct {
+	CarrierID   int   `json:"carrier_id"`
+	LocalityIDs []int `json:"locality_ids"`
+}
+
+// CarrierCredential is the API key a carrier authenticates with. The key is
+// only shown when issued; the carrier keeps a hash of it.
+type CarrierCredential struct {
+	CarrierID int    `json:"carrier_id"`
+	APIKey    string `json:"api_key"`
+}
+
 type LocalityCarriersReport struct {
 	LocalityID    int    `json:"locality_id"`
 	LocalityName  string `json:"locality_name"`
@@ -26,3 +46,11 @@ type CarryResponseId struct {
 type LocalityCarriersResponse struct {
 	Data []LocalityCarriersReport `json:"data"`
 }
+
+type CarrierCoverageResponse struct {
+	Data CarrierCoverage `json:"data"`
+}
+
+type CarrierCredentialResponse struct {
+	Data CarrierCredential `json:"data"`
+}
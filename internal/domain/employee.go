@@ -1,11 +1,14 @@
 package domain
 
+import "github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+
 type Employee struct {
 	ID           int    `json:"id"`
 	CardNumberID string `json:"card_number_id"`
 	FirstName    string `json:"first_name"`
 	LastName     string `json:"last_name"`
 	WarehouseID  int    `json:"warehouse_id"`
+	Version      int    `json:"-"`
 }
 
 type EmployeeResponse struct {
@@ -15,3 +18,43 @@ type EmployeeResponse struct {
 type EmployeeResponseID struct {
 	Data Employee `json:"data"`
 }
+
+// EmployeeAssignment is a period an employee spent in a warehouse. The
+// current assignment has no EndedAt.
+type EmployeeAssignment struct {
+	ID          int           `json:"id"`
+	EmployeeID  int           `json:"employee_id"`
+	WarehouseID int           `json:"warehouse_id"`
+	StartedAt   datetime.Time `json:"started_at"`
+	EndedAt     datetime.Time `json:"ended_at"`
+}
+
+type Shift struct {
+	ID          int           `json:"id"`
+	EmployeeID  int           `json:"employee_id"`
+	WarehouseID int           `json:"warehouse_id"`
+	StartsAt    datetime.Time `json:"starts_at"`
+	EndsAt      datetime.Time `json:"ends_at"`
+}
+
+// ShiftRequest schedules a shift. WarehouseID defaults to the warehouse the
+// employee is assigned to.
+type ShiftRequest struct {
+	WarehouseID int           `json:"warehouse_id"`
+	StartsAt    datetime.Time `json:"starts_at"`
+	EndsAt      datetime.Time `json:"ends_at"`
+}
+
+type RosterShift struct {
+	Shift
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// Roster lists the shifts worked in a warehouse on a given day, including
+// those that start the day before or end the day after.
+type Roster struct {
+	WarehouseID int           `json:"warehouse_id"`
+	Date        string        `json:"date"`
+	Shifts      []RosterShift `json:"shifts"`
+}
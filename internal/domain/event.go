@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+)
+
+// Domain event types, named after the aggregate they belong to.
+const (
+	EventProductCreated             = "product.created"
+	EventProductUpdated             = "product.updated"
+	EventProductDeleted             = "product.deleted"
+	EventProductBatchCreated        = "product_batch.created"
+	EventPurchaseOrderCreated       = "purchase_order.created"
+	EventPurchaseOrderAssigned      = "purchase_order.assigned"
+	EventPurchaseOrderStatusChanged = "purchase_order.status_changed"
+	EventInboundOrderCreated        = "inbound_order.created"
+	EventTransferCreated            = "transfer.created"
+	EventTransferDispatched         = "transfer.dispatched"
+	EventTransferReceived           = "transfer.received"
+	EventSectionUpdated             = "section.updated"
+	EventSellerCreated              = "seller.created"
+	EventSellerUpdated              = "seller.updated"
+	EventSellerDeleted              = "seller.deleted"
+)
+
+// Event is something that happened to an aggregate, such as a product or a
+// seller. Data is the JSON of the aggregate after the change, or of its id
+// when it was deleted. Events of the same aggregate are published in the
+// order they happened. Attempts counts the failed attempts to publish it and
+// is not part of what is published.
+type Event struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id"`
+	Type          string          `json:"type"`
+	Data          json.RawMessage `json:"data"`
+	OccurredAt    datetime.Time   `json:"occurred_at"`
+	Attempts      int             `json:"-"`
+}
+
+// DeletedEvent is the data of the events of deleted aggregates.
+type DeletedEvent struct {
+	ID int `json:"id"`
+}
@@ -0,0 +1,9 @@
+package domain
+
+// GraphQLRequest is a GraphQL query with the operation to run and the values
+// of its variables.
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
@@ -1,12 +1,41 @@
 package domain
 
+import "github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+
 type InboundOrders struct {
-	ID             int    `json:"id"`
-	OrderDate      string `json:"order_date"`
-	OrderNumber    string `json:"order_number"`
-	EmployeeID     int    `json:"employee_id"`
-	ProductBatchID int    `json:"product_batch_id"`
-	WarehouseID    int    `json:"warehouse_id"`
+	ID             int           `json:"id"`
+	OrderDate      datetime.Time `json:"order_date"`
+	OrderNumber    string        `json:"order_number"`
+	EmployeeID     int           `json:"employee_id"`
+	ProductBatchID int           `json:"product_batch_id"`
+	WarehouseID    int           `json:"warehouse_id"`
+}
+
+// InboundOrderFilter narrows an inbound order listing. Zero fields match every
+// order; From is inclusive and To exclusive.
+type InboundOrderFilter struct {
+	EmployeeID  int
+	WarehouseID int
+	From        datetime.Time
+	To          datetime.Time
+}
+
+// Granularities of the inbound orders report buckets.
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// InboundOrdersReportFilter narrows the inbound orders report. Zero fields
+// match every order; From is inclusive and To exclusive. WarehouseID matches
+// the warehouse the employee was assigned to when each order was received.
+// Without a Granularity the report has no buckets.
+type InboundOrdersReportFilter struct {
+	WarehouseID int
+	From        datetime.Time
+	To          datetime.Time
+	Granularity string
 }
 
 type InboundOrdersReport struct {
@@ -16,12 +45,39 @@ type InboundOrdersReport struct {
 	LastName           string `json:"last_name"`
 	WarehouseID        int    `json:"warehouse_id"`
 	InboundOrdersCount int    `json:"inbound_orders_count"`
+	// UnitsReceived is the initial quantity of the batches the orders
+	// brought in.
+	UnitsReceived int `json:"units_received"`
+	// Warehouses splits the count by the warehouse the employee was
+	// assigned to when each order was received.
+	Warehouses []InboundOrdersByWarehouse `json:"warehouses,omitempty"`
+	// Buckets splits the count by day, week or month. Periods without
+	// orders are left out.
+	Buckets []InboundOrdersBucket `json:"buckets,omitempty"`
+}
+
+type InboundOrdersByWarehouse struct {
+	WarehouseID        int `json:"warehouse_id"`
+	InboundOrdersCount int `json:"inbound_orders_count"`
+	UnitsReceived      int `json:"units_received"`
+}
+
+// InboundOrdersBucket holds the orders of the period starting on Start, a
+// "2006-01-02" date. Weeks start on Monday.
+type InboundOrdersBucket struct {
+	Start              string `json:"start"`
+	InboundOrdersCount int    `json:"inbound_orders_count"`
+	UnitsReceived      int    `json:"units_received"`
 }
 
 type InboundOrdersResponseId struct {
 	Data InboundOrders `json:"data"`
 }
 
+type InboundOrdersReportsResponse struct {
+	Data []InboundOrdersReport `json:"data"`
+}
+
 type InboundOrdersReportResponse struct {
 	Data InboundOrdersReport `json:"data"`
 }
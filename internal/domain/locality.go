@@ -9,13 +9,13 @@ type Locality struct {
 type LocalityInput struct {
 	ID           int    `json:"id"`
 	LocalityName string `json:"locality_name"`
-	IdProvince int `json:"id_province"`
+	IdProvince   int    `json:"id_province"`
 }
 
 type LocalityReport struct {
-	IdLocality int `json:"id_locality"`
+	IdLocality   int    `json:"id_locality"`
 	LocalityName string `json:"locality_name"`
-	SellersCount int `json:"sellers_count"`
+	SellersCount int    `json:"sellers_count"`
 }
 
 type LocalityResponseId struct {
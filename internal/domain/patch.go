@@ -0,0 +1,11 @@
+package domain
+
+import "encoding/json"
+
+// Patch is a JSON merge patch (RFC 7396) to apply to a stored resource.
+// Version is the version of the resource the patch was written against, or 0
+// to apply it to any version; it is ignored by resources without versions.
+type Patch struct {
+	Document json.RawMessage
+	Version  int
+}
@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"math"
+	"strconv"
+)
+
 // Product represents an underlying URL with statistics on how it is used.
 type Product struct {
 	ID             int     `json:"id"`
@@ -16,6 +21,78 @@ type Product struct {
 	SellerID       int     `json:"seller_id"`
 }
 
+// Volume is the space taken by one unit of the product, in the cube of the
+// unit its dimensions are given in. It is 0 if a dimension is missing.
+func (p Product) Volume() float64 {
+	return round(decimal(p.Height) * decimal(p.Length) * decimal(p.Width))
+}
+
+// VolumetricWeight is the weight charged for the space the product takes, its
+// volume divided by divisor, the volume a carrier bills as one unit of weight.
+func (p Product) VolumetricWeight(divisor float64) float64 {
+	if divisor <= 0 {
+		return 0
+	}
+	return round(p.Volume() / divisor)
+}
+
+// Density is the net weight of the product per unit of volume. It is 0 if the
+// product has no volume.
+func (p Product) Density() float64 {
+	volume := p.Volume()
+	if volume == 0 {
+		return 0
+	}
+	return round(decimal(p.Netweight) / volume)
+}
+
+// Occupancy is the section capacity taken by quantity units of a product of
+// the given volume, rounded up to whole capacity units.
+func Occupancy(quantity int, volume float64) int {
+	return int(math.Ceil(round(float64(quantity) * volume)))
+}
+
+// FittingUnits is the number of whole units of a product of the given volume
+// that fit into capacity. It is 0 if the product has no volume.
+func FittingUnits(capacity int, volume float64) int {
+	if volume <= 0 || capacity <= 0 {
+		return 0
+	}
+	return int(math.Floor(round(float64(capacity) / volume)))
+}
+
+// decimal returns f as the float64 closest to its shortest decimal form, so
+// a dimension stored as 1.2 is used as 1.2 rather than 1.2000000476837158.
+func decimal(f float32) float64 {
+	d, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'f', -1, 32), 64)
+	return d
+}
+
+// round rounds f to 6 decimals, dropping the error of float arithmetic.
+func round(f float64) float64 {
+	return math.Round(f*1e6) / 1e6
+}
+
+// MeasuredProduct is a product along with the measures computed from its
+// dimensions and weight.
+type MeasuredProduct struct {
+	Product
+	Volume           float64 `json:"volume"`
+	VolumetricWeight float64 `json:"volumetric_weight"`
+	Density          float64 `json:"density"`
+}
+
+// Measure returns p with its measures, the volumetric weight computed with
+// divisor.
+func Measure(p Product, divisor float64) MeasuredProduct {
+	return MeasuredProduct{
+		Product:          p,
+		Volume:           p.Volume(),
+		VolumetricWeight: p.VolumetricWeight(divisor),
+		Density:          p.Density(),
+	}
+}
+
 type ProductRequest struct {
 	Description    string  `json:"description"`
 	ExpirationRate float32 `json:"expiration_rate"`
@@ -38,6 +115,51 @@ type ProductResponseById struct {
 	Data Product `json:"data"`
 }
 
+// ProductSearch is a product search. Query is matched against the description
+// and code of the products and the other fields narrow the results, zero
+// fields matching every product. Freezable products are those recommended to
+// be kept at or below 0 degrees; a nil Freezable matches them all.
+type ProductSearch struct {
+	Query         string
+	ProductTypeID int
+	SellerID      int
+	MinWeight     float64
+	MaxWeight     float64
+	Freezable     *bool
+}
+
+// ProductHit is a product found by a search. Highlights holds, for each of
+// the fields the query matched, an HTML fragment of the field with the
+// matched words wrapped in <em>.
+type ProductHit struct {
+	MeasuredProduct
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// ProductFacet is the number of products found for one product type or
+// seller.
+type ProductFacet struct {
+	ID    int `json:"id"`
+	Count int `json:"count"`
+}
+
+// ProductFacets count the products found by product type and seller. Each
+// facet ignores its own filter, so the counts of the other values show what
+// changing it would find.
+type ProductFacets struct {
+	ProductTypes []ProductFacet `json:"product_types"`
+	Sellers      []ProductFacet `json:"sellers"`
+}
+
+// ProductSearchResult holds the most relevant products found, along with the
+// total number of products found and their facets.
+type ProductSearchResult struct {
+	Total    int           `json:"total"`
+	Products []ProductHit  `json:"products"`
+	Facets   ProductFacets `json:"facets"`
+}
+
 // product record report
 type ProductRecordReport struct {
 	ProductID    int    `json:"product_id"`
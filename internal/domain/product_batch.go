@@ -2,36 +2,39 @@ package domain
 
 import (
 	"errors"
-	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 )
 
 type ProductBatch struct {
-	ID                 int    `json:"id"`
-	BatchNumber        int    `json:"batch_number" binding:"required"`
-	CurrentQuantity    int    `json:"current_quantity" binding:"required" `
-	CurrentTemperature int    `json:"current_temperature" binding:"required"`
-	DueDate            string `json:"due_date" binding:"required"`
-	InitialQuantity    int    `json:"initial_quantity" binding:"required"`
-	ManufacturingDate  string `json:"manufacturing_date" binding:"required"`
-	ManufacturingHour  int    `json:"manufacturing_hour" binding:"required"`
-	MinimumTemperature int    `json:"minimum_temperature" binding:"required"`
-	ProductID          int    `json:"product_id" binding:"required" min:"1"`
-	SectionID          int    `json:"section_id" binding:"required" min:"1"`
+	ID                 int           `json:"id"`
+	BatchNumber        int           `json:"batch_number" binding:"required"`
+	CurrentQuantity    int           `json:"current_quantity" binding:"required" `
+	CurrentTemperature int           `json:"current_temperature" binding:"required"`
+	DueDate            datetime.Time `json:"due_date" binding:"required"`
+	InitialQuantity    int           `json:"initial_quantity" binding:"required"`
+	ManufacturingDate  datetime.Time `json:"manufacturing_date" binding:"required"`
+	ManufacturingHour  int           `json:"manufacturing_hour" binding:"required"`
+	MinimumTemperature int           `json:"minimum_temperature" binding:"required"`
+	ProductID          int           `json:"product_id" binding:"required" min:"1"`
+	SectionID          int           `json:"section_id" binding:"required" min:"1"`
+}
+
+// ProductBatchFilter narrows a product batch listing. Zero fields match
+// every batch.
+type ProductBatchFilter struct {
+	SectionID int
+	ProductID int
 }
 
 var (
 	ErrInvalidManufacturingDate = errors.New("invalid manufacturing date")
 )
 
-const layout = "2006-01-02"
-
+// Validate checks the fields the JSON binding cannot: both dates are
+// required.
 func (p *ProductBatch) Validate() error {
-	_, err := time.Parse(layout, p.ManufacturingDate)
-	if err != nil {
-		return ErrInvalidManufacturingDate
-	}
-	_, err = time.Parse(layout, p.DueDate)
-	if err != nil {
+	if p.ManufacturingDate.IsZero() || p.DueDate.IsZero() {
 		return ErrInvalidManufacturingDate
 	}
 	return nil
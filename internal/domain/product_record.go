@@ -1,20 +1,41 @@
 package domain
 
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
+)
+
 type ProductRecord struct {
-	ID             int    `json:"id"`
-	LastUpdateDate string `json:"last_update_date"`
-	PurchasePrice  int    `json:"purchase_price"`
-	SalePrice      int    `json:"sale_price"`
-	ProductID      int    `json:"product_id"`
+	ID             int           `json:"id"`
+	LastUpdateDate datetime.Time `json:"last_update_date"`
+	PurchasePrice  money.Money   `json:"purchase_price"`
+	SalePrice      money.Money   `json:"sale_price"`
+	ProductID      int           `json:"product_id"`
+	// AllowNegativeMargin lets a record with a sale price below its purchase
+	// price be saved. It is not persisted.
+	AllowNegativeMargin bool `json:"-"`
 }
 
 type ProductRecordRequest struct {
-	LastUpdateDate string `json:"last_update_date"`
-	PurchasePrice  int    `json:"purchase_price"`
-	SalePrice      int    `json:"sale_price"`
-	ProductID      int    `json:"product_id"`
+	LastUpdateDate      datetime.Time `json:"last_update_date"`
+	PurchasePrice       money.Money   `json:"purchase_price"`
+	SalePrice           money.Money   `json:"sale_price"`
+	ProductID           int           `json:"product_id"`
+	AllowNegativeMargin bool          `json:"allow_negative_margin"`
 }
 
 type ProductRecordResponseById struct {
 	Data ProductRecord `json:"data"`
 }
+
+// ProductPrice is the price of a product in effect at a given moment.
+type ProductPrice struct {
+	ProductID      int           `json:"product_id"`
+	RecordID       int           `json:"record_id"`
+	At             datetime.Time `json:"at"`
+	EffectiveSince datetime.Time `json:"effective_since"`
+	PurchasePrice  money.Money   `json:"purchase_price"`
+	SalePrice      money.Money   `json:"sale_price"`
+	Margin         money.Money   `json:"margin"`
+	MarginPercent  float64       `json:"margin_percent"`
+}
@@ -1,25 +1,29 @@
 package domain
 
+import "github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+
 type PurchaseOrders struct {
-	ID              int    `json:"id"`
-	OrderNumber     string `json:"order_number"`
-	OrderDate       string `json:"order_date"`
-	TrackingCode    string `json:"tracking_code"`
-	BuyerID         int    `json:"buyer_id"`
-	ProductRecordID int    `json:"product_record_id"`
-	OrderStatusID   int    `json:"order_status_id"`
+	ID              int           `json:"id"`
+	OrderNumber     string        `json:"order_number"`
+	OrderDate       datetime.Time `json:"order_date"`
+	TrackingCode    string        `json:"tracking_code"`
+	BuyerID         int           `json:"buyer_id"`
+	ProductRecordID int           `json:"product_record_id"`
+	OrderStatusID   int           `json:"order_status_id"`
+	CarrierID       int           `json:"carrier_id,omitempty"`
+	WarehouseID     int           `json:"warehouse_id,omitempty"`
 }
 
 type PurchaseOrdersGetAll struct {
-	ID              int    `json:"id"`
-	OrderNumber     string `json:"order_number"`
-	OrderDate       string `json:"order_date"`
-	TrackingCode    string `json:"tracking_code"`
-	BuyerID         int    `json:"buyer_id"`
-	ProductRecordID int    `json:"product_record_id"`
-	OrderStatusID   int    `json:"order_status_id"`
-	CarrierID       int    `json:"carrier_id"`
-	WarehouseID     int    `json:"warehouse_id"`
+	ID              int           `json:"id"`
+	OrderNumber     string        `json:"order_number"`
+	OrderDate       datetime.Time `json:"order_date"`
+	TrackingCode    string        `json:"tracking_code"`
+	BuyerID         int           `json:"buyer_id"`
+	ProductRecordID int           `json:"product_record_id"`
+	OrderStatusID   int           `json:"order_status_id"`
+	CarrierID       int           `json:"carrier_id"`
+	WarehouseID     int           `json:"warehouse_id"`
 }
 
 type PurchaseOrdersResponse struct {
@@ -29,3 +33,65 @@ type PurchaseOrdersResponse struct {
 type PurchaseOrdersResponseID struct {
 	Data PurchaseOrders `json:"data"`
 }
+
+// Place locates a warehouse, carrier or buyer in the locality, province and
+// country hierarchy. Unknown levels are zero.
+type Place struct {
+	LocalityID int `json:"locality_id"`
+	ProvinceID int `json:"province_id"`
+	CountryID  int `json:"country_id"`
+}
+
+// OrderDemand is what an order needs shipped: the units of each product,
+// keyed by product id, and where the buyer takes delivery.
+type OrderDemand struct {
+	OrderID     int
+	Destination Place
+	Products    map[int]int
+}
+
+// AssignmentWarehouse is a warehouse holding stock of an order's products,
+// with the units of each product keyed by product id.
+type AssignmentWarehouse struct {
+	ID    int
+	Place Place
+	Stock map[int]int
+}
+
+// AssignmentCarrier is a carrier that may deliver an order. Covers is set
+// when the destination is in its coverage, and Load counts the orders
+// already assigned to it.
+type AssignmentCarrier struct {
+	ID     int
+	Place  Place
+	Covers bool
+	Load   int
+}
+
+// PurchaseOrderAssignmentRequest selects the strategy used to assign an
+// order. A non-zero WarehouseID or CarrierID overrides the strategy choice.
+type PurchaseOrderAssignmentRequest struct {
+	Strategy    string `json:"strategy"`
+	WarehouseID int    `json:"warehouse_id"`
+	CarrierID   int    `json:"carrier_id"`
+}
+
+// PurchaseOrderAssignment is the warehouse and carrier recorded on an order.
+// Strategy is "manual" when both were overridden.
+type PurchaseOrderAssignment struct {
+	OrderID     int    `json:"order_id"`
+	WarehouseID int    `json:"warehouse_id"`
+	CarrierID   int    `json:"carrier_id"`
+	Strategy    string `json:"strategy"`
+}
+
+// PurchaseOrderStatusChange is the status a shipment event set on an order.
+type PurchaseOrderStatusChange struct {
+	OrderID      int    `json:"order_id"`
+	OrderStatus  string `json:"order_status"`
+	TrackingCode string `json:"tracking_code"`
+}
+
+type PurchaseOrderAssignmentResponse struct {
+	Data PurchaseOrderAssignment `json:"data"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+
+// ReceiptRequest receives goods into a warehouse: the batch being stored and
+// the inbound order that records it. OrderDate defaults to now.
+type ReceiptRequest struct {
+	OrderNumber  string        `json:"order_number" binding:"required"`
+	OrderDate    datetime.Time `json:"order_date"`
+	EmployeeID   int           `json:"employee_id" binding:"required"`
+	ProductBatch ProductBatch  `json:"product_batch" binding:"required"`
+}
+
+// Receipt is the inbound order and the product batch created together by a
+// receipt.
+type Receipt struct {
+	InboundOrder InboundOrders `json:"inbound_order"`
+	ProductBatch ProductBatch  `json:"product_batch"`
+}
@@ -10,6 +10,11 @@ var (
 	ErrModifySection = errors.New("cannot modify Section")
 )
 
+// Section is a part of a warehouse storing products of one type. Its
+// capacities are volumes, in the cube of the unit product dimensions are
+// given in, so a unit of product takes as much capacity as its volume.
+// CurrentCapacity is the capacity its batches take, computed whenever the
+// section is read; a value sent when creating or updating it is ignored.
 type Section struct {
 	ID                 int `json:"id"`
 	SectionNumber      int `json:"section_number"`
@@ -22,6 +27,31 @@ type Section struct {
 	ProductTypeID      int `json:"product_type_id"`
 }
 
+// RemainingCapacity is the capacity of the section not taken by its
+// batches, 0 if it is overfilled.
+func (s Section) RemainingCapacity() int {
+	if s.CurrentCapacity >= s.MaximumCapacity {
+		return 0
+	}
+	return s.MaximumCapacity - s.CurrentCapacity
+}
+
+// SectionFit is how many units of a product fit into the capacity a section
+// has left, capacities being volumes in the cube of the unit product
+// dimensions are given in.
+type SectionFit struct {
+	SectionID         int     `json:"section_id"`
+	ProductID         int     `json:"product_id"`
+	CurrentCapacity   int     `json:"current_capacity"`
+	RemainingCapacity int     `json:"remaining_capacity"`
+	ProductVolume     float64 `json:"product_volume"`
+	Units             int     `json:"units"`
+}
+
+type SectionFitRequest struct {
+	ProductID int `json:"product_id"`
+}
+
 type SectionsResponse struct {
 	Data []Section `json:"data"`
 }
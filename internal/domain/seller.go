@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
+)
+
 type Seller struct {
 	ID          int    `json:"id"`
 	CID         int    `json:"cid"`
@@ -7,6 +12,7 @@ type Seller struct {
 	Address     string `json:"address"`
 	Telephone   string `json:"telephone"`
 	LocalityId  int    `json:"locality_id"`
+	Version     int    `json:"-"`
 }
 
 type SellerResponse struct {
@@ -15,3 +21,23 @@ type SellerResponse struct {
 type SellerResponseId struct {
 	Data Seller `json:"data"`
 }
+
+// SellerDashboard summarises the catalogue, stock and sales of a seller. Units
+// sold and revenue only count orders placed between From (inclusive) and To
+// (exclusive); an unset bound is open.
+type SellerDashboard struct {
+	SellerID       int                      `json:"seller_id"`
+	From           datetime.Time            `json:"from"`
+	To             datetime.Time            `json:"to"`
+	ProductsByType []SellerProductTypeCount `json:"products_by_type"`
+	TotalStock     int                      `json:"total_stock"`
+	StockValue     []money.Money            `json:"stock_value"`
+	UnitsSold      int                      `json:"units_sold"`
+	Revenue        []money.Money            `json:"revenue"`
+}
+
+type SellerProductTypeCount struct {
+	ProductTypeID int    `json:"product_type_id"`
+	Description   string `json:"description"`
+	ProductsCount int    `json:"products_count"`
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+
+// Shipment event types, in the order a shipment normally goes through them.
+// An exception can happen at any point before delivery.
+const (
+	ShipmentPicked     = "picked"
+	ShipmentDispatched = "dispatched"
+	ShipmentInTransit  = "in_transit"
+	ShipmentDelivered  = "delivered"
+	ShipmentException  = "exception"
+)
+
+// Descriptions of the order statuses set by shipment events.
+const (
+	OrderStatusDelivered = "Delivered"
+	OrderStatusException = "Exception"
+)
+
+// Shipment is the delivery of a purchase order, told by the events its
+// carrier reported, oldest first. Status is the type of the last reported
+// event and is empty until the first one.
+type Shipment struct {
+	TrackingCode string          `json:"tracking_code"`
+	OrderID      int             `json:"order_id"`
+	OrderNumber  string          `json:"order_number"`
+	OrderStatus  string          `json:"order_status"`
+	CarrierID    int             `json:"carrier_id"`
+	Status       string          `json:"status"`
+	Events       []ShipmentEvent `json:"events"`
+}
+
+// ShipmentEvent is a step of a shipment. OccurredAt is when the carrier says
+// it happened and RecordedAt when it was reported.
+type ShipmentEvent struct {
+	ID         int           `json:"id"`
+	CarrierID  int           `json:"carrier_id"`
+	Type       string        `json:"type"`
+	Location   string        `json:"location"`
+	OccurredAt datetime.Time `json:"occurred_at"`
+	RecordedAt datetime.Time `json:"recorded_at"`
+}
+
+type ShipmentEventRequest struct {
+	Type       string        `json:"type" binding:"required"`
+	Location   string        `json:"location" binding:"required"`
+	OccurredAt datetime.Time `json:"occurred_at"`
+}
+
+// ShipmentOrder is what a shipment needs to know about its purchase order.
+type ShipmentOrder struct {
+	ID           int
+	OrderNumber  string
+	TrackingCode string
+	OrderStatus  string
+	CarrierID    int
+}
+
+type ShipmentResponse struct {
+	Data Shipment `json:"data"`
+}
+
+type ShipmentEventResponse struct {
+	Data ShipmentEvent `json:"data"`
+}
@@ -0,0 +1,20 @@
+package domain
+
+// Names of the live warehouse activity events sent on the stream.
+const (
+	StreamInboundOrder     = "inbound_order"
+	StreamProductBatch     = "product_batch"
+	StreamTransfer         = "transfer"
+	StreamTemperatureAlert = "temperature_alert"
+)
+
+// TemperatureAlert reports a section, or a batch stored in it, whose
+// current temperature is below its minimum temperature. BatchID is zero
+// for section readings.
+type TemperatureAlert struct {
+	WarehouseID        int `json:"warehouse_id"`
+	SectionID          int `json:"section_id"`
+	BatchID            int `json:"batch_id,omitempty"`
+	CurrentTemperature int `json:"current_temperature"`
+	MinimumTemperature int `json:"minimum_temperature"`
+}
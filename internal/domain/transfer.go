@@ -0,0 +1,52 @@
+package domain
+
+import "github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+
+// Transfer states, in the only order a transfer can go through them.
+const (
+	TransferDraft     = "draft"
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+)
+
+// Transfer moves some or all of the stock of a product batch from one section
+// to another. MovedBatchID is the batch that actually travels: the original
+// batch when all of it is moved, or the batch split off from it otherwise.
+type Transfer struct {
+	ID              int           `json:"id"`
+	ProductBatchID  int           `json:"product_batch_id"`
+	MovedBatchID    int           `json:"moved_batch_id,omitempty"`
+	Quantity        int           `json:"quantity"`
+	SourceSectionID int           `json:"source_section_id"`
+	TargetSectionID int           `json:"target_section_id"`
+	Status          string        `json:"status"`
+	CreatedAt       datetime.Time `json:"created_at"`
+	DispatchedAt    datetime.Time `json:"dispatched_at"`
+	ReceivedAt      datetime.Time `json:"received_at"`
+}
+
+type TransferRequest struct {
+	ProductBatchID  int `json:"product_batch_id" binding:"required"`
+	Quantity        int `json:"quantity" binding:"required"`
+	SourceSectionID int `json:"source_section_id" binding:"required"`
+	TargetSectionID int `json:"target_section_id" binding:"required"`
+}
+
+// TransferBatch is what a transfer needs to know about the batch it moves.
+// Volume is the volume of one unit of its product.
+type TransferBatch struct {
+	ID              int
+	ProductTypeID   int
+	SectionID       int
+	CurrentQuantity int
+	Volume          float64
+}
+
+// TransferSection is what a transfer needs to know about its target section.
+// Occupied is the capacity taken by every batch stored in it.
+type TransferSection struct {
+	ID              int
+	ProductTypeID   int
+	MaximumCapacity int
+	Occupied        int
+}
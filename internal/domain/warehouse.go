@@ -1,13 +1,18 @@
 package domain
 
+import "github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+
+// Warehouse is a building storing products in sections. Its minimum capacity
+// and temperature are optional and nil when unset.
 type Warehouse struct {
-	ID                 int     `json:"id"`
-	Address            string  `json:"address"`
-	Telephone          string  `json:"telephone"`
-	WarehouseCode      string  `json:"warehouse_code"`
-	MinimumCapacity    int     `json:"minimum_capacity"`
-	MinimumTemperature float64 `json:"minimum_temperature"`
-	LocalityId         int     `json:"locality_id"`
+	ID                 int      `json:"id"`
+	Address            string   `json:"address"`
+	Telephone          string   `json:"telephone"`
+	WarehouseCode      string   `json:"warehouse_code"`
+	MinimumCapacity    *int     `json:"minimum_capacity"`
+	MinimumTemperature *float64 `json:"minimum_temperature"`
+	LocalityId         int      `json:"locality_id"`
+	Version            int      `json:"-"`
 }
 
 type WarehouseResponse struct {
@@ -17,3 +22,45 @@ type WarehouseResponse struct {
 type WarehouseResponseId struct {
 	Data Warehouse `json:"data"`
 }
+
+// WarehouseInventory is the stock held in a warehouse at a given moment.
+type WarehouseInventory struct {
+	WarehouseID     int                `json:"warehouse_id"`
+	WarehouseCode   string             `json:"warehouse_code"`
+	AsOf            datetime.Time      `json:"as_of"`
+	TotalQuantity   int                `json:"total_quantity"`
+	MinimumCapacity *int               `json:"minimum_capacity"`
+	BelowMinimum    bool               `json:"below_minimum"`
+	Products        []InventoryProduct `json:"products"`
+	Sections        []InventorySection `json:"sections"`
+}
+
+type InventoryProduct struct {
+	ProductID   int    `json:"product_id"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+}
+
+type InventorySection struct {
+	SectionID     int              `json:"section_id"`
+	SectionNumber int              `json:"section_number"`
+	Quantity      int              `json:"quantity"`
+	Batches       []InventoryBatch `json:"batches"`
+}
+
+// InventoryBatch is a product batch stored in a warehouse. ReceivedAt is the
+// date of its first inbound order, or its manufacturing date when it has none.
+// A batch split off by a transfer is received with the transfer, and has no
+// ReceivedAt while it is in transit.
+type InventoryBatch struct {
+	ID                 int           `json:"id"`
+	BatchNumber        int           `json:"batch_number"`
+	ProductID          int           `json:"product_id"`
+	ProductDescription string        `json:"product_description"`
+	SectionID          int           `json:"section_id"`
+	InitialQuantity    int           `json:"initial_quantity"`
+	Quantity           int           `json:"quantity"`
+	ManufacturingDate  datetime.Time `json:"manufacturing_date"`
+	ReceivedAt         datetime.Time `json:"received_at"`
+	AgeDays            int           `json:"age_days"`
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+)
+
+// EventPing is only sent by the webhook test endpoint and cannot be
+// subscribed to.
+const EventPing = "ping"
+
+// Delivery statuses. A delivery is pending until the receiver answers with a
+// 2xx status, and dead once it ran out of attempts.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// Webhook is a URL subscribed to some event types. The secret signs every
+// delivery and is only shown when the webhook is created.
+type Webhook struct {
+	ID        int           `json:"id"`
+	URL       string        `json:"url"`
+	Events    []string      `json:"events"`
+	Secret    string        `json:"secret,omitempty"`
+	CreatedAt datetime.Time `json:"created_at"`
+}
+
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events" binding:"required"`
+}
+
+// WebhookEvent is the body posted to the subscribed URLs. ID identifies the
+// event, so receivers can drop the copies a retry may send.
+type WebhookEvent struct {
+	ID         int64         `json:"id"`
+	Type       string        `json:"type"`
+	OccurredAt datetime.Time `json:"occurred_at"`
+	Data       interface{}   `json:"data"`
+}
+
+// WebhookDelivery is an event queued for a webhook. Payload is the exact body
+// sent, so retries carry the same signature input.
+type WebhookDelivery struct {
+	ID             int             `json:"id"`
+	WebhookID      int             `json:"webhook_id"`
+	URL            string          `json:"url"`
+	Secret         string          `json:"-"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  datetime.Time   `json:"next_attempt_at"`
+	LastError      string          `json:"last_error,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	CreatedAt      datetime.Time   `json:"created_at"`
+	DeliveredAt    datetime.Time   `json:"delivered_at"`
+}
+
+// WebhookTest is the outcome of a ping sent to a webhook.
+type WebhookTest struct {
+	WebhookID      int    `json:"webhook_id"`
+	Delivered      bool   `json:"delivered"`
+	ResponseStatus int    `json:"response_status,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+type WebhookResponse struct {
+	Data Webhook `json:"data"`
+}
+
+type WebhooksResponse struct {
+	Data []Webhook `json:"data"`
+}
+
+type WebhookDeliveriesResponse struct {
+	Data []WebhookDelivery `json:"data"`
+}
+
+type WebhookDeliveryResponse struct {
+	Data WebhookDelivery `json:"data"`
+}
+
+type WebhookTestResponse struct {
+	Data WebhookTest `json:"data"`
+}
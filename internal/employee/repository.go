@@ -5,16 +5,36 @@ import (
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
+)
+
+const (
+	OpenAssignmentQuery   = "INSERT INTO employee_assignments (employee_id, warehouse_id, started_at) VALUES (?, ?, ?)"
+	CloseAssignmentQuery  = "UPDATE employee_assignments SET ended_at = ? WHERE employee_id = ? AND ended_at IS NULL"
+	AssignmentsQuery      = "SELECT id, employee_id, warehouse_id, started_at, ended_at FROM employee_assignments WHERE employee_id = ? ORDER BY started_at, id"
+	LockEmployeeQuery     = "SELECT warehouse_id, version FROM employees WHERE id = ? FOR UPDATE"
+	LockEmployeeIDQuery   = "SELECT id FROM employees WHERE id = ? FOR UPDATE"
+	OverlappingShiftQuery = "SELECT COUNT(*) FROM employee_shifts WHERE employee_id = ? AND starts_at < ? AND ends_at > ?"
+	SaveShiftQuery        = "INSERT INTO employee_shifts (employee_id, warehouse_id, starts_at, ends_at) VALUES (?, ?, ?, ?)"
+	RosterQuery           = "SELECT es.id, es.employee_id, es.warehouse_id, es.starts_at, es.ends_at, e.first_name, e.last_name " +
+		"FROM employee_shifts es JOIN employees e ON es.employee_id = e.id WHERE es.warehouse_id = ? AND es.starts_at < ? AND es.ends_at > ? ORDER BY es.starts_at, es.id"
 )
 
 // Repository encapsulates the storage of a employee.
 type Repository interface {
 	GetAll(ctx context.Context) ([]domain.Employee, error)
+	GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Employee, error)
+	ForEach(ctx context.Context, fn func(domain.Employee) error) error
 	Get(ctx context.Context, id int) (domain.Employee, error)
 	Exists(ctx context.Context, cardNumberID string) bool
 	Save(ctx context.Context, e domain.Employee) (int, error)
 	Update(ctx context.Context, e domain.Employee) error
 	Delete(ctx context.Context, id int) error
+	Assignments(ctx context.Context, id int) ([]domain.EmployeeAssignment, error)
+	SaveShift(ctx context.Context, s domain.Shift) (int, error)
+	Roster(ctx context.Context, warehouseID int, from, to datetime.Time) ([]domain.RosterShift, error)
 }
 
 type repository struct {
@@ -38,18 +58,67 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Employee, error) {
 
 	for rows.Next() {
 		e := domain.Employee{}
-		_ = rows.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID)
+		_ = rows.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID, &e.Version)
 		employees = append(employees, e)
 	}
 
 	return employees, nil
 }
 
+// GetByWarehouses returns the employees working in the given warehouses.
+func (r *repository) GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Employee, error) {
+	return r.getIn(ctx, "warehouse_id", warehouseIDs)
+}
+
+// getIn returns the employees whose column holds one of values, in one query.
+func (r *repository) getIn(ctx context.Context, column string, values []int) ([]domain.Employee, error) {
+	employees := []domain.Employee{}
+	if len(values) == 0 {
+		return employees, nil
+	}
+	in, args := mysqlutil.In(values)
+	rows, err := r.db.QueryContext(ctx, "SELECT * FROM employees WHERE "+column+" IN "+in+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := domain.Employee{}
+		if err := rows.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID, &e.Version); err != nil {
+			return nil, err
+		}
+		employees = append(employees, e)
+	}
+	return employees, rows.Err()
+}
+
+// ForEach calls fn for every employee while reading them from the database,
+// so the whole table is never held in memory.
+func (r *repository) ForEach(ctx context.Context, fn func(domain.Employee) error) error {
+	rows, err := r.db.QueryContext(ctx, "SELECT * FROM employees")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := domain.Employee{}
+		if err := rows.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID, &e.Version); err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (r *repository) Get(ctx context.Context, id int) (domain.Employee, error) {
 	query := "SELECT * FROM employees WHERE id=?;"
 	row := r.db.QueryRow(query, id)
 	e := domain.Employee{}
-	err := row.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID)
+	err := row.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID, &e.Version)
 	if err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			return domain.Employee{}, ErrNotFound
@@ -67,69 +136,169 @@ func (r *repository) Exists(ctx context.Context, cardNumberID string) bool {
 	return err == nil
 }
 
+// Save stores the employee and opens its first warehouse assignment. The
+// unique index on card_number_id rejects a card number taken by a concurrent
+// save, which is reported as ErrAlreadyExists.
 func (r *repository) Save(ctx context.Context, e domain.Employee) (int, error) {
-	query := "INSERT INTO employees(card_number_id,first_name,last_name,warehouse_id) VALUES (?,?,?,?)"
-	stmt, err := r.db.Prepare(query)
-	if err != nil {
-		return 0, err
-	}
+	var id int
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		query := "INSERT INTO employees(card_number_id,first_name,last_name,warehouse_id) VALUES (?,?,?,?)"
+		res, err := tx.ExecContext(ctx, query, e.CardNumberID, e.FirstName, e.LastName, e.WarehouseID)
+		if err != nil {
+			if mysqlutil.IsDuplicateEntry(err) {
+				return ErrAlreadyExists
+			}
+			return err
+		}
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		id = int(lastID)
 
-	res, err := stmt.Exec(&e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID)
+		_, err = tx.ExecContext(ctx, OpenAssignmentQuery, id, e.WarehouseID, datetime.Now())
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
+	return id, nil
+}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return 0, err
-	}
+// Update stores the employee if it is still at e.Version, bumping its
+// version, and returns ErrVersionMismatch otherwise. Moving it to another
+// warehouse closes its current assignment and opens a new one in the same
+// transaction. A card number taken by another employee is reported as
+// ErrAlreadyExists.
+func (r *repository) Update(ctx context.Context, e domain.Employee) error {
+	return sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		var warehouseID, version int
+		if err := tx.QueryRowContext(ctx, LockEmployeeQuery, e.ID).Scan(&warehouseID, &version); err != nil {
+			if err.Error() == "sql: no rows in result set" {
+				return ErrNotFound
+			}
+			return err
+		}
+		if version != e.Version {
+			return ErrVersionMismatch
+		}
 
-	return int(id), nil
+		query := "UPDATE employees SET card_number_id=?, first_name=?, last_name=?, warehouse_id=?, version=version+1 WHERE id=?"
+		if _, err := tx.ExecContext(ctx, query, e.CardNumberID, e.FirstName, e.LastName, e.WarehouseID, e.ID); err != nil {
+			if mysqlutil.IsDuplicateEntry(err) {
+				return ErrAlreadyExists
+			}
+			return err
+		}
+		if warehouseID == e.WarehouseID {
+			return nil
+		}
+
+		now := datetime.Now()
+		if _, err := tx.ExecContext(ctx, CloseAssignmentQuery, now, e.ID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, OpenAssignmentQuery, e.ID, e.WarehouseID, now)
+		return err
+	})
 }
 
-func (r *repository) Update(ctx context.Context, e domain.Employee) error {
-	query := "UPDATE employees SET first_name=?, last_name=?, warehouse_id=?  WHERE id=?"
+func (r *repository) Delete(ctx context.Context, id int) error {
+	query := "DELETE FROM employees WHERE id=?"
 	stmt, err := r.db.Prepare(query)
 	if err != nil {
 		return err
 	}
 
-	res, err := stmt.Exec(&e.FirstName, &e.LastName, &e.WarehouseID, &e.ID)
+	res, err := stmt.Exec(id)
 	if err != nil {
 		return err
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	affect, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
-	if rowsAffected == 0 {
+
+	if affect < 1 {
 		return ErrNotFound
 	}
 
 	return nil
 }
 
-func (r *repository) Delete(ctx context.Context, id int) error {
-	query := "DELETE FROM employees WHERE id=?"
-	stmt, err := r.db.Prepare(query)
+func (r *repository) Assignments(ctx context.Context, id int) ([]domain.EmployeeAssignment, error) {
+	rows, err := r.db.QueryContext(ctx, AssignmentsQuery, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer rows.Close()
 
-	res, err := stmt.Exec(id)
-	if err != nil {
-		return err
+	assignments := []domain.EmployeeAssignment{}
+	for rows.Next() {
+		a := domain.EmployeeAssignment{}
+		if err := rows.Scan(&a.ID, &a.EmployeeID, &a.WarehouseID, &a.StartedAt, &a.EndedAt); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
 	}
+	return assignments, rows.Err()
+}
 
-	affect, err := res.RowsAffected()
+// SaveShift stores a shift unless it overlaps another shift of the same
+// employee. The employee row is locked so two overlapping shifts saved at the
+// same time cannot both pass the check.
+func (r *repository) SaveShift(ctx context.Context, s domain.Shift) (int, error) {
+	var id int
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		var locked int
+		if err := tx.QueryRowContext(ctx, LockEmployeeIDQuery, s.EmployeeID).Scan(&locked); err != nil {
+			if err.Error() == "sql: no rows in result set" {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		var overlapping int
+		if err := tx.QueryRowContext(ctx, OverlappingShiftQuery, s.EmployeeID, s.EndsAt, s.StartsAt).Scan(&overlapping); err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return ErrShiftOverlap
+		}
+
+		res, err := tx.ExecContext(ctx, SaveShiftQuery, s.EmployeeID, s.WarehouseID, s.StartsAt, s.EndsAt)
+		if err != nil {
+			return err
+		}
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		id = int(lastID)
+		return nil
+	})
 	if err != nil {
-		return err
+		return 0, err
 	}
+	return id, nil
+}
 
-	if affect < 1 {
-		return ErrNotFound
+// Roster returns the shifts in a warehouse that overlap [from, to).
+func (r *repository) Roster(ctx context.Context, warehouseID int, from, to datetime.Time) ([]domain.RosterShift, error) {
+	rows, err := r.db.QueryContext(ctx, RosterQuery, warehouseID, to, from)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return nil
+	shifts := []domain.RosterShift{}
+	for rows.Next() {
+		s := domain.RosterShift{}
+		if err := rows.Scan(&s.ID, &s.EmployeeID, &s.WarehouseID, &s.StartsAt, &s.EndsAt, &s.FirstName, &s.LastName); err != nil {
+			return nil, err
+		}
+		shifts = append(shifts, s)
+	}
+	return shifts, rows.Err()
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/DATA-DOG/go-txdb"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -45,7 +46,7 @@ func TestSaveEmployeesRepository(t *testing.T) {
 	t.Run("should create a employees and test", func(t *testing.T) {
 		var employeeExpected = domain.Employee{
 			ID:           01,
-			CardNumberID: "001",
+			CardNumberID: "002",
 			FirstName:    "Joana",
 			LastName:     "Silva",
 			WarehouseID:  1,
@@ -72,7 +73,7 @@ func TestExistsRepository(t *testing.T) {
 	t.Run("should test if exists a specific card number ID", func(t *testing.T) {
 		var employeeExpected = domain.Employee{
 			ID:           01,
-			CardNumberID: "001",
+			CardNumberID: "003",
 			FirstName:    "Joana",
 			LastName:     "Silva",
 			WarehouseID:  1,
@@ -86,7 +87,7 @@ func TestExistsRepository(t *testing.T) {
 		_, err := repository.Save(ctx, employeeExpected)
 		assert.NoError(t, err)
 
-		existsResult := repository.Exists(ctx, "001")
+		existsResult := repository.Exists(ctx, "003")
 		assert.True(t, existsResult)
 	})
 }
@@ -95,7 +96,7 @@ func TestGetEmployeesRepository(t *testing.T) {
 	t.Run("Should get the employee when it exists in database", func(t *testing.T) {
 		var employeeExpected = domain.Employee{
 			ID:           01,
-			CardNumberID: "001",
+			CardNumberID: "004",
 			FirstName:    "Joana",
 			LastName:     "Silva",
 			WarehouseID:  1,
@@ -132,7 +133,7 @@ func TestUpdateEmployeesRepository(t *testing.T) {
 	t.Run("should update a employee and test", func(t *testing.T) {
 		var employeeExpected = domain.Employee{
 			ID:           01,
-			CardNumberID: "001",
+			CardNumberID: "005",
 			FirstName:    "Joana",
 			LastName:     "Silva",
 			WarehouseID:  1,
@@ -148,6 +149,7 @@ func TestUpdateEmployeesRepository(t *testing.T) {
 
 		employeeExpected.ID = result
 		employeeExpected.FirstName = "Luciana"
+		employeeExpected.Version = 1
 
 		err = repository.Update(ctx, employeeExpected)
 		assert.NoError(t, err)
@@ -156,13 +158,36 @@ func TestUpdateEmployeesRepository(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, getResult)
 		assert.Equal(t, employeeExpected.ID, getResult.ID)
+		assert.Equal(t, 2, getResult.Version)
+	})
+	t.Run("Should not update a employee changed since it was read", func(t *testing.T) {
+		var employeeExpected = domain.Employee{
+			CardNumberID: "007",
+			FirstName:    "Joana",
+			LastName:     "Silva",
+			WarehouseID:  1,
+		}
+
+		repository := employee.NewRepository(db)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		result, err := repository.Save(ctx, employeeExpected)
+		assert.NoError(t, err)
+		employeeExpected.ID = result
+		employeeExpected.Version = 1
+		assert.NoError(t, repository.Update(ctx, employeeExpected))
+
+		employeeExpected.FirstName = "Luciana"
+		err = repository.Update(ctx, employeeExpected)
+		assert.ErrorIs(t, err, employee.ErrVersionMismatch)
 	})
 	t.Run("Should return error when there is not exists in database", func(t *testing.T) {
 		expectedMessage := employee.ErrNotFound.Error()
 
 		var employeeExpected = domain.Employee{
 			ID:           01,
-			CardNumberID: "001",
+			CardNumberID: "006",
 			FirstName:    "Joana",
 			LastName:     "Silva",
 			WarehouseID:  1,
@@ -180,11 +205,51 @@ func TestUpdateEmployeesRepository(t *testing.T) {
 	})
 }
 
+func TestSaveShiftRepository(t *testing.T) {
+	t.Run("Should save a shift and reject an overlapping one", func(t *testing.T) {
+		repository := employee.NewRepository(db)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		employeeID, err := repository.Save(ctx, domain.Employee{CardNumberID: "008", FirstName: "Joana", LastName: "Silva", WarehouseID: 1})
+		assert.NoError(t, err)
+		shift := domain.Shift{
+			EmployeeID:  employeeID,
+			WarehouseID: 1,
+			StartsAt:    datetime.MustParse("2023-07-03 08:00:00"),
+			EndsAt:      datetime.MustParse("2023-07-03 16:00:00"),
+		}
+
+		id, err := repository.SaveShift(ctx, shift)
+		assert.NoError(t, err)
+		assert.NotZero(t, id)
+
+		shift.StartsAt = datetime.MustParse("2023-07-03 12:00:00")
+		shift.EndsAt = datetime.MustParse("2023-07-03 20:00:00")
+		_, err = repository.SaveShift(ctx, shift)
+		assert.ErrorIs(t, err, employee.ErrShiftOverlap)
+	})
+
+	t.Run("Should return error when the employee does not exist", func(t *testing.T) {
+		repository := employee.NewRepository(db)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+		defer cancel()
+
+		_, err := repository.SaveShift(ctx, domain.Shift{
+			EmployeeID:  200000,
+			WarehouseID: 1,
+			StartsAt:    datetime.MustParse("2023-07-03 08:00:00"),
+			EndsAt:      datetime.MustParse("2023-07-03 16:00:00"),
+		})
+		assert.ErrorIs(t, err, employee.ErrNotFound)
+	})
+}
+
 func TestDeleteEmployeesRepository(t *testing.T) {
 	t.Run("should delete a employee and test", func(t *testing.T) {
 		var employeeExpected = domain.Employee{
 			ID:           01,
-			CardNumberID: "001",
+			CardNumberID: "007",
 			FirstName:    "Joana",
 			LastName:     "Silva",
 			WarehouseID:  1,
@@ -223,7 +288,7 @@ func TestAllEndpointsRepositoryWithErrorDatabaseClosed(t *testing.T) {
 	db.Close()
 	var employeeExpected = domain.Employee{
 		ID:           01,
-		CardNumberID: "001",
+		CardNumberID: "008",
 		FirstName:    "Joana",
 		LastName:     "Silva",
 		WarehouseID:  1,
@@ -3,25 +3,39 @@ package employee
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mergepatch"
 )
 
+// MaxShiftLength is the longest shift that can be scheduled.
+const MaxShiftLength = 24 * time.Hour
+
 // Errors
 var (
-	ErrNotFound      = errors.New("employee not found")
-	ErrAlreadyExists = errors.New("employee already exists")
-	ErrTryAgain      = errors.New("error, try again %s")
-	ErrInvalidId     = errors.New("invalid id")
-	ErrInvalidBody   = errors.New("invalid body")
+	ErrNotFound        = errors.New("employee not found")
+	ErrAlreadyExists   = errors.New("employee already exists")
+	ErrTryAgain        = errors.New("error, try again %s")
+	ErrInvalidId       = errors.New("invalid id")
+	ErrInvalidBody     = errors.New("invalid body")
+	ErrInvalidShift    = errors.New("shift must end after it starts and last at most 24 hours")
+	ErrShiftOverlap    = errors.New("shift overlaps another shift of the employee")
+	ErrVersionMismatch = errors.New("employee was modified by another request")
 )
 
 type Service interface {
 	GetAll(ctx context.Context) ([]domain.Employee, error)
+	GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Employee, error)
+	ForEach(ctx context.Context, fn func(domain.Employee) error) error
 	Get(ctx context.Context, id int) (domain.Employee, error)
 	Save(ctx context.Context, e domain.Employee) (domain.Employee, error)
-	Update(ctx context.Context, e domain.Employee, id int) (domain.Employee, error)
+	Update(ctx context.Context, id int, p domain.Patch) (domain.Employee, error)
 	Delete(ctx context.Context, id int) error
+	Assignments(ctx context.Context, id int) ([]domain.EmployeeAssignment, error)
+	CreateShift(ctx context.Context, id int, req domain.ShiftRequest) (domain.Shift, error)
+	Roster(ctx context.Context, warehouseID int, date datetime.Time) (domain.Roster, error)
 }
 
 type employeeService struct {
@@ -44,11 +58,20 @@ func (s *employeeService) Get(ctx context.Context, id int) (domain.Employee, err
 	return employee, err
 }
 
+func (s *employeeService) ForEach(ctx context.Context, fn func(domain.Employee) error) error {
+	return s.repository.ForEach(ctx, fn)
+}
+
 func (s *employeeService) GetAll(ctx context.Context) ([]domain.Employee, error) {
 	employees, err := s.repository.GetAll(ctx)
 	return employees, err
 }
 
+// GetByWarehouses returns the employees working in the given warehouses.
+func (s *employeeService) GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Employee, error) {
+	return s.repository.GetByWarehouses(ctx, warehouseIDs)
+}
+
 func (s *employeeService) Save(ctx context.Context, e domain.Employee) (domain.Employee, error) {
 	employeeExists := s.repository.Exists(ctx, e.CardNumberID)
 	if employeeExists {
@@ -63,30 +86,89 @@ func (s *employeeService) Save(ctx context.Context, e domain.Employee) (domain.E
 	return e, nil
 }
 
-func (s *employeeService) Update(ctx context.Context, e domain.Employee, id int) (domain.Employee, error) {
+// Update applies a merge patch to the employee. Its names, card number and
+// warehouse cannot be cleared, and the card number must stay unique.
+func (s *employeeService) Update(ctx context.Context, id int, p domain.Patch) (domain.Employee, error) {
 	employeeDomain, err := s.Get(ctx, id)
 	if err != nil {
 		return domain.Employee{}, ErrNotFound
 	}
-	if e.FirstName != "" {
-		employeeDomain.FirstName = e.FirstName
+	if p.Version != 0 && p.Version != employeeDomain.Version {
+		return domain.Employee{}, ErrVersionMismatch
 	}
-	if e.LastName != "" {
-		employeeDomain.LastName = e.LastName
+
+	cardNumberID := employeeDomain.CardNumberID
+	if err := mergepatch.Apply(&employeeDomain, p.Document); err != nil {
+		return domain.Employee{}, ErrInvalidBody
 	}
-	if e.CardNumberID != "" {
-		exists := s.repository.Exists(ctx, e.CardNumberID)
-		if exists && employeeDomain.CardNumberID != e.CardNumberID {
-			return domain.Employee{}, ErrAlreadyExists
-		}
-		employeeDomain.CardNumberID = e.CardNumberID
+	employeeDomain.ID = id
+	if employeeDomain.CardNumberID == "" || employeeDomain.FirstName == "" || employeeDomain.LastName == "" || employeeDomain.WarehouseID == 0 {
+		return domain.Employee{}, ErrInvalidBody
 	}
-	if e.WarehouseID != 0 {
-		employeeDomain.WarehouseID = e.WarehouseID
+	if employeeDomain.CardNumberID != cardNumberID && s.repository.Exists(ctx, employeeDomain.CardNumberID) {
+		return domain.Employee{}, ErrAlreadyExists
 	}
+
 	err = s.repository.Update(ctx, employeeDomain)
 	if err != nil {
 		return domain.Employee{}, err
 	}
+	employeeDomain.Version++
 	return employeeDomain, nil
 }
+
+// Assignments returns the warehouses the employee has been assigned to,
+// oldest first.
+func (s *employeeService) Assignments(ctx context.Context, id int) ([]domain.EmployeeAssignment, error) {
+	if _, err := s.repository.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repository.Assignments(ctx, id)
+}
+
+func (s *employeeService) CreateShift(ctx context.Context, id int, req domain.ShiftRequest) (domain.Shift, error) {
+	employeeDomain, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return domain.Shift{}, err
+	}
+	if req.StartsAt.IsZero() || req.EndsAt.IsZero() {
+		return domain.Shift{}, ErrInvalidShift
+	}
+	length := req.EndsAt.Sub(req.StartsAt.Time)
+	if length <= 0 || length > MaxShiftLength {
+		return domain.Shift{}, ErrInvalidShift
+	}
+
+	shift := domain.Shift{
+		EmployeeID:  id,
+		WarehouseID: req.WarehouseID,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+	}
+	if shift.WarehouseID == 0 {
+		shift.WarehouseID = employeeDomain.WarehouseID
+	}
+
+	shiftId, err := s.repository.SaveShift(ctx, shift)
+	if err != nil {
+		return domain.Shift{}, err
+	}
+	shift.ID = shiftId
+	return shift, nil
+}
+
+// Roster returns the shifts of a warehouse on the day of date, or today when
+// date is unset.
+func (s *employeeService) Roster(ctx context.Context, warehouseID int, date datetime.Time) (domain.Roster, error) {
+	if date.IsZero() {
+		date = datetime.Now()
+	}
+	from := datetime.New(date.Truncate(24 * time.Hour))
+	to := datetime.New(from.Add(24 * time.Hour))
+
+	shifts, err := s.repository.Roster(ctx, warehouseID, from, to)
+	if err != nil {
+		return domain.Roster{}, err
+	}
+	return domain.Roster{WarehouseID: warehouseID, Date: from.Date(), Shifts: shifts}, nil
+}
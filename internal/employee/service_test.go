@@ -7,6 +7,7 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/employee"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -170,17 +171,32 @@ func TestDeleteEmployees(t *testing.T) {
 func TestUpdateEmployee(t *testing.T) {
 
 	t.Run("Should update the employee when it exists.", func(t *testing.T) {
+		updatedEmployee := expectedEmployee
+		updatedEmployee.FirstName = "Luciana"
 
 		repository, service := InitServerWithEmployeesRepository(t)
 
 		repository.On("Get", mock.Anything, expectedEmployee.ID).Return(expectedEmployee, nil)
-		repository.On("Exists", mock.Anything, expectedEmployee.CardNumberID).Return(false)
-		repository.On("Update", mock.Anything, expectedEmployee).Return(nil)
+		repository.On("Update", mock.Anything, updatedEmployee).Return(nil)
 
-		employee, err := service.Update(context.TODO(), expectedEmployee, expectedEmployee.ID)
+		result, err := service.Update(context.TODO(), expectedEmployee.ID, domain.Patch{Document: []byte(`{"first_name":"Luciana"}`)})
 
 		assert.NoError(t, err)
-		assert.Equal(t, employee, expectedEmployee)
+		updatedEmployee.Version = 1
+		assert.Equal(t, updatedEmployee, result)
+	})
+
+	t.Run("Should return an error when the employee changed since the expected version", func(t *testing.T) {
+		storedEmployee := expectedEmployee
+		storedEmployee.Version = 3
+
+		repository, service := InitServerWithEmployeesRepository(t)
+		repository.On("Get", mock.Anything, storedEmployee.ID).Return(storedEmployee, nil)
+
+		_, err := service.Update(context.TODO(), storedEmployee.ID, domain.Patch{Document: []byte(`{"first_name":"Luciana"}`), Version: 2})
+
+		assert.ErrorIs(t, err, employee.ErrVersionMismatch)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
 	})
 
 	t.Run("Should return an error when the employee does not exists", func(t *testing.T) {
@@ -190,42 +206,140 @@ func TestUpdateEmployee(t *testing.T) {
 
 		repository.On("Get", mock.Anything, expectedEmployee.ID).Return(domain.Employee{}, expectedError)
 
-		_, err := service.Update(context.TODO(), expectedEmployee, expectedEmployee.ID)
+		_, err := service.Update(context.TODO(), expectedEmployee.ID, domain.Patch{Document: []byte(`{}`)})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
 	})
 
 	t.Run("Should return err employee already exists when warehouse already exists", func(t *testing.T) {
-		updateEmployee := domain.Employee{
-			CardNumberID: "111",
-		}
-
 		repository, service := InitServerWithEmployeesRepository(t)
 
 		expectedError := errors.New("employee already exists")
 		repository.On("Get", mock.Anything, expectedEmployee.ID).Return(expectedEmployee, nil)
-		repository.On("Exists", mock.Anything, updateEmployee.CardNumberID).Return(true)
+		repository.On("Exists", mock.Anything, "111").Return(true)
 
-		updatedEmployee, err := service.Update(context.TODO(), updateEmployee, 01)
+		updatedEmployee, err := service.Update(context.TODO(), 01, domain.Patch{Document: []byte(`{"card_number_id":"111"}`)})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
 		assert.Equal(t, domain.Employee{}, updatedEmployee)
 	})
 
+	t.Run("Should return an error when a required field is cleared", func(t *testing.T) {
+		repository, service := InitServerWithEmployeesRepository(t)
+		repository.On("Get", mock.Anything, expectedEmployee.ID).Return(expectedEmployee, nil)
+
+		_, err := service.Update(context.TODO(), expectedEmployee.ID, domain.Patch{Document: []byte(`{"warehouse_id":null}`)})
+
+		assert.ErrorIs(t, err, employee.ErrInvalidBody)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return an error when a field has the wrong type", func(t *testing.T) {
+		repository, service := InitServerWithEmployeesRepository(t)
+		repository.On("Get", mock.Anything, expectedEmployee.ID).Return(expectedEmployee, nil)
+
+		_, err := service.Update(context.TODO(), expectedEmployee.ID, domain.Patch{Document: []byte(`{"warehouse_id":"one"}`)})
+
+		assert.ErrorIs(t, err, employee.ErrInvalidBody)
+	})
+
 	t.Run("Should return error when there is an update repository error", func(t *testing.T) {
 
 		repository, service := InitServerWithEmployeesRepository(t)
 
 		expectedError := errors.New("some error")
 		repository.On("Get", mock.Anything, expectedEmployee.ID).Return(expectedEmployee, nil)
-		repository.On("Exists", mock.Anything, expectedEmployee.CardNumberID).Return(false)
 		repository.On("Update", mock.Anything, expectedEmployee).Return(expectedError)
 
-		_, err := service.Update(context.TODO(), expectedEmployee, expectedEmployee.ID)
+		_, err := service.Update(context.TODO(), expectedEmployee.ID, domain.Patch{Document: []byte(`{}`)})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
 	})
 }
+
+func TestCreateShift(t *testing.T) {
+	req := domain.ShiftRequest{
+		StartsAt: datetime.MustParse("2023-07-01T08:00:00Z"),
+		EndsAt:   datetime.MustParse("2023-07-01T16:00:00Z"),
+	}
+
+	t.Run("Should schedule the shift in the employee warehouse", func(t *testing.T) {
+		repository, service := InitServerWithEmployeesRepository(t)
+		expectedShift := domain.Shift{EmployeeID: 1, WarehouseID: 1, StartsAt: req.StartsAt, EndsAt: req.EndsAt}
+		repository.On("Get", mock.Anything, 1).Return(expectedEmployee, nil)
+		repository.On("SaveShift", mock.Anything, expectedShift).Return(3, nil)
+
+		shift, err := service.CreateShift(context.TODO(), 1, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, shift.ID)
+		assert.Equal(t, 1, shift.WarehouseID)
+	})
+
+	t.Run("Should return error when the shift ends before it starts", func(t *testing.T) {
+		repository, service := InitServerWithEmployeesRepository(t)
+		repository.On("Get", mock.Anything, 1).Return(expectedEmployee, nil)
+
+		_, err := service.CreateShift(context.TODO(), 1, domain.ShiftRequest{StartsAt: req.EndsAt, EndsAt: req.StartsAt})
+
+		assert.ErrorIs(t, err, employee.ErrInvalidShift)
+	})
+
+	t.Run("Should return error when the shift is longer than a day", func(t *testing.T) {
+		repository, service := InitServerWithEmployeesRepository(t)
+		repository.On("Get", mock.Anything, 1).Return(expectedEmployee, nil)
+
+		_, err := service.CreateShift(context.TODO(), 1, domain.ShiftRequest{StartsAt: req.StartsAt, EndsAt: datetime.MustParse("2023-07-02T09:00:00Z")})
+
+		assert.ErrorIs(t, err, employee.ErrInvalidShift)
+	})
+
+	t.Run("Should return error when the shift overlaps another one", func(t *testing.T) {
+		repository, service := InitServerWithEmployeesRepository(t)
+		repository.On("Get", mock.Anything, 1).Return(expectedEmployee, nil)
+		repository.On("SaveShift", mock.Anything, mock.Anything).Return(0, employee.ErrShiftOverlap)
+
+		_, err := service.CreateShift(context.TODO(), 1, req)
+
+		assert.ErrorIs(t, err, employee.ErrShiftOverlap)
+	})
+
+	t.Run("Should return error when the employee does not exist", func(t *testing.T) {
+		repository, service := InitServerWithEmployeesRepository(t)
+		repository.On("Get", mock.Anything, 1).Return(domain.Employee{}, employee.ErrNotFound)
+
+		_, err := service.CreateShift(context.TODO(), 1, req)
+
+		assert.ErrorIs(t, err, employee.ErrNotFound)
+	})
+}
+
+func TestRoster(t *testing.T) {
+	t.Run("Should return the shifts of the whole day", func(t *testing.T) {
+		repository, service := InitServerWithEmployeesRepository(t)
+		from := datetime.MustParse("2023-07-01")
+		to := datetime.MustParse("2023-07-02")
+		shifts := []domain.RosterShift{{Shift: domain.Shift{ID: 1, EmployeeID: 1, WarehouseID: 2}, FirstName: "Joana"}}
+		repository.On("Roster", mock.Anything, 2, from, to).Return(shifts, nil)
+
+		roster, err := service.Roster(context.TODO(), 2, datetime.MustParse("2023-07-01T13:30:00Z"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Roster{WarehouseID: 2, Date: "2023-07-01", Shifts: shifts}, roster)
+	})
+}
+
+func TestAssignments(t *testing.T) {
+	t.Run("Should return error when the employee does not exist", func(t *testing.T) {
+		repository, service := InitServerWithEmployeesRepository(t)
+		repository.On("Get", mock.Anything, 1).Return(domain.Employee{}, employee.ErrNotFound)
+
+		_, err := service.Assignments(context.TODO(), 1)
+
+		assert.ErrorIs(t, err, employee.ErrNotFound)
+		repository.AssertNotCalled(t, "Assignments", mock.Anything, mock.Anything)
+	})
+}
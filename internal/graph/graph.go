@@ -0,0 +1,129 @@
+// Package graph serves the domain model over GraphQL. Queries resolve
+// through the services of the other internal packages, and are rejected
+// before running when they are deeper or more complex than allowed.
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/buyer"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/carry"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/locality"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
+	productbatch "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Defaults of an Executor. A page showing a warehouse with its sections,
+// their batches and products, and its employees has a depth of 4 and a
+// complexity of about 600.
+const (
+	DefaultMaxDepth      = 8
+	DefaultMaxComplexity = 5000
+)
+
+// Services are the services queries resolve through.
+type Services struct {
+	Warehouses     warehouse.Service
+	Sections       section.Service
+	Batches        productbatch.Service
+	Products       product.Service
+	Sellers        seller.Service
+	Employees      employee.Service
+	Buyers         buyer.Service
+	PurchaseOrders purchase_orders.Service
+	Carriers       carry.Service
+	Localities     locality.Service
+}
+
+// Executor runs GraphQL queries.
+type Executor struct {
+	MaxDepth      int
+	MaxComplexity int
+
+	schema   graphql.Schema
+	services Services
+}
+
+func NewExecutor(s Services) (*Executor, error) {
+	schema, err := newSchema(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{
+		MaxDepth:      DefaultMaxDepth,
+		MaxComplexity: DefaultMaxComplexity,
+		schema:        schema,
+		services:      s,
+	}, nil
+}
+
+// Execute runs the query. The result has no data when the query was
+// rejected without running: it does not parse, is invalid or exceeds the
+// limits.
+func (e *Executor) Execute(ctx context.Context, req domain.GraphQLRequest) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if validation := graphql.ValidateDocument(&e.schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	if operation := findOperation(doc, req.OperationName); operation != nil {
+		depth, complexity := cost(e.schema, doc, operation)
+		if depth > e.MaxDepth {
+			return rejected("query depth %d exceeds the limit of %d", depth, e.MaxDepth)
+		}
+		if complexity > e.MaxComplexity {
+			return rejected("query complexity %d exceeds the limit of %d", complexity, e.MaxComplexity)
+		}
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       context.WithValue(ctx, loadersKey{}, newLoaders(e.services)),
+	})
+}
+
+// findOperation returns the operation to run, or nil when there is none to
+// pick, which Execute reports.
+func findOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		operation, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = operation
+		} else if operation.Name != nil && operation.Name.Value == name {
+			return operation
+		}
+	}
+	return found
+}
+
+func rejected(format string, args ...interface{}) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(fmt.Sprintf(format, args...))}}
+}
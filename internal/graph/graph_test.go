@@ -0,0 +1,225 @@
+package graph_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/graph"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	employeeMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/employee"
+	localityMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/locality"
+	sectionMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/section"
+	warehouseMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/warehouse"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type services struct {
+	warehouses *warehouseMocks.WarehouseServiceMock
+	sections   *sectionMocks.SectionServiceMock
+	employees  *employeeMocks.EmployeeServiceMock
+	localities *localityMocks.LocalityServiceMock
+}
+
+func InitExecutor(t *testing.T) (*graph.Executor, services) {
+	s := services{
+		warehouses: &warehouseMocks.WarehouseServiceMock{},
+		sections:   &sectionMocks.SectionServiceMock{},
+		employees:  &employeeMocks.EmployeeServiceMock{},
+		localities: &localityMocks.LocalityServiceMock{},
+	}
+	executor, err := graph.NewExecutor(graph.Services{
+		Warehouses: s.warehouses,
+		Sections:   s.sections,
+		Employees:  s.employees,
+		Localities: s.localities,
+	})
+	assert.NoError(t, err)
+	return executor, s
+}
+
+func data(t *testing.T, result *graphql.Result) map[string]interface{} {
+	body, err := json.Marshal(result.Data)
+	assert.NoError(t, err)
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(body, &decoded))
+	return decoded
+}
+
+func TestExecute(t *testing.T) {
+	warehouses := []domain.Warehouse{
+		{ID: 1, WarehouseCode: "W1", LocalityId: 10},
+		{ID: 2, WarehouseCode: "W2", LocalityId: 20},
+	}
+	sections := []domain.Section{
+		{ID: 1, SectionNumber: 100, WarehouseID: 1},
+		{ID: 2, SectionNumber: 200, WarehouseID: 1},
+		{ID: 3, SectionNumber: 300, WarehouseID: 2},
+	}
+	employees := []domain.Employee{
+		{ID: 1, FirstName: "Ana", WarehouseID: 2},
+	}
+	localities := []domain.Locality{
+		{ID: 10, LocalityName: "Palermo"},
+		{ID: 20, LocalityName: "Recoleta"},
+	}
+
+	t.Run("Should resolve each relation loading the rows of all its parents at once", func(t *testing.T) {
+		executor, s := InitExecutor(t)
+		s.warehouses.On("GetAll", mock.Anything).Return(warehouses, nil)
+		s.sections.On("GetByWarehouses", mock.Anything, []int{1, 2}).Return(sections, nil)
+		s.employees.On("GetByWarehouses", mock.Anything, []int{1, 2}).Return(employees, nil)
+		s.localities.On("GetByIDs", mock.Anything, []int{10, 20}).Return(localities, nil)
+
+		result := executor.Execute(context.TODO(), domain.GraphQLRequest{
+			Query: `{ warehouses { warehouse_code locality { locality_name } sections { section_number } employees { first_name } } }`,
+		})
+
+		assert.Empty(t, result.Errors)
+		expected := map[string]interface{}{"warehouses": []interface{}{
+			map[string]interface{}{
+				"warehouse_code": "W1",
+				"locality":       map[string]interface{}{"locality_name": "Palermo"},
+				"sections": []interface{}{
+					map[string]interface{}{"section_number": float64(100)},
+					map[string]interface{}{"section_number": float64(200)},
+				},
+				"employees": []interface{}{},
+			},
+			map[string]interface{}{
+				"warehouse_code": "W2",
+				"locality":       map[string]interface{}{"locality_name": "Recoleta"},
+				"sections": []interface{}{
+					map[string]interface{}{"section_number": float64(300)},
+				},
+				"employees": []interface{}{
+					map[string]interface{}{"first_name": "Ana"},
+				},
+			},
+		}}
+		assert.Equal(t, expected, data(t, result))
+		s.warehouses.AssertNumberOfCalls(t, "GetAll", 1)
+		s.sections.AssertNumberOfCalls(t, "GetByWarehouses", 1)
+		s.employees.AssertNumberOfCalls(t, "GetByWarehouses", 1)
+		s.localities.AssertNumberOfCalls(t, "GetByIDs", 1)
+	})
+
+	t.Run("Should fail only the relation whose rows cannot be loaded", func(t *testing.T) {
+		executor, s := InitExecutor(t)
+		s.warehouses.On("GetAll", mock.Anything).Return(warehouses[:1], nil)
+		s.sections.On("GetByWarehouses", mock.Anything, []int{1}).Return([]domain.Section{}, errors.New("connection lost"))
+
+		result := executor.Execute(context.TODO(), domain.GraphQLRequest{
+			Query: `{ warehouses { warehouse_code sections { id } } }`,
+		})
+
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, "connection lost", result.Errors[0].Message)
+		s.sections.AssertNumberOfCalls(t, "GetByWarehouses", 1)
+	})
+
+	t.Run("Should resolve unset references to null without loading them", func(t *testing.T) {
+		executor, s := InitExecutor(t)
+		s.warehouses.On("GetAll", mock.Anything).Return([]domain.Warehouse{{ID: 3, WarehouseCode: "W3"}}, nil)
+
+		result := executor.Execute(context.TODO(), domain.GraphQLRequest{
+			Query: `{ warehouses { warehouse_code locality { id } } }`,
+		})
+
+		assert.Empty(t, result.Errors)
+		expected := map[string]interface{}{"warehouses": []interface{}{
+			map[string]interface{}{"warehouse_code": "W3", "locality": nil},
+		}}
+		assert.Equal(t, expected, data(t, result))
+		s.localities.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should resolve fragments and variables", func(t *testing.T) {
+		executor, s := InitExecutor(t)
+		s.warehouses.On("Get", mock.Anything, 2).Return(warehouses[1], nil)
+		s.sections.On("GetByWarehouses", mock.Anything, []int{2}).Return(sections[2:], nil)
+
+		result := executor.Execute(context.TODO(), domain.GraphQLRequest{
+			Query:         `query Find($id: Int!) { warehouse(id: $id) { ...fields } } fragment fields on Warehouse { id sections { id } }`,
+			OperationName: "Find",
+			Variables:     map[string]interface{}{"id": 2},
+		})
+
+		assert.Empty(t, result.Errors)
+		expected := map[string]interface{}{"warehouse": map[string]interface{}{
+			"id":       float64(2),
+			"sections": []interface{}{map[string]interface{}{"id": float64(3)}},
+		}}
+		assert.Equal(t, expected, data(t, result))
+	})
+
+	t.Run("Should return null and an error when not found", func(t *testing.T) {
+		executor, s := InitExecutor(t)
+		s.warehouses.On("Get", mock.Anything, 9).Return(domain.Warehouse{}, warehouse.ErrNotFound)
+
+		result := executor.Execute(context.TODO(), domain.GraphQLRequest{Query: `{ warehouse(id: 9) { id } }`})
+
+		assert.NotNil(t, result.Data)
+		assert.Equal(t, map[string]interface{}{"warehouse": nil}, data(t, result))
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, warehouse.ErrNotFound.Error(), result.Errors[0].Message)
+	})
+
+	t.Run("Should reject invalid queries without running them", func(t *testing.T) {
+		executor, s := InitExecutor(t)
+
+		result := executor.Execute(context.TODO(), domain.GraphQLRequest{Query: `{ warehouses { unknown } }`})
+
+		assert.Nil(t, result.Data)
+		assert.NotEmpty(t, result.Errors)
+		s.warehouses.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+
+	t.Run("Should reject queries deeper than allowed", func(t *testing.T) {
+		executor, s := InitExecutor(t)
+		executor.MaxDepth = 3
+
+		result := executor.Execute(context.TODO(), domain.GraphQLRequest{
+			Query: `{ warehouses { locality { warehouses { id } } } }`,
+		})
+
+		assert.Nil(t, result.Data)
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, "query depth 4 exceeds the limit of 3", result.Errors[0].Message)
+		s.warehouses.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+
+	t.Run("Should reject queries more complex than allowed", func(t *testing.T) {
+		executor, s := InitExecutor(t)
+		executor.MaxComplexity = 100
+
+		result := executor.Execute(context.TODO(), domain.GraphQLRequest{
+			Query: `{ warehouses { id sections { id section_number } } }`,
+		})
+
+		assert.Nil(t, result.Data)
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, "query complexity 221 exceeds the limit of 100", result.Errors[0].Message)
+		s.warehouses.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+
+	t.Run("Should count fragments and ignore introspection in the limits", func(t *testing.T) {
+		executor, _ := InitExecutor(t)
+		executor.MaxDepth = 2
+
+		result := executor.Execute(context.TODO(), domain.GraphQLRequest{
+			Query: `{ __schema { types { name fields { name type { name } } } } }`,
+		})
+		assert.Empty(t, result.Errors)
+
+		result = executor.Execute(context.TODO(), domain.GraphQLRequest{
+			Query: `{ warehouses { ...deep } } fragment deep on Warehouse { locality { id } }`,
+		})
+		assert.Nil(t, result.Data)
+		assert.Equal(t, "query depth 3 exceeds the limit of 2", result.Errors[0].Message)
+	})
+}
@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listFactor is how many items a list field is assumed to return when the
+// complexity of a query is estimated.
+const listFactor = 10
+
+// cost returns the depth of the operation, counting nested fields, and its
+// complexity: one per field, with the fields under a list counted once per
+// assumed item. Introspection fields are free, as they are answered without
+// reading any data.
+func cost(schema graphql.Schema, doc *ast.Document, operation *ast.OperationDefinition) (depth, complexity int) {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+	return selectionCost(schema.QueryType(), operation.SelectionSet, fragments)
+}
+
+func selectionCost(parent *graphql.Object, set *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		var d, c int
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, c = fieldCost(parent, s, fragments)
+		case *ast.InlineFragment:
+			d, c = selectionCost(parent, s.SelectionSet, fragments)
+		case *ast.FragmentSpread:
+			if fragment, ok := fragments[s.Name.Value]; ok {
+				d, c = selectionCost(parent, fragment.SelectionSet, fragments)
+			}
+		}
+		if d > depth {
+			depth = d
+		}
+		complexity += c
+	}
+	return depth, complexity
+}
+
+func fieldCost(parent *graphql.Object, field *ast.Field, fragments map[string]*ast.FragmentDefinition) (depth, complexity int) {
+	if parent == nil || strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0
+	}
+	def, ok := parent.Fields()[field.Name.Value]
+	if !ok {
+		return 1, 1
+	}
+
+	isList := false
+	t := def.Type
+	for {
+		if nonNull, ok := t.(*graphql.NonNull); ok {
+			t = nonNull.OfType
+			continue
+		}
+		if list, ok := t.(*graphql.List); ok {
+			isList = true
+			t = list.OfType
+			continue
+		}
+		break
+	}
+	object, _ := t.(*graphql.Object)
+
+	d, c := selectionCost(object, field.SelectionSet, fragments)
+	if isList {
+		c *= listFactor
+	}
+	return d + 1, c + 1
+}
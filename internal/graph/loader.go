@@ -0,0 +1,295 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+)
+
+// loadersKey is the context key of the loaders of a request.
+type loadersKey struct{}
+
+// batch loads the rows related to the keys the resolvers of a request ask
+// for. A resolver queues the key of its parent and returns a thunk, and
+// graphql-go runs the thunks of a level of the query once all its fields are
+// resolved, so the first thunk loads the rows of every key queued so far in
+// one call. A key is loaded at most once per request.
+type batch struct {
+	mu     sync.Mutex
+	load   func(ctx context.Context, keys []int) (map[int]interface{}, error)
+	empty  interface{}
+	queued []int
+	seen   map[int]bool
+	values map[int]interface{}
+	errs   map[int]error
+}
+
+// newBatch returns a batch loading through load, which returns the value of
+// each key having rows. Keys without rows resolve to empty.
+func newBatch(empty interface{}, load func(ctx context.Context, keys []int) (map[int]interface{}, error)) *batch {
+	return &batch{
+		load:   load,
+		empty:  empty,
+		seen:   map[int]bool{},
+		values: map[int]interface{}{},
+		errs:   map[int]error{},
+	}
+}
+
+// thunk queues key and returns a function resolving to its value.
+func (b *batch) thunk(ctx context.Context, key int) func() (interface{}, error) {
+	b.mu.Lock()
+	if !b.seen[key] {
+		b.seen[key] = true
+		b.queued = append(b.queued, key)
+	}
+	b.mu.Unlock()
+
+	return func() (interface{}, error) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if len(b.queued) > 0 {
+			keys := b.queued
+			b.queued = nil
+			values, err := b.load(ctx, keys)
+			for _, k := range keys {
+				if err != nil {
+					b.errs[k] = err
+				} else if v, ok := values[k]; ok {
+					b.values[k] = v
+				}
+			}
+		}
+		if err := b.errs[key]; err != nil {
+			return nil, err
+		}
+		if v, ok := b.values[key]; ok {
+			return v, nil
+		}
+		return b.empty, nil
+	}
+}
+
+// loaders resolve the relations of a request. Each relation loads the rows
+// of all the parents of a level of the query in one call to the service of
+// the related entity, so a query costs at most one call per relation and
+// level however many rows it returns, and reads only the rows it shows.
+type loaders struct {
+	warehouses, warehousesByLocality   *batch
+	sections, sectionsByWarehouse      *batch
+	batchesBySection, batchesByProduct *batch
+	products, productsBySeller         *batch
+	sellers, sellersByLocality         *batch
+	employeesByWarehouse               *batch
+	buyers                             *batch
+	ordersByBuyer, ordersByCarrier     *batch
+	carriers, carriersByLocality       *batch
+	localities                         *batch
+}
+
+func newLoaders(s Services) *loaders {
+	return &loaders{
+		warehouses: newBatch(nil, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Warehouses.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := map[int]interface{}{}
+			for _, w := range all {
+				byID[w.ID] = w
+			}
+			return byID, nil
+		}),
+		warehousesByLocality: newBatch([]domain.Warehouse{}, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Warehouses.GetByLocalities(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byLocality := map[int]interface{}{}
+			for _, w := range all {
+				warehouses, _ := byLocality[w.LocalityId].([]domain.Warehouse)
+				byLocality[w.LocalityId] = append(warehouses, w)
+			}
+			return byLocality, nil
+		}),
+		sections: newBatch(nil, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Sections.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := map[int]interface{}{}
+			for _, section := range all {
+				byID[section.ID] = section
+			}
+			return byID, nil
+		}),
+		sectionsByWarehouse: newBatch([]domain.Section{}, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Sections.GetByWarehouses(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byWarehouse := map[int]interface{}{}
+			for _, section := range all {
+				sections, _ := byWarehouse[section.WarehouseID].([]domain.Section)
+				byWarehouse[section.WarehouseID] = append(sections, section)
+			}
+			return byWarehouse, nil
+		}),
+		batchesBySection: newBatch([]domain.ProductBatch{}, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Batches.GetBySections(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			bySection := map[int]interface{}{}
+			for _, b := range all {
+				batches, _ := bySection[b.SectionID].([]domain.ProductBatch)
+				bySection[b.SectionID] = append(batches, b)
+			}
+			return bySection, nil
+		}),
+		batchesByProduct: newBatch([]domain.ProductBatch{}, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Batches.GetByProducts(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byProduct := map[int]interface{}{}
+			for _, b := range all {
+				batches, _ := byProduct[b.ProductID].([]domain.ProductBatch)
+				byProduct[b.ProductID] = append(batches, b)
+			}
+			return byProduct, nil
+		}),
+		products: newBatch(nil, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Products.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := map[int]interface{}{}
+			for _, p := range all {
+				byID[p.ID] = p
+			}
+			return byID, nil
+		}),
+		productsBySeller: newBatch([]domain.Product{}, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Products.GetBySellers(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			bySeller := map[int]interface{}{}
+			for _, p := range all {
+				products, _ := bySeller[p.SellerID].([]domain.Product)
+				bySeller[p.SellerID] = append(products, p)
+			}
+			return bySeller, nil
+		}),
+		sellers: newBatch(nil, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Sellers.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := map[int]interface{}{}
+			for _, seller := range all {
+				byID[seller.ID] = seller
+			}
+			return byID, nil
+		}),
+		sellersByLocality: newBatch([]domain.Seller{}, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Sellers.GetByLocalities(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byLocality := map[int]interface{}{}
+			for _, seller := range all {
+				sellers, _ := byLocality[seller.LocalityId].([]domain.Seller)
+				byLocality[seller.LocalityId] = append(sellers, seller)
+			}
+			return byLocality, nil
+		}),
+		employeesByWarehouse: newBatch([]domain.Employee{}, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Employees.GetByWarehouses(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byWarehouse := map[int]interface{}{}
+			for _, e := range all {
+				employees, _ := byWarehouse[e.WarehouseID].([]domain.Employee)
+				byWarehouse[e.WarehouseID] = append(employees, e)
+			}
+			return byWarehouse, nil
+		}),
+		buyers: newBatch(nil, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Buyers.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := map[int]interface{}{}
+			for _, b := range all {
+				byID[b.ID] = b
+			}
+			return byID, nil
+		}),
+		ordersByBuyer: newBatch([]domain.PurchaseOrders{}, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.PurchaseOrders.GetByBuyers(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byBuyer := map[int]interface{}{}
+			for _, o := range all {
+				orders, _ := byBuyer[o.BuyerID].([]domain.PurchaseOrders)
+				byBuyer[o.BuyerID] = append(orders, o)
+			}
+			return byBuyer, nil
+		}),
+		ordersByCarrier: newBatch([]domain.PurchaseOrders{}, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.PurchaseOrders.GetByCarriers(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byCarrier := map[int]interface{}{}
+			for _, o := range all {
+				orders, _ := byCarrier[o.CarrierID].([]domain.PurchaseOrders)
+				byCarrier[o.CarrierID] = append(orders, o)
+			}
+			return byCarrier, nil
+		}),
+		carriers: newBatch(nil, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Carriers.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := map[int]interface{}{}
+			for _, c := range all {
+				byID[c.ID] = c
+			}
+			return byID, nil
+		}),
+		carriersByLocality: newBatch([]domain.Carry{}, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Carriers.GetBasedIn(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byLocality := map[int]interface{}{}
+			for _, c := range all {
+				carriers, _ := byLocality[c.LocalityId].([]domain.Carry)
+				byLocality[c.LocalityId] = append(carriers, c)
+			}
+			return byLocality, nil
+		}),
+		localities: newBatch(nil, func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			all, err := s.Localities.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := map[int]interface{}{}
+			for _, locality := range all {
+				byID[locality.ID] = locality
+			}
+			return byID, nil
+		}),
+	}
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,378 @@
+package graph
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// dateTime is an RFC 3339 timestamp, or null when unset.
+var dateTime = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "DateTime",
+	Description: "An RFC 3339 timestamp.",
+	Serialize: func(value interface{}) interface{} {
+		t, ok := value.(datetime.Time)
+		if !ok || t.IsZero() {
+			return nil
+		}
+		return t.String()
+	},
+	ParseValue: func(value interface{}) interface{} {
+		s, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		t, err := datetime.Parse(s)
+		if err != nil {
+			return nil
+		}
+		return t
+	},
+	ParseLiteral: func(value ast.Value) interface{} {
+		s, ok := value.(*ast.StringValue)
+		if !ok {
+			return nil
+		}
+		t, err := datetime.Parse(s.Value)
+		if err != nil {
+			return nil
+		}
+		return t
+	},
+})
+
+// newSchema builds the schema. Field names are the JSON names of the REST
+// API, so the scalar fields resolve from the json tags of the domain types.
+func newSchema(s Services) (graphql.Schema, error) {
+	var (
+		warehouseType, sectionType, batchType, productType, sellerType *graphql.Object
+		employeeType, buyerType, orderType, carrierType, localityType  *graphql.Object
+	)
+	list := func(t *graphql.Object) graphql.Output {
+		return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+	}
+	idArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+	}
+
+	localityType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Locality",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":            &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"locality_name": &graphql.Field{Type: graphql.String},
+				"province_name": &graphql.Field{Type: graphql.String},
+				"warehouses": &graphql.Field{Type: list(warehouseType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return related(p, loadersFrom(p.Context).warehousesByLocality, p.Source.(domain.Locality).ID)
+				}},
+				"sellers": &graphql.Field{Type: list(sellerType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return related(p, loadersFrom(p.Context).sellersByLocality, p.Source.(domain.Locality).ID)
+				}},
+				"carriers": &graphql.Field{Type: list(carrierType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return related(p, loadersFrom(p.Context).carriersByLocality, p.Source.(domain.Locality).ID)
+				}},
+			}
+		}),
+	})
+
+	warehouseType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Warehouse",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"warehouse_code":      &graphql.Field{Type: graphql.String},
+				"address":             &graphql.Field{Type: graphql.String},
+				"telephone":           &graphql.Field{Type: graphql.String},
+				"minimum_capacity":    &graphql.Field{Type: graphql.Int},
+				"minimum_temperature": &graphql.Field{Type: graphql.Float},
+				"locality_id":         &graphql.Field{Type: graphql.Int},
+				"locality": &graphql.Field{Type: localityType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reference(p, loadersFrom(p.Context).localities, p.Source.(domain.Warehouse).LocalityId)
+				}},
+				"sections": &graphql.Field{Type: list(sectionType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return related(p, loadersFrom(p.Context).sectionsByWarehouse, p.Source.(domain.Warehouse).ID)
+				}},
+				"employees": &graphql.Field{Type: list(employeeType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return related(p, loadersFrom(p.Context).employeesByWarehouse, p.Source.(domain.Warehouse).ID)
+				}},
+			}
+		}),
+	})
+
+	sectionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Section",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"section_number":      &graphql.Field{Type: graphql.Int},
+				"current_temperature": &graphql.Field{Type: graphql.Int},
+				"minimum_temperature": &graphql.Field{Type: graphql.Int},
+				"current_capacity":    &graphql.Field{Type: graphql.Int},
+				"minimum_capacity":    &graphql.Field{Type: graphql.Int},
+				"maximum_capacity":    &graphql.Field{Type: graphql.Int},
+				"warehouse_id":        &graphql.Field{Type: graphql.Int},
+				"product_type_id":     &graphql.Field{Type: graphql.Int},
+				"warehouse": &graphql.Field{Type: warehouseType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reference(p, loadersFrom(p.Context).warehouses, p.Source.(domain.Section).WarehouseID)
+				}},
+				"product_batches": &graphql.Field{Type: list(batchType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return related(p, loadersFrom(p.Context).batchesBySection, p.Source.(domain.Section).ID)
+				}},
+			}
+		}),
+	})
+
+	batchType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ProductBatch",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"batch_number":        &graphql.Field{Type: graphql.Int},
+				"current_quantity":    &graphql.Field{Type: graphql.Int},
+				"current_temperature": &graphql.Field{Type: graphql.Int},
+				"due_date":            &graphql.Field{Type: dateTime},
+				"initial_quantity":    &graphql.Field{Type: graphql.Int},
+				"manufacturing_date":  &graphql.Field{Type: dateTime},
+				"manufacturing_hour":  &graphql.Field{Type: graphql.Int},
+				"minimum_temperature": &graphql.Field{Type: graphql.Int},
+				"product_id":          &graphql.Field{Type: graphql.Int},
+				"section_id":          &graphql.Field{Type: graphql.Int},
+				"product": &graphql.Field{Type: productType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reference(p, loadersFrom(p.Context).products, p.Source.(domain.ProductBatch).ProductID)
+				}},
+				"section": &graphql.Field{Type: sectionType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reference(p, loadersFrom(p.Context).sections, p.Source.(domain.ProductBatch).SectionID)
+				}},
+			}
+		}),
+	})
+
+	productType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Product",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                               &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"product_code":                     &graphql.Field{Type: graphql.String},
+				"description":                      &graphql.Field{Type: graphql.String},
+				"expiration_rate":                  &graphql.Field{Type: graphql.Float},
+				"freezing_rate":                    &graphql.Field{Type: graphql.Float},
+				"height":                           &graphql.Field{Type: graphql.Float},
+				"length":                           &graphql.Field{Type: graphql.Float},
+				"width":                            &graphql.Field{Type: graphql.Float},
+				"netweight":                        &graphql.Field{Type: graphql.Float},
+				"recommended_freezing_temperature": &graphql.Field{Type: graphql.Float},
+				"product_type_id":                  &graphql.Field{Type: graphql.Int},
+				"seller_id":                        &graphql.Field{Type: graphql.Int},
+				"seller": &graphql.Field{Type: sellerType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reference(p, loadersFrom(p.Context).sellers, p.Source.(domain.Product).SellerID)
+				}},
+				"product_batches": &graphql.Field{Type: list(batchType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return related(p, loadersFrom(p.Context).batchesByProduct, p.Source.(domain.Product).ID)
+				}},
+			}
+		}),
+	})
+
+	sellerType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Seller",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"cid":          &graphql.Field{Type: graphql.Int},
+				"company_name": &graphql.Field{Type: graphql.String},
+				"address":      &graphql.Field{Type: graphql.String},
+				"telephone":    &graphql.Field{Type: graphql.String},
+				"locality_id":  &graphql.Field{Type: graphql.Int},
+				"locality": &graphql.Field{Type: localityType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reference(p, loadersFrom(p.Context).localities, p.Source.(domain.Seller).LocalityId)
+				}},
+				"products": &graphql.Field{Type: list(productType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return related(p, loadersFrom(p.Context).productsBySeller, p.Source.(domain.Seller).ID)
+				}},
+			}
+		}),
+	})
+
+	employeeType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Employee",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":             &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"card_number_id": &graphql.Field{Type: graphql.String},
+				"first_name":     &graphql.Field{Type: graphql.String},
+				"last_name":      &graphql.Field{Type: graphql.String},
+				"warehouse_id":   &graphql.Field{Type: graphql.Int},
+				"warehouse": &graphql.Field{Type: warehouseType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reference(p, loadersFrom(p.Context).warehouses, p.Source.(domain.Employee).WarehouseID)
+				}},
+			}
+		}),
+	})
+
+	buyerType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Buyer",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":             &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"card_number_id": &graphql.Field{Type: graphql.String},
+				"first_name":     &graphql.Field{Type: graphql.String},
+				"last_name":      &graphql.Field{Type: graphql.String},
+				"locality_id":    &graphql.Field{Type: graphql.Int},
+				"locality": &graphql.Field{Type: localityType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					localityID := p.Source.(domain.Buyer).LocalityID
+					if localityID == nil {
+						return nil, nil
+					}
+					return reference(p, loadersFrom(p.Context).localities, *localityID)
+				}},
+				"purchase_orders": &graphql.Field{Type: list(orderType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return related(p, loadersFrom(p.Context).ordersByBuyer, p.Source.(domain.Buyer).ID)
+				}},
+			}
+		}),
+	})
+
+	orderType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "PurchaseOrder",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"order_number":      &graphql.Field{Type: graphql.String},
+				"order_date":        &graphql.Field{Type: dateTime},
+				"tracking_code":     &graphql.Field{Type: graphql.String},
+				"buyer_id":          &graphql.Field{Type: graphql.Int},
+				"product_record_id": &graphql.Field{Type: graphql.Int},
+				"order_status_id":   &graphql.Field{Type: graphql.Int},
+				"carrier_id":        &graphql.Field{Type: graphql.Int},
+				"warehouse_id":      &graphql.Field{Type: graphql.Int},
+				"buyer": &graphql.Field{Type: buyerType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reference(p, loadersFrom(p.Context).buyers, p.Source.(domain.PurchaseOrders).BuyerID)
+				}},
+				"carrier": &graphql.Field{Type: carrierType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reference(p, loadersFrom(p.Context).carriers, p.Source.(domain.PurchaseOrders).CarrierID)
+				}},
+				"warehouse": &graphql.Field{Type: warehouseType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reference(p, loadersFrom(p.Context).warehouses, p.Source.(domain.PurchaseOrders).WarehouseID)
+				}},
+			}
+		}),
+	})
+
+	carrierType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Carrier",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"cid":          &graphql.Field{Type: graphql.String},
+				"company_name": &graphql.Field{Type: graphql.String},
+				"address":      &graphql.Field{Type: graphql.String},
+				"telephone":    &graphql.Field{Type: graphql.String},
+				"locality_id":  &graphql.Field{Type: graphql.Int},
+				"locality": &graphql.Field{Type: localityType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reference(p, loadersFrom(p.Context).localities, p.Source.(domain.Carry).LocalityId)
+				}},
+				"purchase_orders": &graphql.Field{Type: list(orderType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return related(p, loadersFrom(p.Context).ordersByCarrier, p.Source.(domain.Carry).ID)
+				}},
+			}
+		}),
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"warehouse": &graphql.Field{Type: warehouseType, Args: idArgs, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Warehouses.Get(p.Context, p.Args["id"].(int)))
+			}},
+			"warehouses": &graphql.Field{Type: list(warehouseType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Warehouses.GetAll(p.Context))
+			}},
+			"section": &graphql.Field{Type: sectionType, Args: idArgs, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Sections.Get(p.Context, p.Args["id"].(int)))
+			}},
+			"sections": &graphql.Field{Type: list(sectionType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Sections.GetAll(p.Context))
+			}},
+			"product_batches": &graphql.Field{
+				Type: list(batchType),
+				Args: graphql.FieldConfigArgument{
+					"section_id": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+					"product_id": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					filter := domain.ProductBatchFilter{SectionID: p.Args["section_id"].(int), ProductID: p.Args["product_id"].(int)}
+					return resolved(s.Batches.GetAll(p.Context, filter))
+				},
+			},
+			"product": &graphql.Field{Type: productType, Args: idArgs, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Products.Get(p.Context, p.Args["id"].(int)))
+			}},
+			"products": &graphql.Field{Type: list(productType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Products.GetAll(p.Context))
+			}},
+			"seller": &graphql.Field{Type: sellerType, Args: idArgs, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Sellers.Get(p.Context, p.Args["id"].(int)))
+			}},
+			"sellers": &graphql.Field{Type: list(sellerType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Sellers.GetAll(p.Context))
+			}},
+			"employee": &graphql.Field{Type: employeeType, Args: idArgs, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Employees.Get(p.Context, p.Args["id"].(int)))
+			}},
+			"employees": &graphql.Field{Type: list(employeeType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Employees.GetAll(p.Context))
+			}},
+			"buyer": &graphql.Field{Type: buyerType, Args: idArgs, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Buyers.Get(p.Context, p.Args["id"].(int)))
+			}},
+			"buyers": &graphql.Field{Type: list(buyerType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Buyers.GetAll(p.Context))
+			}},
+			"purchase_order": &graphql.Field{Type: orderType, Args: idArgs, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.PurchaseOrders.Get(p.Context, p.Args["id"].(int)))
+			}},
+			"purchase_orders": &graphql.Field{Type: list(orderType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.PurchaseOrders.GetAll(p.Context))
+			}},
+			"carrier": &graphql.Field{Type: carrierType, Args: idArgs, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Carriers.Get(p.Context, p.Args["id"].(int)))
+			}},
+			"carriers": &graphql.Field{Type: list(carrierType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Carriers.GetAll(p.Context))
+			}},
+			"locality": &graphql.Field{Type: localityType, Args: idArgs, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Localities.Get(p.Context, p.Args["id"].(int)))
+			}},
+			"localities": &graphql.Field{Type: list(localityType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(s.Localities.GetAll(p.Context))
+			}},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// resolved returns the value a service returned, or nil when it failed: graphql
+// keeps a value returned along with an error, which would render the zero
+// value of a missing row instead of null.
+func resolved(value interface{}, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// related resolves the rows b loads for the key of the parent.
+func related(p graphql.ResolveParams, b *batch, key int) (interface{}, error) {
+	return b.thunk(p.Context, key), nil
+}
+
+// reference resolves a reference to the row b loads for id, or to null when
+// it is unset or points to a row that no longer exists.
+func reference(p graphql.ResolveParams, b *batch, id int) (interface{}, error) {
+	if id == 0 {
+		return nil, nil
+	}
+	return b.thunk(p.Context, id), nil
+}
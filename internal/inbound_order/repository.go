@@ -3,25 +3,50 @@ package inbound_order
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"sort"
 
 	_ "github.com/go-sql-driver/mysql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
 )
 
 type Repository interface {
+	GetAll(ctx context.Context, filter domain.InboundOrderFilter) ([]domain.InboundOrders, error)
 	Create(ctx context.Context, c domain.InboundOrders) (int, error)
 	Get(ctx context.Context, id int) (domain.InboundOrders, error)
 	Exists(ctx context.Context, order string) bool
-	ReportByAll(ctx context.Context) ([]domain.InboundOrdersReport, error)
-	ReportByOne(ctx context.Context, id int) (domain.InboundOrdersReport, error)
+	ReportByAll(ctx context.Context, filter domain.InboundOrdersReportFilter) ([]domain.InboundOrdersReport, error)
+	ReportByOne(ctx context.Context, id int, filter domain.InboundOrdersReportFilter) (domain.InboundOrdersReport, error)
 }
 
 const (
-	ReportByAll = "SELECT io.employee_id as `id`, employees.card_number_id, employees.first_name, employees.last_name, employees.warehouse_id, count(io.id) as `inbound_order_count`FROM inbound_orders io JOIN employees ON io.employee_id = employees.id Group BY io.employee_id"
-	ReportByOne = "SELECT io.employee_id as `id`, employees.card_number_id, employees.first_name, employees.last_name, employees.warehouse_id, count(io.id) as `inbound_order_count`FROM inbound_orders io JOIN employees ON io.employee_id = employees.id WHERE employee_id=? Group BY io.employee_id"
+	// assignedWarehouse is the warehouse the employee was assigned to when the
+	// order was received, or its current one for orders older than its
+	// assignment history.
+	assignedWarehouse = "COALESCE((SELECT ea.warehouse_id FROM employee_assignments ea WHERE ea.employee_id = io.employee_id AND ea.started_at <= io.order_date " +
+		"AND (ea.ended_at IS NULL OR ea.ended_at > io.order_date) ORDER BY ea.started_at DESC LIMIT 1), employees.warehouse_id)"
+	GetAllQuery = "SELECT id, order_date, order_number, employee_id, product_batch_id, warehouse_id FROM inbound_orders " +
+		"WHERE (? = 0 OR employee_id = ?) AND (? = 0 OR warehouse_id = ?) AND (? IS NULL OR order_date >= ?) AND (? IS NULL OR order_date < ?) ORDER BY order_date, id"
+	ReportEmployeesQuery = "SELECT id, card_number_id, first_name, last_name, warehouse_id FROM employees WHERE (? = 0 OR id = ?) ORDER BY id"
+	// ReportActivityQuery takes the bucket expression, one of reportBuckets.
+	ReportActivityQuery = "SELECT io.employee_id, " + assignedWarehouse + " as `assigned_warehouse_id`, %s as `bucket`, count(io.id), COALESCE(SUM(pb.initial_quantity), 0) " +
+		"FROM inbound_orders io JOIN employees ON io.employee_id = employees.id LEFT JOIN product_batches pb ON io.product_batch_id = pb.id " +
+		"WHERE (? = 0 OR io.employee_id = ?) AND (? IS NULL OR io.order_date >= ?) AND (? IS NULL OR io.order_date < ?) " +
+		"GROUP BY io.employee_id, assigned_warehouse_id, bucket HAVING (? = 0 OR assigned_warehouse_id = ?) ORDER BY io.employee_id, assigned_warehouse_id, bucket"
 )
 
+// reportBuckets maps each granularity to the start date of the bucket an
+// order falls in. Without a granularity every order falls in the same one.
+var reportBuckets = map[string]string{
+	"":                      "''",
+	domain.GranularityDay:   "DATE_FORMAT(io.order_date, '%Y-%m-%d')",
+	domain.GranularityWeek:  "DATE_FORMAT(DATE_SUB(io.order_date, INTERVAL WEEKDAY(io.order_date) DAY), '%Y-%m-%d')",
+	domain.GranularityMonth: "DATE_FORMAT(io.order_date, '%Y-%m-01')",
+}
+
 type repository struct {
 	db *sql.DB
 }
@@ -32,24 +57,46 @@ func NewRepository(db *sql.DB) Repository {
 	}
 }
 
+// Create inserts the order and its inbound_order.created event in one
+// transaction.
 func (r *repository) Create(ctx context.Context, i domain.InboundOrders) (int, error) {
 	query := "INSERT INTO inbound_orders (order_date, order_number, employee_id, product_batch_id, warehouse_id) VALUES (?,?,?,?,?)"
-	stmt, err := r.db.Prepare(query)
-	if err != nil {
-		return 0, err
-	}
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, i.OrderDate, i.OrderNumber, i.EmployeeID, i.ProductBatchID, i.WarehouseID)
+		if err != nil {
+			return err
+		}
 
-	res, err := stmt.Exec(&i.OrderDate, &i.OrderNumber, &i.EmployeeID, &i.ProductBatchID, &i.WarehouseID)
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		i.ID = int(id)
+
+		return outbox.Write(ctx, tx, domain.EventInboundOrderCreated, i.ID, i)
+	})
 	if err != nil {
 		return 0, err
 	}
+	return i.ID, nil
+}
 
-	id, err := res.LastInsertId()
+func (r *repository) GetAll(ctx context.Context, filter domain.InboundOrderFilter) ([]domain.InboundOrders, error) {
+	rows, err := r.db.QueryContext(ctx, GetAllQuery, filter.EmployeeID, filter.EmployeeID, filter.WarehouseID, filter.WarehouseID, filter.From, filter.From, filter.To, filter.To)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
+	defer rows.Close()
 
-	return int(id), nil
+	orders := []domain.InboundOrders{}
+	for rows.Next() {
+		i := domain.InboundOrders{}
+		if err := rows.Scan(&i.ID, &i.OrderDate, &i.OrderNumber, &i.EmployeeID, &i.ProductBatchID, &i.WarehouseID); err != nil {
+			return nil, err
+		}
+		orders = append(orders, i)
+	}
+	return orders, rows.Err()
 }
 
 func (r *repository) Get(ctx context.Context, id int) (domain.InboundOrders, error) {
@@ -71,37 +118,116 @@ func (r *repository) Exists(ctx context.Context, orderNumber string) bool {
 	return err == nil
 }
 
-func (r *repository) ReportByAll(ctx context.Context) ([]domain.InboundOrdersReport, error) {
+// get the inbound orders report of every employee, including those without
+// orders
+func (r *repository) ReportByAll(ctx context.Context, filter domain.InboundOrdersReportFilter) ([]domain.InboundOrdersReport, error) {
+	return r.report(ctx, 0, filter)
+}
+
+// get the inbound orders report of one specific employee
+func (r *repository) ReportByOne(ctx context.Context, id int, filter domain.InboundOrdersReportFilter) (domain.InboundOrdersReport, error) {
+	report, err := r.report(ctx, id, filter)
+	if err != nil {
+		return domain.InboundOrdersReport{}, err
+	}
+	if len(report) == 0 {
+		return domain.InboundOrdersReport{}, ErrNotFound
+	}
+	return report[0], nil
+}
+
+// report lists the employees, all of them when id is 0, and adds up their
+// orders per warehouse and per bucket. With a warehouse filter it keeps the
+// employees currently in the warehouse and those who received orders there.
+func (r *repository) report(ctx context.Context, id int, filter domain.InboundOrdersReportFilter) ([]domain.InboundOrdersReport, error) {
+	bucket, ok := reportBuckets[filter.Granularity]
+	if !ok {
+		return nil, ErrInvalidGranularity
+	}
 
-	rows, err := r.db.Query(ReportByAll)
+	employees, err := r.reportEmployees(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(ReportActivityQuery, bucket), id, id, filter.From, filter.From, filter.To, filter.To, filter.WarehouseID, filter.WarehouseID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var report []domain.InboundOrdersReport
+
+	index := make(map[int]int, len(employees))
+	for i, e := range employees {
+		index[e.ID] = i
+	}
+	active := make(map[int]bool)
 	for rows.Next() {
-		p := domain.InboundOrdersReport{}
-		err := rows.Scan(&p.ID, &p.CardNumberID, &p.FirstName, &p.LastName, &p.WarehouseID, &p.InboundOrdersCount)
-		if err != nil {
+		var employeeID int
+		var start string
+		w := domain.InboundOrdersByWarehouse{}
+		if err := rows.Scan(&employeeID, &w.WarehouseID, &start, &w.InboundOrdersCount, &w.UnitsReceived); err != nil {
 			return nil, err
 		}
-		report = append(report, p)
+		i, ok := index[employeeID]
+		if !ok {
+			continue
+		}
+		e := &employees[i]
+		active[employeeID] = true
+		e.InboundOrdersCount += w.InboundOrdersCount
+		e.UnitsReceived += w.UnitsReceived
+		if n := len(e.Warehouses); n > 0 && e.Warehouses[n-1].WarehouseID == w.WarehouseID {
+			e.Warehouses[n-1].InboundOrdersCount += w.InboundOrdersCount
+			e.Warehouses[n-1].UnitsReceived += w.UnitsReceived
+		} else {
+			e.Warehouses = append(e.Warehouses, w)
+		}
+		if filter.Granularity != "" {
+			e.Buckets = addBucket(e.Buckets, domain.InboundOrdersBucket{Start: start, InboundOrdersCount: w.InboundOrdersCount, UnitsReceived: w.UnitsReceived})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	report := []domain.InboundOrdersReport{}
+	for _, e := range employees {
+		if filter.WarehouseID == 0 || e.WarehouseID == filter.WarehouseID || active[e.ID] {
+			report = append(report, e)
+		}
 	}
 	return report, nil
 }
 
-// get all product_records by one specific product
-func (r *repository) ReportByOne(ctx context.Context, id int) (domain.InboundOrdersReport, error) {
-
-	row := r.db.QueryRow(ReportByOne, id)
-	p := domain.InboundOrdersReport{}
-	err := row.Scan(&p.ID, &p.CardNumberID, &p.FirstName, &p.LastName, &p.WarehouseID, &p.InboundOrdersCount)
+func (r *repository) reportEmployees(ctx context.Context, id int) ([]domain.InboundOrdersReport, error) {
+	rows, err := r.db.QueryContext(ctx, ReportEmployeesQuery, id, id)
 	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		if err.Error() == "sql: no rows in result set" {
-			return domain.InboundOrdersReport{}, ErrNotFound
+	employees := []domain.InboundOrdersReport{}
+	for rows.Next() {
+		e := domain.InboundOrdersReport{}
+		if err := rows.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID); err != nil {
+			return nil, err
 		}
-		return domain.InboundOrdersReport{}, err
+		employees = append(employees, e)
+	}
+	return employees, rows.Err()
+}
+
+// addBucket adds b to buckets, kept sorted by start date. Rows come sorted by
+// warehouse first, so the same period shows up once per warehouse.
+func addBucket(buckets []domain.InboundOrdersBucket, b domain.InboundOrdersBucket) []domain.InboundOrdersBucket {
+	i := sort.Search(len(buckets), func(i int) bool { return buckets[i].Start >= b.Start })
+	if i < len(buckets) && buckets[i].Start == b.Start {
+		buckets[i].InboundOrdersCount += b.InboundOrdersCount
+		buckets[i].UnitsReceived += b.UnitsReceived
+		return buckets
 	}
-	return p, nil
+	buckets = append(buckets, domain.InboundOrdersBucket{})
+	copy(buckets[i+1:], buckets[i:])
+	buckets[i] = b
+	return buckets
 }
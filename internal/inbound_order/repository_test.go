@@ -11,6 +11,7 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/inbound_order"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -19,7 +20,7 @@ import (
 var db = InitDatabase()
 
 var InboundOrdersExpected = domain.InboundOrders{
-	OrderDate:      "01/01/01",
+	OrderDate:      datetime.MustParse("2001-01-01"),
 	OrderNumber:    "001",
 	EmployeeID:     1,
 	ProductBatchID: 1,
@@ -106,6 +107,7 @@ func TestGetAllInboundOrders(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
 		defer cancel()
 
+		EmployeeExpected.CardNumberID = uuid.New().String()
 		resultEmployee, err := repositoryEmployee.Save(ctx, EmployeeExpected)
 		assert.NoError(t, err)
 
@@ -114,7 +116,7 @@ func TestGetAllInboundOrders(t *testing.T) {
 		_, err = repository.Create(ctx, InboundOrdersExpected)
 		assert.NoError(t, err)
 
-		report, err := repository.ReportByAll(ctx)
+		report, err := repository.ReportByAll(ctx, domain.InboundOrdersReportFilter{})
 		assert.NoError(t, err)
 		assert.True(t, len(report) > 0)
 	})
@@ -128,6 +130,7 @@ func TestGetOneInboundOrders(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
 		defer cancel()
 
+		EmployeeExpected.CardNumberID = uuid.New().String()
 		resultEmployee, err := repositoryEmployee.Save(ctx, EmployeeExpected)
 		assert.NoError(t, err)
 
@@ -136,7 +139,7 @@ func TestGetOneInboundOrders(t *testing.T) {
 		_, err = repository.Create(ctx, InboundOrdersExpected)
 		assert.NoError(t, err)
 
-		result, err := repository.ReportByOne(ctx, resultEmployee)
+		result, err := repository.ReportByOne(ctx, resultEmployee, domain.InboundOrdersReportFilter{})
 		assert.NoError(t, err)
 		assert.Equal(t, resultEmployee, result.ID)
 		assert.True(t, result.InboundOrdersCount == 1)
@@ -146,13 +149,37 @@ func TestGetOneInboundOrders(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
 		defer cancel()
 
-		_, err := repository.ReportByOne(ctx, 50000000)
+		_, err := repository.ReportByOne(ctx, 50000000, domain.InboundOrdersReportFilter{})
 		assert.Error(t, err)
 		expectedErrorMessage := inbound_order.ErrNotFound.Error()
 		assert.Equal(t, expectedErrorMessage, err.Error())
 	})
 }
 
+func TestReportInboundOrdersWithoutActivity(t *testing.T) {
+	t.Run("It should report employees without inbound orders", func(t *testing.T) {
+		repository := inbound_order.NewRepository(db)
+		repositoryEmployee := employee.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+		defer cancel()
+
+		EmployeeExpected.CardNumberID = uuid.New().String()
+		resultEmployee, err := repositoryEmployee.Save(ctx, EmployeeExpected)
+		assert.NoError(t, err)
+
+		result, err := repository.ReportByOne(ctx, resultEmployee, domain.InboundOrdersReportFilter{Granularity: domain.GranularityDay})
+		assert.NoError(t, err)
+		assert.Equal(t, resultEmployee, result.ID)
+		assert.Equal(t, 0, result.InboundOrdersCount)
+		assert.Empty(t, result.Buckets)
+
+		report, err := repository.ReportByAll(ctx, domain.InboundOrdersReportFilter{WarehouseID: EmployeeExpected.WarehouseID})
+		assert.NoError(t, err)
+		assert.Contains(t, report, result)
+	})
+}
+
 func InitDatabase() *sql.DB {
 	txdb.Register("txdb", "mysql", "root:@/melisprint")
 	db, _ := sql.Open("txdb", uuid.New().String())
@@ -5,6 +5,10 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
+	productbatch "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
 )
 
 // Errors
@@ -16,29 +20,51 @@ var (
 	ErrTryAgain            = errors.New("internal error")
 	ErrAlredyExists        = errors.New("already exists")
 	ErrInvalidJSON         = errors.New("invalid JSON")
+	ErrEmployeeNotFound    = errors.New("employee not found")
+	ErrWarehouseNotFound   = errors.New("warehouse not found")
+	ErrBatchNotFound       = errors.New("product batch not found")
+	ErrEmployeeWarehouse   = errors.New("employee does not work in the warehouse")
+	ErrBatchWarehouse      = errors.New("product batch is not stored in the warehouse")
+	ErrInvalidRange        = errors.New("from must be before to")
+	ErrInvalidGranularity  = errors.New("granularity must be day, week or month")
 )
 
 type Service interface {
 	Get(ctx context.Context, id int) (domain.InboundOrders, error)
+	GetAll(ctx context.Context, filter domain.InboundOrderFilter) ([]domain.InboundOrders, error)
 	Create(ctx context.Context, d domain.InboundOrders) (domain.InboundOrders, error)
-	ReportByAll(ctx context.Context) ([]domain.InboundOrdersReport, error)
-	ReportByOne(ctx context.Context, id int) (domain.InboundOrdersReport, error)
+	ReportByAll(ctx context.Context, filter domain.InboundOrdersReportFilter) ([]domain.InboundOrdersReport, error)
+	ReportByOne(ctx context.Context, id int, filter domain.InboundOrdersReportFilter) (domain.InboundOrdersReport, error)
 }
 
 type inboundOrderService struct {
-	repository Repository
+	repository          Repository
+	employeeRepository  employee.Repository
+	warehouseRepository warehouse.Repository
+	batchRepository     productbatch.Repository
+	sectionRepository   section.Repository
 }
 
-func NewService(r Repository) Service {
+func NewService(r Repository, er employee.Repository, wr warehouse.Repository, br productbatch.Repository, sr section.Repository) Service {
 	return &inboundOrderService{
-		repository: r,
+		repository:          r,
+		employeeRepository:  er,
+		warehouseRepository: wr,
+		batchRepository:     br,
+		sectionRepository:   sr,
 	}
 }
 
+// Create stores an inbound order after checking that the employee, the
+// warehouse and the product batch exist, that the employee works in the
+// warehouse and that the batch is stored in one of its sections.
 func (c *inboundOrderService) Create(ctx context.Context, d domain.InboundOrders) (domain.InboundOrders, error) {
 	if c.repository.Exists(ctx, d.OrderNumber) {
 		return domain.InboundOrders{}, ErrAlredyExists
 	}
+	if err := c.validate(ctx, d); err != nil {
+		return domain.InboundOrders{}, err
+	}
 
 	InboundOrdersId, err := c.repository.Create(ctx, d)
 	if err != nil {
@@ -48,6 +74,49 @@ func (c *inboundOrderService) Create(ctx context.Context, d domain.InboundOrders
 	return d, nil
 }
 
+func (c *inboundOrderService) validate(ctx context.Context, d domain.InboundOrders) error {
+	employeeDomain, err := c.employeeRepository.Get(ctx, d.EmployeeID)
+	if err != nil {
+		if errors.Is(err, employee.ErrNotFound) {
+			return ErrEmployeeNotFound
+		}
+		return err
+	}
+	if _, err := c.warehouseRepository.Get(ctx, d.WarehouseID); err != nil {
+		if errors.Is(err, warehouse.ErrNotFound) {
+			return ErrWarehouseNotFound
+		}
+		return err
+	}
+	batch, err := c.batchRepository.Get(ctx, d.ProductBatchID)
+	if err != nil {
+		if errors.Is(err, productbatch.ErrNotFound) {
+			return ErrBatchNotFound
+		}
+		return err
+	}
+
+	if employeeDomain.WarehouseID != d.WarehouseID {
+		return ErrEmployeeWarehouse
+	}
+	sectionDomain, err := c.sectionRepository.Get(ctx, batch.SectionID)
+	if err != nil {
+		return err
+	}
+	if sectionDomain.WarehouseID != d.WarehouseID {
+		return ErrBatchWarehouse
+	}
+	return nil
+}
+
+// GetAll lists the inbound orders matching filter, oldest first.
+func (s *inboundOrderService) GetAll(ctx context.Context, filter domain.InboundOrderFilter) ([]domain.InboundOrders, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To.Time) {
+		return nil, ErrInvalidRange
+	}
+	return s.repository.GetAll(ctx, filter)
+}
+
 func (s *inboundOrderService) Get(ctx context.Context, id int) (domain.InboundOrders, error) {
 	inboundOrders, err := s.repository.Get(ctx, id)
 	if err != nil {
@@ -56,14 +125,30 @@ func (s *inboundOrderService) Get(ctx context.Context, id int) (domain.InboundOr
 	return inboundOrders, nil
 }
 
-func (s *inboundOrderService) ReportByAll(ctx context.Context) ([]domain.InboundOrdersReport, error) {
-	report, err := s.repository.ReportByAll(ctx)
-
-	return report, err
+// ReportByAll reports the orders received by every employee within filter,
+// including employees who received none.
+func (s *inboundOrderService) ReportByAll(ctx context.Context, filter domain.InboundOrdersReportFilter) ([]domain.InboundOrdersReport, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.repository.ReportByAll(ctx, filter)
 }
 
-func (s *inboundOrderService) ReportByOne(ctx context.Context, id int) (domain.InboundOrdersReport, error) {
-	report, err := s.repository.ReportByOne(ctx, id)
+func (s *inboundOrderService) ReportByOne(ctx context.Context, id int, filter domain.InboundOrdersReportFilter) (domain.InboundOrdersReport, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return domain.InboundOrdersReport{}, err
+	}
+	return s.repository.ReportByOne(ctx, id, filter)
+}
 
-	return report, err
+func validateReportFilter(filter domain.InboundOrdersReportFilter) error {
+	switch filter.Granularity {
+	case "", domain.GranularityDay, domain.GranularityWeek, domain.GranularityMonth:
+	default:
+		return ErrInvalidGranularity
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To.Time) {
+		return ErrInvalidRange
+	}
+	return nil
 }
@@ -6,8 +6,16 @@ import (
 	"testing"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/inbound_order"
+	productbatch "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	employeemocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/employee"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/inbound_order"
+	batchmocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product_batch"
+	sectionmocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/section"
+	warehousemocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/warehouse"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -15,7 +23,7 @@ import (
 var id = 2
 
 var expectedInboundOrder = domain.InboundOrders{
-	OrderDate:      "01/01/01",
+	OrderDate:      datetime.MustParse("2001-01-01"),
 	OrderNumber:    "001",
 	EmployeeID:     1,
 	ProductBatchID: 1,
@@ -24,14 +32,15 @@ var expectedInboundOrder = domain.InboundOrders{
 
 func TestCreateInboundOrders(t *testing.T) {
 	t.Run("Should create the inbound order if it contains the required fields", func(t *testing.T) {
-		repository, service := InitServerWithInboundOrdersRepository(t)
+		repository, references, service := InitInboundOrderService(t)
+		references.valid()
 		repository.On("Exists", mock.Anything, "001").Return(false)
 		repository.On("Create", mock.Anything, expectedInboundOrder).Return(id, nil)
 
 		inbound_order, err := service.Create(context.TODO(), expectedInboundOrder)
 
 		assert.Equal(t, 2, inbound_order.ID)
-		assert.Equal(t, "01/01/01", inbound_order.OrderDate)
+		assert.Equal(t, datetime.MustParse("2001-01-01"), inbound_order.OrderDate)
 		assert.Equal(t, "001", inbound_order.OrderNumber)
 		assert.Equal(t, 1, inbound_order.EmployeeID)
 		assert.Equal(t, 1, inbound_order.ProductBatchID)
@@ -53,14 +62,15 @@ func TestCreateInboundOrders(t *testing.T) {
 	})
 
 	t.Run("Should return error when there is an save repository error", func(t *testing.T) {
-		repository, service := InitServerWithInboundOrdersRepository(t)
+		repository, references, service := InitInboundOrderService(t)
+		references.valid()
 
 		repository.On("Exists", mock.Anything, mock.Anything).Return(false)
 
 		expectedError := errors.New("some error")
-		repository.On("Create", mock.Anything, domain.InboundOrders{}).Return(0, expectedError)
+		repository.On("Create", mock.Anything, expectedInboundOrder).Return(0, expectedError)
 
-		_, err := service.Create(context.TODO(), domain.InboundOrders{})
+		_, err := service.Create(context.TODO(), expectedInboundOrder)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
@@ -124,9 +134,9 @@ func TestReportByAllInboundOrders(t *testing.T) {
 		}
 
 		repository, service := InitServerWithInboundOrdersRepository(t)
-		repository.On("ReportByAll", mock.Anything).Return(expectedReport, nil)
+		repository.On("ReportByAll", mock.Anything, mock.Anything).Return(expectedReport, nil)
 
-		reports, err := service.ReportByAll(context.TODO())
+		reports, err := service.ReportByAll(context.TODO(), domain.InboundOrdersReportFilter{})
 
 		assert.True(t, len(reports) == 2)
 		assert.NoError(t, err)
@@ -146,9 +156,9 @@ func TestReportByOneInboundOrders(t *testing.T) {
 		}
 		repository, service := InitServerWithInboundOrdersRepository(t)
 
-		repository.On("ReportByOne", mock.Anything, mock.Anything).Return(expectedReport, nil)
+		repository.On("ReportByOne", mock.Anything, mock.Anything, mock.Anything).Return(expectedReport, nil)
 
-		report, err := service.ReportByOne(context.TODO(), 1)
+		report, err := service.ReportByOne(context.TODO(), 1, domain.InboundOrdersReportFilter{})
 
 		assert.Equal(t, expectedReport, report)
 		assert.NoError(t, err)
@@ -158,16 +168,170 @@ func TestReportByOneInboundOrders(t *testing.T) {
 		repository, service := InitServerWithInboundOrdersRepository(t)
 
 		expectedError := errors.New("inbound orders not found")
-		repository.On("ReportByOne", mock.Anything, mock.Anything).Return(expectedEmpityReport, inbound_order.ErrNotFound)
-		_, err := service.ReportByOne(context.TODO(), 1)
+		repository.On("ReportByOne", mock.Anything, mock.Anything, mock.Anything).Return(expectedEmpityReport, inbound_order.ErrNotFound)
+		_, err := service.ReportByOne(context.TODO(), 1, domain.InboundOrdersReportFilter{})
 		assert.Equal(t, expectedError, err)
 		assert.Error(t, err)
 	})
 }
 
+func TestReportInboundOrdersFilter(t *testing.T) {
+	t.Run("Should pass the filter to the repository", func(t *testing.T) {
+		filter := domain.InboundOrdersReportFilter{
+			WarehouseID: 1,
+			From:        datetime.MustParse("2023-01-01"),
+			To:          datetime.MustParse("2023-02-01"),
+			Granularity: domain.GranularityWeek,
+		}
+		expectedReport := []domain.InboundOrdersReport{
+			{ID: 1, WarehouseID: 1, InboundOrdersCount: 2, UnitsReceived: 30, Buckets: []domain.InboundOrdersBucket{
+				{Start: "2023-01-02", InboundOrdersCount: 2, UnitsReceived: 30},
+			}},
+			{ID: 2, WarehouseID: 1},
+		}
+		repository, service := InitServerWithInboundOrdersRepository(t)
+		repository.On("ReportByAll", mock.Anything, filter).Return(expectedReport, nil)
+
+		report, err := service.ReportByAll(context.TODO(), filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReport, report)
+	})
+	t.Run("Should return error when the granularity is not supported", func(t *testing.T) {
+		repository, service := InitServerWithInboundOrdersRepository(t)
+
+		_, err := service.ReportByAll(context.TODO(), domain.InboundOrdersReportFilter{Granularity: "year"})
+		assert.ErrorIs(t, err, inbound_order.ErrInvalidGranularity)
+
+		_, err = service.ReportByOne(context.TODO(), 1, domain.InboundOrdersReportFilter{Granularity: "year"})
+		assert.ErrorIs(t, err, inbound_order.ErrInvalidGranularity)
+		repository.AssertNotCalled(t, "ReportByAll", mock.Anything, mock.Anything)
+		repository.AssertNotCalled(t, "ReportByOne", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Should return error when from is not before to", func(t *testing.T) {
+		_, service := InitServerWithInboundOrdersRepository(t)
+		filter := domain.InboundOrdersReportFilter{From: datetime.MustParse("2023-02-01"), To: datetime.MustParse("2023-01-01")}
+
+		_, err := service.ReportByAll(context.TODO(), filter)
+
+		assert.ErrorIs(t, err, inbound_order.ErrInvalidRange)
+	})
+}
+
+func TestCreateInboundOrdersReferences(t *testing.T) {
+	t.Run("Should return error when the employee does not exist", func(t *testing.T) {
+		repository, references, service := InitInboundOrderService(t)
+		repository.On("Exists", mock.Anything, "001").Return(false)
+		references.employee.On("Get", mock.Anything, 1).Return(domain.Employee{}, employee.ErrNotFound)
+
+		_, err := service.Create(context.TODO(), expectedInboundOrder)
+
+		assert.ErrorIs(t, err, inbound_order.ErrEmployeeNotFound)
+		repository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return error when the warehouse does not exist", func(t *testing.T) {
+		repository, references, service := InitInboundOrderService(t)
+		repository.On("Exists", mock.Anything, "001").Return(false)
+		references.employee.On("Get", mock.Anything, 1).Return(domain.Employee{ID: 1, WarehouseID: 1}, nil)
+		references.warehouse.On("Get", mock.Anything, 1).Return(domain.Warehouse{}, warehouse.ErrNotFound)
+
+		_, err := service.Create(context.TODO(), expectedInboundOrder)
+
+		assert.ErrorIs(t, err, inbound_order.ErrWarehouseNotFound)
+	})
+
+	t.Run("Should return error when the product batch does not exist", func(t *testing.T) {
+		repository, references, service := InitInboundOrderService(t)
+		repository.On("Exists", mock.Anything, "001").Return(false)
+		references.employee.On("Get", mock.Anything, 1).Return(domain.Employee{ID: 1, WarehouseID: 1}, nil)
+		references.warehouse.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1}, nil)
+		references.batch.On("Get", mock.Anything, 1).Return(domain.ProductBatch{}, productbatch.ErrNotFound)
+
+		_, err := service.Create(context.TODO(), expectedInboundOrder)
+
+		assert.ErrorIs(t, err, inbound_order.ErrBatchNotFound)
+	})
+
+	t.Run("Should return error when the employee works in another warehouse", func(t *testing.T) {
+		repository, references, service := InitInboundOrderService(t)
+		repository.On("Exists", mock.Anything, "001").Return(false)
+		references.employee.On("Get", mock.Anything, 1).Return(domain.Employee{ID: 1, WarehouseID: 2}, nil)
+		references.warehouse.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1}, nil)
+		references.batch.On("Get", mock.Anything, 1).Return(domain.ProductBatch{ID: 1, SectionID: 3}, nil)
+
+		_, err := service.Create(context.TODO(), expectedInboundOrder)
+
+		assert.ErrorIs(t, err, inbound_order.ErrEmployeeWarehouse)
+	})
+
+	t.Run("Should return error when the batch is stored in another warehouse", func(t *testing.T) {
+		repository, references, service := InitInboundOrderService(t)
+		repository.On("Exists", mock.Anything, "001").Return(false)
+		references.employee.On("Get", mock.Anything, 1).Return(domain.Employee{ID: 1, WarehouseID: 1}, nil)
+		references.warehouse.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1}, nil)
+		references.batch.On("Get", mock.Anything, 1).Return(domain.ProductBatch{ID: 1, SectionID: 3}, nil)
+		references.section.On("Get", 3).Return(domain.Section{ID: 3, WarehouseID: 2}, nil)
+
+		_, err := service.Create(context.TODO(), expectedInboundOrder)
+
+		assert.ErrorIs(t, err, inbound_order.ErrBatchWarehouse)
+	})
+}
+
+func TestListInboundOrders(t *testing.T) {
+	t.Run("Should return the orders matching the filter", func(t *testing.T) {
+		repository, service := InitServerWithInboundOrdersRepository(t)
+		filter := domain.InboundOrderFilter{EmployeeID: 1, From: datetime.MustParse("2001-01-01")}
+		repository.On("GetAll", mock.Anything, filter).Return([]domain.InboundOrders{expectedInboundOrder}, nil)
+
+		orders, err := service.GetAll(context.TODO(), filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.InboundOrders{expectedInboundOrder}, orders)
+	})
+
+	t.Run("Should return error when from is not before to", func(t *testing.T) {
+		_, service := InitServerWithInboundOrdersRepository(t)
+		filter := domain.InboundOrderFilter{From: datetime.MustParse("2001-02-01"), To: datetime.MustParse("2001-01-01")}
+
+		_, err := service.GetAll(context.TODO(), filter)
+
+		assert.ErrorIs(t, err, inbound_order.ErrInvalidRange)
+	})
+}
+
+type inboundOrderReferences struct {
+	employee  *employeemocks.EmployeeRepositoryMock
+	warehouse *warehousemocks.WarehouseRepositoryMock
+	batch     *batchmocks.ProductBatchRepositoryMock
+	section   *sectionmocks.SectionRepositoryMock
+}
+
+// valid makes every reference of expectedInboundOrder exist and agree on
+// warehouse 1.
+func (r inboundOrderReferences) valid() {
+	r.employee.On("Get", mock.Anything, 1).Return(domain.Employee{ID: 1, WarehouseID: 1}, nil)
+	r.warehouse.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1}, nil)
+	r.batch.On("Get", mock.Anything, 1).Return(domain.ProductBatch{ID: 1, SectionID: 1}, nil)
+	r.section.On("Get", 1).Return(domain.Section{ID: 1, WarehouseID: 1}, nil)
+}
+
 func InitServerWithInboundOrdersRepository(t *testing.T) (*mocks.InboundOrderRepositoryMock, inbound_order.Service) {
 	t.Helper()
-	mockRepositoryInboundOrders := &mocks.InboundOrderRepositoryMock{}
-	mockService := inbound_order.NewService(mockRepositoryInboundOrders)
+	mockRepositoryInboundOrders, _, mockService := InitInboundOrderService(t)
 	return mockRepositoryInboundOrders, mockService
 }
+
+func InitInboundOrderService(t *testing.T) (*mocks.InboundOrderRepositoryMock, inboundOrderReferences, inbound_order.Service) {
+	t.Helper()
+	mockRepositoryInboundOrders := &mocks.InboundOrderRepositoryMock{}
+	references := inboundOrderReferences{
+		employee:  &employeemocks.EmployeeRepositoryMock{},
+		warehouse: &warehousemocks.WarehouseRepositoryMock{},
+		batch:     &batchmocks.ProductBatchRepositoryMock{},
+		section:   &sectionmocks.SectionRepositoryMock{},
+	}
+	mockService := inbound_order.NewService(mockRepositoryInboundOrders, references.employee, references.warehouse, references.batch, references.section)
+	return mockRepositoryInboundOrders, references, mockService
+}
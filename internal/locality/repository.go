@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
 )
 
 const (
@@ -13,9 +14,14 @@ const (
 	ReportSellersByLocality = "SELECT l.ID, l.locality_name, COUNT(s.ID) FROM localities l JOIN sellers s ON l.ID = s.locality_id where l.id = ? GROUP BY l.id, l.locality_name"
 	ReportLocality = "SELECT l.ID, l.locality_name, COUNT(s.ID) FROM localities l JOIN sellers s GROUP BY l.id, l.locality_name"
 	CreateLocality = "INSERT INTO localities (locality_name, province_id) VALUES (?, ?)"
+	GetAllLocalities = "SELECT l.id, l.locality_name, COALESCE(p.province_name, '') FROM localities l LEFT JOIN provinces p ON l.province_id = p.id ORDER BY l.id"
+	GetLocality = "SELECT l.id, l.locality_name, COALESCE(p.province_name, '') FROM localities l LEFT JOIN provinces p ON l.province_id = p.id WHERE l.id = ?"
 )
 
 type Repository interface {
+	GetAll(ctx context.Context) ([]domain.Locality, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Locality, error)
+	Get(ctx context.Context, id int) (domain.Locality, error)
 	Save(ctx context.Context, l domain.LocalityInput) (int, error)
 	GetProvinceByName(ctx context.Context, name string) (int, error)
 	ExistsById(ctx context.Context, id int) bool
@@ -33,6 +39,64 @@ func NewRepository(db *sql.DB) Repository {
 	}
 }
 
+func (r *repository) GetAll(ctx context.Context) ([]domain.Locality, error) {
+	rows, err := r.db.QueryContext(ctx, GetAllLocalities)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	localities := []domain.Locality{}
+	for rows.Next() {
+		l := domain.Locality{}
+		if err := rows.Scan(&l.ID, &l.LocalityName, &l.ProvinceName); err != nil {
+			return nil, err
+		}
+		localities = append(localities, l)
+	}
+	return localities, rows.Err()
+}
+
+// GetByIDs returns the localities with the given ids.
+func (r *repository) GetByIDs(ctx context.Context, ids []int) ([]domain.Locality, error) {
+	return r.getIn(ctx, "id", ids)
+}
+
+// getIn returns the localities whose column holds one of values, in one query.
+func (r *repository) getIn(ctx context.Context, column string, values []int) ([]domain.Locality, error) {
+	localities := []domain.Locality{}
+	if len(values) == 0 {
+		return localities, nil
+	}
+	in, args := mysqlutil.In(values)
+	rows, err := r.db.QueryContext(ctx, "SELECT l.id, l.locality_name, COALESCE(p.province_name, '') FROM localities l LEFT JOIN provinces p ON l.province_id = p.id WHERE l."+column+" IN "+in+" ORDER BY l.id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		l := domain.Locality{}
+		if err := rows.Scan(&l.ID, &l.LocalityName, &l.ProvinceName); err != nil {
+			return nil, err
+		}
+		localities = append(localities, l)
+	}
+	return localities, rows.Err()
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.Locality, error) {
+	l := domain.Locality{}
+	err := r.db.QueryRowContext(ctx, GetLocality, id).Scan(&l.ID, &l.LocalityName, &l.ProvinceName)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return domain.Locality{}, ErrNotFound
+		}
+		return domain.Locality{}, err
+	}
+	return l, nil
+}
+
 func (r *repository) Save(ctx context.Context, l domain.LocalityInput) (int, error) {
 	stmt, err := r.db.Prepare(CreateLocality)
 	if err != nil {
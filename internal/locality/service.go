@@ -16,6 +16,9 @@ var (
 )
 
 type Service interface {
+	GetAll(ctx context.Context) ([]domain.Locality, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Locality, error)
+	Get(ctx context.Context, id int) (domain.Locality, error)
 	Save(ctx context.Context, locality domain.Locality) (domain.LocalityInput, error)
 	ReportSellersByLocality(ctx context.Context, id int) ([]domain.LocalityReport, error)
 	ExistsById(c context.Context, idLocality int) error
@@ -31,6 +34,19 @@ func NewService(r Repository) Service {
 	}
 }
 
+func (l *LocalityService) GetAll(c context.Context) ([]domain.Locality, error) {
+	return l.repository.GetAll(c)
+}
+
+// GetByIDs returns the localities with the given ids.
+func (l *LocalityService) GetByIDs(ctx context.Context, ids []int) ([]domain.Locality, error) {
+	return l.repository.GetByIDs(ctx, ids)
+}
+
+func (l *LocalityService) Get(c context.Context, id int) (domain.Locality, error) {
+	return l.repository.Get(c, id)
+}
+
 func (l *LocalityService) Save(c context.Context, locality domain.Locality) (domain.LocalityInput, error) {
 	IdProvince, err := l.repository.GetProvinceByName(c, locality.ProvinceName)
 	if err != nil {
@@ -0,0 +1,51 @@
+// Package outbox publishes domain events reliably. Repositories write an
+// event in the same transaction as the change it describes, and a Relay
+// publishes the stored events afterwards, so an event is neither lost when
+// the process stops after the commit nor published for a change that was
+// rolled back.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+)
+
+const WriteQuery = "INSERT INTO outbox_events (aggregate_type, aggregate_id, event_type, payload, occurred_at) VALUES (?, ?, ?, ?, ?)"
+
+// Publisher sends events somewhere. Publish may be called more than once for
+// the same event, so consumers should tell copies apart by the event id.
+type Publisher interface {
+	Publish(ctx context.Context, e domain.Event) error
+}
+
+// PublisherFunc adapts a function to a Publisher.
+type PublisherFunc func(ctx context.Context, e domain.Event) error
+
+func (f PublisherFunc) Publish(ctx context.Context, e domain.Event) error {
+	return f(ctx, e)
+}
+
+// Write stores an event of the given type about the aggregate with the given
+// id, as part of tx. The aggregate type is the part of the event type before
+// the dot.
+func Write(ctx context.Context, tx *sql.Tx, eventType string, aggregateID int, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, WriteQuery, AggregateType(eventType), aggregateID, eventType, string(payload), datetime.Now())
+	return err
+}
+
+// AggregateType returns the aggregate an event type belongs to.
+func AggregateType(eventType string) string {
+	if i := strings.IndexByte(eventType, '.'); i >= 0 {
+		return eventType[:i]
+	}
+	return eventType
+}
@@ -0,0 +1,130 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+)
+
+// Environment variables read by PublisherFromEnv.
+const (
+	PublisherEnv = "OUTBOX_PUBLISHER"
+	FileEnv      = "OUTBOX_FILE"
+)
+
+// DefaultFile is where the file publisher writes when OUTBOX_FILE is unset.
+const DefaultFile = "outbox.jsonl"
+
+var ErrUnknownPublisher = errors.New("unknown outbox publisher")
+
+// PublisherFromEnv builds the publisher named by OUTBOX_PUBLISHER: "log",
+// the default, writes events to the standard logger, "file" appends them
+// to OUTBOX_FILE and "none" drops them.
+func PublisherFromEnv() (Publisher, error) {
+	switch name := strings.ToLower(strings.TrimSpace(os.Getenv(PublisherEnv))); name {
+	case "", "log":
+		return NewLogPublisher(log.Default()), nil
+	case "file":
+		path := os.Getenv(FileEnv)
+		if path == "" {
+			path = DefaultFile
+		}
+		return NewFilePublisher(path)
+	case "none":
+		return Multi(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPublisher, name)
+	}
+}
+
+// NewLogPublisher returns a publisher that logs every event as a JSON line.
+func NewLogPublisher(l *log.Logger) Publisher {
+	return PublisherFunc(func(ctx context.Context, e domain.Event) error {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		l.Printf("outbox event %s", line)
+		return nil
+	})
+}
+
+// FilePublisher appends events to a file, one JSON document per line.
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFilePublisher opens path for appending, creating it if needed.
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FilePublisher{file: file}, nil
+}
+
+// Publish writes the event and syncs the file, so a published event is on
+// disk before it is marked as published.
+func (p *FilePublisher) Publish(ctx context.Context, e domain.Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return p.file.Sync()
+}
+
+func (p *FilePublisher) Close() error {
+	return p.file.Close()
+}
+
+// ChannelPublisher hands events to an in-process consumer through a
+// channel. Publish waits while the channel is full, so a slow consumer holds
+// the relay back instead of losing events.
+type ChannelPublisher struct {
+	events chan domain.Event
+}
+
+func NewChannelPublisher(buffer int) *ChannelPublisher {
+	return &ChannelPublisher{events: make(chan domain.Event, buffer)}
+}
+
+// Events returns the channel the published events are sent to.
+func (p *ChannelPublisher) Events() <-chan domain.Event {
+	return p.events
+}
+
+func (p *ChannelPublisher) Publish(ctx context.Context, e domain.Event) error {
+	select {
+	case p.events <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Multi returns a publisher that publishes to every given publisher in turn
+// and fails on the first error. A retry publishes the event to all of them
+// again.
+func Multi(publishers ...Publisher) Publisher {
+	return PublisherFunc(func(ctx context.Context, e domain.Event) error {
+		for _, p := range publishers {
+			if err := p.Publish(ctx, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,131 @@
+package outbox_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateType(t *testing.T) {
+	t.Run("Should take the part of the event type before the dot", func(t *testing.T) {
+		assert.Equal(t, "product_batch", outbox.AggregateType(domain.EventProductBatchCreated))
+		assert.Equal(t, "seller", outbox.AggregateType(domain.EventSellerDeleted))
+		assert.Equal(t, "ping", outbox.AggregateType("ping"))
+	})
+}
+
+func TestPublisherFromEnv(t *testing.T) {
+	t.Run("Should default to the log publisher", func(t *testing.T) {
+		t.Setenv(outbox.PublisherEnv, "")
+
+		p, err := outbox.PublisherFromEnv()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, p)
+	})
+
+	t.Run("Should build the file publisher on OUTBOX_FILE", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		t.Setenv(outbox.PublisherEnv, "file")
+		t.Setenv(outbox.FileEnv, path)
+
+		p, err := outbox.PublisherFromEnv()
+
+		assert.NoError(t, err)
+		assert.IsType(t, &outbox.FilePublisher{}, p)
+		assert.NoError(t, p.(*outbox.FilePublisher).Close())
+	})
+
+	t.Run("Should reject an unknown publisher", func(t *testing.T) {
+		t.Setenv(outbox.PublisherEnv, "kafka")
+
+		_, err := outbox.PublisherFromEnv()
+
+		assert.ErrorIs(t, err, outbox.ErrUnknownPublisher)
+	})
+}
+
+func TestLogPublisher(t *testing.T) {
+	t.Run("Should log the event as JSON", func(t *testing.T) {
+		var out bytes.Buffer
+		p := outbox.NewLogPublisher(log.New(&out, "", 0))
+
+		err := p.Publish(context.TODO(), event(7, domain.EventProductDeleted, 3))
+
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), `"id":7`)
+		assert.Contains(t, out.String(), `"type":"product.deleted"`)
+	})
+}
+
+func TestFilePublisher(t *testing.T) {
+	t.Run("Should append one JSON line per event", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		p, err := outbox.NewFilePublisher(path)
+		assert.NoError(t, err)
+
+		assert.NoError(t, p.Publish(context.TODO(), event(1, domain.EventSellerCreated, 1)))
+		assert.NoError(t, p.Publish(context.TODO(), event(2, domain.EventSellerUpdated, 1)))
+		assert.NoError(t, p.Close())
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		assert.Len(t, lines, 2)
+		var e domain.Event
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &e))
+		assert.Equal(t, int64(2), e.ID)
+		assert.Equal(t, domain.EventSellerUpdated, e.Type)
+	})
+}
+
+func TestChannelPublisher(t *testing.T) {
+	t.Run("Should hand the events to the channel", func(t *testing.T) {
+		p := outbox.NewChannelPublisher(1)
+
+		assert.NoError(t, p.Publish(context.TODO(), event(1, domain.EventProductCreated, 1)))
+
+		e := <-p.Events()
+		assert.Equal(t, int64(1), e.ID)
+	})
+
+	t.Run("Should give up when the channel stays full", func(t *testing.T) {
+		p := outbox.NewChannelPublisher(0)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := p.Publish(ctx, event(1, domain.EventProductCreated, 1))
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestMulti(t *testing.T) {
+	t.Run("Should publish to every publisher and stop at the first error", func(t *testing.T) {
+		var calls []string
+		first := outbox.PublisherFunc(func(ctx context.Context, e domain.Event) error {
+			calls = append(calls, "first")
+			return errPublish
+		})
+		second := outbox.PublisherFunc(func(ctx context.Context, e domain.Event) error {
+			calls = append(calls, "second")
+			return nil
+		})
+
+		err := outbox.Multi(second, first, second).Publish(context.TODO(), event(1, domain.EventProductCreated, 1))
+
+		assert.True(t, errors.Is(err, errPublish))
+		assert.Equal(t, []string{"second", "first"}, calls)
+	})
+}
@@ -0,0 +1,121 @@
+package outbox
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+)
+
+// Defaults of a Relay. With them an event is retried for about two hours
+// before it is dead: the waits between its attempts add up to about ten
+// minutes until the backoff reaches its cap, and the last twelve are ten
+// minutes each.
+const (
+	DefaultBatchSize   = 100
+	DefaultMaxAttempts = 20
+	DefaultInterval    = time.Second
+	baseBackoff        = 5 * time.Second
+	maxBackoff         = 10 * time.Minute
+)
+
+// Relay publishes the stored events. Delivery is at least once: an event is
+// marked as published only after Publish returns, so it is published again
+// if the process stops in between. Events of the same aggregate go out in
+// the order they were written: when one fails, the later ones of its
+// aggregate wait for it to be published. A failed event is retried with
+// exponential backoff until MaxAttempts, after which it is dead and no
+// longer holds back its aggregate.
+type Relay struct {
+	BatchSize   int
+	MaxAttempts int
+
+	repository Repository
+	publisher  Publisher
+}
+
+func NewRelay(r Repository, p Publisher) *Relay {
+	return &Relay{
+		BatchSize:   DefaultBatchSize,
+		MaxAttempts: DefaultMaxAttempts,
+		repository:  r,
+		publisher:   p,
+	}
+}
+
+// Backoff returns how long to wait before the attempt that follows the given
+// number of failed ones: 5s, 10s, 20s and so on, capped at ten minutes.
+func Backoff(attempts int) time.Duration {
+	wait := baseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return wait
+}
+
+// Run publishes pending events every interval until ctx is done.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Errors are left for the next tick: the events stay pending.
+		_, _ = r.RelayOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce publishes one batch of pending events and returns how many were
+// published. It does nothing while another relay holds the lock.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	unlock, ok, err := r.repository.Lock(ctx)
+	if err != nil || !ok {
+		return 0, err
+	}
+	defer unlock()
+
+	events, err := r.repository.Pending(ctx, datetime.Now(), r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := map[string]bool{}
+	for _, e := range events {
+		aggregate := e.AggregateType + ":" + strconv.Itoa(e.AggregateID)
+		if blocked[aggregate] {
+			continue
+		}
+		if err := r.publisher.Publish(ctx, e); err != nil {
+			blocked[aggregate] = true
+			if err := r.fail(ctx, e, err); err != nil {
+				return published, err
+			}
+			continue
+		}
+		if err := r.repository.MarkPublished(ctx, e.ID, datetime.Now()); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+// fail records a failed attempt to publish e, killing it once it has used up
+// its attempts.
+func (r *Relay) fail(ctx context.Context, e domain.Event, err error) error {
+	now := datetime.Now()
+	attempts := e.Attempts + 1
+	if attempts >= r.MaxAttempts {
+		return r.repository.MarkDead(ctx, e.ID, err.Error(), now)
+	}
+	return r.repository.MarkFailed(ctx, e.ID, err.Error(), datetime.New(now.Add(Backoff(attempts))))
+}
@@ -0,0 +1,259 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var errPublish = errors.New("broker unavailable")
+
+func event(id int64, eventType string, aggregateID int) domain.Event {
+	return domain.Event{
+		ID:            id,
+		AggregateType: outbox.AggregateType(eventType),
+		AggregateID:   aggregateID,
+		Type:          eventType,
+		Data:          []byte(`{}`),
+	}
+}
+
+// memoryRepository keeps the events in memory, following the contract of
+// Repository.Pending.
+type memoryRepository struct {
+	events    []domain.Event
+	published map[int64]bool
+	dead      map[int64]bool
+	retryAt   map[int64]datetime.Time
+}
+
+func newMemoryRepository(events ...domain.Event) *memoryRepository {
+	return &memoryRepository{
+		events:    events,
+		published: map[int64]bool{},
+		dead:      map[int64]bool{},
+		retryAt:   map[int64]datetime.Time{},
+	}
+}
+
+func (r *memoryRepository) Lock(ctx context.Context) (func(), bool, error) {
+	return func() {}, true, nil
+}
+
+func (r *memoryRepository) Pending(ctx context.Context, now datetime.Time, limit int) ([]domain.Event, error) {
+	pending := []domain.Event{}
+	retried := map[string]bool{}
+	for _, e := range r.events {
+		if r.published[e.ID] || r.dead[e.ID] {
+			continue
+		}
+		aggregate := e.AggregateType + ":" + strconv.Itoa(e.AggregateID)
+		held := retried[aggregate]
+		if e.Attempts > 0 {
+			retried[aggregate] = true
+		}
+		if held || r.retryAt[e.ID].After(now.Time) || len(pending) == limit {
+			continue
+		}
+		pending = append(pending, e)
+	}
+	return pending, nil
+}
+
+func (r *memoryRepository) MarkPublished(ctx context.Context, id int64, at datetime.Time) error {
+	r.published[id] = true
+	return nil
+}
+
+func (r *memoryRepository) MarkFailed(ctx context.Context, id int64, reason string, retryAt datetime.Time) error {
+	r.attempt(id)
+	r.retryAt[id] = retryAt
+	return nil
+}
+
+func (r *memoryRepository) MarkDead(ctx context.Context, id int64, reason string, at datetime.Time) error {
+	r.attempt(id)
+	r.dead[id] = true
+	return nil
+}
+
+func (r *memoryRepository) attempt(id int64) {
+	for i := range r.events {
+		if r.events[i].ID == id {
+			r.events[i].Attempts++
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Run("Should double the wait after every failed attempt", func(t *testing.T) {
+		assert.Equal(t, 5*time.Second, outbox.Backoff(1))
+		assert.Equal(t, 10*time.Second, outbox.Backoff(2))
+		assert.Equal(t, 40*time.Second, outbox.Backoff(4))
+		assert.Equal(t, 10*time.Minute, outbox.Backoff(30))
+	})
+
+	t.Run("Should retry an event for about two hours by default", func(t *testing.T) {
+		var total time.Duration
+		for attempts := 1; attempts < outbox.DefaultMaxAttempts; attempts++ {
+			total += outbox.Backoff(attempts)
+		}
+
+		assert.Equal(t, 10*time.Minute, outbox.Backoff(outbox.DefaultMaxAttempts-1))
+		assert.Greater(t, total, 2*time.Hour)
+		assert.Less(t, total, 150*time.Minute)
+	})
+}
+
+func TestRelayOnce(t *testing.T) {
+	t.Run("Should publish pending events in order and mark them as published", func(t *testing.T) {
+		repository := &mocks.OutboxRepositoryMock{}
+		events := []domain.Event{
+			event(1, domain.EventProductCreated, 1),
+			event(2, domain.EventSellerCreated, 1),
+			event(3, domain.EventProductUpdated, 1),
+		}
+		repository.On("Lock", mock.Anything).Return(true, nil)
+		repository.On("Pending", mock.Anything, mock.Anything, outbox.DefaultBatchSize).Return(events, nil)
+		repository.On("MarkPublished", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		var published []int64
+		relay := outbox.NewRelay(repository, outbox.PublisherFunc(func(ctx context.Context, e domain.Event) error {
+			published = append(published, e.ID)
+			return nil
+		}))
+
+		count, err := relay.RelayOnce(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.Equal(t, []int64{1, 2, 3}, published)
+		assert.True(t, repository.Unlocked)
+		repository.AssertNumberOfCalls(t, "MarkPublished", 3)
+	})
+
+	t.Run("Should hold back the later events of an aggregate whose event failed", func(t *testing.T) {
+		repository := &mocks.OutboxRepositoryMock{}
+		publisher := &mocks.PublisherMock{}
+		failed := event(1, domain.EventProductCreated, 1)
+		other := event(2, domain.EventProductCreated, 2)
+		later := event(3, domain.EventProductUpdated, 1)
+		repository.On("Lock", mock.Anything).Return(true, nil)
+		repository.On("Pending", mock.Anything, mock.Anything, outbox.DefaultBatchSize).Return([]domain.Event{failed, other, later}, nil)
+		repository.On("MarkFailed", mock.Anything, int64(1), errPublish.Error(), mock.Anything).Return(nil)
+		repository.On("MarkPublished", mock.Anything, int64(2), mock.Anything).Return(nil)
+		publisher.On("Publish", mock.Anything, failed).Return(errPublish)
+		publisher.On("Publish", mock.Anything, other).Return(nil)
+
+		count, err := outbox.NewRelay(repository, publisher).RelayOnce(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		publisher.AssertNotCalled(t, "Publish", mock.Anything, later)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should publish again an event whose publication was not recorded", func(t *testing.T) {
+		repository := &mocks.OutboxRepositoryMock{}
+		publisher := &mocks.PublisherMock{}
+		e := event(1, domain.EventSellerUpdated, 4)
+		repository.On("Lock", mock.Anything).Return(true, nil)
+		repository.On("Pending", mock.Anything, mock.Anything, outbox.DefaultBatchSize).Return([]domain.Event{e}, nil)
+		repository.On("MarkPublished", mock.Anything, int64(1), mock.Anything).Return(errors.New("connection lost")).Once()
+		repository.On("MarkPublished", mock.Anything, int64(1), mock.Anything).Return(nil).Once()
+		publisher.On("Publish", mock.Anything, e).Return(nil)
+		relay := outbox.NewRelay(repository, publisher)
+
+		_, err := relay.RelayOnce(context.TODO())
+		assert.Error(t, err)
+		count, err := relay.RelayOnce(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		publisher.AssertNumberOfCalls(t, "Publish", 2)
+	})
+
+	t.Run("Should do nothing while another relay holds the lock", func(t *testing.T) {
+		repository := &mocks.OutboxRepositoryMock{}
+		repository.On("Lock", mock.Anything).Return(false, nil)
+
+		count, err := outbox.NewRelay(repository, outbox.Multi()).RelayOnce(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+		repository.AssertNotCalled(t, "Pending", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return the error reading the pending events", func(t *testing.T) {
+		repository := &mocks.OutboxRepositoryMock{}
+		repository.On("Lock", mock.Anything).Return(true, nil)
+		repository.On("Pending", mock.Anything, mock.Anything, outbox.DefaultBatchSize).Return([]domain.Event{}, errors.New("db down"))
+
+		_, err := outbox.NewRelay(repository, outbox.Multi()).RelayOnce(context.TODO())
+
+		assert.EqualError(t, err, "db down")
+		assert.True(t, repository.Unlocked)
+	})
+
+	t.Run("Should retry a failed event later and kill it once it runs out of attempts", func(t *testing.T) {
+		repository := &mocks.OutboxRepositoryMock{}
+		publisher := &mocks.PublisherMock{}
+		retried := event(1, domain.EventProductCreated, 1)
+		exhausted := event(2, domain.EventProductCreated, 2)
+		exhausted.Attempts = outbox.DefaultMaxAttempts - 1
+		before := time.Now()
+		repository.On("Lock", mock.Anything).Return(true, nil)
+		repository.On("Pending", mock.Anything, mock.Anything, outbox.DefaultBatchSize).Return([]domain.Event{retried, exhausted}, nil)
+		retryAt := mock.MatchedBy(func(at datetime.Time) bool { return !at.Before(before.Add(outbox.Backoff(1))) })
+		repository.On("MarkFailed", mock.Anything, int64(1), errPublish.Error(), retryAt).Return(nil)
+		repository.On("MarkDead", mock.Anything, int64(2), errPublish.Error(), mock.Anything).Return(nil)
+		publisher.On("Publish", mock.Anything, mock.Anything).Return(errPublish)
+
+		count, err := outbox.NewRelay(repository, publisher).RelayOnce(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should keep publishing the other aggregates behind a failing one with more events than a batch", func(t *testing.T) {
+		events := []domain.Event{}
+		for id := int64(1); id <= 5; id++ {
+			events = append(events, event(id, domain.EventProductUpdated, 1))
+		}
+		events = append(events, event(6, domain.EventSellerCreated, 1), event(7, domain.EventSellerCreated, 2))
+		repository := newMemoryRepository(events...)
+
+		var published []int64
+		relay := outbox.NewRelay(repository, outbox.PublisherFunc(func(ctx context.Context, e domain.Event) error {
+			if e.AggregateType == "product" {
+				return errPublish
+			}
+			published = append(published, e.ID)
+			return nil
+		}))
+		relay.BatchSize = 3
+
+		first, err := relay.RelayOnce(context.TODO())
+		assert.NoError(t, err)
+		second, err := relay.RelayOnce(context.TODO())
+		assert.NoError(t, err)
+
+		assert.Equal(t, 0, first)
+		assert.Equal(t, 2, second)
+		assert.Equal(t, []int64{6, 7}, published)
+		assert.Equal(t, 1, repository.events[0].Attempts)
+		for _, e := range repository.events[1:5] {
+			assert.Equal(t, 0, e.Attempts)
+		}
+	})
+}
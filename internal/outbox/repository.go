@@ -0,0 +1,124 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+)
+
+// lockName is the MySQL named lock held by the relay that is publishing.
+const lockName = "outbox_relay"
+
+const (
+	LockQuery    = "SELECT COALESCE(GET_LOCK(?, 0), 0)"
+	UnlockQuery  = "SELECT RELEASE_LOCK(?)"
+	PendingQuery = "SELECT e.id, e.aggregate_type, e.aggregate_id, e.event_type, e.payload, e.occurred_at, e.attempts FROM outbox_events e " +
+		"WHERE e.published_at IS NULL AND e.dead_at IS NULL AND (e.next_attempt_at IS NULL OR e.next_attempt_at <= ?) " +
+		"AND NOT EXISTS (SELECT 1 FROM outbox_events f WHERE f.aggregate_type = e.aggregate_type AND f.aggregate_id = e.aggregate_id " +
+		"AND f.id < e.id AND f.published_at IS NULL AND f.dead_at IS NULL AND f.attempts > 0) ORDER BY e.id LIMIT ?"
+	PublishedQuery = "UPDATE outbox_events SET published_at = ? WHERE id = ?"
+	FailedQuery    = "UPDATE outbox_events SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?"
+	DeadQuery      = "UPDATE outbox_events SET attempts = attempts + 1, last_error = ?, dead_at = ? WHERE id = ?"
+)
+
+// maxErrorLength is the size of the last_error column.
+const maxErrorLength = 255
+
+// Repository encapsulates the storage of the events waiting to be published.
+type Repository interface {
+	// Lock takes the lock that lets a single relay publish at a time. It
+	// reports false when another relay holds it. The lock is released by
+	// calling unlock, or when the process dies.
+	Lock(ctx context.Context) (unlock func(), ok bool, err error)
+	// Pending lists, oldest first, the unpublished events due at now. It
+	// leaves out the dead events, and the later events of an aggregate while
+	// an earlier one is being retried, so a failing event does not take up
+	// the batch of the other aggregates.
+	Pending(ctx context.Context, now datetime.Time, limit int) ([]domain.Event, error)
+	MarkPublished(ctx context.Context, id int64, at datetime.Time) error
+	MarkFailed(ctx context.Context, id int64, reason string, retryAt datetime.Time) error
+	MarkDead(ctx context.Context, id int64, reason string, at datetime.Time) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// Lock takes a MySQL named lock. Named locks belong to a connection, so the
+// connection is kept out of the pool until the lock is released.
+func (r *repository) Lock(ctx context.Context) (func(), bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	var locked int
+	if err := conn.QueryRowContext(ctx, LockQuery, lockName).Scan(&locked); err != nil {
+		_ = conn.Close()
+		return nil, false, err
+	}
+	if locked != 1 {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		var released sql.NullInt64
+		_ = conn.QueryRowContext(context.Background(), UnlockQuery, lockName).Scan(&released)
+		_ = conn.Close()
+	}
+	return unlock, true, nil
+}
+
+func (r *repository) Pending(ctx context.Context, now datetime.Time, limit int) ([]domain.Event, error) {
+	rows, err := r.db.QueryContext(ctx, PendingQuery, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []domain.Event{}
+	for rows.Next() {
+		e := domain.Event{}
+		var payload string
+		if err := rows.Scan(&e.ID, &e.AggregateType, &e.AggregateID, &e.Type, &payload, &e.OccurredAt, &e.Attempts); err != nil {
+			return nil, err
+		}
+		e.Data = []byte(payload)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (r *repository) MarkPublished(ctx context.Context, id int64, at datetime.Time) error {
+	_, err := r.db.ExecContext(ctx, PublishedQuery, at, id)
+	return err
+}
+
+// MarkFailed counts a failed attempt to publish the event, keeps the reason
+// and sets when to try again.
+func (r *repository) MarkFailed(ctx context.Context, id int64, reason string, retryAt datetime.Time) error {
+	_, err := r.db.ExecContext(ctx, FailedQuery, truncate(reason), retryAt, id)
+	return err
+}
+
+// MarkDead counts the last failed attempt to publish the event and stops
+// publishing it.
+func (r *repository) MarkDead(ctx context.Context, id int64, reason string, at datetime.Time) error {
+	_, err := r.db.ExecContext(ctx, DeadQuery, truncate(reason), at, id)
+	return err
+}
+
+func truncate(reason string) string {
+	if len(reason) > maxErrorLength {
+		return reason[:maxErrorLength]
+	}
+	return reason
+}
@@ -0,0 +1,33 @@
+package product
+
+import (
+	"errors"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DivisorEnv is the environment variable read by DivisorFromEnv.
+const DivisorEnv = "VOLUMETRIC_DIVISOR"
+
+// DefaultDivisor is the volumetric divisor used when DivisorEnv is unset, the
+// usual one for dimensions in centimetres and weights in kilograms.
+const DefaultDivisor = 5000
+
+var ErrInvalidDivisor = errors.New("invalid volumetric divisor")
+
+// DivisorFromEnv reads the volumetric divisor from VOLUMETRIC_DIVISOR, the
+// volume billed as one unit of weight. An unset variable yields
+// DefaultDivisor.
+func DivisorFromEnv() (float64, error) {
+	value := strings.TrimSpace(os.Getenv(DivisorEnv))
+	if value == "" {
+		return DefaultDivisor, nil
+	}
+	divisor, err := strconv.ParseFloat(value, 64)
+	if err != nil || !(divisor > 0) || math.IsInf(divisor, 1) {
+		return 0, ErrInvalidDivisor
+	}
+	return divisor, nil
+}
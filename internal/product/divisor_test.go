@@ -0,0 +1,38 @@
+package product_test
+
+import (
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDivisorFromEnv(t *testing.T) {
+	t.Run("Should use the default divisor when unset", func(t *testing.T) {
+		t.Setenv(product.DivisorEnv, "")
+
+		divisor, err := product.DivisorFromEnv()
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(product.DefaultDivisor), divisor)
+	})
+
+	t.Run("Should read the divisor", func(t *testing.T) {
+		t.Setenv(product.DivisorEnv, " 6000 ")
+
+		divisor, err := product.DivisorFromEnv()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 6000.0, divisor)
+	})
+
+	t.Run("Should reject a divisor that is not a positive number", func(t *testing.T) {
+		for _, value := range []string{"0", "-5000", "heavy", "NaN", "Inf"} {
+			t.Setenv(product.DivisorEnv, value)
+
+			_, err := product.DivisorFromEnv()
+
+			assert.ErrorIs(t, err, product.ErrInvalidDivisor, value)
+		}
+	})
+}
@@ -5,19 +5,45 @@ import (
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
 )
 
 const ProductExists = "SELECT id FROM products WHERE id=?"
 
+const (
+	// searchFilter matches the products of a search. Its first three
+	// parameters are the full-text query, twice, and the search query, which
+	// also matches a product code exactly; the rest are the filters, each
+	// given twice, ending with the freezable flag, null to match any product.
+	searchFilter = "(? = '' OR MATCH(description, product_code) AGAINST (? IN BOOLEAN MODE) OR product_code = ?) " +
+		"AND (? = 0 OR id_product_type = ?) AND (? = 0 OR id_seller = ?) AND (? = 0 OR netweight >= ?) AND (? = 0 OR netweight <= ?) " +
+		"AND (? IS NULL OR (recommended_freezing_temperature <= 0) = ?)"
+	// searchRelevance ranks the products matching the full-text query, its
+	// first parameter, and puts the product whose code is the query, its
+	// second, first.
+	searchRelevance = "MATCH(description, product_code) AGAINST (? IN BOOLEAN MODE) + IF(product_code = ?, 100, 0)"
+
+	SearchQuery = "SELECT id, description, expiration_rate, freezing_rate, height, lenght, netweight, product_code, recommended_freezing_temperature, width, id_product_type, id_seller, " +
+		searchRelevance + " AS score FROM products WHERE " + searchFilter + " ORDER BY score DESC, id LIMIT ?"
+	SearchCountQuery        = "SELECT COUNT(*) FROM products WHERE " + searchFilter
+	SearchProductTypesQuery = "SELECT id_product_type, COUNT(*) FROM products WHERE " + searchFilter + " GROUP BY id_product_type ORDER BY id_product_type"
+	SearchSellersQuery      = "SELECT id_seller, COUNT(*) FROM products WHERE " + searchFilter + " GROUP BY id_seller ORDER BY id_seller"
+)
+
 // Repository encapsulates the storage of a Product.
 type Repository interface {
 	GetAll(ctx context.Context) ([]domain.Product, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Product, error)
+	GetBySellers(ctx context.Context, sellerIDs []int) ([]domain.Product, error)
 	Get(ctx context.Context, id int) (domain.Product, error)
 	Exists(ctx context.Context, productCode string) bool
 	Save(ctx context.Context, p domain.Product) (int, error)
 	Update(ctx context.Context, p domain.Product) error
 	Delete(ctx context.Context, id int) error
 	ExistsById(productID int) bool
+	Search(ctx context.Context, s domain.ProductSearch, limit int) (domain.ProductSearchResult, error)
 }
 
 type repository struct {
@@ -48,6 +74,39 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Product, error) {
 	return products, nil
 }
 
+// GetByIDs returns the products with the given ids.
+func (r *repository) GetByIDs(ctx context.Context, ids []int) ([]domain.Product, error) {
+	return r.getIn(ctx, "id", ids)
+}
+
+// GetBySellers returns the products of the given sellers.
+func (r *repository) GetBySellers(ctx context.Context, sellerIDs []int) ([]domain.Product, error) {
+	return r.getIn(ctx, "seller_id", sellerIDs)
+}
+
+// getIn returns the products whose column holds one of values, in one query.
+func (r *repository) getIn(ctx context.Context, column string, values []int) ([]domain.Product, error) {
+	products := []domain.Product{}
+	if len(values) == 0 {
+		return products, nil
+	}
+	in, args := mysqlutil.In(values)
+	rows, err := r.db.QueryContext(ctx, "SELECT * FROM products WHERE "+column+" IN "+in+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := domain.Product{}
+		if err := rows.Scan(&p.ID, &p.Description, &p.ExpirationRate, &p.FreezingRate, &p.Height, &p.Length, &p.Netweight, &p.ProductCode, &p.RecomFreezTemp, &p.Width, &p.ProductTypeID, &p.SellerID); err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
+
 func (r *repository) Get(ctx context.Context, id int) (domain.Product, error) {
 	query := "SELECT * FROM products WHERE id=?;"
 	row := r.db.QueryRow(query, id)
@@ -69,72 +128,75 @@ func (r *repository) Exists(ctx context.Context, productCode string) bool {
 	return err == nil
 }
 
+// Save inserts the product and its product.created event in one
+// transaction.
 func (r *repository) Save(ctx context.Context, p domain.Product) (int, error) {
 	query := "INSERT INTO products(description,expiration_rate,freezing_rate,height,lenght,netweight,product_code,recommended_freezing_temperature,width,id_product_type,id_seller) VALUES (?,?,?,?,?,?,?,?,?,?,?)"
-	stmt, err := r.db.Prepare(query)
-	if err != nil {
-		return 0, err
-	}
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, p.Description, p.ExpirationRate, p.FreezingRate, p.Height, p.Length, p.Netweight, p.ProductCode, p.RecomFreezTemp, p.Width, p.ProductTypeID, p.SellerID)
+		if err != nil {
+			return err
+		}
 
-	res, err := stmt.Exec(p.Description, p.ExpirationRate, p.FreezingRate, p.Height, p.Length, p.Netweight, p.ProductCode, p.RecomFreezTemp, p.Width, p.ProductTypeID, p.SellerID)
-	if err != nil {
-		return 0, err
-	}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		p.ID = int(id)
 
-	id, err := res.LastInsertId()
+		return outbox.Write(ctx, tx, domain.EventProductCreated, p.ID, p)
+	})
 	if err != nil {
 		return 0, err
 	}
 
-	return int(id), nil
+	return p.ID, nil
 }
 
+// Update stores the product and its product.updated event in one
+// transaction.
 func (r *repository) Update(ctx context.Context, p domain.Product) error {
 	query := "UPDATE products SET description=?, expiration_rate=?, freezing_rate=?, height=?, lenght=?, netweight=?, product_code=?, recommended_freezing_temperature=?, width=?, id_product_type=?, id_seller=?  WHERE id=?"
-	stmt, err := r.db.Prepare(query)
-	if err != nil {
-		return err
-	}
-
-	res, err := stmt.Exec(p.Description, p.ExpirationRate, p.FreezingRate, p.Height, p.Length, p.Netweight, p.ProductCode, p.RecomFreezTemp, p.Width, p.ProductTypeID, p.SellerID, p.ID)
-	if err != nil {
-		return err
-	}
+	return sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, p.Description, p.ExpirationRate, p.FreezingRate, p.Height, p.Length, p.Netweight, p.ProductCode, p.RecomFreezTemp, p.Width, p.ProductTypeID, p.SellerID, p.ID)
+		if err != nil {
+			return err
+		}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
 
-	if rowsAffected == 0 {
-		return ErrNotFound
-	}
+		if rowsAffected == 0 {
+			return ErrNotFound
+		}
 
-	return nil
+		return outbox.Write(ctx, tx, domain.EventProductUpdated, p.ID, p)
+	})
 }
 
+// Delete removes the product and stores its product.deleted event in one
+// transaction.
 func (r *repository) Delete(ctx context.Context, id int) error {
 	query := "DELETE FROM products WHERE id=?"
-	stmt, err := r.db.Prepare(query)
-	if err != nil {
-		return err
-	}
-
-	res, err := stmt.Exec(id)
-	if err != nil {
-		return err
-	}
+	return sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return err
+		}
 
-	affect, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
+		affect, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
 
-	if affect < 1 {
-		return ErrNotFound
-	}
+		if affect < 1 {
+			return ErrNotFound
+		}
 
-	return nil
+		return outbox.Write(ctx, tx, domain.EventProductDeleted, id, domain.DeletedEvent{ID: id})
+	})
 }
 
 func (r *repository) ExistsById(productID int) bool {
@@ -143,3 +205,78 @@ func (r *repository) ExistsById(productID int) bool {
 	return err == nil
 
 }
+
+// Search returns up to limit products matching s, the most relevant first,
+// along with the number of products matching s and their facets. The
+// queries run in one transaction so that they all see the same products.
+func (r *repository) Search(ctx context.Context, s domain.ProductSearch, limit int) (domain.ProductSearchResult, error) {
+	result := domain.ProductSearchResult{Products: []domain.ProductHit{}}
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		match := booleanQuery(s.Query)
+		args := append([]interface{}{match, s.Query}, searchArgs(match, s)...)
+		rows, err := tx.QueryContext(ctx, SearchQuery, append(args, limit)...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			h := domain.ProductHit{}
+			if err := rows.Scan(&h.ID, &h.Description, &h.ExpirationRate, &h.FreezingRate, &h.Height, &h.Length, &h.Netweight, &h.ProductCode, &h.RecomFreezTemp, &h.Width, &h.ProductTypeID, &h.SellerID, &h.Score); err != nil {
+				return err
+			}
+			result.Products = append(result.Products, h)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if err := tx.QueryRowContext(ctx, SearchCountQuery, searchArgs(match, s)...).Scan(&result.Total); err != nil {
+			return err
+		}
+
+		anyType := s
+		anyType.ProductTypeID = 0
+		if result.Facets.ProductTypes, err = facets(ctx, tx, SearchProductTypesQuery, searchArgs(match, anyType)); err != nil {
+			return err
+		}
+		anySeller := s
+		anySeller.SellerID = 0
+		result.Facets.Sellers, err = facets(ctx, tx, SearchSellersQuery, searchArgs(match, anySeller))
+		return err
+	})
+	if err != nil {
+		return domain.ProductSearchResult{}, err
+	}
+	return result, nil
+}
+
+// searchArgs are the parameters of searchFilter for s and its full-text
+// query.
+func searchArgs(match string, s domain.ProductSearch) []interface{} {
+	return []interface{}{
+		match, match, s.Query,
+		s.ProductTypeID, s.ProductTypeID,
+		s.SellerID, s.SellerID,
+		s.MinWeight, s.MinWeight,
+		s.MaxWeight, s.MaxWeight,
+		s.Freezable, s.Freezable,
+	}
+}
+
+func facets(ctx context.Context, tx *sql.Tx, query string, args []interface{}) ([]domain.ProductFacet, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []domain.ProductFacet{}
+	for rows.Next() {
+		f := domain.ProductFacet{}
+		if err := rows.Scan(&f.ID, &f.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, f)
+	}
+	return counts, rows.Err()
+}
@@ -93,6 +93,44 @@ func TestProductSave(t *testing.T) {
 	})
 }
 
+func TestProductSearch(t *testing.T) {
+	t.Run("Should find a product by its code", func(t *testing.T) {
+		repository := product.NewRepository(db)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		searched := expectedProductResult
+		searched.ProductCode = "SRCH01"
+		productId, err := repository.Save(ctx, searched)
+		assert.NoError(t, err)
+
+		result, err := repository.Search(ctx, domain.ProductSearch{Query: "SRCH01", SellerID: searched.SellerID}, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Total)
+		assert.Equal(t, productId, result.Products[0].ID)
+		assert.Equal(t, []domain.ProductFacet{{ID: searched.ProductTypeID, Count: 1}}, result.Facets.ProductTypes)
+		assert.Equal(t, []domain.ProductFacet{{ID: searched.SellerID, Count: 1}}, result.Facets.Sellers)
+	})
+	t.Run("Should leave out the products that do not pass the filters", func(t *testing.T) {
+		repository := product.NewRepository(db)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		searched := expectedProductResult
+		searched.ProductCode = "SRCH02"
+		_, err := repository.Save(ctx, searched)
+		assert.NoError(t, err)
+
+		freezable := true
+		result, err := repository.Search(ctx, domain.ProductSearch{Query: "SRCH02", Freezable: &freezable}, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Total)
+		assert.Empty(t, result.Products)
+	})
+}
+
 func TestProductDelete(t *testing.T) {
 	t.Run("It should delete a product", func(t *testing.T) {
 		repository := product.NewRepository(db)
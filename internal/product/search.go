@@ -0,0 +1,135 @@
+package product
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+)
+
+const (
+	// fragmentLength is the number of characters of a field shown in its
+	// highlight, when the field is longer.
+	fragmentLength = 100
+	// fragmentContext is the number of characters shown before the first
+	// match of a field cut to fragmentLength.
+	fragmentContext = 20
+)
+
+// terms returns the distinct words of a search query, lowercased. Anything
+// that is not a letter or digit separates words, as in the full-text index.
+func terms(query string) []string {
+	seen := map[string]bool{}
+	words := []string{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(query), isSeparator) {
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// booleanQuery is the full-text query matching the products with any word
+// starting with one of the words of query. Since only letters and digits are
+// kept, the query carries no operators of its own.
+func booleanQuery(query string) string {
+	words := terms(query)
+	for i, word := range words {
+		words[i] = word + "*"
+	}
+	return strings.Join(words, " ")
+}
+
+// highlights returns the highlighted fields of p, nil if words match none.
+func highlights(p domain.Product, words []string) map[string]string {
+	fields := map[string]string{}
+	if fragment, ok := highlight(p.Description, words); ok {
+		fields["description"] = fragment
+	}
+	if fragment, ok := highlight(p.ProductCode, words); ok {
+		fields["product_code"] = fragment
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// highlight returns text escaped as HTML with the words starting with one of
+// words wrapped in <em>. Text longer than fragmentLength is cut around its
+// first match, and the cuts are marked with an ellipsis. It returns false if
+// no word matches.
+func highlight(text string, words []string) (string, bool) {
+	runes := []rune(text)
+	type span struct{ start, end int }
+	var matches []span
+	for i := 0; i < len(runes); {
+		if isSeparator(runes[i]) {
+			i++
+			continue
+		}
+		end := i
+		for end < len(runes) && !isSeparator(runes[end]) {
+			end++
+		}
+		word := strings.ToLower(string(runes[i:end]))
+		for _, w := range words {
+			if strings.HasPrefix(word, w) {
+				matches = append(matches, span{i, end})
+				break
+			}
+		}
+		i = end
+	}
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	start, end := 0, len(runes)
+	if len(runes) > fragmentLength {
+		start = matches[0].start - fragmentContext
+		if start < 0 {
+			start = 0
+		}
+		for start > 0 && start < matches[0].start && !isSeparator(runes[start-1]) {
+			start++
+		}
+		end = start + fragmentLength
+		if end > len(runes) {
+			end = len(runes)
+		}
+		for end < len(runes) && end > matches[0].end && !isSeparator(runes[end]) {
+			end--
+		}
+		if end < matches[0].end {
+			end = matches[0].end
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	position := start
+	for _, m := range matches {
+		if m.start < start || m.end > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[position:m.start])))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(string(runes[m.start:m.end])))
+		b.WriteString("</em>")
+		position = m.end
+	}
+	b.WriteString(html.EscapeString(string(runes[position:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -3,8 +3,10 @@ package product
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mergepatch"
 )
 
 // Errors
@@ -17,15 +19,24 @@ var (
 	ErrInvalidJson  = errors.New("invalid json")
 
 	ErrProductAlreadyExists = errors.New("product already exists")
+
+	ErrInvalidQuery       = errors.New("query has no words to search")
+	ErrInvalidWeightRange = errors.New("min_weight is greater than max_weight")
 )
 
+// SearchLimit is the number of products returned by a search.
+const SearchLimit = 50
+
 type Service interface {
 	Save(ctx context.Context, p domain.Product) (int, error)
 	GetAll(ctx context.Context) ([]domain.Product, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Product, error)
+	GetBySellers(ctx context.Context, sellerIDs []int) ([]domain.Product, error)
 	Delete(ctx context.Context, id int) error
 	Get(ctx context.Context, id int) (domain.Product, error)
-	Update(ctx context.Context, p domain.Product) error
+	Update(ctx context.Context, id int, p domain.Patch) (domain.Product, error)
 	ExistsById(productID int) error
+	Search(ctx context.Context, search domain.ProductSearch) (domain.ProductSearchResult, error)
 }
 
 type productService struct {
@@ -55,6 +66,16 @@ func (s *productService) GetAll(ctx context.Context) ([]domain.Product, error) {
 
 }
 
+// GetByIDs returns the products with the given ids.
+func (s *productService) GetByIDs(ctx context.Context, ids []int) ([]domain.Product, error) {
+	return s.repository.GetByIDs(ctx, ids)
+}
+
+// GetBySellers returns the products of the given sellers.
+func (s *productService) GetBySellers(ctx context.Context, sellerIDs []int) ([]domain.Product, error) {
+	return s.repository.GetBySellers(ctx, sellerIDs)
+}
+
 func (s *productService) Delete(ctx context.Context, id int) error {
 	err := s.repository.Delete(ctx, id)
 	return err
@@ -65,9 +86,30 @@ func (s *productService) Get(ctx context.Context, id int) (domain.Product, error
 	return product, err
 }
 
-func (s *productService) Update(ctx context.Context, p domain.Product) error {
-	err := s.repository.Update(ctx, p)
-	return err
+// Update applies a merge patch to the product. Its description, code, type
+// and seller cannot be cleared, and the code must stay unique.
+func (s *productService) Update(ctx context.Context, id int, p domain.Patch) (domain.Product, error) {
+	product, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+
+	productCode := product.ProductCode
+	if err := mergepatch.Apply(&product, p.Document); err != nil {
+		return domain.Product{}, ErrInvalidBody
+	}
+	product.ID = id
+	if product.Description == "" || product.ProductCode == "" || product.ProductTypeID == 0 || product.SellerID == 0 {
+		return domain.Product{}, ErrInvalidField
+	}
+	if product.ProductCode != productCode && s.repository.Exists(ctx, product.ProductCode) {
+		return domain.Product{}, ErrProductAlreadyExists
+	}
+
+	if err := s.repository.Update(ctx, product); err != nil {
+		return domain.Product{}, err
+	}
+	return product, nil
 }
 
 func (s *productService) ExistsById(productID int) error {
@@ -78,3 +120,25 @@ func (s *productService) ExistsById(productID int) error {
 	}
 	return nil
 }
+
+// Search finds the products matching search, the most relevant first, and
+// highlights the words of its query in their description and code.
+func (s *productService) Search(ctx context.Context, search domain.ProductSearch) (domain.ProductSearchResult, error) {
+	search.Query = strings.TrimSpace(search.Query)
+	words := terms(search.Query)
+	if search.Query != "" && len(words) == 0 {
+		return domain.ProductSearchResult{}, ErrInvalidQuery
+	}
+	if search.MaxWeight != 0 && search.MinWeight > search.MaxWeight {
+		return domain.ProductSearchResult{}, ErrInvalidWeightRange
+	}
+
+	result, err := s.repository.Search(ctx, search, SearchLimit)
+	if err != nil {
+		return domain.ProductSearchResult{}, err
+	}
+	for i := range result.Products {
+		result.Products[i].Highlights = highlights(result.Products[i].Product, words)
+	}
+	return result, nil
+}
@@ -3,6 +3,7 @@ package product_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
@@ -149,29 +150,91 @@ func TestCreateProducts(t *testing.T) {
 func TestUpdateProducts(t *testing.T) {
 
 	t.Run("Should update the product when it exists.", func(t *testing.T) {
+		updatedProduct := expectedProduct
+		updatedProduct.Description = "yogurt"
 
 		service, repository := CreateProductService(t)
 
-		repository.On("Exists", mock.Anything, expectedProduct.ProductCode).Return(false)
-		repository.On("Update", mock.Anything, expectedProduct).Return(nil)
+		repository.On("Get", expectedProduct.ID).Return(expectedProduct, nil)
+		repository.On("Update", mock.Anything, updatedProduct).Return(nil)
 
-		err := service.Update(context.TODO(), expectedProduct)
+		result, err := service.Update(context.TODO(), expectedProduct.ID, domain.Patch{Document: []byte(`{"description":"yogurt"}`)})
 
 		assert.NoError(t, err)
+		assert.Equal(t, updatedProduct, result)
+	})
+
+	t.Run("Should set a field to zero when given explicitly", func(t *testing.T) {
+		updatedProduct := expectedProduct
+		updatedProduct.RecomFreezTemp = 0
+
+		service, repository := CreateProductService(t)
+
+		repository.On("Get", expectedProduct.ID).Return(expectedProduct, nil)
+		repository.On("Update", mock.Anything, updatedProduct).Return(nil)
+
+		result, err := service.Update(context.TODO(), expectedProduct.ID, domain.Patch{Document: []byte(`{"recommended_freezing_temperature":0}`)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, updatedProduct, result)
 	})
 
 	t.Run("Should return an error when the product does not exists", func(t *testing.T) {
 		service, repository := CreateProductService(t)
 
 		expectedError := errors.New("product not found")
-		repository.On("Update", mock.Anything, mock.Anything).Return(product.ErrNotFound)
+		repository.On("Get", expectedProduct.ID).Return(domain.Product{}, product.ErrNotFound)
 
-		err := service.Update(context.TODO(), expectedProduct)
+		_, err := service.Update(context.TODO(), expectedProduct.ID, domain.Patch{Document: []byte(`{}`)})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
 	})
 
+	t.Run("Should return an error when the product code is taken", func(t *testing.T) {
+		service, repository := CreateProductService(t)
+
+		repository.On("Get", expectedProduct.ID).Return(expectedProduct, nil)
+		repository.On("Exists", mock.Anything, "PROD03").Return(true)
+
+		_, err := service.Update(context.TODO(), expectedProduct.ID, domain.Patch{Document: []byte(`{"product_code":"PROD03"}`)})
+
+		assert.Equal(t, product.ErrProductAlreadyExists, err)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return an error when a required field is emptied", func(t *testing.T) {
+		service, repository := CreateProductService(t)
+
+		repository.On("Get", expectedProduct.ID).Return(expectedProduct, nil)
+
+		_, err := service.Update(context.TODO(), expectedProduct.ID, domain.Patch{Document: []byte(`{"description":""}`)})
+
+		assert.Equal(t, product.ErrInvalidField, err)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return an error when a required field is cleared", func(t *testing.T) {
+		service, repository := CreateProductService(t)
+
+		repository.On("Get", expectedProduct.ID).Return(expectedProduct, nil)
+
+		_, err := service.Update(context.TODO(), expectedProduct.ID, domain.Patch{Document: []byte(`{"seller_id":null}`)})
+
+		assert.Equal(t, product.ErrInvalidBody, err)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return an error when a field has the wrong type", func(t *testing.T) {
+		service, repository := CreateProductService(t)
+
+		repository.On("Get", expectedProduct.ID).Return(expectedProduct, nil)
+
+		_, err := service.Update(context.TODO(), expectedProduct.ID, domain.Patch{Document: []byte(`{"width":"wide"}`)})
+
+		assert.Equal(t, product.ErrInvalidBody, err)
+	})
+
 }
 
 func TestExistsById(t *testing.T) {
@@ -192,6 +255,93 @@ func TestExistsById(t *testing.T) {
 	})
 }
 
+func TestSearchProducts(t *testing.T) {
+	t.Run("Should highlight the words of the query in the products found", func(t *testing.T) {
+		service, repository := CreateProductService(t)
+
+		found := domain.ProductSearchResult{
+			Total: 2,
+			Products: []domain.ProductHit{
+				{MeasuredProduct: domain.MeasuredProduct{Product: domain.Product{ID: 1, Description: "Frozen Yogurt & berries", ProductCode: "YOG01"}}, Score: 2.5},
+				{MeasuredProduct: domain.MeasuredProduct{Product: domain.Product{ID: 2, Description: "Milk", ProductCode: "FROZ-7"}}, Score: 1},
+			},
+			Facets: domain.ProductFacets{
+				ProductTypes: []domain.ProductFacet{{ID: 1, Count: 2}},
+				Sellers:      []domain.ProductFacet{{ID: 1, Count: 1}, {ID: 2, Count: 1}},
+			},
+		}
+		repository.On("Search", mock.Anything, domain.ProductSearch{Query: "froz yog", SellerID: 1}, product.SearchLimit).Return(found, nil)
+
+		result, err := service.Search(context.TODO(), domain.ProductSearch{Query: "  froz yog ", SellerID: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Total)
+		assert.Equal(t, found.Facets, result.Facets)
+		assert.Equal(t, map[string]string{
+			"description":  "<em>Frozen</em> <em>Yogurt</em> &amp; berries",
+			"product_code": "<em>YOG01</em>",
+		}, result.Products[0].Highlights)
+		assert.Equal(t, map[string]string{"product_code": "<em>FROZ</em>-7"}, result.Products[1].Highlights)
+	})
+
+	t.Run("Should cut long descriptions around the first match", func(t *testing.T) {
+		service, repository := CreateProductService(t)
+
+		description := strings.Repeat("fresh whole milk from the farm, ", 5) + "frozen at the source. " + strings.Repeat("keep cold, ", 10)
+		found := domain.ProductSearchResult{Total: 1, Products: []domain.ProductHit{{MeasuredProduct: domain.MeasuredProduct{Product: domain.Product{ID: 1, Description: description}}}}}
+		repository.On("Search", mock.Anything, mock.Anything, product.SearchLimit).Return(found, nil)
+
+		result, err := service.Search(context.TODO(), domain.ProductSearch{Query: "frozen"})
+
+		assert.NoError(t, err)
+		fragment := result.Products[0].Highlights["description"]
+		assert.True(t, strings.HasPrefix(fragment, "…"), fragment)
+		assert.True(t, strings.HasSuffix(fragment, "…"), fragment)
+		assert.Contains(t, fragment, "<em>frozen</em> at the source.")
+	})
+
+	t.Run("Should list every product without a query", func(t *testing.T) {
+		service, repository := CreateProductService(t)
+
+		found := domain.ProductSearchResult{Total: 1, Products: []domain.ProductHit{{MeasuredProduct: domain.MeasuredProduct{Product: expectedProduct}}}}
+		repository.On("Search", mock.Anything, domain.ProductSearch{}, product.SearchLimit).Return(found, nil)
+
+		result, err := service.Search(context.TODO(), domain.ProductSearch{})
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.Products[0].Highlights)
+	})
+
+	t.Run("Should reject a query without words", func(t *testing.T) {
+		service, repository := CreateProductService(t)
+
+		_, err := service.Search(context.TODO(), domain.ProductSearch{Query: "*+-"})
+
+		assert.ErrorIs(t, err, product.ErrInvalidQuery)
+		repository.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should reject a minimum weight above the maximum", func(t *testing.T) {
+		service, repository := CreateProductService(t)
+
+		_, err := service.Search(context.TODO(), domain.ProductSearch{MinWeight: 5, MaxWeight: 2})
+
+		assert.ErrorIs(t, err, product.ErrInvalidWeightRange)
+		repository.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return the errors of the repository", func(t *testing.T) {
+		service, repository := CreateProductService(t)
+
+		expectedError := errors.New("some error")
+		repository.On("Search", mock.Anything, mock.Anything, product.SearchLimit).Return(domain.ProductSearchResult{}, expectedError)
+
+		_, err := service.Search(context.TODO(), domain.ProductSearch{Query: "milk"})
+
+		assert.Equal(t, expectedError, err)
+	})
+}
+
 func CreateProductService(t *testing.T) (product.Service, *mocks.ProductRepositoryMock) {
 	mockRepository := new(mocks.ProductRepositoryMock)
 	mockService := product.NewService(mockRepository)
@@ -1,20 +1,48 @@
 package productbatch
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
 )
 
 const (
-	SaveQuery = "INSERT INTO product_batches ( batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id) VALUES (?,?,?,?,?,?,?,?,?,?)"
+	SaveQuery           = "INSERT INTO product_batches ( batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id) VALUES (?,?,?,?,?,?,?,?,?,?)"
+	GetAllQuery         = "SELECT id, batch_number, current_quantity, CAST(current_temperature AS SIGNED), due_date, initial_quantity, manufacturing_date, manufacturing_hour, CAST(minimum_temperature AS SIGNED), product_id, section_id FROM product_batches WHERE (? = 0 OR section_id = ?) AND (? = 0 OR product_id = ?) ORDER BY id"
+	GetQuery            = "SELECT id, batch_number, current_quantity, CAST(current_temperature AS SIGNED), due_date, initial_quantity, manufacturing_date, manufacturing_hour, CAST(minimum_temperature AS SIGNED), product_id, section_id FROM product_batches WHERE id = ?"
+	GetByWarehouseQuery = "SELECT pb.id, pb.batch_number, pb.product_id, p.description, pb.section_id, pb.initial_quantity, pb.current_quantity, pb.manufacturing_date, " +
+		"CASE WHEN t.id IS NULL THEN COALESCE(MIN(io.order_date), pb.manufacturing_date) ELSE t.received_at END " +
+		"FROM product_batches pb JOIN sections s ON pb.section_id = s.id JOIN products p ON pb.product_id = p.id LEFT JOIN inbound_orders io ON io.product_batch_id = pb.id " +
+		"LEFT JOIN transfers t ON t.moved_batch_id = pb.id AND t.product_batch_id <> pb.id " +
+		"WHERE s.warehouse_id = ? GROUP BY pb.id, pb.batch_number, pb.product_id, p.description, pb.section_id, pb.initial_quantity, pb.current_quantity, pb.manufacturing_date, t.id, t.received_at ORDER BY pb.id"
+	ConsumedQuery = "SELECT pr.product_id, COALESCE(SUM(od.quantity), 0) FROM order_details od JOIN purchase_orders po ON od.purchase_order_id = po.id " +
+		"JOIN product_records pr ON od.product_record_id = pr.id WHERE po.warehouse_id = ? AND po.order_date <= ? GROUP BY pr.product_id"
+	SplitOffQuery = "SELECT t.product_batch_id, SUM(t.quantity) FROM transfers t JOIN sections s ON t.source_section_id = s.id " +
+		"WHERE s.warehouse_id = ? AND t.moved_batch_id <> t.product_batch_id AND t.received_at <= ? GROUP BY t.product_batch_id"
 )
 
 type Querys struct {
-	SaveQuery string
+	SaveQuery           string
+	GetAllQuery         string
+	GetQuery            string
+	GetByWarehouseQuery string
+	ConsumedQuery       string
+	SplitOffQuery       string
 }
 type Repository interface {
-	Save(produsctBatch domain.ProductBatch) (int, error)
+	Save(ctx context.Context, produsctBatch domain.ProductBatch) (int, error)
+	Get(ctx context.Context, id int) (domain.ProductBatch, error)
+	ForEach(ctx context.Context, filter domain.ProductBatchFilter, fn func(domain.ProductBatch) error) error
+	GetBySections(ctx context.Context, sectionIDs []int) ([]domain.ProductBatch, error)
+	GetByProducts(ctx context.Context, productIDs []int) ([]domain.ProductBatch, error)
+	GetByWarehouse(ctx context.Context, warehouseID int) ([]domain.InventoryBatch, error)
+	Consumed(ctx context.Context, warehouseID int, until datetime.Time) (map[int]int, error)
+	SplitOff(ctx context.Context, warehouseID int, until datetime.Time) (map[int]int, error)
 }
 
 type repository struct {
@@ -26,6 +54,21 @@ func buildQuerys(Querys Querys) Querys {
 	if Querys.SaveQuery == "" {
 		Querys.SaveQuery = SaveQuery
 	}
+	if Querys.GetAllQuery == "" {
+		Querys.GetAllQuery = GetAllQuery
+	}
+	if Querys.GetQuery == "" {
+		Querys.GetQuery = GetQuery
+	}
+	if Querys.GetByWarehouseQuery == "" {
+		Querys.GetByWarehouseQuery = GetByWarehouseQuery
+	}
+	if Querys.ConsumedQuery == "" {
+		Querys.ConsumedQuery = ConsumedQuery
+	}
+	if Querys.SplitOffQuery == "" {
+		Querys.SplitOffQuery = SplitOffQuery
+	}
 	return Querys
 }
 
@@ -37,19 +80,154 @@ func NewRepository(db *sql.DB, Querys Querys) Repository {
 	}
 }
 
-func (r *repository) Save(produsctBatch domain.ProductBatch) (int, error) {
-	stmt, err := r.db.Prepare(r.Querys.SaveQuery)
+// Save inserts the batch and its product_batch.created event in one
+// transaction.
+func (r *repository) Save(ctx context.Context, produsctBatch domain.ProductBatch) (int, error) {
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, r.Querys.SaveQuery, produsctBatch.BatchNumber, produsctBatch.CurrentQuantity, produsctBatch.CurrentTemperature, produsctBatch.DueDate, produsctBatch.InitialQuantity, produsctBatch.ManufacturingDate, produsctBatch.ManufacturingHour, produsctBatch.MinimumTemperature, produsctBatch.ProductID, produsctBatch.SectionID)
+		if err != nil {
+			return err
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		produsctBatch.ID = int(id)
+
+		return outbox.Write(ctx, tx, domain.EventProductBatchCreated, produsctBatch.ID, produsctBatch)
+	})
 	if err != nil {
 		return 0, err
 	}
-	res, err := stmt.Exec(produsctBatch.BatchNumber, produsctBatch.CurrentQuantity, produsctBatch.CurrentTemperature, produsctBatch.DueDate, produsctBatch.InitialQuantity, produsctBatch.ManufacturingDate, produsctBatch.ManufacturingHour, produsctBatch.MinimumTemperature, produsctBatch.ProductID, produsctBatch.SectionID)
+	return produsctBatch.ID, nil
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.ProductBatch, error) {
+	p := domain.ProductBatch{}
+	err := r.db.QueryRowContext(ctx, r.Querys.GetQuery, id).Scan(&p.ID, &p.BatchNumber, &p.CurrentQuantity, &p.CurrentTemperature, &p.DueDate, &p.InitialQuantity, &p.ManufacturingDate, &p.ManufacturingHour, &p.MinimumTemperature, &p.ProductID, &p.SectionID)
 	if err != nil {
-		return 0, err
+		if err.Error() == "sql: no rows in result set" {
+			return domain.ProductBatch{}, ErrNotFound
+		}
+		return domain.ProductBatch{}, err
 	}
+	return p, nil
+}
 
-	id, err := res.LastInsertId()
+// ForEach calls fn for every product batch matching filter while reading them
+// from the database, so the whole table is never held in memory.
+func (r *repository) ForEach(ctx context.Context, filter domain.ProductBatchFilter, fn func(domain.ProductBatch) error) error {
+	rows, err := r.db.QueryContext(ctx, r.Querys.GetAllQuery, filter.SectionID, filter.SectionID, filter.ProductID, filter.ProductID)
 	if err != nil {
-		return 0, err
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := domain.ProductBatch{}
+		err := rows.Scan(&p.ID, &p.BatchNumber, &p.CurrentQuantity, &p.CurrentTemperature, &p.DueDate, &p.InitialQuantity, &p.ManufacturingDate, &p.ManufacturingHour, &p.MinimumTemperature, &p.ProductID, &p.SectionID)
+		if err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetBySections returns the batches stored in the given sections.
+func (r *repository) GetBySections(ctx context.Context, sectionIDs []int) ([]domain.ProductBatch, error) {
+	return r.getIn(ctx, "section_id", sectionIDs)
+}
+
+// GetByProducts returns the batches of the given products.
+func (r *repository) GetByProducts(ctx context.Context, productIDs []int) ([]domain.ProductBatch, error) {
+	return r.getIn(ctx, "product_id", productIDs)
+}
+
+// getIn returns the batches whose column holds one of values, in one query.
+func (r *repository) getIn(ctx context.Context, column string, values []int) ([]domain.ProductBatch, error) {
+	productBatches := []domain.ProductBatch{}
+	if len(values) == 0 {
+		return productBatches, nil
+	}
+	in, args := mysqlutil.In(values)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, batch_number, current_quantity, CAST(current_temperature AS SIGNED), due_date, initial_quantity, manufacturing_date, manufacturing_hour, CAST(minimum_temperature AS SIGNED), product_id, section_id FROM product_batches WHERE "+column+" IN "+in+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := domain.ProductBatch{}
+		if err := rows.Scan(&p.ID, &p.BatchNumber, &p.CurrentQuantity, &p.CurrentTemperature, &p.DueDate, &p.InitialQuantity, &p.ManufacturingDate, &p.ManufacturingHour, &p.MinimumTemperature, &p.ProductID, &p.SectionID); err != nil {
+			return nil, err
+		}
+		productBatches = append(productBatches, p)
+	}
+	return productBatches, rows.Err()
+}
+
+// GetByWarehouse returns the batches stored in the sections of a warehouse,
+// with their current quantity. A batch split off by a transfer is dated by
+// the reception of that transfer.
+func (r *repository) GetByWarehouse(ctx context.Context, warehouseID int) ([]domain.InventoryBatch, error) {
+	rows, err := r.db.QueryContext(ctx, r.Querys.GetByWarehouseQuery, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batches := []domain.InventoryBatch{}
+	for rows.Next() {
+		b := domain.InventoryBatch{}
+		err := rows.Scan(&b.ID, &b.BatchNumber, &b.ProductID, &b.ProductDescription, &b.SectionID, &b.InitialQuantity, &b.Quantity, &b.ManufacturingDate, &b.ReceivedAt)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, b)
+	}
+	return batches, rows.Err()
+}
+
+// Consumed returns, per product, the units shipped from a warehouse by
+// purchase orders placed up to until.
+func (r *repository) Consumed(ctx context.Context, warehouseID int, until datetime.Time) (map[int]int, error) {
+	rows, err := r.db.QueryContext(ctx, r.Querys.ConsumedQuery, warehouseID, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	consumed := map[int]int{}
+	for rows.Next() {
+		var productID, units int
+		if err := rows.Scan(&productID, &units); err != nil {
+			return nil, err
+		}
+		consumed[productID] = units
+	}
+	return consumed, rows.Err()
+}
+
+// SplitOff returns, per batch of a warehouse, the units split off it by
+// transfers received up to until.
+func (r *repository) SplitOff(ctx context.Context, warehouseID int, until datetime.Time) (map[int]int, error) {
+	rows, err := r.db.QueryContext(ctx, r.Querys.SplitOffQuery, warehouseID, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	splitOff := map[int]int{}
+	for rows.Next() {
+		var batchID, units int
+		if err := rows.Scan(&batchID, &units); err != nil {
+			return nil, err
+		}
+		splitOff[batchID] = units
 	}
-	return int(id), nil
+	return splitOff, rows.Err()
 }
@@ -1,12 +1,14 @@
 package productbatch_test
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 
 	"github.com/DATA-DOG/go-txdb"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	productbatch "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
@@ -26,20 +28,20 @@ func TestCreateProductBatchRepository(t *testing.T) {
 		BatchNumber:        1,
 		CurrentQuantity:    1,
 		InitialQuantity:    1,
-		ManufacturingDate:  "2021-01-01",
+		ManufacturingDate:  datetime.MustParse("2021-01-01"),
 		CurrentTemperature: 1,
 		MinimumTemperature: 1,
-		DueDate:            "2021-01-01",
+		DueDate:            datetime.MustParse("2021-01-01"),
 		ManufacturingHour:  1,
 	}
 	t.Run("Should create a new product batch", func(t *testing.T) {
 		repository := productbatch.NewRepository(db, productbatch.Querys{})
-		_, err := repository.Save(productBatchExpected)
+		_, err := repository.Save(context.TODO(), productBatchExpected)
 		assert.NoError(t, err)
 	})
 	t.Run("Should fail when query is invalid", func(t *testing.T) {
 		repository := productbatch.NewRepository(db, repoQuerysIncorrect)
-		_, err := repository.Save(productBatchExpected)
+		_, err := repository.Save(context.TODO(), productBatchExpected)
 		assert.Error(t, err)
 	})
 }
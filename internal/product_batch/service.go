@@ -2,12 +2,21 @@ package productbatch
 
 import (
 	"context"
+	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 )
 
+var (
+	ErrNotFound = errors.New("product batch not found")
+)
+
 type Service interface {
 	Save(ctx context.Context, p domain.ProductBatch) (int, error)
+	GetAll(ctx context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error)
+	GetBySections(ctx context.Context, sectionIDs []int) ([]domain.ProductBatch, error)
+	GetByProducts(ctx context.Context, productIDs []int) ([]domain.ProductBatch, error)
+	ForEach(ctx context.Context, filter domain.ProductBatchFilter, fn func(domain.ProductBatch) error) error
 }
 
 type serviceProductBatch struct {
@@ -20,6 +29,32 @@ func NewService(r Repository) Service {
 	}
 }
 func (s *serviceProductBatch) Save(ctx context.Context, p domain.ProductBatch) (int, error) {
-	productBatchID, err := s.repository.Save(p)
+	productBatchID, err := s.repository.Save(ctx, p)
 	return productBatchID, err
 }
+
+func (s *serviceProductBatch) GetAll(ctx context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error) {
+	productBatches := []domain.ProductBatch{}
+	err := s.repository.ForEach(ctx, filter, func(p domain.ProductBatch) error {
+		productBatches = append(productBatches, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return productBatches, nil
+}
+
+// GetBySections returns the batches stored in the given sections.
+func (s *serviceProductBatch) GetBySections(ctx context.Context, sectionIDs []int) ([]domain.ProductBatch, error) {
+	return s.repository.GetBySections(ctx, sectionIDs)
+}
+
+// GetByProducts returns the batches of the given products.
+func (s *serviceProductBatch) GetByProducts(ctx context.Context, productIDs []int) ([]domain.ProductBatch, error) {
+	return s.repository.GetByProducts(ctx, productIDs)
+}
+
+func (s *serviceProductBatch) ForEach(ctx context.Context, filter domain.ProductBatchFilter, fn func(domain.ProductBatch) error) error {
+	return s.repository.ForEach(ctx, filter, fn)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	productbatch "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product_batch"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -24,13 +25,13 @@ func TestCreateProductBatch(t *testing.T) {
 			CurrentQuantity:    1,
 			CurrentTemperature: 1,
 			MinimumTemperature: 1,
-			DueDate:            "2021-01-01",
+			DueDate:            datetime.MustParse("2021-01-01"),
 			InitialQuantity:    1,
-			ManufacturingDate:  "2021-01-01",
+			ManufacturingDate:  datetime.MustParse("2021-01-01"),
 			ManufacturingHour:  1,
 		}
 		mockRepository, service := InitProductBatchService(t)
-		mockRepository.On("Save", mock.Anything).Return(1, nil)
+		mockRepository.On("Save", mock.Anything, mock.Anything).Return(1, nil)
 
 		productBatchId, err := service.Save(context.TODO(), expectedProductBatch)
 		assert.Equal(t, 1, productBatchId)
@@ -47,19 +48,39 @@ func TestCreateProductBatch(t *testing.T) {
 			CurrentQuantity:    1,
 			CurrentTemperature: 1,
 			MinimumTemperature: 1,
-			DueDate:            "2021-01-01",
+			DueDate:            datetime.MustParse("2021-01-01"),
 			InitialQuantity:    1,
-			ManufacturingDate:  "2021-01-01",
+			ManufacturingDate:  datetime.MustParse("2021-01-01"),
 			ManufacturingHour:  1,
 		}
 		mockRepository, service := InitProductBatchService(t)
-		mockRepository.On("Save", mock.Anything).Return(0, errors.New("error"))
+		mockRepository.On("Save", mock.Anything, mock.Anything).Return(0, errors.New("error"))
 
 		productBatchId, err := service.Save(context.TODO(), expectedProductBatch)
 		assert.Equal(t, 0, productBatchId)
 		assert.Error(t, err)
 	})
 }
+func TestGetAllProductBatch(t *testing.T) {
+	t.Run("Should collect the product batches matching the filter", func(t *testing.T) {
+		filter := domain.ProductBatchFilter{SectionID: 1}
+		expected := []domain.ProductBatch{{ID: 1, SectionID: 1}, {ID: 2, SectionID: 1}}
+		mockRepository, service := InitProductBatchService(t)
+		mockRepository.On("ForEach", mock.Anything, filter).Return(expected, nil)
+
+		productBatches, err := service.GetAll(context.TODO(), filter)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, productBatches)
+	})
+	t.Run("Should return the repository error", func(t *testing.T) {
+		mockRepository, service := InitProductBatchService(t)
+		mockRepository.On("ForEach", mock.Anything, mock.Anything).Return([]domain.ProductBatch{}, errors.New("error"))
+
+		_, err := service.GetAll(context.TODO(), domain.ProductBatchFilter{})
+		assert.Error(t, err)
+	})
+}
+
 func InitProductBatchService(t *testing.T) (*mocks.ProductBatchRepositoryMock, productbatch.Service) {
 	mockRepository := new(mocks.ProductBatchRepositoryMock)
 	mockService := productbatch.NewService(mockRepository)
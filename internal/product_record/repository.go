@@ -5,13 +5,16 @@ import (
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 )
 
 const (
 	ProductExistsQuery        = "SELECT id FROM products WHERE id=?"
-	SaveQuery                 = "INSERT INTO product_records (last_update_date, purchase_price, sale_price, product_id) VALUES (?,?,?,?)"
+	SaveQuery                 = "INSERT INTO product_records (last_update_date, purchase_price, sale_price, currency, product_id) VALUES (?,?,?,?,?)"
 	RecordsByAllProductsQuery = "SELECT  pr.product_id,  products.description, count(pr.id) as `records_count` FROM product_records pr JOIN products ON pr.product_id = products.id Group BY pr.product_id"
 	RecordsByOneProductQuery  = "SELECT  pr.product_id,  products.description, count(pr.id) as `records_count` FROM product_records pr JOIN products ON pr.product_id = products.id WHERE product_id=? Group BY pr.product_id;"
+	PriceHistoryQuery         = "SELECT id, last_update_date, purchase_price, sale_price, currency, product_id FROM product_records WHERE product_id=? ORDER BY last_update_date, id"
+	EffectivePriceQuery       = "SELECT id, last_update_date, purchase_price, sale_price, currency, product_id FROM product_records WHERE product_id=? AND last_update_date <= ? ORDER BY last_update_date DESC, id DESC LIMIT 1"
 )
 
 type Repository interface {
@@ -19,6 +22,8 @@ type Repository interface {
 	Save(ctx context.Context, p domain.ProductRecord) (int, error)
 	RecordsByOneProductReport(ctx context.Context, id int) (domain.ProductRecordReport, error)
 	RecordsByAllProductsReport(ctx context.Context) ([]domain.ProductRecordReport, error)
+	GetByProduct(ctx context.Context, productID int) ([]domain.ProductRecord, error)
+	GetEffective(ctx context.Context, productID int, at datetime.Time) (domain.ProductRecord, error)
 }
 
 type repository struct {
@@ -72,7 +77,7 @@ func (r *repository) Save(ctx context.Context, productRecord domain.ProductRecor
 	if err != nil {
 		return 0, err
 	}
-	res, err := stmt.Exec(productRecord.LastUpdateDate, productRecord.PurchasePrice, productRecord.SalePrice, productRecord.ProductID)
+	res, err := stmt.Exec(productRecord.LastUpdateDate, productRecord.PurchasePrice, productRecord.SalePrice, productRecord.SalePrice.Currency(), productRecord.ProductID)
 	if err != nil {
 
 		return 0, err
@@ -85,3 +90,51 @@ func (r *repository) Save(ctx context.Context, productRecord domain.ProductRecor
 
 	return int(id), nil
 }
+
+// get the price records of one product, oldest first
+func (r *repository) GetByProduct(ctx context.Context, productID int) ([]domain.ProductRecord, error) {
+	rows, err := r.db.QueryContext(ctx, PriceHistoryQuery, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []domain.ProductRecord{}
+	for rows.Next() {
+		p, err := scanProductRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, p)
+	}
+	return records, rows.Err()
+}
+
+// get the latest record of a product updated at or before at
+func (r *repository) GetEffective(ctx context.Context, productID int, at datetime.Time) (domain.ProductRecord, error) {
+	p, err := scanProductRecord(r.db.QueryRowContext(ctx, EffectivePriceQuery, productID, at))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return domain.ProductRecord{}, ErrNoPrice
+		}
+		return domain.ProductRecord{}, err
+	}
+	return p, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanProductRecord reads a record whose prices are followed by their
+// currency column.
+func scanProductRecord(row scanner) (domain.ProductRecord, error) {
+	p := domain.ProductRecord{}
+	var currency string
+	if err := row.Scan(&p.ID, &p.LastUpdateDate, &p.PurchasePrice, &p.SalePrice, &currency, &p.ProductID); err != nil {
+		return domain.ProductRecord{}, err
+	}
+	p.PurchasePrice = p.PurchasePrice.In(currency)
+	p.SalePrice = p.SalePrice.In(currency)
+	return p, nil
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/DATA-DOG/go-txdb"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	productrecord "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_record"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -16,9 +18,9 @@ import (
 
 var expectedProductRecordResult = domain.ProductRecord{
 	ID:             1,
-	LastUpdateDate: "2021-04-04",
-	PurchasePrice:  10,
-	SalePrice:      15,
+	LastUpdateDate: datetime.MustParse("2021-04-04"),
+	PurchasePrice:  money.MustParse("10", "USD"),
+	SalePrice:      money.MustParse("15", "USD"),
 	ProductID:      1,
 }
 
@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
 )
 
 var (
@@ -13,24 +15,43 @@ var (
 	ErrTryAgain     = errors.New("error, try again")
 	ErrInvalidField = errors.New("invalid field")
 	ErrInvalidDate  = errors.New("invalid date")
+	ErrNoPrice      = errors.New("no price in effect at the given time")
+	// ErrNegativeMargin is returned when a record sells below its purchase
+	// price and was not flagged to allow it.
+	ErrNegativeMargin   = errors.New("sale price is below purchase price")
+	ErrCurrencyMismatch = errors.New("purchase and sale prices must be in the same currency")
+	ErrUnknownCurrency  = errors.New("no conversion rate for currency")
 )
 
 type Service interface {
 	Save(ctx context.Context, p domain.ProductRecord) (int, error)
 	RecordsByAllProductsReport(ctx context.Context) ([]domain.ProductRecordReport, error)
 	RecordsByOneProductReport(ctx context.Context, id int) (domain.ProductRecordReport, error)
+	PriceHistory(ctx context.Context, productID int, currency string) ([]domain.ProductRecord, error)
+	PriceAt(ctx context.Context, productID int, at datetime.Time, currency string) (domain.ProductPrice, error)
 }
 
 type ProductRecordService struct {
 	repository Repository
+	rates      *money.Rates
 }
 
-func NewService(r Repository) Service {
+// NewService returns a service that converts prices with rates. With nil
+// rates, prices can only be read in the currency they were saved in.
+func NewService(r Repository, rates *money.Rates) Service {
 	return &ProductRecordService{
 		repository: r,
+		rates:      rates,
 	}
 }
 func (s *ProductRecordService) Save(ctx context.Context, p domain.ProductRecord) (int, error) {
+	cmp, err := p.SalePrice.Cmp(p.PurchasePrice)
+	if err != nil {
+		return 0, ErrCurrencyMismatch
+	}
+	if cmp < 0 && !p.AllowNegativeMargin {
+		return 0, ErrNegativeMargin
+	}
 	productReportId, err := s.repository.Save(ctx, p)
 	return productReportId, err
 }
@@ -46,3 +67,69 @@ func (s *ProductRecordService) RecordsByOneProductReport(ctx context.Context, id
 
 	return productRecord, err
 }
+
+// PriceHistory returns the records of a product, with prices converted to
+// currency unless it is empty.
+func (s *ProductRecordService) PriceHistory(ctx context.Context, productID int, currency string) ([]domain.ProductRecord, error) {
+	records, err := s.repository.GetByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if records[i].PurchasePrice, err = s.convert(records[i].PurchasePrice, currency); err != nil {
+			return nil, err
+		}
+		if records[i].SalePrice, err = s.convert(records[i].SalePrice, currency); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// PriceAt resolves the record in effect for the product at the given time and
+// computes its margin.
+func (s *ProductRecordService) PriceAt(ctx context.Context, productID int, at datetime.Time, currency string) (domain.ProductPrice, error) {
+	record, err := s.repository.GetEffective(ctx, productID, at)
+	if err != nil {
+		return domain.ProductPrice{}, err
+	}
+
+	if record.PurchasePrice, err = s.convert(record.PurchasePrice, currency); err != nil {
+		return domain.ProductPrice{}, err
+	}
+	if record.SalePrice, err = s.convert(record.SalePrice, currency); err != nil {
+		return domain.ProductPrice{}, err
+	}
+	margin, err := record.SalePrice.Sub(record.PurchasePrice)
+	if err != nil {
+		return domain.ProductPrice{}, ErrCurrencyMismatch
+	}
+
+	return domain.ProductPrice{
+		ProductID:      productID,
+		RecordID:       record.ID,
+		At:             at,
+		EffectiveSince: record.LastUpdateDate,
+		PurchasePrice:  record.PurchasePrice,
+		SalePrice:      record.SalePrice,
+		Margin:         margin,
+		MarginPercent:  margin.Percent(record.SalePrice),
+	}, nil
+}
+
+func (s *ProductRecordService) convert(m money.Money, currency string) (money.Money, error) {
+	if currency == "" {
+		return m, nil
+	}
+	if s.rates == nil {
+		if m.Currency() == currency {
+			return m, nil
+		}
+		return money.Money{}, ErrUnknownCurrency
+	}
+	converted, err := s.rates.Convert(m, currency)
+	if err != nil {
+		return money.Money{}, ErrUnknownCurrency
+	}
+	return converted, nil
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	productrecord "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_record"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product_record"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -71,9 +73,9 @@ func TestCreateProducts(t *testing.T) {
 
 	expectedProductRecord := domain.ProductRecord{
 		ID:             1,
-		LastUpdateDate: "2021-04-04",
-		PurchasePrice:  10,
-		SalePrice:      15,
+		LastUpdateDate: datetime.MustParse("2021-04-04"),
+		PurchasePrice:  money.MustParse("10", "USD"),
+		SalePrice:      money.MustParse("15", "USD"),
 		ProductID:      1,
 	}
 	id := 1
@@ -88,10 +90,124 @@ func TestCreateProducts(t *testing.T) {
 
 		assert.NoError(t, err)
 	})
+
+	t.Run("Should not create a product record that sells below its purchase price", func(t *testing.T) {
+		service, repository := CreateProductRecordService(t)
+
+		_, err := service.Save(context.TODO(), domain.ProductRecord{PurchasePrice: money.MustParse("15", "USD"), SalePrice: money.MustParse("10", "USD"), ProductID: 1})
+
+		assert.ErrorIs(t, err, productrecord.ErrNegativeMargin)
+		repository.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("Should create a product record with a negative margin when it is flagged", func(t *testing.T) {
+		service, repository := CreateProductRecordService(t)
+		repository.On("Save", mock.Anything).Return(id, nil)
+
+		productReportId, err := service.Save(context.TODO(), domain.ProductRecord{PurchasePrice: money.MustParse("15", "USD"), SalePrice: money.MustParse("10", "USD"), ProductID: 1, AllowNegativeMargin: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, id, productReportId)
+	})
+
+	t.Run("Should not create a product record with prices in different currencies", func(t *testing.T) {
+		service, repository := CreateProductRecordService(t)
+
+		_, err := service.Save(context.TODO(), domain.ProductRecord{PurchasePrice: money.MustParse("10", "USD"), SalePrice: money.MustParse("15", "BRL"), ProductID: 1})
+
+		assert.ErrorIs(t, err, productrecord.ErrCurrencyMismatch)
+		repository.AssertNotCalled(t, "Save", mock.Anything)
+	})
+}
+
+func TestPriceHistory(t *testing.T) {
+	t.Run("Should return the price records of a product", func(t *testing.T) {
+		expectedRecords := []domain.ProductRecord{
+			{ID: 1, LastUpdateDate: datetime.MustParse("2023-01-01 00:00:00"), PurchasePrice: money.MustParse("10", "USD"), SalePrice: money.MustParse("15", "USD"), ProductID: 1},
+			{ID: 2, LastUpdateDate: datetime.MustParse("2023-02-01 00:00:00"), PurchasePrice: money.MustParse("12", "USD"), SalePrice: money.MustParse("18", "USD"), ProductID: 1},
+		}
+		service, repository := CreateProductRecordService(t)
+		repository.On("GetByProduct", 1).Return(expectedRecords, nil)
+
+		records, err := service.PriceHistory(context.TODO(), 1, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRecords, records)
+	})
+}
+
+func TestPriceAt(t *testing.T) {
+	at := datetime.MustParse("2023-01-15T10:30:00Z")
+
+	t.Run("Should return the price in effect with its margin", func(t *testing.T) {
+		service, repository := CreateProductRecordService(t)
+		repository.On("GetEffective", 1, at).Return(domain.ProductRecord{
+			ID: 3, LastUpdateDate: datetime.MustParse("2023-01-01 00:00:00"), PurchasePrice: money.MustParse("20", "USD"), SalePrice: money.MustParse("30", "USD"), ProductID: 1,
+		}, nil)
+
+		price, err := service.PriceAt(context.TODO(), 1, at, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ProductPrice{
+			ProductID:      1,
+			RecordID:       3,
+			At:             datetime.MustParse("2023-01-15T10:30:00Z"),
+			EffectiveSince: datetime.MustParse("2023-01-01 00:00:00"),
+			PurchasePrice:  money.MustParse("20", "USD"),
+			SalePrice:      money.MustParse("30", "USD"),
+			Margin:         money.MustParse("10", "USD"),
+			MarginPercent:  33.33,
+		}, price)
+	})
+
+	t.Run("Should return a zero margin percent when the sale price is zero", func(t *testing.T) {
+		service, repository := CreateProductRecordService(t)
+		repository.On("GetEffective", 1, mock.Anything).Return(domain.ProductRecord{ID: 3, PurchasePrice: money.MustParse("5", "USD"), ProductID: 1}, nil)
+
+		price, err := service.PriceAt(context.TODO(), 1, at, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("-5", "USD"), price.Margin)
+		assert.Equal(t, 0.0, price.MarginPercent)
+	})
+
+	t.Run("Should convert the price to the requested currency", func(t *testing.T) {
+		service, repository := CreateProductRecordService(t)
+		repository.On("GetEffective", 1, mock.Anything).Return(domain.ProductRecord{
+			ID: 3, PurchasePrice: money.MustParse("10.01", "USD"), SalePrice: money.MustParse("15.50", "USD"), ProductID: 1,
+		}, nil)
+
+		price, err := service.PriceAt(context.TODO(), 1, at, "BRL")
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("50.05", "BRL"), price.PurchasePrice)
+		assert.Equal(t, money.MustParse("77.50", "BRL"), price.SalePrice)
+		assert.Equal(t, money.MustParse("27.45", "BRL"), price.Margin)
+	})
+
+	t.Run("Should return an error when there is no rate for the currency", func(t *testing.T) {
+		service, repository := CreateProductRecordService(t)
+		repository.On("GetEffective", 1, mock.Anything).Return(domain.ProductRecord{ID: 3, SalePrice: money.MustParse("1", "USD"), ProductID: 1}, nil)
+
+		_, err := service.PriceAt(context.TODO(), 1, at, "EUR")
+
+		assert.ErrorIs(t, err, productrecord.ErrUnknownCurrency)
+	})
+
+	t.Run("Should return an error when no price is in effect", func(t *testing.T) {
+		service, repository := CreateProductRecordService(t)
+		repository.On("GetEffective", 1, mock.Anything).Return(domain.ProductRecord{}, productrecord.ErrNoPrice)
+
+		_, err := service.PriceAt(context.TODO(), 1, at, "")
+
+		assert.ErrorIs(t, err, productrecord.ErrNoPrice)
+	})
 }
 
 func CreateProductRecordService(t *testing.T) (productrecord.Service, *mocks.ProductRecordRepositoryMock) {
+	rates, err := money.NewRates("USD", map[string]string{"BRL": "0.2"})
+	assert.NoError(t, err)
 	mockRepository := new(mocks.ProductRecordRepositoryMock)
-	mockService := productrecord.NewService(mockRepository)
+	mockService := productrecord.NewService(mockRepository, rates)
 	return mockService, mockRepository
 }
@@ -5,14 +5,54 @@ import (
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
 )
 
 // Repository encapsulates the storage of a purchased order.
 type Repository interface {
+	GetAll(ctx context.Context) ([]domain.PurchaseOrders, error)
+	GetByBuyers(ctx context.Context, buyerIDs []int) ([]domain.PurchaseOrders, error)
+	GetByCarriers(ctx context.Context, carrierIDs []int) ([]domain.PurchaseOrders, error)
+	Get(ctx context.Context, id int) (domain.PurchaseOrders, error)
 	ExistsOrder(ctx context.Context, orderNumber string) bool
-	Save(ctx context.Context, o domain.PurchaseOrders) error
+	Save(ctx context.Context, o domain.PurchaseOrders) (int, error)
+	Demand(ctx context.Context, id int) (domain.OrderDemand, error)
+	Warehouses(ctx context.Context, id int) ([]domain.AssignmentWarehouse, error)
+	Carriers(ctx context.Context, destination domain.Place) ([]domain.AssignmentCarrier, error)
+	Assign(ctx context.Context, id, warehouseID, carrierID int) error
 }
 
+const (
+	GetAllQuery = "SELECT id, order_number, order_date, tracking_code, buyer_id, product_record_id, order_status_id, COALESCE(carrier_id, 0), COALESCE(warehouse_id, 0) " +
+		"FROM purchase_orders ORDER BY id"
+	GetQuery = "SELECT id, order_number, order_date, tracking_code, buyer_id, product_record_id, order_status_id, COALESCE(carrier_id, 0), COALESCE(warehouse_id, 0) " +
+		"FROM purchase_orders WHERE id = ?"
+	SaveQuery = "INSERT INTO purchase_orders(order_number, order_date, tracking_code, buyer_id, product_record_id, order_status_id, carrier_id, warehouse_id) " +
+		"VALUES(?, ?, ?, ?, ?, ?, ?, ?)"
+	// DestinationQuery reads the product of an order and the place of its
+	// buyer. Buyers without a locality come back with a zero place.
+	DestinationQuery = "SELECT po.id, pr.product_id, COALESCE(b.locality_id, 0), COALESCE(l.province_id, 0), COALESCE(p.country_id, 0) " +
+		"FROM purchase_orders po JOIN product_records pr ON po.product_record_id = pr.id JOIN buyers b ON po.buyer_id = b.id " +
+		"LEFT JOIN localities l ON b.locality_id = l.id LEFT JOIN provinces p ON l.province_id = p.id WHERE po.id = ?"
+	DemandQuery = "SELECT pr.product_id, SUM(COALESCE(od.quantity, 1)) FROM order_details od JOIN product_records pr ON od.product_record_id = pr.id " +
+		"WHERE od.purchase_order_id = ? GROUP BY pr.product_id"
+	// WarehousesQuery reads the stock each warehouse holds of the products
+	// of an order, one row per warehouse and product.
+	WarehousesQuery = "SELECT w.id, w.locality_id, COALESCE(l.province_id, 0), COALESCE(p.country_id, 0), pb.product_id, SUM(pb.current_quantity) " +
+		"FROM warehouses w JOIN localities l ON w.locality_id = l.id LEFT JOIN provinces p ON l.province_id = p.id " +
+		"JOIN sections s ON s.warehouse_id = w.id JOIN product_batches pb ON pb.section_id = s.id " +
+		"WHERE pb.product_id IN (SELECT pr.product_id FROM order_details od JOIN product_records pr ON od.product_record_id = pr.id WHERE od.purchase_order_id = ? " +
+		"UNION SELECT pr.product_id FROM purchase_orders po JOIN product_records pr ON po.product_record_id = pr.id WHERE po.id = ?) " +
+		"GROUP BY w.id, w.locality_id, l.province_id, p.country_id, pb.product_id ORDER BY w.id"
+	CarriersQuery = "SELECT c.id, c.locality_id, COALESCE(l.province_id, 0), COALESCE(p.country_id, 0), " +
+		"EXISTS (SELECT 1 FROM carrier_localities cl WHERE cl.carrier_id = c.id AND cl.locality_id = ?), " +
+		"(SELECT COUNT(*) FROM purchase_orders po WHERE po.carrier_id = c.id) " +
+		"FROM carriers c JOIN localities l ON c.locality_id = l.id LEFT JOIN provinces p ON l.province_id = p.id ORDER BY c.id"
+	AssignQuery = "UPDATE purchase_orders SET warehouse_id = ?, carrier_id = ? WHERE id = ?"
+)
+
 type repository struct {
 	db *sql.DB
 }
@@ -23,31 +63,213 @@ func NewRepository(db *sql.DB) Repository {
 	}
 }
 
+func (r *repository) GetAll(ctx context.Context) ([]domain.PurchaseOrders, error) {
+	rows, err := r.db.QueryContext(ctx, GetAllQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []domain.PurchaseOrders{}
+	for rows.Next() {
+		o := domain.PurchaseOrders{}
+		err := rows.Scan(&o.ID, &o.OrderNumber, &o.OrderDate, &o.TrackingCode, &o.BuyerID, &o.ProductRecordID, &o.OrderStatusID, &o.CarrierID, &o.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+	return orders, rows.Err()
+}
+
+// GetByBuyers returns the orders of the given buyers.
+func (r *repository) GetByBuyers(ctx context.Context, buyerIDs []int) ([]domain.PurchaseOrders, error) {
+	return r.getIn(ctx, "buyer_id", buyerIDs)
+}
+
+// GetByCarriers returns the orders shipped by the given carriers.
+func (r *repository) GetByCarriers(ctx context.Context, carrierIDs []int) ([]domain.PurchaseOrders, error) {
+	return r.getIn(ctx, "carrier_id", carrierIDs)
+}
+
+// getIn returns the orders whose column holds one of values, in one query.
+func (r *repository) getIn(ctx context.Context, column string, values []int) ([]domain.PurchaseOrders, error) {
+	orders := []domain.PurchaseOrders{}
+	if len(values) == 0 {
+		return orders, nil
+	}
+	in, args := mysqlutil.In(values)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, order_number, order_date, tracking_code, buyer_id, product_record_id, order_status_id, COALESCE(carrier_id, 0), COALESCE(warehouse_id, 0) FROM purchase_orders WHERE "+column+" IN "+in+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		o := domain.PurchaseOrders{}
+		if err := rows.Scan(&o.ID, &o.OrderNumber, &o.OrderDate, &o.TrackingCode, &o.BuyerID, &o.ProductRecordID, &o.OrderStatusID, &o.CarrierID, &o.WarehouseID); err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+	return orders, rows.Err()
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.PurchaseOrders, error) {
+	o := domain.PurchaseOrders{}
+	err := r.db.QueryRowContext(ctx, GetQuery, id).Scan(&o.ID, &o.OrderNumber, &o.OrderDate, &o.TrackingCode, &o.BuyerID, &o.ProductRecordID,
+		&o.OrderStatusID, &o.CarrierID, &o.WarehouseID)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return domain.PurchaseOrders{}, ErrNotFound
+		}
+		return domain.PurchaseOrders{}, err
+	}
+	return o, nil
+}
+
 func (r *repository) ExistsOrder(ctx context.Context, orderNumber string) bool {
 	query := "SELECT order_number FROM purchase_orders WHERE order_number = ?"
 	err := r.db.QueryRow(query, orderNumber).Scan(&orderNumber)
 	return err == nil
 }
 
-func (r *repository) Save(ctx context.Context, o domain.PurchaseOrders) error {
-	query := "INSERT INTO purchase_orders(order_number, order_date, tracking_code, buyer_id, product_record_id, order_status_id) VALUES(?, ?, ?, ?, ?, ?)"
-	stmt, err := r.db.Prepare(query)
+// Save inserts the order and its purchase_order.created event in one
+// transaction.
+func (r *repository) Save(ctx context.Context, o domain.PurchaseOrders) (int, error) {
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, SaveQuery, o.OrderNumber, o.OrderDate, o.TrackingCode, o.BuyerID, o.ProductRecordID,
+			o.OrderStatusID, mysqlutil.NullableInt(o.CarrierID), mysqlutil.NullableInt(o.WarehouseID))
+		if err != nil {
+			switch {
+			case mysqlutil.IsDuplicateEntry(err):
+				return ErrTrackingCodeExists
+			case mysqlutil.IsNoReferencedRow(err):
+				return ErrInvalidReference
+			}
+			return err
+		}
+
+		insertedID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		o.ID = int(insertedID)
+
+		return outbox.Write(ctx, tx, domain.EventPurchaseOrderCreated, o.ID, o)
+	})
 	if err != nil {
-		return err
+		return 0, err
 	}
-	defer stmt.Close()
 
-	result, err := stmt.Exec(o.OrderNumber, o.OrderDate, o.TrackingCode, o.BuyerID, o.ProductRecordID, o.OrderStatusID)
+	return o.ID, nil
+}
+
+// Demand reads what an order needs shipped. Orders without details need one
+// unit of the product of their record.
+func (r *repository) Demand(ctx context.Context, id int) (domain.OrderDemand, error) {
+	d := domain.OrderDemand{}
+	var productID int
+	err := r.db.QueryRowContext(ctx, DestinationQuery, id).Scan(&d.OrderID, &productID,
+		&d.Destination.LocalityID, &d.Destination.ProvinceID, &d.Destination.CountryID)
 	if err != nil {
-		return err
+		if err.Error() == "sql: no rows in result set" {
+			return domain.OrderDemand{}, ErrNotFound
+		}
+		return domain.OrderDemand{}, err
 	}
 
-	insertedID, err := result.LastInsertId()
+	rows, err := r.db.QueryContext(ctx, DemandQuery, id)
 	if err != nil {
-		return err
+		return domain.OrderDemand{}, err
+	}
+	defer rows.Close()
+
+	d.Products = map[int]int{}
+	for rows.Next() {
+		var lineProductID, quantity int
+		if err := rows.Scan(&lineProductID, &quantity); err != nil {
+			return domain.OrderDemand{}, err
+		}
+		d.Products[lineProductID] = quantity
+	}
+	if err := rows.Err(); err != nil {
+		return domain.OrderDemand{}, err
 	}
+	if len(d.Products) == 0 {
+		d.Products[productID] = 1
+	}
+	return d, nil
+}
 
-	o.ID = int(insertedID)
+// Warehouses lists the warehouses holding any of the products of an order.
+func (r *repository) Warehouses(ctx context.Context, id int) ([]domain.AssignmentWarehouse, error) {
+	rows, err := r.db.QueryContext(ctx, WarehousesQuery, id, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	return nil
+	warehouses := []domain.AssignmentWarehouse{}
+	for rows.Next() {
+		w := domain.AssignmentWarehouse{}
+		var productID, quantity int
+		err := rows.Scan(&w.ID, &w.Place.LocalityID, &w.Place.ProvinceID, &w.Place.CountryID, &productID, &quantity)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(warehouses); n == 0 || warehouses[n-1].ID != w.ID {
+			w.Stock = map[int]int{}
+			warehouses = append(warehouses, w)
+		}
+		warehouses[len(warehouses)-1].Stock[productID] = quantity
+	}
+	return warehouses, rows.Err()
+}
+
+// Carriers lists every carrier with its load, telling which ones have the
+// destination in their coverage.
+func (r *repository) Carriers(ctx context.Context, destination domain.Place) ([]domain.AssignmentCarrier, error) {
+	rows, err := r.db.QueryContext(ctx, CarriersQuery, destination.LocalityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	carriers := []domain.AssignmentCarrier{}
+	for rows.Next() {
+		c := domain.AssignmentCarrier{}
+		err := rows.Scan(&c.ID, &c.Place.LocalityID, &c.Place.ProvinceID, &c.Place.CountryID, &c.Covers, &c.Load)
+		if err != nil {
+			return nil, err
+		}
+		carriers = append(carriers, c)
+	}
+	return carriers, rows.Err()
+}
+
+// Assign records the warehouse and carrier of an order, with its
+// purchase_order.assigned event, in one transaction.
+func (r *repository) Assign(ctx context.Context, id, warehouseID, carrierID int) error {
+	return sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, AssignQuery, warehouseID, carrierID, id)
+		if err != nil {
+			return err
+		}
+		affect, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affect < 1 && !exists(ctx, tx, id) {
+			return ErrNotFound
+		}
+
+		assignment := domain.PurchaseOrderAssignment{OrderID: id, WarehouseID: warehouseID, CarrierID: carrierID}
+		return outbox.Write(ctx, tx, domain.EventPurchaseOrderAssigned, id, assignment)
+	})
+}
+
+func exists(ctx context.Context, tx *sql.Tx, id int) bool {
+	err := tx.QueryRowContext(ctx, "SELECT id FROM purchase_orders WHERE id = ?", id).Scan(&id)
+	return err == nil
 }
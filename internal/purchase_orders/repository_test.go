@@ -9,6 +9,7 @@ import (
 	"github.com/DATA-DOG/go-txdb"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	purchaseOrders "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -25,14 +26,14 @@ func TestSaveOrders(t *testing.T) {
 
 		expectedOrder := domain.PurchaseOrders{
 			OrderNumber:     "9423i",
-			OrderDate:       "2021-04-04",
-			TrackingCode:    "afijaehn",
+			OrderDate:       datetime.MustParse("2021-04-04"),
+			TrackingCode:    "track-save",
 			BuyerID:         1,
 			ProductRecordID: 1,
 			OrderStatusID:   1,
 		}
 
-		err := repositoryPurchaseOrders.Save(ctx, expectedOrder)
+		_, err := repositoryPurchaseOrders.Save(ctx, expectedOrder)
 		assert.NoError(t, err)
 	})
 }
@@ -47,14 +48,14 @@ func TestExistsOrder(t *testing.T) {
 
 		expectedOrder := domain.PurchaseOrders{
 			OrderNumber:     "9423i",
-			OrderDate:       "2021-04-04",
-			TrackingCode:    "afijaehn",
+			OrderDate:       datetime.MustParse("2021-04-04"),
+			TrackingCode:    "track-exists",
 			BuyerID:         1,
 			ProductRecordID: 1,
 			OrderStatusID:   1,
 		}
 
-		err := repository.Save(ctx, expectedOrder)
+		_, err := repository.Save(ctx, expectedOrder)
 		assert.NoError(t, err)
 
 		exists := repository.ExistsOrder(ctx, expectedOrder.OrderNumber)
@@ -71,6 +72,52 @@ func TestExistsOrder(t *testing.T) {
 	})
 }
 
+func TestAssignOrderRepository(t *testing.T) {
+	t.Run("Should read the candidates of an order and record its assignment", func(t *testing.T) {
+		repository := purchaseOrders.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		id, err := repository.Save(ctx, domain.PurchaseOrders{
+			OrderNumber:     "assign-1",
+			OrderDate:       datetime.MustParse("2021-04-04"),
+			TrackingCode:    "track-assign",
+			BuyerID:         1,
+			ProductRecordID: 1,
+			OrderStatusID:   1,
+		})
+		assert.NoError(t, err)
+
+		demand, err := repository.Demand(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, demand.Destination.LocalityID)
+		assert.Equal(t, map[int]int{1: 1}, demand.Products)
+
+		warehouses, err := repository.Warehouses(ctx, id)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, warehouses)
+
+		carriers, err := repository.Carriers(ctx, demand.Destination)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, carriers)
+
+		assert.NoError(t, repository.Assign(ctx, id, 1, 1))
+	})
+	t.Run("Should return not found for a missing order", func(t *testing.T) {
+		repository := purchaseOrders.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		_, err := repository.Demand(ctx, 50000000)
+		assert.ErrorIs(t, err, purchaseOrders.ErrNotFound)
+		_, err = repository.Get(ctx, 50000000)
+		assert.ErrorIs(t, err, purchaseOrders.ErrNotFound)
+		assert.ErrorIs(t, repository.Assign(ctx, 50000000, 1, 1), purchaseOrders.ErrNotFound)
+	})
+}
+
 func initDatabase() *sql.DB {
 	txdb.Register("txdb", "mysql", "root:@/melisprint")
 	db, _ := sql.Open("txdb", uuid.New().String())
@@ -13,28 +13,179 @@ var (
 	ErrExists    = errors.New("order already exists")
 	ErrInvalidID = errors.New("invalid ID")
 	ErrConflict  = errors.New("buyer not found")
+	// ErrInvalidReference is returned when an order is saved with a carrier,
+	// warehouse or product record that does not exist.
+	ErrInvalidReference = errors.New("order references a missing record")
+	// ErrTrackingCodeExists is returned when another order already uses the
+	// tracking code.
+	ErrTrackingCodeExists = errors.New("tracking code already in use")
+
+	ErrUnknownStrategy   = errors.New("unknown assignment strategy")
+	ErrNoDestination     = errors.New("buyer has no delivery locality")
+	ErrNoWarehouse       = errors.New("no warehouse holds enough stock")
+	ErrInsufficientStock = errors.New("warehouse does not hold enough stock")
+	ErrNoCarrier         = errors.New("no carrier delivers to the buyer locality")
+	ErrCarrierNotFound   = errors.New("carrier not found")
 )
 
 type Service interface {
+	GetAll(ctx context.Context) ([]domain.PurchaseOrders, error)
+	GetByBuyers(ctx context.Context, buyerIDs []int) ([]domain.PurchaseOrders, error)
+	GetByCarriers(ctx context.Context, carrierIDs []int) ([]domain.PurchaseOrders, error)
+	Get(ctx context.Context, id int) (domain.PurchaseOrders, error)
 	Create(ctx context.Context, o domain.PurchaseOrders) (domain.PurchaseOrders, error)
+	Assign(ctx context.Context, id int, req domain.PurchaseOrderAssignmentRequest) (domain.PurchaseOrderAssignment, error)
 }
 
 type purchaseordersService struct {
 	repository Repository
+	strategies map[string]Strategy
 }
 
 func NewService(r Repository) Service {
 	return &purchaseordersService{
 		repository: r,
+		strategies: Strategies(),
 	}
 }
 
+func (s *purchaseordersService) GetAll(ctx context.Context) ([]domain.PurchaseOrders, error) {
+	return s.repository.GetAll(ctx)
+}
+
+// GetByBuyers returns the orders of the given buyers.
+func (s *purchaseordersService) GetByBuyers(ctx context.Context, buyerIDs []int) ([]domain.PurchaseOrders, error) {
+	return s.repository.GetByBuyers(ctx, buyerIDs)
+}
+
+// GetByCarriers returns the orders shipped by the given carriers.
+func (s *purchaseordersService) GetByCarriers(ctx context.Context, carrierIDs []int) ([]domain.PurchaseOrders, error) {
+	return s.repository.GetByCarriers(ctx, carrierIDs)
+}
+
+func (s *purchaseordersService) Get(ctx context.Context, id int) (domain.PurchaseOrders, error) {
+	return s.repository.Get(ctx, id)
+}
+
 func (s *purchaseordersService) Create(ctx context.Context, o domain.PurchaseOrders) (domain.PurchaseOrders, error) {
 	orderExists := s.repository.ExistsOrder(ctx, o.OrderNumber)
 	if orderExists {
 		return domain.PurchaseOrders{}, ErrExists
-	} else {
-		order := s.repository.Save(ctx, o)
-		return o, order
 	}
+	id, err := s.repository.Save(ctx, o)
+	if err != nil {
+		return domain.PurchaseOrders{}, err
+	}
+	o.ID = id
+	return o, nil
+}
+
+// Assign picks the warehouse an order ships from and the carrier delivering
+// it, then records both on the order. The warehouse must hold enough stock of
+// every product of the order and the carrier must be in the country of the
+// buyer or cover its locality. Overridden warehouses must hold the stock as
+// well; overridden carriers only need to exist.
+func (s *purchaseordersService) Assign(ctx context.Context, id int, req domain.PurchaseOrderAssignmentRequest) (domain.PurchaseOrderAssignment, error) {
+	name := req.Strategy
+	if name == "" {
+		name = DefaultStrategy
+	}
+	strategy, ok := s.strategies[name]
+	if !ok {
+		return domain.PurchaseOrderAssignment{}, ErrUnknownStrategy
+	}
+
+	demand, err := s.repository.Demand(ctx, id)
+	if err != nil {
+		return domain.PurchaseOrderAssignment{}, err
+	}
+
+	warehouseID, err := s.warehouse(ctx, demand, strategy, req.WarehouseID)
+	if err != nil {
+		return domain.PurchaseOrderAssignment{}, err
+	}
+	carrierID, err := s.carrier(ctx, demand, strategy, req.CarrierID)
+	if err != nil {
+		return domain.PurchaseOrderAssignment{}, err
+	}
+
+	if err := s.repository.Assign(ctx, id, warehouseID, carrierID); err != nil {
+		return domain.PurchaseOrderAssignment{}, err
+	}
+	if req.WarehouseID != 0 && req.CarrierID != 0 {
+		name = StrategyManual
+	}
+	return domain.PurchaseOrderAssignment{
+		OrderID:     id,
+		WarehouseID: warehouseID,
+		CarrierID:   carrierID,
+		Strategy:    name,
+	}, nil
+}
+
+func (s *purchaseordersService) warehouse(ctx context.Context, demand domain.OrderDemand, strategy Strategy, override int) (int, error) {
+	warehouses, err := s.repository.Warehouses(ctx, demand.OrderID)
+	if err != nil {
+		return 0, err
+	}
+
+	stocked := []domain.AssignmentWarehouse{}
+	for _, w := range warehouses {
+		if holdsDemand(w, demand) {
+			stocked = append(stocked, w)
+		}
+	}
+
+	if override != 0 {
+		for _, w := range stocked {
+			if w.ID == override {
+				return w.ID, nil
+			}
+		}
+		return 0, ErrInsufficientStock
+	}
+	if len(stocked) == 0 {
+		return 0, ErrNoWarehouse
+	}
+	return strategy.Warehouse(demand.Destination, stocked).ID, nil
+}
+
+func (s *purchaseordersService) carrier(ctx context.Context, demand domain.OrderDemand, strategy Strategy, override int) (int, error) {
+	if override == 0 && demand.Destination.LocalityID == 0 {
+		return 0, ErrNoDestination
+	}
+
+	carriers, err := s.repository.Carriers(ctx, demand.Destination)
+	if err != nil {
+		return 0, err
+	}
+
+	if override != 0 {
+		for _, c := range carriers {
+			if c.ID == override {
+				return c.ID, nil
+			}
+		}
+		return 0, ErrCarrierNotFound
+	}
+
+	reachable := []domain.AssignmentCarrier{}
+	for _, c := range carriers {
+		if carrierDistance(demand.Destination, c) <= sameCountry {
+			reachable = append(reachable, c)
+		}
+	}
+	if len(reachable) == 0 {
+		return 0, ErrNoCarrier
+	}
+	return strategy.Carrier(demand.Destination, reachable).ID, nil
+}
+
+func holdsDemand(w domain.AssignmentWarehouse, demand domain.OrderDemand) bool {
+	for productID, quantity := range demand.Products {
+		if w.Stock[productID] < quantity {
+			return false
+		}
+	}
+	return true
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/purchase_orders"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -16,7 +17,7 @@ func TestCreate(t *testing.T) {
 		id := 10
 		expectedOrder := domain.PurchaseOrders{
 			OrderNumber:     "9423i",
-			OrderDate:       "2021-04-04",
+			OrderDate:       datetime.MustParse("2021-04-04"),
 			TrackingCode:    "afijaehn",
 			BuyerID:         1,
 			ProductRecordID: 1,
@@ -29,8 +30,9 @@ func TestCreate(t *testing.T) {
 
 		order, err := service.Create(context.TODO(), expectedOrder)
 
+		assert.Equal(t, id, order.ID)
 		assert.Equal(t, "9423i", order.OrderNumber)
-		assert.Equal(t, "2021-04-04", order.OrderDate)
+		assert.Equal(t, datetime.MustParse("2021-04-04"), order.OrderDate)
 		assert.Equal(t, "afijaehn", order.TrackingCode)
 
 		assert.NoError(t, err)
@@ -47,6 +49,152 @@ func TestCreate(t *testing.T) {
 	})
 }
 
+func TestAssign(t *testing.T) {
+	demand := domain.OrderDemand{OrderID: 7, Destination: destination, Products: map[int]int{1: 5, 2: 1}}
+	warehouses := []domain.AssignmentWarehouse{
+		{ID: 1, Place: destination, Stock: map[int]int{1: 4, 2: 10}},
+		{ID: 2, Place: sameCountry, Stock: map[int]int{1: 5, 2: 1}},
+		{ID: 3, Place: sameProvince, Stock: map[int]int{1: 50}},
+	}
+	carriers := []domain.AssignmentCarrier{
+		{ID: 1, Place: otherLocality},
+		{ID: 2, Place: sameCountry, Load: 3},
+		{ID: 3, Place: sameProvince, Load: 9},
+	}
+
+	t.Run("Should assign the closest stocked warehouse and carrier by default", func(t *testing.T) {
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("Demand", mock.Anything, 7).Return(demand, nil)
+		repository.On("Warehouses", mock.Anything, 7).Return(warehouses, nil)
+		repository.On("Carriers", mock.Anything, destination).Return(carriers, nil)
+		repository.On("Assign", mock.Anything, 7, 2, 3).Return(nil)
+
+		assignment, err := service.Assign(context.TODO(), 7, domain.PurchaseOrderAssignmentRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PurchaseOrderAssignment{OrderID: 7, WarehouseID: 2, CarrierID: 3, Strategy: purchase_orders.StrategySameLocality}, assignment)
+	})
+	t.Run("Should use the requested strategy", func(t *testing.T) {
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("Demand", mock.Anything, 7).Return(demand, nil)
+		repository.On("Warehouses", mock.Anything, 7).Return(warehouses, nil)
+		repository.On("Carriers", mock.Anything, destination).Return(carriers, nil)
+		repository.On("Assign", mock.Anything, 7, 2, 2).Return(nil)
+
+		assignment, err := service.Assign(context.TODO(), 7, domain.PurchaseOrderAssignmentRequest{Strategy: purchase_orders.StrategyLeastLoaded})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, assignment.CarrierID)
+		assert.Equal(t, purchase_orders.StrategyLeastLoaded, assignment.Strategy)
+	})
+	t.Run("Should record manual overrides", func(t *testing.T) {
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("Demand", mock.Anything, 7).Return(demand, nil)
+		repository.On("Warehouses", mock.Anything, 7).Return(warehouses, nil)
+		repository.On("Carriers", mock.Anything, destination).Return(carriers, nil)
+		repository.On("Assign", mock.Anything, 7, 2, 1).Return(nil)
+
+		assignment, err := service.Assign(context.TODO(), 7, domain.PurchaseOrderAssignmentRequest{WarehouseID: 2, CarrierID: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PurchaseOrderAssignment{OrderID: 7, WarehouseID: 2, CarrierID: 1, Strategy: purchase_orders.StrategyManual}, assignment)
+	})
+	t.Run("Should return err when the overridden warehouse lacks stock", func(t *testing.T) {
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("Demand", mock.Anything, 7).Return(demand, nil)
+		repository.On("Warehouses", mock.Anything, 7).Return(warehouses, nil)
+
+		_, err := service.Assign(context.TODO(), 7, domain.PurchaseOrderAssignmentRequest{WarehouseID: 1})
+
+		assert.ErrorIs(t, err, purchase_orders.ErrInsufficientStock)
+		repository.AssertNotCalled(t, "Assign", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Should return err when the overridden carrier does not exist", func(t *testing.T) {
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("Demand", mock.Anything, 7).Return(demand, nil)
+		repository.On("Warehouses", mock.Anything, 7).Return(warehouses, nil)
+		repository.On("Carriers", mock.Anything, destination).Return(carriers, nil)
+
+		_, err := service.Assign(context.TODO(), 7, domain.PurchaseOrderAssignmentRequest{CarrierID: 99})
+
+		assert.ErrorIs(t, err, purchase_orders.ErrCarrierNotFound)
+	})
+	t.Run("Should return err when no warehouse holds enough stock", func(t *testing.T) {
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("Demand", mock.Anything, 7).Return(demand, nil)
+		repository.On("Warehouses", mock.Anything, 7).Return(warehouses[:1], nil)
+
+		_, err := service.Assign(context.TODO(), 7, domain.PurchaseOrderAssignmentRequest{})
+
+		assert.ErrorIs(t, err, purchase_orders.ErrNoWarehouse)
+	})
+	t.Run("Should return err when no carrier reaches the buyer", func(t *testing.T) {
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("Demand", mock.Anything, 7).Return(demand, nil)
+		repository.On("Warehouses", mock.Anything, 7).Return(warehouses, nil)
+		repository.On("Carriers", mock.Anything, destination).Return(carriers[:1], nil)
+
+		_, err := service.Assign(context.TODO(), 7, domain.PurchaseOrderAssignmentRequest{})
+
+		assert.ErrorIs(t, err, purchase_orders.ErrNoCarrier)
+	})
+	t.Run("Should return err when the buyer has no delivery locality", func(t *testing.T) {
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("Demand", mock.Anything, 7).Return(domain.OrderDemand{OrderID: 7, Products: demand.Products}, nil)
+		repository.On("Warehouses", mock.Anything, 7).Return(warehouses, nil)
+
+		_, err := service.Assign(context.TODO(), 7, domain.PurchaseOrderAssignmentRequest{})
+
+		assert.ErrorIs(t, err, purchase_orders.ErrNoDestination)
+	})
+	t.Run("Should return err when the strategy is unknown", func(t *testing.T) {
+		_, service := InitServerWithPurchaseOrdersRepository(t)
+
+		_, err := service.Assign(context.TODO(), 7, domain.PurchaseOrderAssignmentRequest{Strategy: "cheapest"})
+
+		assert.ErrorIs(t, err, purchase_orders.ErrUnknownStrategy)
+	})
+	t.Run("Should return err when the order does not exist", func(t *testing.T) {
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("Demand", mock.Anything, 7).Return(domain.OrderDemand{}, purchase_orders.ErrNotFound)
+
+		_, err := service.Assign(context.TODO(), 7, domain.PurchaseOrderAssignmentRequest{})
+
+		assert.ErrorIs(t, err, purchase_orders.ErrNotFound)
+	})
+}
+
+func TestGet(t *testing.T) {
+	t.Run("Should return the order", func(t *testing.T) {
+		expectedOrder := domain.PurchaseOrders{ID: 1, OrderNumber: "9423i", CarrierID: 2}
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("Get", mock.Anything, 1).Return(expectedOrder, nil)
+
+		order, err := service.Get(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedOrder, order)
+	})
+	t.Run("Should return not found", func(t *testing.T) {
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("Get", mock.Anything, 1).Return(domain.PurchaseOrders{}, purchase_orders.ErrNotFound)
+
+		_, err := service.Get(context.TODO(), 1)
+
+		assert.ErrorIs(t, err, purchase_orders.ErrNotFound)
+	})
+	t.Run("Should return all the orders", func(t *testing.T) {
+		expectedOrders := []domain.PurchaseOrders{{ID: 1}, {ID: 2}}
+		repository, service := InitServerWithPurchaseOrdersRepository(t)
+		repository.On("GetAll", mock.Anything).Return(expectedOrders, nil)
+
+		orders, err := service.GetAll(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedOrders, orders)
+	})
+}
+
 func InitServerWithPurchaseOrdersRepository(t *testing.T) (*mocks.PurchaseOrdersRepositoryMock, purchase_orders.Service) {
 	t.Helper()
 	mockRepository := &mocks.PurchaseOrdersRepositoryMock{}
@@ -0,0 +1,144 @@
+package purchase_orders
+
+import (
+	"sync"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+)
+
+// Names of the assignment strategies.
+const (
+	StrategySameLocality = "same-locality"
+	StrategyRoundRobin   = "round-robin"
+	StrategyLeastLoaded  = "least-loaded"
+	// StrategyManual is reported when both the warehouse and the carrier of
+	// an assignment were overridden.
+	StrategyManual = "manual"
+)
+
+// DefaultStrategy is used when an assignment request names none.
+const DefaultStrategy = StrategySameLocality
+
+// Distances between two places, from the closest to the farthest. Carriers
+// farther than sameCountry cannot deliver unless the destination is in their
+// coverage.
+const (
+	sameLocality = iota
+	sameProvince
+	sameCountry
+	elsewhere
+)
+
+// Strategy chooses where an order ships from and who delivers it. It is only
+// called with candidates able to serve the order, and never with none.
+type Strategy interface {
+	Warehouse(destination domain.Place, warehouses []domain.AssignmentWarehouse) domain.AssignmentWarehouse
+	Carrier(destination domain.Place, carriers []domain.AssignmentCarrier) domain.AssignmentCarrier
+}
+
+// Strategies returns a new instance of every strategy, by name. Round-robin
+// keeps its position in the instance, so a service holds on to its own.
+func Strategies() map[string]Strategy {
+	return map[string]Strategy{
+		StrategySameLocality: sameLocalityStrategy{},
+		StrategyRoundRobin:   &roundRobinStrategy{},
+		StrategyLeastLoaded:  leastLoadedStrategy{},
+	}
+}
+
+// sameLocalityStrategy ships from the closest warehouse with the closest
+// carrier, favouring the lowest ids on ties.
+type sameLocalityStrategy struct{}
+
+func (sameLocalityStrategy) Warehouse(destination domain.Place, warehouses []domain.AssignmentWarehouse) domain.AssignmentWarehouse {
+	return closestWarehouse(destination, warehouses)
+}
+
+func (sameLocalityStrategy) Carrier(destination domain.Place, carriers []domain.AssignmentCarrier) domain.AssignmentCarrier {
+	best := carriers[0]
+	for _, c := range carriers[1:] {
+		if carrierDistance(destination, c) < carrierDistance(destination, best) {
+			best = c
+		}
+	}
+	return best
+}
+
+// roundRobinStrategy ships from the closest warehouse and takes turns among
+// the closest carriers.
+type roundRobinStrategy struct {
+	mu   sync.Mutex
+	next int
+}
+
+func (s *roundRobinStrategy) Warehouse(destination domain.Place, warehouses []domain.AssignmentWarehouse) domain.AssignmentWarehouse {
+	return closestWarehouse(destination, warehouses)
+}
+
+func (s *roundRobinStrategy) Carrier(destination domain.Place, carriers []domain.AssignmentCarrier) domain.AssignmentCarrier {
+	closest := []domain.AssignmentCarrier{}
+	for _, c := range carriers {
+		switch {
+		case len(closest) == 0 || carrierDistance(destination, c) < carrierDistance(destination, closest[0]):
+			closest = []domain.AssignmentCarrier{c}
+		case carrierDistance(destination, c) == carrierDistance(destination, closest[0]):
+			closest = append(closest, c)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := closest[s.next%len(closest)]
+	s.next++
+	return c
+}
+
+// leastLoadedStrategy ships from the closest warehouse with the carrier that
+// has the fewest orders, the closest one on ties.
+type leastLoadedStrategy struct{}
+
+func (leastLoadedStrategy) Warehouse(destination domain.Place, warehouses []domain.AssignmentWarehouse) domain.AssignmentWarehouse {
+	return closestWarehouse(destination, warehouses)
+}
+
+func (leastLoadedStrategy) Carrier(destination domain.Place, carriers []domain.AssignmentCarrier) domain.AssignmentCarrier {
+	best := carriers[0]
+	for _, c := range carriers[1:] {
+		if c.Load < best.Load || c.Load == best.Load && carrierDistance(destination, c) < carrierDistance(destination, best) {
+			best = c
+		}
+	}
+	return best
+}
+
+func closestWarehouse(destination domain.Place, warehouses []domain.AssignmentWarehouse) domain.AssignmentWarehouse {
+	best := warehouses[0]
+	for _, w := range warehouses[1:] {
+		if distance(destination, w.Place) < distance(destination, best.Place) {
+			best = w
+		}
+	}
+	return best
+}
+
+// carrierDistance treats a destination in the coverage of the carrier as its
+// own locality.
+func carrierDistance(destination domain.Place, c domain.AssignmentCarrier) int {
+	if c.Covers {
+		return sameLocality
+	}
+	return distance(destination, c.Place)
+}
+
+func distance(a, b domain.Place) int {
+	switch {
+	case a.LocalityID != 0 && a.LocalityID == b.LocalityID:
+		return sameLocality
+	case a.ProvinceID != 0 && a.ProvinceID == b.ProvinceID:
+		return sameProvince
+	case a.CountryID != 0 && a.CountryID == b.CountryID:
+		return sameCountry
+	default:
+		return elsewhere
+	}
+}
@@ -0,0 +1,85 @@
+package purchase_orders_test
+
+import (
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/purchase_orders"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	destination   = domain.Place{LocalityID: 1, ProvinceID: 10, CountryID: 100}
+	sameProvince  = domain.Place{LocalityID: 2, ProvinceID: 10, CountryID: 100}
+	sameCountry   = domain.Place{LocalityID: 3, ProvinceID: 11, CountryID: 100}
+	otherLocality = domain.Place{LocalityID: 4, ProvinceID: 12, CountryID: 200}
+)
+
+func TestStrategyWarehouse(t *testing.T) {
+	warehouses := []domain.AssignmentWarehouse{
+		{ID: 1, Place: sameCountry},
+		{ID: 2, Place: sameProvince},
+		{ID: 3, Place: sameProvince},
+	}
+	for name, strategy := range purchase_orders.Strategies() {
+		t.Run("Should pick the closest warehouse with "+name, func(t *testing.T) {
+			assert.Equal(t, 2, strategy.Warehouse(destination, warehouses).ID)
+		})
+	}
+}
+
+func TestSameLocalityStrategy(t *testing.T) {
+	strategy := purchase_orders.Strategies()[purchase_orders.StrategySameLocality]
+
+	t.Run("Should pick the carrier of the destination locality", func(t *testing.T) {
+		carriers := []domain.AssignmentCarrier{
+			{ID: 1, Place: sameCountry},
+			{ID: 2, Place: destination, Load: 5},
+			{ID: 3, Place: sameProvince},
+		}
+		assert.Equal(t, 2, strategy.Carrier(destination, carriers).ID)
+	})
+	t.Run("Should treat a covered destination as the carrier locality", func(t *testing.T) {
+		carriers := []domain.AssignmentCarrier{
+			{ID: 1, Place: sameProvince},
+			{ID: 2, Place: otherLocality, Covers: true},
+		}
+		assert.Equal(t, 2, strategy.Carrier(destination, carriers).ID)
+	})
+}
+
+func TestRoundRobinStrategy(t *testing.T) {
+	t.Run("Should take turns among the closest carriers", func(t *testing.T) {
+		strategy := purchase_orders.Strategies()[purchase_orders.StrategyRoundRobin]
+		carriers := []domain.AssignmentCarrier{
+			{ID: 1, Place: sameProvince},
+			{ID: 2, Place: sameCountry},
+			{ID: 3, Place: sameProvince},
+		}
+
+		picked := []int{}
+		for i := 0; i < 3; i++ {
+			picked = append(picked, strategy.Carrier(destination, carriers).ID)
+		}
+		assert.Equal(t, []int{1, 3, 1}, picked)
+	})
+}
+
+func TestLeastLoadedStrategy(t *testing.T) {
+	strategy := purchase_orders.Strategies()[purchase_orders.StrategyLeastLoaded]
+
+	t.Run("Should pick the carrier with the fewest orders", func(t *testing.T) {
+		carriers := []domain.AssignmentCarrier{
+			{ID: 1, Place: destination, Load: 4},
+			{ID: 2, Place: sameCountry, Load: 1},
+		}
+		assert.Equal(t, 2, strategy.Carrier(destination, carriers).ID)
+	})
+	t.Run("Should pick the closest carrier on ties", func(t *testing.T) {
+		carriers := []domain.AssignmentCarrier{
+			{ID: 1, Place: sameCountry, Load: 1},
+			{ID: 2, Place: sameProvince, Load: 1},
+		}
+		assert.Equal(t, 2, strategy.Carrier(destination, carriers).ID)
+	})
+}
@@ -0,0 +1,94 @@
+package receipt
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
+)
+
+const (
+	LockSectionQuery       = "SELECT " + section.Occupied + ", s.maximum_capacity FROM sections s WHERE s.id = ? FOR UPDATE"
+	ProductDimensionsQuery = "SELECT height, lenght, width FROM products WHERE id = ?"
+	SaveBatchQuery         = "INSERT INTO product_batches (batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id) VALUES (?,?,?,?,?,?,?,?,?,?)"
+	SaveOrderQuery         = "INSERT INTO inbound_orders (order_date, order_number, employee_id, product_batch_id, warehouse_id) VALUES (?,?,?,?,?)"
+)
+
+// Repository encapsulates the storage of a receipt.
+type Repository interface {
+	Create(ctx context.Context, r domain.Receipt) (domain.Receipt, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// Create inserts the batch and the inbound order that references it, in one
+// transaction with their created events. The section row is locked first so
+// concurrent receipts cannot overfill it.
+func (r *repository) Create(ctx context.Context, receipt domain.Receipt) (domain.Receipt, error) {
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		b := receipt.ProductBatch
+		var occupied, maximum int
+		if err := tx.QueryRowContext(ctx, LockSectionQuery, b.SectionID).Scan(&occupied, &maximum); err != nil {
+			if err.Error() == "sql: no rows in result set" {
+				return ErrSectionNotFound
+			}
+			return err
+		}
+		var p domain.Product
+		if err := tx.QueryRowContext(ctx, ProductDimensionsQuery, b.ProductID).Scan(&p.Height, &p.Length, &p.Width); err != nil {
+			if err.Error() == "sql: no rows in result set" {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		volume := p.Volume()
+		if volume == 0 {
+			return ErrNoDimensions
+		}
+		if occupied+domain.Occupancy(b.CurrentQuantity, volume) > maximum {
+			return ErrCapacityExceeded
+		}
+
+		res, err := tx.ExecContext(ctx, SaveBatchQuery, b.BatchNumber, b.CurrentQuantity, b.CurrentTemperature, b.DueDate, b.InitialQuantity, b.ManufacturingDate, b.ManufacturingHour, b.MinimumTemperature, b.ProductID, b.SectionID)
+		if err != nil {
+			return err
+		}
+		batchID, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		receipt.ProductBatch.ID = int(batchID)
+		receipt.InboundOrder.ProductBatchID = int(batchID)
+
+		o := receipt.InboundOrder
+		res, err = tx.ExecContext(ctx, SaveOrderQuery, o.OrderDate, o.OrderNumber, o.EmployeeID, o.ProductBatchID, o.WarehouseID)
+		if err != nil {
+			return err
+		}
+		orderID, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		receipt.InboundOrder.ID = int(orderID)
+
+		if err := outbox.Write(ctx, tx, domain.EventProductBatchCreated, receipt.ProductBatch.ID, receipt.ProductBatch); err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.EventInboundOrderCreated, receipt.InboundOrder.ID, receipt.InboundOrder)
+	})
+	if err != nil {
+		return domain.Receipt{}, err
+	}
+	return receipt, nil
+}
@@ -0,0 +1,109 @@
+package receipt
+
+import (
+	"context"
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/inbound_order"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+)
+
+var (
+	ErrInvalidId         = errors.New("invalid id")
+	ErrAlreadyExists     = errors.New("inbound order already exists")
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+	ErrEmployeeNotFound  = errors.New("employee not found")
+	ErrProductNotFound   = errors.New("product not found")
+	ErrSectionNotFound   = errors.New("section not found")
+	ErrEmployeeWarehouse = errors.New("employee does not work in the warehouse")
+	ErrSectionWarehouse  = errors.New("section is not in the warehouse")
+	ErrCapacityExceeded  = errors.New("section does not have enough capacity")
+	ErrNoDimensions      = errors.New("product has no dimensions")
+	ErrTryAgain          = errors.New("error, try again")
+)
+
+type Service interface {
+	Create(ctx context.Context, warehouseID int, req domain.ReceiptRequest) (domain.Receipt, error)
+}
+
+type service struct {
+	repository          Repository
+	warehouseRepository warehouse.Repository
+	employeeRepository  employee.Repository
+	productRepository   product.Repository
+	sectionRepository   section.Repository
+	orderRepository     inbound_order.Repository
+}
+
+func NewService(r Repository, wr warehouse.Repository, er employee.Repository, pr product.Repository, sr section.Repository, ir inbound_order.Repository) Service {
+	return &service{
+		repository:          r,
+		warehouseRepository: wr,
+		employeeRepository:  er,
+		productRepository:   pr,
+		sectionRepository:   sr,
+		orderRepository:     ir,
+	}
+}
+
+// Create stores the product batch and its inbound order in one go once the
+// warehouse, employee, product and section are known to exist and agree on
+// the warehouse.
+func (s *service) Create(ctx context.Context, warehouseID int, req domain.ReceiptRequest) (domain.Receipt, error) {
+	if err := req.ProductBatch.Validate(); err != nil {
+		return domain.Receipt{}, err
+	}
+	if s.orderRepository.Exists(ctx, req.OrderNumber) {
+		return domain.Receipt{}, ErrAlreadyExists
+	}
+
+	if _, err := s.warehouseRepository.Get(ctx, warehouseID); err != nil {
+		if errors.Is(err, warehouse.ErrNotFound) {
+			return domain.Receipt{}, ErrWarehouseNotFound
+		}
+		return domain.Receipt{}, err
+	}
+	employeeDomain, err := s.employeeRepository.Get(ctx, req.EmployeeID)
+	if err != nil {
+		if errors.Is(err, employee.ErrNotFound) {
+			return domain.Receipt{}, ErrEmployeeNotFound
+		}
+		return domain.Receipt{}, err
+	}
+	if !s.productRepository.ExistsById(req.ProductBatch.ProductID) {
+		return domain.Receipt{}, ErrProductNotFound
+	}
+	if !s.sectionRepository.ExistsById(req.ProductBatch.SectionID) {
+		return domain.Receipt{}, ErrSectionNotFound
+	}
+	sectionDomain, err := s.sectionRepository.Get(ctx, req.ProductBatch.SectionID)
+	if err != nil {
+		return domain.Receipt{}, err
+	}
+
+	if employeeDomain.WarehouseID != warehouseID {
+		return domain.Receipt{}, ErrEmployeeWarehouse
+	}
+	if sectionDomain.WarehouseID != warehouseID {
+		return domain.Receipt{}, ErrSectionWarehouse
+	}
+
+	receipt := domain.Receipt{
+		ProductBatch: req.ProductBatch,
+		InboundOrder: domain.InboundOrders{
+			OrderDate:   req.OrderDate,
+			OrderNumber: req.OrderNumber,
+			EmployeeID:  req.EmployeeID,
+			WarehouseID: warehouseID,
+		},
+	}
+	if receipt.InboundOrder.OrderDate.IsZero() {
+		receipt.InboundOrder.OrderDate = datetime.Now()
+	}
+	return s.repository.Create(ctx, receipt)
+}
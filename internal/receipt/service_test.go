@@ -0,0 +1,154 @@
+package receipt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/receipt"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	employeemocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/employee"
+	ordermocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/inbound_order"
+	productmocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product"
+	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/receipt"
+	sectionmocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/section"
+	warehousemocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/warehouse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var receiptRequest = domain.ReceiptRequest{
+	OrderNumber: "INB100",
+	OrderDate:   datetime.MustParse("2023-07-05T14:00:00Z"),
+	EmployeeID:  1,
+	ProductBatch: domain.ProductBatch{
+		BatchNumber:       10,
+		CurrentQuantity:   20,
+		InitialQuantity:   20,
+		DueDate:           datetime.MustParse("2023-08-01"),
+		ManufacturingDate: datetime.MustParse("2023-07-01"),
+		ProductID:         1,
+		SectionID:         1,
+	},
+}
+
+type receiptMocks struct {
+	repository *mocks.ReceiptRepositoryMock
+	warehouse  *warehousemocks.WarehouseRepositoryMock
+	employee   *employeemocks.EmployeeRepositoryMock
+	product    *productmocks.ProductRepositoryMock
+	section    *sectionmocks.SectionRepositoryMock
+	order      *ordermocks.InboundOrderRepositoryMock
+}
+
+// valid makes every reference of receiptRequest exist in warehouse 1.
+func (m receiptMocks) valid() {
+	m.order.On("Exists", mock.Anything, "INB100").Return(false)
+	m.warehouse.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1}, nil)
+	m.employee.On("Get", mock.Anything, 1).Return(domain.Employee{ID: 1, WarehouseID: 1}, nil)
+	m.product.On("ExistsById", 1).Return(true)
+	m.section.On("ExistsById", 1).Return(true)
+	m.section.On("Get", 1).Return(domain.Section{ID: 1, WarehouseID: 1}, nil)
+}
+
+func TestCreateReceipt(t *testing.T) {
+	t.Run("Should store the batch and the inbound order together", func(t *testing.T) {
+		m, service := InitReceiptService(t)
+		m.valid()
+		expected := domain.Receipt{
+			ProductBatch: receiptRequest.ProductBatch,
+			InboundOrder: domain.InboundOrders{OrderDate: receiptRequest.OrderDate, OrderNumber: "INB100", EmployeeID: 1, WarehouseID: 1},
+		}
+		stored := expected
+		stored.ProductBatch.ID = 5
+		stored.InboundOrder.ID = 7
+		stored.InboundOrder.ProductBatchID = 5
+		m.repository.On("Create", mock.Anything, expected).Return(stored, nil)
+
+		result, err := service.Create(context.TODO(), 1, receiptRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, stored, result)
+	})
+
+	t.Run("Should return error when the order number already exists", func(t *testing.T) {
+		m, service := InitReceiptService(t)
+		m.order.On("Exists", mock.Anything, "INB100").Return(true)
+
+		_, err := service.Create(context.TODO(), 1, receiptRequest)
+
+		assert.ErrorIs(t, err, receipt.ErrAlreadyExists)
+	})
+
+	t.Run("Should return error when the warehouse does not exist", func(t *testing.T) {
+		m, service := InitReceiptService(t)
+		m.order.On("Exists", mock.Anything, "INB100").Return(false)
+		m.warehouse.On("Get", mock.Anything, 1).Return(domain.Warehouse{}, warehouse.ErrNotFound)
+
+		_, err := service.Create(context.TODO(), 1, receiptRequest)
+
+		assert.ErrorIs(t, err, receipt.ErrWarehouseNotFound)
+	})
+
+	t.Run("Should return error when the employee does not exist", func(t *testing.T) {
+		m, service := InitReceiptService(t)
+		m.order.On("Exists", mock.Anything, "INB100").Return(false)
+		m.warehouse.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1}, nil)
+		m.employee.On("Get", mock.Anything, 1).Return(domain.Employee{}, employee.ErrNotFound)
+
+		_, err := service.Create(context.TODO(), 1, receiptRequest)
+
+		assert.ErrorIs(t, err, receipt.ErrEmployeeNotFound)
+	})
+
+	t.Run("Should return error when the section is in another warehouse", func(t *testing.T) {
+		m, service := InitReceiptService(t)
+		m.order.On("Exists", mock.Anything, "INB100").Return(false)
+		m.warehouse.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1}, nil)
+		m.employee.On("Get", mock.Anything, 1).Return(domain.Employee{ID: 1, WarehouseID: 1}, nil)
+		m.product.On("ExistsById", 1).Return(true)
+		m.section.On("ExistsById", 1).Return(true)
+		m.section.On("Get", 1).Return(domain.Section{ID: 1, WarehouseID: 2}, nil)
+
+		_, err := service.Create(context.TODO(), 1, receiptRequest)
+
+		assert.ErrorIs(t, err, receipt.ErrSectionWarehouse)
+		m.repository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return error when the section is full", func(t *testing.T) {
+		m, service := InitReceiptService(t)
+		m.valid()
+		m.repository.On("Create", mock.Anything, mock.Anything).Return(domain.Receipt{}, receipt.ErrCapacityExceeded)
+
+		_, err := service.Create(context.TODO(), 1, receiptRequest)
+
+		assert.ErrorIs(t, err, receipt.ErrCapacityExceeded)
+	})
+
+	t.Run("Should return error when a batch date is missing", func(t *testing.T) {
+		_, service := InitReceiptService(t)
+		req := receiptRequest
+		req.ProductBatch.DueDate = datetime.Time{}
+
+		_, err := service.Create(context.TODO(), 1, req)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidManufacturingDate)
+	})
+}
+
+func InitReceiptService(t *testing.T) (receiptMocks, receipt.Service) {
+	t.Helper()
+	m := receiptMocks{
+		repository: &mocks.ReceiptRepositoryMock{},
+		warehouse:  &warehousemocks.WarehouseRepositoryMock{},
+		employee:   &employeemocks.EmployeeRepositoryMock{},
+		product:    &productmocks.ProductRepositoryMock{},
+		section:    &sectionmocks.SectionRepositoryMock{},
+		order:      &ordermocks.InboundOrderRepositoryMock{},
+	}
+	service := receipt.NewService(m.repository, m.warehouse, m.employee, m.product, m.section, m.order)
+	return m, service
+}
@@ -5,17 +5,35 @@ import (
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
 )
 
 const (
 	SectionExists                   = "SELECT id FROM sections WHERE id=?"
 	SectionProductsReports          = "SELECT count(pb.id) as `products_count`, pb.section_id, s.section_number FROM product_batches pb JOIN sections s ON pb.section_id = s.id GROUP BY pb.section_id"
 	SectionProductsReportsBySection = "SELECT count(pb.id) as `products_count`, pb.section_id, s.section_number FROM product_batches pb JOIN sections s ON pb.section_id = s.id WHERE pb.section_id = ? GROUP BY pb.section_id"
+	SectionsByWarehouse             = "SELECT " + columns + " FROM sections s WHERE s.warehouse_id = ? ORDER BY s.section_number, s.id"
+	// columns are the columns of a section s, its current capacity being
+	// the one its batches take.
+	columns = "s.id, s.section_number, s.current_temperature, s.minimum_temperature, " + Occupied + ", s.minimum_capacity, s.maximum_capacity, s.warehouse_id, s.id_product_type"
+	// Occupied is the capacity taken in section s by the batches it stores,
+	// each batch rounded up to whole capacity units. Every check of the room
+	// left in a section uses it, so receipts, transfers and fits agree on it.
+	Occupied = "COALESCE((SELECT SUM(CEIL(pb.current_quantity * " + unitVolume + ")) FROM product_batches pb JOIN products p ON pb.product_id = p.id WHERE pb.section_id = s.id), 0)"
+	// unitVolume is the volume of one unit of product p. Its dimensions are
+	// cast to decimals so that it matches domain.Occupancy without the error
+	// of float arithmetic.
+	unitVolume = "CAST(p.height AS DECIMAL(12,6)) * CAST(p.lenght AS DECIMAL(12,6)) * CAST(p.width AS DECIMAL(12,6))"
 )
 
 // Repository encapsulates the storage of a section.
 type Repository interface {
 	GetAll(ctx context.Context) ([]domain.Section, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Section, error)
+	GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Section, error)
+	ForEach(ctx context.Context, fn func(domain.Section) error) error
 	Get(ctx context.Context, id int) (domain.Section, error)
 	Exists(ctx context.Context, sectionNumber int) bool
 	Save(ctx context.Context, s domain.Section) (int, error)
@@ -24,6 +42,7 @@ type Repository interface {
 	ExistsById(sectionID int) bool
 	SectionProductsReportsBySection(id int) (domain.ProductBySection, error)
 	SectionProductsReports() ([]domain.ProductBySection, error)
+	GetByWarehouse(ctx context.Context, warehouseID int) ([]domain.Section, error)
 }
 
 type repository struct {
@@ -37,7 +56,7 @@ func NewRepository(db *sql.DB) Repository {
 }
 
 func (r *repository) GetAll(ctx context.Context) ([]domain.Section, error) {
-	query := "SELECT * FROM sections;"
+	query := "SELECT " + columns + " FROM sections s;"
 	rows, err := r.db.Query(query)
 	if err != nil {
 		return nil, err
@@ -46,20 +65,86 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Section, error) {
 	var sections []domain.Section
 
 	for rows.Next() {
-		s := domain.Section{}
-		_ = rows.Scan(&s.ID, &s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID)
+		s, _ := scanSection(rows)
 		sections = append(sections, s)
 	}
 
 	return sections, nil
 }
 
-func (r *repository) Get(ctx context.Context, id int) (domain.Section, error) {
-	query := "SELECT * FROM sections WHERE id=?;"
-	row := r.db.QueryRow(query, id)
+// GetByIDs returns the sections with the given ids.
+func (r *repository) GetByIDs(ctx context.Context, ids []int) ([]domain.Section, error) {
+	return r.getIn(ctx, "id", ids)
+}
+
+// GetByWarehouses returns the sections of the given warehouses.
+func (r *repository) GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Section, error) {
+	return r.getIn(ctx, "warehouse_id", warehouseIDs)
+}
+
+// getIn returns the sections whose column holds one of values, in one query.
+func (r *repository) getIn(ctx context.Context, column string, values []int) ([]domain.Section, error) {
+	sections := []domain.Section{}
+	if len(values) == 0 {
+		return sections, nil
+	}
+	in, args := mysqlutil.In(values)
+	rows, err := r.db.QueryContext(ctx, "SELECT "+columns+" FROM sections s WHERE s."+column+" IN "+in+" ORDER BY s.id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s, err := scanSection(rows)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, s)
+	}
+	return sections, rows.Err()
+}
+
+// ForEach calls fn for every section while reading them from the database,
+// so the whole table is never held in memory.
+func (r *repository) ForEach(ctx context.Context, fn func(domain.Section) error) error {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+columns+" FROM sections s;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s, err := scanSection(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSection reads a section selected with columns.
+func scanSection(row scanner) (domain.Section, error) {
 	s := domain.Section{}
 	err := row.Scan(&s.ID, &s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID)
+	return s, err
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.Section, error) {
+	query := "SELECT " + columns + " FROM sections s WHERE s.id=?;"
+	row := r.db.QueryRow(query, id)
+	s, err := scanSection(row)
 	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return domain.Section{}, domain.ErrNotFound
+		}
 		return domain.Section{}, err
 	}
 
@@ -74,13 +159,13 @@ func (r *repository) Exists(ctx context.Context, sectionNumber int) bool {
 }
 
 func (r *repository) Save(ctx context.Context, s domain.Section) (int, error) {
-	query := "INSERT INTO sections (section_number, current_temperature, minimum_temperature, current_capacity, minimum_capacity, maximum_capacity, warehouse_id, id_product_type) VALUES (?, ?, ?, ?, ?, ?, ?, ?);"
+	query := "INSERT INTO sections (section_number, current_temperature, minimum_temperature, minimum_capacity, maximum_capacity, warehouse_id, id_product_type) VALUES (?, ?, ?, ?, ?, ?, ?);"
 	stmt, err := r.db.Prepare(query)
 	if err != nil {
 		return 0, err
 	}
 
-	res, err := stmt.Exec(&s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID)
+	res, err := stmt.Exec(&s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID)
 	if err != nil {
 		return 0, err
 	}
@@ -93,27 +178,26 @@ func (r *repository) Save(ctx context.Context, s domain.Section) (int, error) {
 	return int(id), nil
 }
 
+// Update stores the section and its section.updated event in one
+// transaction.
 func (r *repository) Update(ctx context.Context, s domain.Section) error {
-	query := "UPDATE sections SET section_number=?, current_temperature=?, minimum_temperature=?, current_capacity=?, minimum_capacity=?, maximum_capacity=?, warehouse_id=?, id_product_type=? WHERE id=?;"
-	stmt, err := r.db.Prepare(query)
-	if err != nil {
-		return err
-	}
-
-	res, err := stmt.Exec(&s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID, &s.ID)
-	if err != nil {
-		return err
-	}
+	query := "UPDATE sections SET section_number=?, current_temperature=?, minimum_temperature=?, minimum_capacity=?, maximum_capacity=?, warehouse_id=?, id_product_type=? WHERE id=?;"
+	return sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, s.SectionNumber, s.CurrentTemperature, s.MinimumTemperature, s.MinimumCapacity, s.MaximumCapacity, s.WarehouseID, s.ProductTypeID, s.ID)
+		if err != nil {
+			return err
+		}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rowsAffected == 0 {
-		return ErrNotFound
-	}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return ErrNotFound
+		}
 
-	return nil
+		return outbox.Write(ctx, tx, domain.EventSectionUpdated, s.ID, s)
+	})
 }
 
 func (r *repository) Delete(ctx context.Context, id int) error {
@@ -172,3 +256,21 @@ func (r *repository) SectionProductsReports() ([]domain.ProductBySection, error)
 	}
 	return productsBySection, nil
 }
+
+func (r *repository) GetByWarehouse(ctx context.Context, warehouseID int) ([]domain.Section, error) {
+	rows, err := r.db.QueryContext(ctx, SectionsByWarehouse, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sections := []domain.Section{}
+	for rows.Next() {
+		s, err := scanSection(rows)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, s)
+	}
+	return sections, rows.Err()
+}
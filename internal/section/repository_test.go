@@ -11,6 +11,7 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
 	productbatch "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_batch"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -47,11 +48,11 @@ var (
 		ProductID:          1,
 		SectionID:          1,
 		CurrentQuantity:    1,
-		DueDate:            "2021-01-01",
+		DueDate:            datetime.MustParse("2021-01-01"),
 		BatchNumber:        1,
 		CurrentTemperature: 1,
 		InitialQuantity:    1,
-		ManufacturingDate:  "2021-01-01",
+		ManufacturingDate:  datetime.MustParse("2021-01-01"),
 		ManufacturingHour:  1,
 		MinimumTemperature: 1,
 	}
@@ -150,7 +151,7 @@ func TestUpdateSectionRepository(t *testing.T) {
 		sectionExpected.SectionNumber = 2
 		sectionExpected.CurrentTemperature = 2
 		sectionExpected.MinimumTemperature = 2
-		sectionExpected.CurrentCapacity = 2
+		sectionExpected.CurrentCapacity = 0
 		sectionExpected.MinimumCapacity = 2
 		sectionExpected.MaximumCapacity = 2
 		sectionExpected.WarehouseID = 2
@@ -231,7 +232,7 @@ func TestSectionProductsReportsBySectionRepository(t *testing.T) {
 
 		productBatchExpected.ProductID = newProductId
 		productBatchExpected.SectionID = newSectionId
-		_, err = repositoryProductsBatch.Save(productBatchExpected)
+		_, err = repositoryProductsBatch.Save(ctx, productBatchExpected)
 		assert.NoError(t, err)
 
 		section, err := repositorySection.SectionProductsReportsBySection(newSectionId)
@@ -247,6 +248,41 @@ func TestSectionProductsReportsBySectionRepository(t *testing.T) {
 
 }
 
+func TestCurrentCapacitySectionRepository(t *testing.T) {
+	t.Run("Should read the capacity taken by the batches of the section", func(t *testing.T) {
+		repositorySection := section.NewRepository(db)
+		repositoryProducts := product.NewRepository(db)
+		repositoryProductsBatch := productbatch.NewRepository(db, productbatch.Querys{})
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		newSectionId, err := repositorySection.Save(ctx, domain.Section{SectionNumber: 9001, CurrentCapacity: 90, MaximumCapacity: 100, WarehouseID: 1, ProductTypeID: 1})
+		assert.NoError(t, err)
+
+		stored, err := repositorySection.Get(ctx, newSectionId)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, stored.CurrentCapacity)
+
+		box := productExpected
+		box.ProductCode = "OCCUPIED-1"
+		box.Height, box.Length, box.Width = 2.5, 4, 1.2
+		newProductId, err := repositoryProducts.Save(ctx, box)
+		assert.NoError(t, err)
+
+		batch := productBatchExpected
+		batch.BatchNumber = 9001
+		batch.ProductID = newProductId
+		batch.SectionID = newSectionId
+		batch.CurrentQuantity = 3
+		_, err = repositoryProductsBatch.Save(ctx, batch)
+		assert.NoError(t, err)
+
+		stored, err = repositorySection.Get(ctx, newSectionId)
+		assert.NoError(t, err)
+		assert.Equal(t, 36, stored.CurrentCapacity)
+	})
+}
+
 func TestSectionProductsReports(t *testing.T) {
 	t.Run("Should return products by section", func(t *testing.T) {
 		repositorySection := section.NewRepository(db)
@@ -263,7 +299,7 @@ func TestSectionProductsReports(t *testing.T) {
 
 		productBatchExpected.ProductID = newProductId
 		productBatchExpected.SectionID = newSectionId
-		_, err = repositoryProductsBatch.Save(productBatchExpected)
+		_, err = repositoryProductsBatch.Save(ctx, productBatchExpected)
 		assert.NoError(t, err)
 
 		sections, err := repositorySection.SectionProductsReports()
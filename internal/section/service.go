@@ -5,37 +5,62 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mergepatch"
 )
 
 // Errors
 var (
-	ErrNotFound = errors.New("section not found")
+	ErrNotFound        = errors.New("section not found")
+	ErrInvalidBody     = errors.New("invalid body")
+	ErrProductNotFound = errors.New("product not found")
+	ErrNoDimensions    = errors.New("product has no dimensions")
+	ErrProductType     = errors.New("product type does not match the section")
 )
 
 type Service interface {
 	Save(ctx context.Context, s domain.Section) (int, error)
 	Delete(ctx context.Context, id int) error
 	GetAll(ctx context.Context) ([]domain.Section, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Section, error)
+	GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Section, error)
+	ForEach(ctx context.Context, fn func(domain.Section) error) error
 	Get(ctx context.Context, id int) (domain.Section, error)
-	Update(ctx context.Context, s domain.Section) error
+	Update(ctx context.Context, id int, p domain.Patch) (domain.Section, error)
 	ExistsById(productID int) error
 	ReportProductsById(ctx context.Context, id int) (domain.ProductBySection, error)
 	ReportProducts(ctx context.Context) ([]domain.ProductBySection, error)
+	Fit(ctx context.Context, id int, productID int) (domain.SectionFit, error)
 }
 
 type serviceSection struct {
-	repository Repository
+	repository        Repository
+	productRepository product.Repository
 }
 
-func NewService(r Repository) Service {
+func NewService(r Repository, pr product.Repository) Service {
 	return &serviceSection{
-		repository: r,
+		repository:        r,
+		productRepository: pr,
 	}
 }
 func (s *serviceSection) GetAll(ctx context.Context) ([]domain.Section, error) {
 	sections, err := s.repository.GetAll(ctx)
 	return sections, err
 }
+
+// GetByIDs returns the sections with the given ids.
+func (s *serviceSection) GetByIDs(ctx context.Context, ids []int) ([]domain.Section, error) {
+	return s.repository.GetByIDs(ctx, ids)
+}
+
+// GetByWarehouses returns the sections of the given warehouses.
+func (s *serviceSection) GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Section, error) {
+	return s.repository.GetByWarehouses(ctx, warehouseIDs)
+}
+func (s *serviceSection) ForEach(ctx context.Context, fn func(domain.Section) error) error {
+	return s.repository.ForEach(ctx, fn)
+}
 func (s *serviceSection) Get(ctx context.Context, id int) (domain.Section, error) {
 	section, err := s.repository.Get(ctx, id)
 	return section, err
@@ -52,9 +77,33 @@ func (s *serviceSection) Delete(ctx context.Context, sectionNumber int) error {
 	err := s.repository.Delete(ctx, sectionNumber)
 	return err
 }
-func (s *serviceSection) Update(ctx context.Context, sect domain.Section) error {
-	err := s.repository.Update(ctx, sect)
-	return err
+
+// Update applies a merge patch to the section. Its temperatures and
+// capacities can be set to zero, but its number, warehouse and product type
+// cannot be cleared. Its current capacity stays the one its batches take.
+func (s *serviceSection) Update(ctx context.Context, id int, p domain.Patch) (domain.Section, error) {
+	sect, err := s.repository.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Section{}, ErrNotFound
+		}
+		return domain.Section{}, err
+	}
+
+	occupied := sect.CurrentCapacity
+	if err := mergepatch.Apply(&sect, p.Document); err != nil {
+		return domain.Section{}, ErrInvalidBody
+	}
+	sect.ID = id
+	sect.CurrentCapacity = occupied
+	if sect.SectionNumber == 0 || sect.WarehouseID == 0 || sect.ProductTypeID == 0 {
+		return domain.Section{}, ErrInvalidBody
+	}
+
+	if err := s.repository.Update(ctx, sect); err != nil {
+		return domain.Section{}, err
+	}
+	return sect, nil
 }
 
 func (s *serviceSection) ExistsById(productID int) error {
@@ -73,3 +122,40 @@ func (s *serviceSection) ReportProductsById(ctx context.Context, id int) (domain
 func (s *serviceSection) ReportProducts(ctx context.Context) ([]domain.ProductBySection, error) {
 	return s.repository.SectionProductsReports()
 }
+
+// Fit returns how many units of the product fit into the capacity the
+// batches stored in the section leave. The product must have all its
+// dimensions and be of the product type the section stores.
+func (s *serviceSection) Fit(ctx context.Context, id int, productID int) (domain.SectionFit, error) {
+	sect, err := s.repository.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.SectionFit{}, ErrNotFound
+		}
+		return domain.SectionFit{}, err
+	}
+	p, err := s.productRepository.Get(ctx, productID)
+	if err != nil {
+		if errors.Is(err, product.ErrNotFound) {
+			return domain.SectionFit{}, ErrProductNotFound
+		}
+		return domain.SectionFit{}, err
+	}
+	if p.ProductTypeID != sect.ProductTypeID {
+		return domain.SectionFit{}, ErrProductType
+	}
+	volume := p.Volume()
+	if volume == 0 {
+		return domain.SectionFit{}, ErrNoDimensions
+	}
+
+	remaining := sect.RemainingCapacity()
+	return domain.SectionFit{
+		SectionID:         sect.ID,
+		ProductID:         p.ID,
+		CurrentCapacity:   sect.CurrentCapacity,
+		RemainingCapacity: remaining,
+		ProductVolume:     volume,
+		Units:             domain.FittingUnits(remaining, volume),
+	}, nil
+}
@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	productMocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/section"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -162,7 +164,7 @@ func TestDelete(t *testing.T) {
 }
 
 func TestUpdate(t *testing.T) {
-	section := domain.Section{
+	stored := domain.Section{
 		ID:                 1,
 		SectionNumber:      1,
 		CurrentTemperature: 10,
@@ -174,18 +176,54 @@ func TestUpdate(t *testing.T) {
 		ProductTypeID:      1,
 	}
 	t.Run("should update a section", func(t *testing.T) {
+		expected := stored
+		expected.MinimumTemperature = 0
 		mockRepository, service := InitServerWithWarehousesRepository(t)
-		mockRepository.On("Update", mock.Anything, mock.Anything).Return(nil)
-		err := service.Update(context.Background(), section)
+		mockRepository.On("Get", 1).Return(stored, nil)
+		mockRepository.On("Update", mock.Anything, expected).Return(nil)
+		updated, err := service.Update(context.Background(), 1, domain.Patch{Document: []byte(`{"minimum_temperature":0}`)})
 		assert.NoError(t, err)
+		assert.Equal(t, expected, updated)
+	})
+
+	t.Run("should keep the capacity its batches take", func(t *testing.T) {
+		mockRepository, service := InitServerWithWarehousesRepository(t)
+		mockRepository.On("Get", 1).Return(stored, nil)
+		mockRepository.On("Update", mock.Anything, stored).Return(nil)
+		updated, err := service.Update(context.Background(), 1, domain.Patch{Document: []byte(`{"current_capacity":0}`)})
+		assert.NoError(t, err)
+		assert.Equal(t, stored, updated)
 	})
 
 	t.Run("should not update a section", func(t *testing.T) {
 		mockRepository, service := InitServerWithWarehousesRepository(t)
+		mockRepository.On("Get", 1).Return(stored, nil)
 		mockRepository.On("Update", mock.Anything, mock.Anything).Return(errors.New("error"))
-		err := service.Update(context.Background(), section)
+		_, err := service.Update(context.Background(), 1, domain.Patch{Document: []byte(`{}`)})
 		assert.Error(t, err)
 	})
+
+	t.Run("should not update a section that does not exist", func(t *testing.T) {
+		mockRepository, service := InitServerWithWarehousesRepository(t)
+		mockRepository.On("Get", 1).Return(domain.Section{}, domain.ErrNotFound)
+		_, err := service.Update(context.Background(), 1, domain.Patch{Document: []byte(`{}`)})
+		assert.ErrorIs(t, err, section.ErrNotFound)
+	})
+
+	t.Run("should not clear a required field", func(t *testing.T) {
+		mockRepository, service := InitServerWithWarehousesRepository(t)
+		mockRepository.On("Get", 1).Return(stored, nil)
+		_, err := service.Update(context.Background(), 1, domain.Patch{Document: []byte(`{"product_type_id":null}`)})
+		assert.ErrorIs(t, err, section.ErrInvalidBody)
+		mockRepository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not update a field with a value of the wrong type", func(t *testing.T) {
+		mockRepository, service := InitServerWithWarehousesRepository(t)
+		mockRepository.On("Get", 1).Return(stored, nil)
+		_, err := service.Update(context.Background(), 1, domain.Patch{Document: []byte(`{"maximum_capacity":"full"}`)})
+		assert.ErrorIs(t, err, section.ErrInvalidBody)
+	})
 }
 
 func TestExistsById(t *testing.T) {
@@ -262,9 +300,87 @@ func TestReportProducts(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+func TestFit(t *testing.T) {
+	stored := domain.Section{ID: 1, CurrentCapacity: 40, MaximumCapacity: 100, ProductTypeID: 2}
+	box := domain.Product{ID: 7, Height: 2.5, Length: 4, Width: 1.2, ProductTypeID: 2}
+
+	t.Run("should count the units that fit into the capacity its batches leave", func(t *testing.T) {
+		repository, products, service := InitServiceWithProducts(t)
+		repository.On("Get", 1).Return(stored, nil)
+		products.On("Get", 7).Return(box, nil)
+
+		fit, err := service.Fit(context.Background(), 1, 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SectionFit{SectionID: 1, ProductID: 7, CurrentCapacity: 40, RemainingCapacity: 60, ProductVolume: 12, Units: 5}, fit)
+	})
+
+	t.Run("should fit nothing into a full section", func(t *testing.T) {
+		repository, products, service := InitServiceWithProducts(t)
+		full := stored
+		full.CurrentCapacity = 120
+		repository.On("Get", 1).Return(full, nil)
+		products.On("Get", 7).Return(box, nil)
+
+		fit, err := service.Fit(context.Background(), 1, 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, fit.RemainingCapacity)
+		assert.Equal(t, 0, fit.Units)
+	})
+
+	t.Run("should not fit a product without dimensions", func(t *testing.T) {
+		repository, products, service := InitServiceWithProducts(t)
+		flat := box
+		flat.Width = 0
+		repository.On("Get", 1).Return(stored, nil)
+		products.On("Get", 7).Return(flat, nil)
+
+		_, err := service.Fit(context.Background(), 1, 7)
+
+		assert.ErrorIs(t, err, section.ErrNoDimensions)
+	})
+
+	t.Run("should not fit a product of another type", func(t *testing.T) {
+		repository, products, service := InitServiceWithProducts(t)
+		other := box
+		other.ProductTypeID = 3
+		repository.On("Get", 1).Return(stored, nil)
+		products.On("Get", 7).Return(other, nil)
+
+		_, err := service.Fit(context.Background(), 1, 7)
+
+		assert.ErrorIs(t, err, section.ErrProductType)
+	})
+
+	t.Run("should return an error when the section or product is not found", func(t *testing.T) {
+		repository, _, service := InitServiceWithProducts(t)
+		repository.On("Get", 1).Return(domain.Section{}, domain.ErrNotFound)
+
+		_, err := service.Fit(context.Background(), 1, 7)
+
+		assert.ErrorIs(t, err, section.ErrNotFound)
+
+		repository, products, service := InitServiceWithProducts(t)
+		repository.On("Get", 1).Return(stored, nil)
+		products.On("Get", 7).Return(domain.Product{}, product.ErrNotFound)
+
+		_, err = service.Fit(context.Background(), 1, 7)
+
+		assert.ErrorIs(t, err, section.ErrProductNotFound)
+	})
+}
+
 func InitServerWithWarehousesRepository(t *testing.T) (*mocks.SectionRepositoryMock, section.Service) {
 	t.Helper()
-	mockRepository := &mocks.SectionRepositoryMock{}
-	mockService := section.NewService(mockRepository)
+	mockRepository, _, mockService := InitServiceWithProducts(t)
 	return mockRepository, mockService
 }
+
+func InitServiceWithProducts(t *testing.T) (*mocks.SectionRepositoryMock, *productMocks.ProductRepositoryMock, section.Service) {
+	t.Helper()
+	mockRepository := &mocks.SectionRepositoryMock{}
+	mockProducts := &productMocks.ProductRepositoryMock{}
+	mockService := section.NewService(mockRepository, mockProducts)
+	return mockRepository, mockProducts, mockService
+}
@@ -5,15 +5,23 @@ import (
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
 )
 
 type Repository interface {
 	GetAll(ctx context.Context) ([]domain.Seller, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Seller, error)
+	GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Seller, error)
 	Get(ctx context.Context, id int) (domain.Seller, error)
 	Exists(ctx context.Context, cid int) bool
 	Save(ctx context.Context, s domain.Seller) (int, error)
 	Update(ctx context.Context, s domain.Seller) error
 	Delete(ctx context.Context, id int) error
+	Dashboard(ctx context.Context, id int, from, to datetime.Time) (domain.SellerDashboard, error)
 }
 
 type repository struct {
@@ -37,18 +45,51 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Seller, error) {
 
 	for rows.Next() {
 		s := domain.Seller{}
-		_ = rows.Scan(&s.ID, &s.CID, &s.CompanyName, &s.Address, &s.Telephone, &s.LocalityId)
+		_ = rows.Scan(&s.ID, &s.CID, &s.CompanyName, &s.Address, &s.Telephone, &s.LocalityId, &s.Version)
 		sellers = append(sellers, s)
 	}
 
 	return sellers, nil
 }
 
+// GetByIDs returns the sellers with the given ids.
+func (r *repository) GetByIDs(ctx context.Context, ids []int) ([]domain.Seller, error) {
+	return r.getIn(ctx, "id", ids)
+}
+
+// GetByLocalities returns the sellers of the given localities.
+func (r *repository) GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Seller, error) {
+	return r.getIn(ctx, "locality_id", localityIDs)
+}
+
+// getIn returns the sellers whose column holds one of values, in one query.
+func (r *repository) getIn(ctx context.Context, column string, values []int) ([]domain.Seller, error) {
+	sellers := []domain.Seller{}
+	if len(values) == 0 {
+		return sellers, nil
+	}
+	in, args := mysqlutil.In(values)
+	rows, err := r.db.QueryContext(ctx, "SELECT * FROM sellers WHERE "+column+" IN "+in+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s := domain.Seller{}
+		if err := rows.Scan(&s.ID, &s.CID, &s.CompanyName, &s.Address, &s.Telephone, &s.LocalityId, &s.Version); err != nil {
+			return nil, err
+		}
+		sellers = append(sellers, s)
+	}
+	return sellers, rows.Err()
+}
+
 func (r *repository) Get(ctx context.Context, id int) (domain.Seller, error) {
 	query := "SELECT * FROM sellers WHERE id=?;"
 	row := r.db.QueryRow(query, id)
 	s := domain.Seller{}
-	err := row.Scan(&s.ID, &s.CID, &s.CompanyName, &s.Address, &s.Telephone, &s.LocalityId)
+	err := row.Scan(&s.ID, &s.CID, &s.CompanyName, &s.Address, &s.Telephone, &s.LocalityId, &s.Version)
 	if err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			return domain.Seller{}, ErrNotFound
@@ -66,66 +107,162 @@ func (r *repository) Exists(ctx context.Context, cid int) bool {
 	return err == nil
 }
 
+// Save inserts the seller and its seller.created event in one transaction.
 func (r *repository) Save(ctx context.Context, s domain.Seller) (int, error) {
 	query := "INSERT INTO sellers (cid, company_name, address, telephone, locality_id) VALUES (?, ?, ?, ?, ?)"
-	stmt, err := r.db.Prepare(query)
-	if err != nil {
-		return 0, err
-	}
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, s.CID, s.CompanyName, s.Address, s.Telephone, s.LocalityId)
+		if err != nil {
+			return err
+		}
 
-	res, err := stmt.Exec(s.CID, s.CompanyName, s.Address, s.Telephone, s.LocalityId)
-	if err != nil {
-		return 0, err
-	}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		s.ID = int(id)
 
-	id, err := res.LastInsertId()
+		return outbox.Write(ctx, tx, domain.EventSellerCreated, s.ID, s)
+	})
 	if err != nil {
 		return 0, err
 	}
 
-	return int(id), nil
+	return s.ID, nil
 }
 
+// Update stores the seller and its seller.updated event in one transaction.
+// The seller is only stored if it is still at s.Version, and its version is
+// bumped; otherwise it returns ErrVersionMismatch.
 func (r *repository) Update(ctx context.Context, s domain.Seller) error {
-	query := "UPDATE sellers SET cid=?, company_name=?, address=?, telephone=?, locality_id=? WHERE id=?"
-	stmt, err := r.db.Prepare(query)
-	if err != nil {
-		return err
-	}
+	query := "UPDATE sellers SET cid=?, company_name=?, address=?, telephone=?, locality_id=?, version=version+1 WHERE id=? AND version=?"
+	return sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, s.CID, s.CompanyName, s.Address, s.Telephone, s.LocalityId, s.ID, s.Version)
+		if err != nil {
+			return err
+		}
+		affect, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affect < 1 {
+			var version int
+			if err := tx.QueryRowContext(ctx, "SELECT version FROM sellers WHERE id=?", s.ID).Scan(&version); err != nil {
+				if err.Error() == "sql: no rows in result set" {
+					return ErrNotFound
+				}
+				return err
+			}
+			return ErrVersionMismatch
+		}
 
-	res, err := stmt.Exec(s.CID, s.CompanyName, s.Address, s.Telephone, s.LocalityId, s.ID)
-	if err != nil {
-		return err
+		return outbox.Write(ctx, tx, domain.EventSellerUpdated, s.ID, s)
+	})
+}
+
+// Delete removes the seller and stores its seller.deleted event in one
+// transaction.
+func (r *repository) Delete(ctx context.Context, id int) error {
+	query := "DELETE FROM sellers WHERE id=?"
+	return sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return err
+		}
+
+		affect, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affect < 1 {
+			return ErrNotFound
+		}
+
+		return outbox.Write(ctx, tx, domain.EventSellerDeleted, id, domain.DeletedEvent{ID: id})
+	})
+}
+
+func (r *repository) Dashboard(ctx context.Context, id int, from, to datetime.Time) (domain.SellerDashboard, error) {
+	dashboard := domain.SellerDashboard{
+		SellerID:       id,
+		From:           from,
+		To:             to,
+		ProductsByType: []domain.SellerProductTypeCount{},
+		StockValue:     []money.Money{},
+		Revenue:        []money.Money{},
 	}
 
-	_, err = res.RowsAffected()
+	query := "SELECT pt.id, pt.description, COUNT(p.id) FROM products p JOIN product_types pt ON p.id_product_type = pt.id WHERE p.id_seller=? GROUP BY pt.id, pt.description ORDER BY pt.id"
+	rows, err := r.db.QueryContext(ctx, query, id)
 	if err != nil {
-		return err
+		return domain.SellerDashboard{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		t := domain.SellerProductTypeCount{}
+		if err := rows.Scan(&t.ProductTypeID, &t.Description, &t.ProductsCount); err != nil {
+			return domain.SellerDashboard{}, err
+		}
+		dashboard.ProductsByType = append(dashboard.ProductsByType, t)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.SellerDashboard{}, err
 	}
 
-	return nil
-}
+	query = "SELECT COALESCE(SUM(pb.current_quantity), 0) FROM product_batches pb JOIN products p ON pb.product_id = p.id WHERE p.id_seller=?"
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&dashboard.TotalStock); err != nil {
+		return domain.SellerDashboard{}, err
+	}
 
-func (r *repository) Delete(ctx context.Context, id int) error {
-	query := "DELETE FROM sellers WHERE id=?"
-	stmt, err := r.db.Prepare(query)
+	query = "SELECT pr.currency, SUM(pb.current_quantity * pr.purchase_price) FROM product_batches pb JOIN products p ON pb.product_id = p.id " +
+		"JOIN product_records pr ON pr.id = (SELECT latest.id FROM product_records latest WHERE latest.product_id = p.id ORDER BY latest.last_update_date DESC, latest.id DESC LIMIT 1) " +
+		"WHERE p.id_seller=? GROUP BY pr.currency ORDER BY pr.currency"
+	dashboard.StockValue, err = r.amountsByCurrency(ctx, query, id)
 	if err != nil {
-		return err
+		return domain.SellerDashboard{}, err
 	}
 
-	res, err := stmt.Exec(id)
+	query = "SELECT pr.currency, COALESCE(SUM(od.quantity), 0), COALESCE(SUM(od.quantity * pr.sale_price), 0) FROM order_details od " +
+		"JOIN purchase_orders po ON od.purchase_order_id = po.id JOIN product_records pr ON od.product_record_id = pr.id JOIN products p ON pr.product_id = p.id " +
+		"WHERE p.id_seller=? AND (? IS NULL OR po.order_date >= ?) AND (? IS NULL OR po.order_date < ?) GROUP BY pr.currency ORDER BY pr.currency"
+	rows, err = r.db.QueryContext(ctx, query, id, from, from, to, to)
 	if err != nil {
-		return err
+		return domain.SellerDashboard{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var currency string
+		var units int
+		var revenue money.Money
+		if err := rows.Scan(&currency, &units, &revenue); err != nil {
+			return domain.SellerDashboard{}, err
+		}
+		dashboard.UnitsSold += units
+		dashboard.Revenue = append(dashboard.Revenue, revenue.In(currency))
 	}
+	if err := rows.Err(); err != nil {
+		return domain.SellerDashboard{}, err
+	}
+
+	return dashboard, nil
+}
 
-	affect, err := res.RowsAffected()
+func (r *repository) amountsByCurrency(ctx context.Context, query string, args ...interface{}) ([]money.Money, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer rows.Close()
 
-	if affect < 1 {
-		return ErrNotFound
+	amounts := []money.Money{}
+	for rows.Next() {
+		var currency string
+		var amount money.Money
+		if err := rows.Scan(&currency, &amount); err != nil {
+			return nil, err
+		}
+		amounts = append(amounts, amount.In(currency))
 	}
-
-	return nil
+	return amounts, rows.Err()
 }
@@ -147,6 +147,7 @@ func TestUpdate(t *testing.T) {
 			Address:     "Rua Feliz",
 			Telephone:   "123456",
 			LocalityId:  1,
+			Version:     1,
 		}
 
 		err := repository.Update(ctx, sellerUpdate)
@@ -158,8 +159,32 @@ func TestUpdate(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, sellersResult)
 		assert.Equal(t, sellerUpdate.ID, sellersResult.ID)
+		assert.Equal(t, 2, sellersResult.Version)
 
 	})
+	t.Run("should not update a seller changed since it was read", func(t *testing.T) {
+		repository := seller.NewRepository(db)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		sellerCreated := domain.Seller{
+			CID:         16,
+			CompanyName: "Mercado Livre",
+			Address:     "Rua Feliz",
+			Telephone:   "123456",
+			LocalityId:  1,
+		}
+
+		sellerId, err := repository.Save(ctx, sellerCreated)
+		assert.NoError(t, err)
+		sellerCreated.ID = sellerId
+		sellerCreated.Version = 1
+		assert.NoError(t, repository.Update(ctx, sellerCreated))
+
+		sellerCreated.Address = "Rua Triste"
+		err = repository.Update(ctx, sellerCreated)
+		assert.ErrorIs(t, err, seller.ErrVersionMismatch)
+	})
 }
 
 func TestDelete(t *testing.T) {
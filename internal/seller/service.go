@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mergepatch"
 )
 
 var (
@@ -15,14 +17,19 @@ var (
 	ErrCidAlreadyExists = errors.New("cid already registered")
 	ErrSaveSeller       = errors.New("error saving seller")
 	ErrLocality         = errors.New("locality does not exist")
+	ErrInvalidRange     = errors.New("from must be before to")
+	ErrVersionMismatch  = errors.New("seller was modified by another request")
 )
 
 type Service interface {
 	GetAll(ctx context.Context) ([]domain.Seller, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Seller, error)
+	GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Seller, error)
 	Get(ctx context.Context, id int) (domain.Seller, error)
 	Save(ctx context.Context, d domain.Seller) (domain.Seller, error)
 	Delete(ctx context.Context, id int) error
-	Update(ctx context.Context, id int, s domain.Seller) (domain.Seller, error)
+	Update(ctx context.Context, id int, p domain.Patch) (domain.Seller, error)
+	Dashboard(ctx context.Context, id int, from, to datetime.Time) (domain.SellerDashboard, error)
 }
 
 type sellerService struct {
@@ -43,6 +50,16 @@ func (s *sellerService) GetAll(ctx context.Context) ([]domain.Seller, error) {
 	return sellers, nil
 }
 
+// GetByIDs returns the sellers with the given ids.
+func (s *sellerService) GetByIDs(ctx context.Context, ids []int) ([]domain.Seller, error) {
+	return s.repository.GetByIDs(ctx, ids)
+}
+
+// GetByLocalities returns the sellers of the given localities.
+func (s *sellerService) GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Seller, error) {
+	return s.repository.GetByLocalities(ctx, localityIDs)
+}
+
 func (s *sellerService) Save(ctx context.Context, seller domain.Seller) (domain.Seller, error) {
 	if s.repository.Exists(ctx, seller.CID) {
 		return domain.Seller{}, ErrCidAlreadyExists
@@ -65,36 +82,43 @@ func (s *sellerService) Get(ctx context.Context, id int) (domain.Seller, error)
 	return seller, err
 }
 
-func (s *sellerService) Update(ctx context.Context, id int, newSeller domain.Seller) (domain.Seller, error) {
+// Update applies a merge patch to the seller. None of its fields can be
+// cleared, and its cid must stay unique.
+func (s *sellerService) Update(ctx context.Context, id int, p domain.Patch) (domain.Seller, error) {
 	seller, err := s.repository.Get(ctx, id)
 	if err != nil {
 		return domain.Seller{}, ErrNotFound
 	}
-	if newSeller.CID != 0 {
-		if newSeller.CID != seller.CID {
-			cidAlreadyExists := s.repository.Exists(ctx, newSeller.CID)
-			if cidAlreadyExists {
-				return domain.Seller{}, ErrCidAlreadyExists
-			}
-		}
-		seller.CID = newSeller.CID
-	}
-	if newSeller.Address != "" {
-		seller.Address = newSeller.Address
+	if p.Version != 0 && p.Version != seller.Version {
+		return domain.Seller{}, ErrVersionMismatch
 	}
-	if newSeller.CompanyName != "" {
-		seller.CompanyName = newSeller.CompanyName
+
+	cid := seller.CID
+	if err := mergepatch.Apply(&seller, p.Document); err != nil {
+		return domain.Seller{}, ErrInvalidBody
 	}
-	if newSeller.Telephone != "" {
-		seller.Telephone = newSeller.Telephone
+	seller.ID = id
+	if seller.CID == 0 || seller.CompanyName == "" || seller.Address == "" || seller.Telephone == "" || seller.LocalityId == 0 {
+		return domain.Seller{}, ErrInvalidBody
 	}
-	if newSeller.LocalityId != 0 {
-		seller.LocalityId = newSeller.LocalityId
+	if seller.CID != cid && s.repository.Exists(ctx, seller.CID) {
+		return domain.Seller{}, ErrCidAlreadyExists
 	}
 
 	errUpdate := s.repository.Update(ctx, seller)
 	if errUpdate != nil {
 		return domain.Seller{}, errUpdate
 	}
+	seller.Version++
 	return seller, nil
 }
+
+func (s *sellerService) Dashboard(ctx context.Context, id int, from, to datetime.Time) (domain.SellerDashboard, error) {
+	if !from.IsZero() && !to.IsZero() && !from.Before(to.Time) {
+		return domain.SellerDashboard{}, ErrInvalidRange
+	}
+	if _, err := s.repository.Get(ctx, id); err != nil {
+		return domain.SellerDashboard{}, err
+	}
+	return s.repository.Dashboard(ctx, id, from, to)
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/seller"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -200,16 +202,35 @@ func TestUpdateSellers(t *testing.T) {
 			LocalityId: 1,
 		}
 
+		storedSeller := expectedSellers
+		storedSeller.Telephone = "88748500"
+
 		repository, service := InitServerRepository(t)
-		repository.On("Get", mock.Anything, expectedSellers.ID).Return(expectedSellers, nil)
-		repository.On("Exists", mock.Anything, expectedSellers.CID).Return(false)
+		repository.On("Get", mock.Anything, expectedSellers.ID).Return(storedSeller, nil)
 		repository.On("Update", mock.Anything, expectedSellers).Return(nil)
 
-		updatedSeller, err := service.Update(context.TODO(),expectedSellers.ID, expectedSellers )
+		updatedSeller, err := service.Update(context.TODO(), expectedSellers.ID, domain.Patch{Document: []byte(`{"telephone":"88748585"}`)})
 
 		assert.NoError(t, err)
+		expectedSellers.Version = 1
 		assert.Equal(t, expectedSellers, updatedSeller)
 	})
+	t.Run("Should return error when the seller changed since the expected version", func(t *testing.T) {
+		storedSeller := domain.Seller{
+			ID:      1,
+			CID:     1,
+			Address: "Address",
+			Version: 3,
+		}
+
+		repository, service := InitServerRepository(t)
+		repository.On("Get", mock.Anything, storedSeller.ID).Return(storedSeller, nil)
+
+		_, err := service.Update(context.TODO(), storedSeller.ID, domain.Patch{Document: []byte(`{"address":"New Address"}`), Version: 2})
+
+		assert.ErrorIs(t, err, seller.ErrVersionMismatch)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
 	t.Run("Should return error when there is not exists in database", func(t *testing.T) {
 		expectedSeller := domain.Seller{
             ID: 1,
@@ -225,7 +246,7 @@ func TestUpdateSellers(t *testing.T) {
 		expectedError := errors.New("seller not found")
 		repository.On("Get", mock.Anything, expectedSeller.ID).Return(domain.Seller{}, expectedError)
 
-		updatedSeller, err := service.Update(context.TODO(), expectedSeller.ID, expectedSeller)
+		updatedSeller, err := service.Update(context.TODO(), expectedSeller.ID, domain.Patch{Document: []byte(`{}`)})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
@@ -240,23 +261,41 @@ func TestUpdateSellers(t *testing.T) {
 			Telephone:   "88748585",
 			LocalityId: 1,
 		}
-		updateSeller := domain.Seller{
-			CID:      2,
-		}
-
 		repository, service := InitServerRepository(t)
 
 		expectedError := errors.New("cid already registered")
 		repository.On("Get", mock.Anything, Seller.ID).Return(Seller, nil)
-		repository.On("Exists", mock.Anything, updateSeller.CID).Return(true)
+		repository.On("Exists", mock.Anything, 2).Return(true)
 
-		updatedSeller, err := service.Update(context.TODO(), 1, updateSeller)
+		updatedSeller, err := service.Update(context.TODO(), 1, domain.Patch{Document: []byte(`{"cid":2}`)})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
 		assert.Equal(t, domain.Seller{}, updatedSeller)
 	})
 
+	t.Run("Should return error when a required field is cleared", func(t *testing.T) {
+		storedSeller := domain.Seller{ID: 1, CID: 1, CompanyName: "Company Name", Address: "Address", Telephone: "88748585", LocalityId: 1}
+
+		repository, service := InitServerRepository(t)
+		repository.On("Get", mock.Anything, storedSeller.ID).Return(storedSeller, nil)
+
+		_, err := service.Update(context.TODO(), storedSeller.ID, domain.Patch{Document: []byte(`{"telephone":null}`)})
+
+		assert.ErrorIs(t, err, seller.ErrInvalidBody)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+	t.Run("Should return error when a field has the wrong type", func(t *testing.T) {
+		storedSeller := domain.Seller{ID: 1, CID: 1, CompanyName: "Company Name", Address: "Address", Telephone: "88748585", LocalityId: 1}
+
+		repository, service := InitServerRepository(t)
+		repository.On("Get", mock.Anything, storedSeller.ID).Return(storedSeller, nil)
+
+		_, err := service.Update(context.TODO(), storedSeller.ID, domain.Patch{Document: []byte(`{"cid":"one"}`)})
+
+		assert.ErrorIs(t, err, seller.ErrInvalidBody)
+	})
+
 	t.Run("Should return error when there is an update repository error", func(t *testing.T) {
 		expectedSeller := domain.Seller{
             ID: 1,
@@ -271,16 +310,59 @@ func TestUpdateSellers(t *testing.T) {
 
 		expectedError := errors.New("some error")
 		repository.On("Get", mock.Anything, expectedSeller.ID).Return(expectedSeller, nil)
-		repository.On("Exists", mock.Anything, expectedSeller.CID).Return(false)
 		repository.On("Update", mock.Anything, expectedSeller).Return(expectedError)
 
-		_, err := service.Update(context.TODO(), expectedSeller.ID, expectedSeller)
+		_, err := service.Update(context.TODO(), expectedSeller.ID, domain.Patch{Document: []byte(`{}`)})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
 	})
 }
 
+func TestDashboardSellers(t *testing.T) {
+	from := datetime.MustParse("2023-01-01")
+	to := datetime.MustParse("2023-02-01")
+
+	t.Run("Should return the dashboard of an existing seller", func(t *testing.T) {
+		expectedDashboard := domain.SellerDashboard{
+			SellerID:   1,
+			From:       from,
+			To:         to,
+			TotalStock: 30,
+			StockValue: []money.Money{money.MustParse("300", "USD")},
+			UnitsSold:  4,
+			Revenue:    []money.Money{money.MustParse("60", "USD")},
+		}
+		repository, service := InitServerRepository(t)
+		repository.On("Get", mock.Anything, 1).Return(domain.Seller{ID: 1}, nil)
+		repository.On("Dashboard", mock.Anything, 1, from, to).Return(expectedDashboard, nil)
+
+		dashboard, err := service.Dashboard(context.TODO(), 1, from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDashboard, dashboard)
+	})
+
+	t.Run("Should return error when the seller does not exist", func(t *testing.T) {
+		repository, service := InitServerRepository(t)
+		repository.On("Get", mock.Anything, 1).Return(domain.Seller{}, seller.ErrNotFound)
+
+		_, err := service.Dashboard(context.TODO(), 1, datetime.Time{}, datetime.Time{})
+
+		assert.ErrorIs(t, err, seller.ErrNotFound)
+		repository.AssertNotCalled(t, "Dashboard", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return error when the range is reversed", func(t *testing.T) {
+		repository, service := InitServerRepository(t)
+
+		_, err := service.Dashboard(context.TODO(), 1, to, from)
+
+		assert.ErrorIs(t, err, seller.ErrInvalidRange)
+		repository.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+}
+
 func InitServerRepository(t *testing.T) (*mocks.SellerRepositoryMock, seller.Service) {
 	t.Helper()
 	mockRepository := &mocks.SellerRepositoryMock{}
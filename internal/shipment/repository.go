@@ -0,0 +1,153 @@
+package shipment
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
+)
+
+const (
+	GetOrderQuery = "SELECT po.id, po.order_number, po.tracking_code, os.description, COALESCE(po.carrier_id, 0) " +
+		"FROM purchase_orders po JOIN order_status os ON po.order_status_id = os.id WHERE po.tracking_code = ?"
+	EventsQuery = "SELECT id, carrier_id, event_type, location, occurred_at, recorded_at FROM shipment_events " +
+		"WHERE purchase_order_id = ? ORDER BY occurred_at, id"
+	CarrierByKeyQuery = "SELECT id FROM carriers WHERE api_key_hash = ?"
+	LockOrderQuery    = "SELECT id FROM purchase_orders WHERE id = ? FOR UPDATE"
+	EventTypesQuery   = "SELECT event_type FROM shipment_events WHERE purchase_order_id = ?"
+	SaveEventQuery    = "INSERT INTO shipment_events (purchase_order_id, carrier_id, event_type, location, occurred_at, recorded_at) VALUES (?, ?, ?, ?, ?, ?)"
+	SetStatusQuery    = "UPDATE purchase_orders SET order_status_id = (SELECT id FROM order_status WHERE description = ?) WHERE id = ?"
+)
+
+// Repository encapsulates the storage of shipment events. AddEvent locks the
+// purchase order, so events reported concurrently are checked one after the
+// other.
+type Repository interface {
+	GetOrder(ctx context.Context, trackingCode string) (domain.ShipmentOrder, error)
+	Events(ctx context.Context, orderID int) ([]domain.ShipmentEvent, error)
+	CarrierByKey(ctx context.Context, hash string) (int, error)
+	AddEvent(ctx context.Context, order domain.ShipmentOrder, e domain.ShipmentEvent) (int, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) GetOrder(ctx context.Context, trackingCode string) (domain.ShipmentOrder, error) {
+	o := domain.ShipmentOrder{}
+	err := r.db.QueryRowContext(ctx, GetOrderQuery, trackingCode).Scan(&o.ID, &o.OrderNumber, &o.TrackingCode, &o.OrderStatus, &o.CarrierID)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return domain.ShipmentOrder{}, ErrNotFound
+		}
+		return domain.ShipmentOrder{}, err
+	}
+	return o, nil
+}
+
+// Events lists the events of an order in the order they happened.
+func (r *repository) Events(ctx context.Context, orderID int) ([]domain.ShipmentEvent, error) {
+	rows, err := r.db.QueryContext(ctx, EventsQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []domain.ShipmentEvent{}
+	for rows.Next() {
+		e := domain.ShipmentEvent{}
+		if err := rows.Scan(&e.ID, &e.CarrierID, &e.Type, &e.Location, &e.OccurredAt, &e.RecordedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// CarrierByKey returns the carrier holding the API key with the given hash.
+func (r *repository) CarrierByKey(ctx context.Context, hash string) (int, error) {
+	var id int
+	err := r.db.QueryRowContext(ctx, CarrierByKeyQuery, hash).Scan(&id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return 0, ErrUnauthorized
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// AddEvent stores the event if it can follow the recorded ones and sets the
+// order status it implies, with its purchase_order.status_changed event, in a
+// single transaction.
+func (r *repository) AddEvent(ctx context.Context, order domain.ShipmentOrder, e domain.ShipmentEvent) (int, error) {
+	var id int
+	orderID := order.ID
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, LockOrderQuery, orderID).Scan(&orderID); err != nil {
+			if err.Error() == "sql: no rows in result set" {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		last, err := lastStep(ctx, tx, orderID)
+		if err != nil {
+			return err
+		}
+		if err := checkTransition(last, e.Type); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, SaveEventQuery, orderID, e.CarrierID, e.Type, e.Location, e.OccurredAt, e.RecordedAt)
+		if err != nil {
+			return err
+		}
+		insertedID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		id = int(insertedID)
+
+		status := orderStatus(e.Type)
+		if status == "" {
+			return nil
+		}
+		if _, err := tx.ExecContext(ctx, SetStatusQuery, status, orderID); err != nil {
+			return err
+		}
+		change := domain.PurchaseOrderStatusChange{OrderID: orderID, OrderStatus: status, TrackingCode: order.TrackingCode}
+		return outbox.Write(ctx, tx, domain.EventPurchaseOrderStatusChanged, orderID, change)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// lastStep returns the furthest step the events of an order reached.
+func lastStep(ctx context.Context, tx *sql.Tx, orderID int) (int, error) {
+	rows, err := tx.QueryContext(ctx, EventTypesQuery, orderID)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	last := 0
+	for rows.Next() {
+		var eventType string
+		if err := rows.Scan(&eventType); err != nil {
+			return 0, err
+		}
+		last = furthest(last, eventType)
+	}
+	return last, rows.Err()
+}
@@ -0,0 +1,82 @@
+package shipment_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-txdb"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/shipment"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var db = initDatabase()
+
+func TestAddEventRepository(t *testing.T) {
+	t.Run("Should set the order status with its status_changed event", func(t *testing.T) {
+		repository := shipment.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		order, err := repository.GetOrder(ctx, "TRACK001")
+		assert.NoError(t, err)
+
+		_, err = repository.AddEvent(ctx, order, domain.ShipmentEvent{
+			CarrierID:  order.CarrierID,
+			Type:       domain.ShipmentException,
+			Location:   "São Paulo",
+			OccurredAt: datetime.MustParse("2023-07-03 10:00:00"),
+			RecordedAt: datetime.Now(),
+		})
+		assert.NoError(t, err)
+
+		updated, err := repository.GetOrder(ctx, "TRACK001")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusException, updated.OrderStatus)
+
+		var payload string
+		err = db.QueryRowContext(ctx, "SELECT payload FROM outbox_events WHERE event_type = ? AND aggregate_id = ? ORDER BY id DESC LIMIT 1",
+			domain.EventPurchaseOrderStatusChanged, order.ID).Scan(&payload)
+		assert.NoError(t, err)
+		change := domain.PurchaseOrderStatusChange{}
+		assert.NoError(t, json.Unmarshal([]byte(payload), &change))
+		assert.Equal(t, domain.PurchaseOrderStatusChange{OrderID: order.ID, OrderStatus: domain.OrderStatusException, TrackingCode: "TRACK001"}, change)
+	})
+	t.Run("Should not write a status_changed event for a step that keeps the status", func(t *testing.T) {
+		repository := shipment.NewRepository(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		order, err := repository.GetOrder(ctx, "TRACK002")
+		assert.NoError(t, err)
+
+		_, err = repository.AddEvent(ctx, order, domain.ShipmentEvent{
+			CarrierID:  order.CarrierID,
+			Type:       domain.ShipmentPicked,
+			Location:   "São Paulo",
+			OccurredAt: datetime.MustParse("2023-07-03 10:00:00"),
+			RecordedAt: datetime.Now(),
+		})
+		assert.NoError(t, err)
+
+		var count int
+		err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM outbox_events WHERE event_type = ? AND aggregate_id = ?",
+			domain.EventPurchaseOrderStatusChanged, order.ID).Scan(&count)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
+
+func initDatabase() *sql.DB {
+	txdb.Register("txdb", "mysql", "root:@/melisprint")
+	db, _ := sql.Open("txdb", uuid.New().String())
+	return db
+}
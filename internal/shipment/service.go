@@ -0,0 +1,153 @@
+package shipment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/carry"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+)
+
+var (
+	ErrNotFound          = errors.New("shipment not found")
+	ErrInvalidEvent      = errors.New("event needs a known type, a location and occurred_at")
+	ErrUnauthorized      = errors.New("invalid carrier credential")
+	ErrForbidden         = errors.New("shipment is not assigned to this carrier")
+	ErrInvalidTransition = errors.New("shipment cannot go back to this event")
+	ErrDelivered         = errors.New("shipment is already delivered")
+)
+
+// steps ranks the event types. A shipment only moves forward, except for
+// exceptions, which do not move it and can be followed by any step not
+// behind the last one.
+var steps = map[string]int{
+	domain.ShipmentPicked:     1,
+	domain.ShipmentDispatched: 2,
+	domain.ShipmentInTransit:  3,
+	domain.ShipmentDelivered:  4,
+	domain.ShipmentException:  0,
+}
+
+type Service interface {
+	Get(ctx context.Context, trackingCode string) (domain.Shipment, error)
+	AddEvent(ctx context.Context, trackingCode, apiKey string, req domain.ShipmentEventRequest) (domain.ShipmentEvent, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+// Get returns the shipment of the order with the tracking code and its
+// timeline.
+func (s *service) Get(ctx context.Context, trackingCode string) (domain.Shipment, error) {
+	order, err := s.repository.GetOrder(ctx, trackingCode)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	events, err := s.repository.Events(ctx, order.ID)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+
+	shipment := domain.Shipment{
+		TrackingCode: order.TrackingCode,
+		OrderID:      order.ID,
+		OrderNumber:  order.OrderNumber,
+		OrderStatus:  order.OrderStatus,
+		CarrierID:    order.CarrierID,
+		Events:       events,
+	}
+	if n := len(events); n > 0 {
+		shipment.Status = events[n-1].Type
+	}
+	return shipment, nil
+}
+
+// AddEvent records an event reported by the carrier holding apiKey, which
+// must be the carrier assigned to the order. Delivered and exception events
+// update the status of the order as well. The transition is checked again
+// when the event is stored, against events reported in the meantime.
+func (s *service) AddEvent(ctx context.Context, trackingCode, apiKey string, req domain.ShipmentEventRequest) (domain.ShipmentEvent, error) {
+	if _, ok := steps[req.Type]; !ok || req.Location == "" || req.OccurredAt.IsZero() {
+		return domain.ShipmentEvent{}, ErrInvalidEvent
+	}
+	if apiKey == "" {
+		return domain.ShipmentEvent{}, ErrUnauthorized
+	}
+	carrierID, err := s.repository.CarrierByKey(ctx, carry.HashAPIKey(apiKey))
+	if err != nil {
+		return domain.ShipmentEvent{}, err
+	}
+
+	order, err := s.repository.GetOrder(ctx, trackingCode)
+	if err != nil {
+		return domain.ShipmentEvent{}, err
+	}
+	if order.CarrierID != carrierID {
+		return domain.ShipmentEvent{}, ErrForbidden
+	}
+	events, err := s.repository.Events(ctx, order.ID)
+	if err != nil {
+		return domain.ShipmentEvent{}, err
+	}
+	last := 0
+	for _, e := range events {
+		last = furthest(last, e.Type)
+	}
+	if err := checkTransition(last, req.Type); err != nil {
+		return domain.ShipmentEvent{}, err
+	}
+
+	e := domain.ShipmentEvent{
+		CarrierID:  carrierID,
+		Type:       req.Type,
+		Location:   req.Location,
+		OccurredAt: req.OccurredAt,
+		RecordedAt: datetime.Now(),
+	}
+	id, err := s.repository.AddEvent(ctx, order, e)
+	if err != nil {
+		return domain.ShipmentEvent{}, err
+	}
+	e.ID = id
+	return e, nil
+}
+
+// checkTransition reports whether an event of type next can follow the
+// events of a shipment whose furthest step so far is last.
+func checkTransition(last int, next string) error {
+	if last == steps[domain.ShipmentDelivered] {
+		return ErrDelivered
+	}
+	if next != domain.ShipmentException && steps[next] < last {
+		return ErrInvalidTransition
+	}
+	return nil
+}
+
+// furthest returns the furthest of a step and the step of an event type.
+func furthest(last int, eventType string) int {
+	if steps[eventType] > last {
+		return steps[eventType]
+	}
+	return last
+}
+
+// orderStatus returns the order status an event sets, if any.
+func orderStatus(eventType string) string {
+	switch eventType {
+	case domain.ShipmentDelivered:
+		return domain.OrderStatusDelivered
+	case domain.ShipmentException:
+		return domain.OrderStatusException
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,143 @@
+package shipment_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/carry"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/shipment"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/shipment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const apiKey = "secret"
+
+var order = domain.ShipmentOrder{ID: 3, OrderNumber: "PO003", TrackingCode: "TRACK003", OrderStatus: "Processing", CarrierID: 2}
+
+var eventRequest = domain.ShipmentEventRequest{
+	Type:       domain.ShipmentInTransit,
+	Location:   "São Paulo",
+	OccurredAt: datetime.MustParse("2023-07-03 10:00:00"),
+}
+
+func TestGetShipment(t *testing.T) {
+	t.Run("Should return the timeline with the last event as status", func(t *testing.T) {
+		repository, service := InitShipmentService(t)
+		events := []domain.ShipmentEvent{
+			{ID: 1, Type: domain.ShipmentPicked},
+			{ID: 2, Type: domain.ShipmentDispatched},
+		}
+		repository.On("GetOrder", mock.Anything, "TRACK003").Return(order, nil)
+		repository.On("Events", mock.Anything, 3).Return(events, nil)
+
+		result, err := service.Get(context.TODO(), "TRACK003")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ShipmentDispatched, result.Status)
+		assert.Equal(t, events, result.Events)
+		assert.Equal(t, "PO003", result.OrderNumber)
+	})
+	t.Run("Should return not found for an unknown tracking code", func(t *testing.T) {
+		repository, service := InitShipmentService(t)
+		repository.On("GetOrder", mock.Anything, "nope").Return(domain.ShipmentOrder{}, shipment.ErrNotFound)
+
+		_, err := service.Get(context.TODO(), "nope")
+
+		assert.ErrorIs(t, err, shipment.ErrNotFound)
+	})
+}
+
+func TestAddShipmentEvent(t *testing.T) {
+	t.Run("Should record the event of the assigned carrier", func(t *testing.T) {
+		repository, service := InitShipmentService(t)
+		repository.On("CarrierByKey", mock.Anything, carry.HashAPIKey(apiKey)).Return(2, nil)
+		repository.On("GetOrder", mock.Anything, "TRACK003").Return(order, nil)
+		repository.On("Events", mock.Anything, 3).Return([]domain.ShipmentEvent{{Type: domain.ShipmentDispatched}}, nil)
+		repository.On("AddEvent", mock.Anything, order, mock.MatchedBy(func(e domain.ShipmentEvent) bool {
+			return e.CarrierID == 2 && e.Type == domain.ShipmentInTransit && e.OccurredAt == eventRequest.OccurredAt && !e.RecordedAt.IsZero()
+		})).Return(9, nil)
+
+		result, err := service.AddEvent(context.TODO(), "TRACK003", apiKey, eventRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 9, result.ID)
+		assert.Equal(t, "São Paulo", result.Location)
+	})
+	t.Run("Should allow an exception after delivery started", func(t *testing.T) {
+		repository, service := InitShipmentService(t)
+		repository.On("CarrierByKey", mock.Anything, mock.Anything).Return(2, nil)
+		repository.On("GetOrder", mock.Anything, "TRACK003").Return(order, nil)
+		repository.On("Events", mock.Anything, 3).Return([]domain.ShipmentEvent{{Type: domain.ShipmentInTransit}}, nil)
+		repository.On("AddEvent", mock.Anything, order, mock.Anything).Return(10, nil)
+
+		req := eventRequest
+		req.Type = domain.ShipmentException
+		_, err := service.AddEvent(context.TODO(), "TRACK003", apiKey, req)
+
+		assert.NoError(t, err)
+	})
+	t.Run("Should reject invalid events", func(t *testing.T) {
+		_, service := InitShipmentService(t)
+		for _, req := range []domain.ShipmentEventRequest{
+			{Type: "lost", Location: "São Paulo", OccurredAt: eventRequest.OccurredAt},
+			{Type: domain.ShipmentPicked, OccurredAt: eventRequest.OccurredAt},
+			{Type: domain.ShipmentPicked, Location: "São Paulo"},
+		} {
+			_, err := service.AddEvent(context.TODO(), "TRACK003", apiKey, req)
+			assert.ErrorIs(t, err, shipment.ErrInvalidEvent)
+		}
+	})
+	t.Run("Should reject a missing or unknown credential", func(t *testing.T) {
+		repository, service := InitShipmentService(t)
+		repository.On("CarrierByKey", mock.Anything, carry.HashAPIKey("wrong")).Return(0, shipment.ErrUnauthorized)
+
+		_, err := service.AddEvent(context.TODO(), "TRACK003", "", eventRequest)
+		assert.ErrorIs(t, err, shipment.ErrUnauthorized)
+
+		_, err = service.AddEvent(context.TODO(), "TRACK003", "wrong", eventRequest)
+		assert.ErrorIs(t, err, shipment.ErrUnauthorized)
+	})
+	t.Run("Should reject a carrier not assigned to the order", func(t *testing.T) {
+		repository, service := InitShipmentService(t)
+		repository.On("CarrierByKey", mock.Anything, mock.Anything).Return(5, nil)
+		repository.On("GetOrder", mock.Anything, "TRACK003").Return(order, nil)
+
+		_, err := service.AddEvent(context.TODO(), "TRACK003", apiKey, eventRequest)
+
+		assert.ErrorIs(t, err, shipment.ErrForbidden)
+		repository.AssertNotCalled(t, "AddEvent", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Should reject an event behind the shipment", func(t *testing.T) {
+		repository, service := InitShipmentService(t)
+		repository.On("CarrierByKey", mock.Anything, mock.Anything).Return(2, nil)
+		repository.On("GetOrder", mock.Anything, "TRACK003").Return(order, nil)
+		repository.On("Events", mock.Anything, 3).Return([]domain.ShipmentEvent{{Type: domain.ShipmentInTransit}, {Type: domain.ShipmentException}}, nil)
+
+		req := eventRequest
+		req.Type = domain.ShipmentPicked
+		_, err := service.AddEvent(context.TODO(), "TRACK003", apiKey, req)
+
+		assert.ErrorIs(t, err, shipment.ErrInvalidTransition)
+	})
+	t.Run("Should reject events after delivery", func(t *testing.T) {
+		repository, service := InitShipmentService(t)
+		repository.On("CarrierByKey", mock.Anything, mock.Anything).Return(2, nil)
+		repository.On("GetOrder", mock.Anything, "TRACK003").Return(order, nil)
+		repository.On("Events", mock.Anything, 3).Return([]domain.ShipmentEvent{{Type: domain.ShipmentDelivered}}, nil)
+
+		req := eventRequest
+		req.Type = domain.ShipmentException
+		_, err := service.AddEvent(context.TODO(), "TRACK003", apiKey, req)
+
+		assert.ErrorIs(t, err, shipment.ErrDelivered)
+	})
+}
+
+func InitShipmentService(t *testing.T) (*mocks.ShipmentRepositoryMock, shipment.Service) {
+	t.Helper()
+	repository := &mocks.ShipmentRepositoryMock{}
+	return repository, shipment.NewService(repository)
+}
@@ -0,0 +1,168 @@
+// Package stream pushes live warehouse activity to connected clients. The
+// outbox relay hands domain events to a Publisher, which turns the ones
+// about warehouse activity into messages and broadcasts them through an
+// in-process Hub. Only the instance running the relay broadcasts, so the
+// stream is served by that instance.
+package stream
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Defaults of a Hub.
+const (
+	DefaultHistory = 1000
+	DefaultBuffer  = 64
+)
+
+// Message is an event sent on the stream. ID orders the messages of a hub
+// and is what clients send back as Last-Event-ID to resume.
+type Message struct {
+	ID         int64
+	Event      string
+	Data       json.RawMessage
+	EventID    int64
+	Warehouses []int
+}
+
+// For reports whether the message concerns the warehouse. Every message
+// concerns warehouse 0, which stands for all of them.
+func (m Message) For(warehouseID int) bool {
+	if warehouseID == 0 {
+		return true
+	}
+	for _, id := range m.Warehouses {
+		if id == warehouseID {
+			return true
+		}
+	}
+	return false
+}
+
+// Client is a subscription to the messages of a warehouse. Its channel is
+// closed when it is unsubscribed or when it falls a whole buffer behind.
+type Client struct {
+	warehouseID int
+	messages    chan Message
+}
+
+// Messages returns the channel the messages of the client are sent to.
+func (c *Client) Messages() <-chan Message {
+	return c.messages
+}
+
+// Hub broadcasts messages to its clients. It keeps the latest messages so a
+// client that reconnects gets the ones it missed. Broadcasting never waits
+// for a client: one whose buffer is full is disconnected, and can resume
+// from the last message it received.
+type Hub struct {
+	mu      sync.Mutex
+	seq     int64
+	history []Message
+	size    int
+	buffer  int
+	clients map[*Client]bool
+	seen    map[int64]bool
+}
+
+// NewHub returns a hub that keeps the latest history messages and buffers up
+// to buffer messages per client.
+func NewHub(history, buffer int) *Hub {
+	return &Hub{
+		size:    history,
+		buffer:  buffer,
+		clients: map[*Client]bool{},
+		seen:    map[int64]bool{},
+	}
+}
+
+// Subscribe registers a client for the messages of a warehouse, or of every
+// warehouse when warehouseID is 0. The kept messages after lastID are
+// replayed first. A lastID ahead of the hub comes from before a restart,
+// so every kept message is replayed.
+func (h *Hub) Subscribe(warehouseID int, lastID int64) *Client {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	resume := lastID > 0
+	if lastID > h.seq {
+		lastID = 0
+	}
+	missed := []Message{}
+	if resume {
+		for _, m := range h.history {
+			if m.ID > lastID && m.For(warehouseID) {
+				missed = append(missed, m)
+			}
+		}
+	}
+
+	c := &Client{warehouseID: warehouseID, messages: make(chan Message, h.buffer+len(missed))}
+	for _, m := range missed {
+		c.messages <- m
+	}
+	h.clients[c] = true
+	return c
+}
+
+// Unsubscribe removes the client and closes its channel. It does nothing if
+// the client was already removed.
+func (h *Hub) Unsubscribe(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(c)
+}
+
+// Broadcast numbers the messages made from one event and sends them to the
+// clients of their warehouses. Messages of an EventID already broadcast are
+// dropped, as the relay may publish the same event more than once.
+func (h *Hub) Broadcast(messages ...Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(messages) == 0 {
+		return
+	}
+	if id := messages[0].EventID; id != 0 {
+		if h.seen[id] {
+			return
+		}
+		h.seen[id] = true
+	}
+
+	for _, m := range messages {
+		h.seq++
+		m.ID = h.seq
+		h.history = append(h.history, m)
+		if len(h.history) > h.size {
+			delete(h.seen, h.history[0].EventID)
+			h.history = h.history[1:]
+		}
+
+		for c := range h.clients {
+			if !m.For(c.warehouseID) {
+				continue
+			}
+			select {
+			case c.messages <- m:
+			default:
+				h.remove(c)
+			}
+		}
+	}
+}
+
+// Clients returns how many clients are subscribed.
+func (h *Hub) Clients() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+func (h *Hub) remove(c *Client) {
+	if h.clients[c] {
+		delete(h.clients, c)
+		close(c.messages)
+	}
+}
@@ -0,0 +1,126 @@
+package stream_test
+
+import (
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+func message(eventID int64, event string, warehouses ...int) stream.Message {
+	return stream.Message{EventID: eventID, Event: event, Data: []byte(`{}`), Warehouses: warehouses}
+}
+
+// received drains the messages waiting on a client.
+func received(c *stream.Client) []stream.Message {
+	messages := []stream.Message{}
+	for {
+		select {
+		case m, ok := <-c.Messages():
+			if !ok {
+				return messages
+			}
+			messages = append(messages, m)
+		default:
+			return messages
+		}
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	t.Run("Should send messages to the clients of their warehouses", func(t *testing.T) {
+		hub := stream.NewHub(10, 10)
+		first := hub.Subscribe(1, 0)
+		second := hub.Subscribe(2, 0)
+		all := hub.Subscribe(0, 0)
+
+		hub.Broadcast(message(1, "transfer", 1, 2))
+		hub.Broadcast(message(2, "inbound_order", 2))
+
+		assert.Len(t, received(first), 1)
+		assert.Len(t, received(second), 2)
+		messages := received(all)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, int64(1), messages[0].ID)
+		assert.Equal(t, int64(2), messages[1].ID)
+	})
+
+	t.Run("Should drop the messages of an event already broadcast", func(t *testing.T) {
+		hub := stream.NewHub(10, 10)
+		c := hub.Subscribe(1, 0)
+
+		hub.Broadcast(message(7, "product_batch", 1), message(7, "temperature_alert", 1))
+		hub.Broadcast(message(7, "product_batch", 1), message(7, "temperature_alert", 1))
+
+		assert.Len(t, received(c), 2)
+	})
+
+	t.Run("Should disconnect a client whose buffer is full", func(t *testing.T) {
+		hub := stream.NewHub(10, 1)
+		slow := hub.Subscribe(1, 0)
+
+		hub.Broadcast(message(1, "inbound_order", 1))
+		hub.Broadcast(message(2, "inbound_order", 1))
+
+		assert.Equal(t, 0, hub.Clients())
+		m, ok := <-slow.Messages()
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), m.ID)
+		_, ok = <-slow.Messages()
+		assert.False(t, ok)
+	})
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Run("Should replay the messages after the last event id", func(t *testing.T) {
+		hub := stream.NewHub(10, 1)
+		for i := int64(1); i <= 4; i++ {
+			hub.Broadcast(message(i, "inbound_order", 1))
+		}
+		hub.Broadcast(message(5, "inbound_order", 2))
+
+		messages := received(hub.Subscribe(1, 2))
+
+		assert.Len(t, messages, 2)
+		assert.Equal(t, int64(3), messages[0].ID)
+		assert.Equal(t, int64(4), messages[1].ID)
+	})
+
+	t.Run("Should replay only the messages it keeps", func(t *testing.T) {
+		hub := stream.NewHub(2, 10)
+		for i := int64(1); i <= 5; i++ {
+			hub.Broadcast(message(i, "inbound_order", 1))
+		}
+
+		messages := received(hub.Subscribe(1, 1))
+
+		assert.Len(t, messages, 2)
+		assert.Equal(t, int64(4), messages[0].ID)
+	})
+
+	t.Run("Should replay every kept message for an id from before a restart", func(t *testing.T) {
+		hub := stream.NewHub(10, 10)
+		hub.Broadcast(message(1, "inbound_order", 1))
+
+		assert.Len(t, received(hub.Subscribe(1, 500)), 1)
+	})
+
+	t.Run("Should replay nothing without a last event id", func(t *testing.T) {
+		hub := stream.NewHub(10, 10)
+		hub.Broadcast(message(1, "inbound_order", 1))
+
+		assert.Empty(t, received(hub.Subscribe(1, 0)))
+	})
+
+	t.Run("Should close the channel when unsubscribed", func(t *testing.T) {
+		hub := stream.NewHub(10, 10)
+		c := hub.Subscribe(1, 0)
+
+		hub.Unsubscribe(c)
+		hub.Unsubscribe(c)
+
+		_, ok := <-c.Messages()
+		assert.False(t, ok)
+		assert.Equal(t, 0, hub.Clients())
+	})
+}
@@ -0,0 +1,120 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+)
+
+// Publisher is an outbox publisher that broadcasts the domain events about
+// warehouse activity: inbound orders, batch creations, transfers, and
+// temperature alerts raised by batches and section readings below their
+// minimum temperature. Other events are ignored.
+type Publisher struct {
+	repository Repository
+	hub        *Hub
+}
+
+func NewPublisher(r Repository, h *Hub) *Publisher {
+	return &Publisher{
+		repository: r,
+		hub:        h,
+	}
+}
+
+func (p *Publisher) Publish(ctx context.Context, e domain.Event) error {
+	messages, err := p.messages(ctx, e)
+	if err != nil {
+		return err
+	}
+	for i := range messages {
+		messages[i].EventID = e.ID
+	}
+	p.hub.Broadcast(messages...)
+	return nil
+}
+
+// messages returns the stream messages an event turns into.
+func (p *Publisher) messages(ctx context.Context, e domain.Event) ([]Message, error) {
+	switch e.Type {
+	case domain.EventInboundOrderCreated:
+		var o domain.InboundOrders
+		if err := json.Unmarshal(e.Data, &o); err != nil {
+			return nil, err
+		}
+		return []Message{{Event: domain.StreamInboundOrder, Data: e.Data, Warehouses: []int{o.WarehouseID}}}, nil
+
+	case domain.EventProductBatchCreated:
+		var b domain.ProductBatch
+		if err := json.Unmarshal(e.Data, &b); err != nil {
+			return nil, err
+		}
+		warehouseID, err := p.repository.Warehouse(ctx, b.SectionID)
+		if err != nil {
+			return nil, err
+		}
+		messages := []Message{{Event: domain.StreamProductBatch, Data: e.Data, Warehouses: []int{warehouseID}}}
+		if b.CurrentTemperature < b.MinimumTemperature {
+			alert, err := alertMessage(domain.TemperatureAlert{
+				WarehouseID:        warehouseID,
+				SectionID:          b.SectionID,
+				BatchID:            b.ID,
+				CurrentTemperature: b.CurrentTemperature,
+				MinimumTemperature: b.MinimumTemperature,
+			})
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, alert)
+		}
+		return messages, nil
+
+	case domain.EventTransferCreated, domain.EventTransferDispatched, domain.EventTransferReceived:
+		var t domain.Transfer
+		if err := json.Unmarshal(e.Data, &t); err != nil {
+			return nil, err
+		}
+		source, err := p.repository.Warehouse(ctx, t.SourceSectionID)
+		if err != nil {
+			return nil, err
+		}
+		target, err := p.repository.Warehouse(ctx, t.TargetSectionID)
+		if err != nil {
+			return nil, err
+		}
+		warehouses := []int{source}
+		if target != source {
+			warehouses = append(warehouses, target)
+		}
+		return []Message{{Event: domain.StreamTransfer, Data: e.Data, Warehouses: warehouses}}, nil
+
+	case domain.EventSectionUpdated:
+		var s domain.Section
+		if err := json.Unmarshal(e.Data, &s); err != nil {
+			return nil, err
+		}
+		if s.CurrentTemperature >= s.MinimumTemperature {
+			return nil, nil
+		}
+		alert, err := alertMessage(domain.TemperatureAlert{
+			WarehouseID:        s.WarehouseID,
+			SectionID:          s.ID,
+			CurrentTemperature: s.CurrentTemperature,
+			MinimumTemperature: s.MinimumTemperature,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []Message{alert}, nil
+	}
+	return nil, nil
+}
+
+func alertMessage(a domain.TemperatureAlert) (Message, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Event: domain.StreamTemperatureAlert, Data: data, Warehouses: []int{a.WarehouseID}}, nil
+}
@@ -0,0 +1,106 @@
+package stream_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/stream"
+	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func event(id int64, eventType string, data interface{}) domain.Event {
+	payload, _ := json.Marshal(data)
+	return domain.Event{ID: id, Type: eventType, Data: payload}
+}
+
+func InitPublisher() (*stream.Publisher, *stream.Hub, *mocks.StreamRepositoryMock) {
+	repository := &mocks.StreamRepositoryMock{}
+	hub := stream.NewHub(stream.DefaultHistory, stream.DefaultBuffer)
+	return stream.NewPublisher(repository, hub), hub, repository
+}
+
+func TestPublish(t *testing.T) {
+	t.Run("Should send inbound orders to their warehouse", func(t *testing.T) {
+		publisher, hub, _ := InitPublisher()
+		c := hub.Subscribe(3, 0)
+
+		err := publisher.Publish(context.TODO(), event(1, domain.EventInboundOrderCreated, domain.InboundOrders{ID: 9, WarehouseID: 3}))
+
+		assert.NoError(t, err)
+		messages := received(c)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, domain.StreamInboundOrder, messages[0].Event)
+		assert.Equal(t, int64(1), messages[0].EventID)
+	})
+
+	t.Run("Should raise an alert for a batch below its minimum temperature", func(t *testing.T) {
+		publisher, hub, repository := InitPublisher()
+		repository.On("Warehouse", mock.Anything, 4).Return(2, nil)
+		c := hub.Subscribe(2, 0)
+		batch := domain.ProductBatch{ID: 5, SectionID: 4, CurrentTemperature: -30, MinimumTemperature: -20}
+
+		err := publisher.Publish(context.TODO(), event(1, domain.EventProductBatchCreated, batch))
+
+		assert.NoError(t, err)
+		messages := received(c)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, domain.StreamProductBatch, messages[0].Event)
+		assert.Equal(t, domain.StreamTemperatureAlert, messages[1].Event)
+		var alert domain.TemperatureAlert
+		assert.NoError(t, json.Unmarshal(messages[1].Data, &alert))
+		assert.Equal(t, domain.TemperatureAlert{WarehouseID: 2, SectionID: 4, BatchID: 5, CurrentTemperature: -30, MinimumTemperature: -20}, alert)
+	})
+
+	t.Run("Should send transfers to the warehouses of both sections", func(t *testing.T) {
+		publisher, hub, repository := InitPublisher()
+		repository.On("Warehouse", mock.Anything, 1).Return(1, nil)
+		repository.On("Warehouse", mock.Anything, 2).Return(2, nil)
+		source := hub.Subscribe(1, 0)
+		target := hub.Subscribe(2, 0)
+		transfer := domain.Transfer{ID: 3, SourceSectionID: 1, TargetSectionID: 2, Status: domain.TransferInTransit}
+
+		err := publisher.Publish(context.TODO(), event(1, domain.EventTransferDispatched, transfer))
+
+		assert.NoError(t, err)
+		assert.Len(t, received(source), 1)
+		assert.Len(t, received(target), 1)
+	})
+
+	t.Run("Should raise an alert only for sections below their minimum temperature", func(t *testing.T) {
+		publisher, hub, _ := InitPublisher()
+		c := hub.Subscribe(1, 0)
+
+		err := publisher.Publish(context.TODO(), event(1, domain.EventSectionUpdated, domain.Section{ID: 1, WarehouseID: 1, CurrentTemperature: 5, MinimumTemperature: 2}))
+		assert.NoError(t, err)
+		err = publisher.Publish(context.TODO(), event(2, domain.EventSectionUpdated, domain.Section{ID: 1, WarehouseID: 1, CurrentTemperature: 0, MinimumTemperature: 2}))
+		assert.NoError(t, err)
+
+		messages := received(c)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, domain.StreamTemperatureAlert, messages[0].Event)
+	})
+
+	t.Run("Should ignore events that are not warehouse activity", func(t *testing.T) {
+		publisher, hub, _ := InitPublisher()
+		c := hub.Subscribe(0, 0)
+
+		err := publisher.Publish(context.TODO(), event(1, domain.EventSellerCreated, domain.Seller{ID: 1}))
+
+		assert.NoError(t, err)
+		assert.Empty(t, received(c))
+	})
+
+	t.Run("Should return the error finding the warehouse", func(t *testing.T) {
+		publisher, _, repository := InitPublisher()
+		repository.On("Warehouse", mock.Anything, 4).Return(0, errors.New("db down"))
+
+		err := publisher.Publish(context.TODO(), event(1, domain.EventProductBatchCreated, domain.ProductBatch{SectionID: 4}))
+
+		assert.EqualError(t, err, "db down")
+	})
+}
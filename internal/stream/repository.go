@@ -0,0 +1,37 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+)
+
+const WarehouseQuery = "SELECT warehouse_id FROM sections WHERE id = ?"
+
+// Repository finds the warehouse of the sections events refer to.
+type Repository interface {
+	Warehouse(ctx context.Context, sectionID int) (int, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// Warehouse returns the warehouse of a section, or 0 when the section no
+// longer exists.
+func (r *repository) Warehouse(ctx context.Context, sectionID int) (int, error) {
+	var warehouseID int
+	err := r.db.QueryRowContext(ctx, WarehouseQuery, sectionID).Scan(&warehouseID)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return warehouseID, nil
+}
@@ -0,0 +1,228 @@
+package transfer
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
+)
+
+const (
+	GetTransferQuery = "SELECT id, product_batch_id, moved_batch_id, quantity, source_section_id, target_section_id, status, created_at, dispatched_at, received_at FROM transfers WHERE id = ?"
+	GetBatchQuery    = "SELECT pb.id, p.id_product_type, pb.section_id, pb.current_quantity, p.height, p.lenght, p.width FROM product_batches pb JOIN products p ON pb.product_id = p.id WHERE pb.id = ?"
+	GetSectionQuery  = "SELECT s.id, s.id_product_type, s.maximum_capacity, " +
+		section.Occupied + " FROM sections s WHERE s.id = ?"
+	SaveTransferQuery = "INSERT INTO transfers (product_batch_id, quantity, source_section_id, target_section_id, status, created_at) VALUES (?, ?, ?, ?, ?, ?)"
+	SplitBatchQuery   = "INSERT INTO product_batches (batch_number, initial_quantity, current_quantity, current_temperature, due_date, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id) " +
+		"SELECT batch_number, ?, ?, current_temperature, due_date, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id FROM product_batches WHERE id = ?"
+	ShrinkBatchQuery = "UPDATE product_batches SET current_quantity = current_quantity - ? WHERE id = ?"
+	MoveBatchQuery   = "UPDATE product_batches SET section_id = ? WHERE id = ?"
+	DispatchQuery    = "UPDATE transfers SET status = ?, moved_batch_id = ?, dispatched_at = ? WHERE id = ?"
+	ReceiveQuery     = "UPDATE transfers SET status = ?, received_at = ? WHERE id = ?"
+	forUpdate        = " FOR UPDATE"
+)
+
+// Repository encapsulates the storage of a transfer. Dispatch and Receive run
+// in a single transaction each and lock the rows they check, so concurrent
+// transfers cannot overdraw a batch or overfill a section.
+type Repository interface {
+	Get(ctx context.Context, id int) (domain.Transfer, error)
+	GetBatch(ctx context.Context, id int) (domain.TransferBatch, error)
+	GetSection(ctx context.Context, id int) (domain.TransferSection, error)
+	Save(ctx context.Context, t domain.Transfer) (int, error)
+	Dispatch(ctx context.Context, id int, at datetime.Time) (domain.Transfer, error)
+	Receive(ctx context.Context, id int, at datetime.Time) (domain.Transfer, error)
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.Transfer, error) {
+	return getTransfer(ctx, r.db, GetTransferQuery, id)
+}
+
+func (r *repository) GetBatch(ctx context.Context, id int) (domain.TransferBatch, error) {
+	return getBatch(ctx, r.db, GetBatchQuery, id)
+}
+
+func (r *repository) GetSection(ctx context.Context, id int) (domain.TransferSection, error) {
+	return getSection(ctx, r.db, GetSectionQuery, id)
+}
+
+// Save inserts the transfer and its transfer.created event in one
+// transaction.
+func (r *repository) Save(ctx context.Context, t domain.Transfer) (int, error) {
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, SaveTransferQuery, t.ProductBatchID, t.Quantity, t.SourceSectionID, t.TargetSectionID, t.Status, t.CreatedAt)
+		if err != nil {
+			return err
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		t.ID = int(id)
+		return outbox.Write(ctx, tx, domain.EventTransferCreated, t.ID, t)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return t.ID, nil
+}
+
+// Dispatch takes the quantity out of the source batch. Moving part of a batch
+// splits it: the current quantity of the source batch shrinks and a new batch
+// with the same data holds the moved units. The source batch keeps its
+// initial quantity, which is what was received. Moving the whole batch moves
+// the batch itself.
+func (r *repository) Dispatch(ctx context.Context, id int, at datetime.Time) (domain.Transfer, error) {
+	var t domain.Transfer
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		if t, err = getTransfer(ctx, tx, GetTransferQuery+forUpdate, id); err != nil {
+			return err
+		}
+		if t.Status != domain.TransferDraft {
+			return ErrInvalidTransition
+		}
+
+		batch, err := getBatch(ctx, tx, GetBatchQuery+forUpdate, t.ProductBatchID)
+		if err != nil {
+			return err
+		}
+		if err := checkBatch(t, batch); err != nil {
+			return err
+		}
+		target, err := getSection(ctx, tx, GetSectionQuery+forUpdate, t.TargetSectionID)
+		if err != nil {
+			return err
+		}
+		if err := checkTarget(t, batch, target); err != nil {
+			return err
+		}
+
+		t.MovedBatchID = batch.ID
+		if t.Quantity < batch.CurrentQuantity {
+			result, err := tx.ExecContext(ctx, SplitBatchQuery, t.Quantity, t.Quantity, batch.ID)
+			if err != nil {
+				return err
+			}
+			movedID, err := result.LastInsertId()
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, ShrinkBatchQuery, t.Quantity, batch.ID); err != nil {
+				return err
+			}
+			t.MovedBatchID = int(movedID)
+		}
+
+		t.Status = domain.TransferInTransit
+		t.DispatchedAt = at
+		if _, err := tx.ExecContext(ctx, DispatchQuery, t.Status, t.MovedBatchID, t.DispatchedAt, t.ID); err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.EventTransferDispatched, t.ID, t)
+	})
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	return t, nil
+}
+
+// Receive moves the batch dispatched by the transfer into the target section,
+// checking its capacity again since it may have filled up in the meantime.
+func (r *repository) Receive(ctx context.Context, id int, at datetime.Time) (domain.Transfer, error) {
+	var t domain.Transfer
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		if t, err = getTransfer(ctx, tx, GetTransferQuery+forUpdate, id); err != nil {
+			return err
+		}
+		if t.Status != domain.TransferInTransit {
+			return ErrInvalidTransition
+		}
+
+		batch, err := getBatch(ctx, tx, GetBatchQuery+forUpdate, t.MovedBatchID)
+		if err != nil {
+			return err
+		}
+		target, err := getSection(ctx, tx, GetSectionQuery+forUpdate, t.TargetSectionID)
+		if err != nil {
+			return err
+		}
+		if err := checkTarget(t, batch, target); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, MoveBatchQuery, t.TargetSectionID, t.MovedBatchID); err != nil {
+			return err
+		}
+		t.Status = domain.TransferReceived
+		t.ReceivedAt = at
+		if _, err := tx.ExecContext(ctx, ReceiveQuery, t.Status, t.ReceivedAt, t.ID); err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.EventTransferReceived, t.ID, t)
+	})
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	return t, nil
+}
+
+func getTransfer(ctx context.Context, q querier, query string, id int) (domain.Transfer, error) {
+	t := domain.Transfer{}
+	var movedBatchID sql.NullInt64
+	err := q.QueryRowContext(ctx, query, id).Scan(&t.ID, &t.ProductBatchID, &movedBatchID, &t.Quantity, &t.SourceSectionID, &t.TargetSectionID, &t.Status, &t.CreatedAt, &t.DispatchedAt, &t.ReceivedAt)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return domain.Transfer{}, ErrNotFound
+		}
+		return domain.Transfer{}, err
+	}
+	t.MovedBatchID = int(movedBatchID.Int64)
+	return t, nil
+}
+
+func getBatch(ctx context.Context, q querier, query string, id int) (domain.TransferBatch, error) {
+	b := domain.TransferBatch{}
+	p := domain.Product{}
+	err := q.QueryRowContext(ctx, query, id).Scan(&b.ID, &b.ProductTypeID, &b.SectionID, &b.CurrentQuantity, &p.Height, &p.Length, &p.Width)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return domain.TransferBatch{}, ErrBatchNotFound
+		}
+		return domain.TransferBatch{}, err
+	}
+	b.Volume = p.Volume()
+	return b, nil
+}
+
+func getSection(ctx context.Context, q querier, query string, id int) (domain.TransferSection, error) {
+	s := domain.TransferSection{}
+	err := q.QueryRowContext(ctx, query, id).Scan(&s.ID, &s.ProductTypeID, &s.MaximumCapacity, &s.Occupied)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return domain.TransferSection{}, ErrSectionNotFound
+		}
+		return domain.TransferSection{}, err
+	}
+	return s, nil
+}
@@ -0,0 +1,126 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+)
+
+var (
+	ErrNotFound            = errors.New("transfer not found")
+	ErrInvalidId           = errors.New("invalid id")
+	ErrTryAgain            = errors.New("error, try again")
+	ErrBatchNotFound       = errors.New("product batch not found")
+	ErrSectionNotFound     = errors.New("section not found")
+	ErrInvalidQuantity     = errors.New("quantity must be greater than zero")
+	ErrSameSection         = errors.New("source and target sections must be different")
+	ErrWrongSection        = errors.New("product batch is not stored in the source section")
+	ErrInsufficientStock   = errors.New("product batch does not have enough stock")
+	ErrProductTypeMismatch = errors.New("target section does not store this product type")
+	ErrCapacityExceeded    = errors.New("target section does not have enough capacity")
+	ErrNoDimensions        = errors.New("product has no dimensions")
+	ErrInvalidTransition   = errors.New("transfer cannot change to this status")
+)
+
+type Service interface {
+	Create(ctx context.Context, req domain.TransferRequest) (domain.Transfer, error)
+	Get(ctx context.Context, id int) (domain.Transfer, error)
+	Dispatch(ctx context.Context, id int) (domain.Transfer, error)
+	Receive(ctx context.Context, id int) (domain.Transfer, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+// Create stores a draft transfer once the batch and the target section can
+// take it. Nothing is moved until the transfer is dispatched, where the same
+// checks run again inside the transaction.
+func (s *service) Create(ctx context.Context, req domain.TransferRequest) (domain.Transfer, error) {
+	t := domain.Transfer{
+		ProductBatchID:  req.ProductBatchID,
+		Quantity:        req.Quantity,
+		SourceSectionID: req.SourceSectionID,
+		TargetSectionID: req.TargetSectionID,
+		Status:          domain.TransferDraft,
+		CreatedAt:       datetime.Now(),
+	}
+	if t.Quantity <= 0 {
+		return domain.Transfer{}, ErrInvalidQuantity
+	}
+	if t.SourceSectionID == t.TargetSectionID {
+		return domain.Transfer{}, ErrSameSection
+	}
+
+	batch, err := s.repository.GetBatch(ctx, t.ProductBatchID)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	if err := checkBatch(t, batch); err != nil {
+		return domain.Transfer{}, err
+	}
+	target, err := s.repository.GetSection(ctx, t.TargetSectionID)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	if err := checkTarget(t, batch, target); err != nil {
+		return domain.Transfer{}, err
+	}
+
+	id, err := s.repository.Save(ctx, t)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	t.ID = id
+	return t, nil
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.Transfer, error) {
+	return s.repository.Get(ctx, id)
+}
+
+// Dispatch takes the stock out of the source batch and moves the transfer to
+// in_transit.
+func (s *service) Dispatch(ctx context.Context, id int) (domain.Transfer, error) {
+	return s.repository.Dispatch(ctx, id, datetime.Now())
+}
+
+// Receive places the moved batch in the target section and moves the transfer
+// to received.
+func (s *service) Receive(ctx context.Context, id int) (domain.Transfer, error) {
+	return s.repository.Receive(ctx, id, datetime.Now())
+}
+
+// checkBatch reports whether the batch still holds the stock to transfer.
+func checkBatch(t domain.Transfer, b domain.TransferBatch) error {
+	if b.SectionID != t.SourceSectionID {
+		return ErrWrongSection
+	}
+	if b.CurrentQuantity < t.Quantity {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// checkTarget reports whether the target section stores the product type of
+// the batch and has room for the volume of the transferred quantity.
+func checkTarget(t domain.Transfer, b domain.TransferBatch, s domain.TransferSection) error {
+	if b.ProductTypeID != s.ProductTypeID {
+		return ErrProductTypeMismatch
+	}
+	if b.Volume == 0 {
+		return ErrNoDimensions
+	}
+	if s.Occupied+domain.Occupancy(t.Quantity, b.Volume) > s.MaximumCapacity {
+		return ErrCapacityExceeded
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package transfer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/transfer"
+	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/transfer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var transferRequest = domain.TransferRequest{
+	ProductBatchID:  1,
+	Quantity:        40,
+	SourceSectionID: 1,
+	TargetSectionID: 2,
+}
+
+var sourceBatch = domain.TransferBatch{ID: 1, ProductTypeID: 1, SectionID: 1, CurrentQuantity: 100, Volume: 1.5}
+
+var targetSection = domain.TransferSection{ID: 2, ProductTypeID: 1, MaximumCapacity: 100, Occupied: 30}
+
+func TestCreateTransfer(t *testing.T) {
+	t.Run("Should save a draft transfer", func(t *testing.T) {
+		repository, service := InitTransferService(t)
+		repository.On("GetBatch", mock.Anything, 1).Return(sourceBatch, nil)
+		repository.On("GetSection", mock.Anything, 2).Return(targetSection, nil)
+		repository.On("Save", mock.Anything, mock.MatchedBy(func(tr domain.Transfer) bool {
+			return tr.Status == domain.TransferDraft && tr.Quantity == 40 && !tr.CreatedAt.IsZero()
+		})).Return(7, nil)
+
+		result, err := service.Create(context.TODO(), transferRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, result.ID)
+		assert.Equal(t, domain.TransferDraft, result.Status)
+	})
+
+	t.Run("Should reject a quantity that is not positive", func(t *testing.T) {
+		_, service := InitTransferService(t)
+		req := transferRequest
+		req.Quantity = -1
+
+		_, err := service.Create(context.TODO(), req)
+
+		assert.ErrorIs(t, err, transfer.ErrInvalidQuantity)
+	})
+
+	t.Run("Should reject a transfer to the same section", func(t *testing.T) {
+		_, service := InitTransferService(t)
+		req := transferRequest
+		req.TargetSectionID = 1
+
+		_, err := service.Create(context.TODO(), req)
+
+		assert.ErrorIs(t, err, transfer.ErrSameSection)
+	})
+
+	t.Run("Should reject a batch stored in another section", func(t *testing.T) {
+		repository, service := InitTransferService(t)
+		batch := sourceBatch
+		batch.SectionID = 3
+		repository.On("GetBatch", mock.Anything, 1).Return(batch, nil)
+
+		_, err := service.Create(context.TODO(), transferRequest)
+
+		assert.ErrorIs(t, err, transfer.ErrWrongSection)
+	})
+
+	t.Run("Should reject more than the batch holds", func(t *testing.T) {
+		repository, service := InitTransferService(t)
+		batch := sourceBatch
+		batch.CurrentQuantity = 10
+		repository.On("GetBatch", mock.Anything, 1).Return(batch, nil)
+
+		_, err := service.Create(context.TODO(), transferRequest)
+
+		assert.ErrorIs(t, err, transfer.ErrInsufficientStock)
+	})
+
+	t.Run("Should reject a section for another product type", func(t *testing.T) {
+		repository, service := InitTransferService(t)
+		section := targetSection
+		section.ProductTypeID = 2
+		repository.On("GetBatch", mock.Anything, 1).Return(sourceBatch, nil)
+		repository.On("GetSection", mock.Anything, 2).Return(section, nil)
+
+		_, err := service.Create(context.TODO(), transferRequest)
+
+		assert.ErrorIs(t, err, transfer.ErrProductTypeMismatch)
+	})
+
+	t.Run("Should reject a section without room for the volume of the quantity", func(t *testing.T) {
+		repository, service := InitTransferService(t)
+		section := targetSection
+		section.Occupied = 50
+		repository.On("GetBatch", mock.Anything, 1).Return(sourceBatch, nil)
+		repository.On("GetSection", mock.Anything, 2).Return(section, nil)
+
+		_, err := service.Create(context.TODO(), transferRequest)
+
+		assert.ErrorIs(t, err, transfer.ErrCapacityExceeded)
+		repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should reject a batch of a product without dimensions", func(t *testing.T) {
+		repository, service := InitTransferService(t)
+		batch := sourceBatch
+		batch.Volume = 0
+		repository.On("GetBatch", mock.Anything, 1).Return(batch, nil)
+		repository.On("GetSection", mock.Anything, 2).Return(targetSection, nil)
+
+		_, err := service.Create(context.TODO(), transferRequest)
+
+		assert.ErrorIs(t, err, transfer.ErrNoDimensions)
+		repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return an error when the batch does not exist", func(t *testing.T) {
+		repository, service := InitTransferService(t)
+		repository.On("GetBatch", mock.Anything, 1).Return(domain.TransferBatch{}, transfer.ErrBatchNotFound)
+
+		_, err := service.Create(context.TODO(), transferRequest)
+
+		assert.ErrorIs(t, err, transfer.ErrBatchNotFound)
+	})
+}
+
+func TestDispatchTransfer(t *testing.T) {
+	t.Run("Should dispatch the transfer", func(t *testing.T) {
+		repository, service := InitTransferService(t)
+		expected := domain.Transfer{ID: 1, MovedBatchID: 5, Status: domain.TransferInTransit}
+		repository.On("Dispatch", mock.Anything, 1, mock.Anything).Return(expected, nil)
+
+		result, err := service.Dispatch(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("Should return an error when the transfer is not a draft", func(t *testing.T) {
+		repository, service := InitTransferService(t)
+		repository.On("Dispatch", mock.Anything, 1, mock.Anything).Return(domain.Transfer{}, transfer.ErrInvalidTransition)
+
+		_, err := service.Dispatch(context.TODO(), 1)
+
+		assert.ErrorIs(t, err, transfer.ErrInvalidTransition)
+	})
+}
+
+func TestReceiveTransfer(t *testing.T) {
+	t.Run("Should receive the transfer", func(t *testing.T) {
+		repository, service := InitTransferService(t)
+		expected := domain.Transfer{ID: 1, MovedBatchID: 5, Status: domain.TransferReceived}
+		repository.On("Receive", mock.Anything, 1, mock.Anything).Return(expected, nil)
+
+		result, err := service.Receive(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+}
+
+func InitTransferService(t *testing.T) (*mocks.TransferRepositoryMock, transfer.Service) {
+	t.Helper()
+	mockRepository := &mocks.TransferRepositoryMock{}
+	mockService := transfer.NewService(mockRepository)
+	return mockRepository, mockService
+}
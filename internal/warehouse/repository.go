@@ -5,11 +5,15 @@ import (
 	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
 )
 
 // Repository encapsulates the storage of a warehouse.
 type Repository interface {
 	GetAll(ctx context.Context) ([]domain.Warehouse, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Warehouse, error)
+	GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Warehouse, error)
+	ForEach(ctx context.Context, fn func(domain.Warehouse) error) error
 	Get(ctx context.Context, id int) (domain.Warehouse, error)
 	Exists(ctx context.Context, warehouseCode string) bool
 	Save(ctx context.Context, w domain.Warehouse) (int, error)
@@ -37,19 +41,82 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
 	var warehouses []domain.Warehouse
 
 	for rows.Next() {
-		w := domain.Warehouse{}
-		_ = rows.Scan(&w.ID, &w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.LocalityId)
+		w, _ := scanWarehouse(rows)
 		warehouses = append(warehouses, w)
 	}
 
 	return warehouses, nil
 }
 
+// GetByIDs returns the warehouses with the given ids.
+func (r *repository) GetByIDs(ctx context.Context, ids []int) ([]domain.Warehouse, error) {
+	return r.getIn(ctx, "id", ids)
+}
+
+// GetByLocalities returns the warehouses of the given localities.
+func (r *repository) GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Warehouse, error) {
+	return r.getIn(ctx, "locality_id", localityIDs)
+}
+
+// getIn returns the warehouses whose column holds one of values, in one query.
+func (r *repository) getIn(ctx context.Context, column string, values []int) ([]domain.Warehouse, error) {
+	warehouses := []domain.Warehouse{}
+	if len(values) == 0 {
+		return warehouses, nil
+	}
+	in, args := mysqlutil.In(values)
+	rows, err := r.db.QueryContext(ctx, "SELECT * FROM warehouses WHERE "+column+" IN "+in+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		w, err := scanWarehouse(rows)
+		if err != nil {
+			return nil, err
+		}
+		warehouses = append(warehouses, w)
+	}
+	return warehouses, rows.Err()
+}
+
+// ForEach calls fn for every warehouse while reading them from the database,
+// so the whole table is never held in memory.
+func (r *repository) ForEach(ctx context.Context, fn func(domain.Warehouse) error) error {
+	rows, err := r.db.QueryContext(ctx, "SELECT * FROM warehouses")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		w, err := scanWarehouse(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(w); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanWarehouse reads a warehouse selected with all its columns.
+func scanWarehouse(row scanner) (domain.Warehouse, error) {
+	w := domain.Warehouse{}
+	err := row.Scan(&w.ID, &w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.LocalityId, &w.Version)
+	return w, err
+}
+
 func (r *repository) Get(ctx context.Context, id int) (domain.Warehouse, error) {
 	query := "SELECT * FROM warehouses WHERE id=?;"
 	row := r.db.QueryRow(query, id)
-	w := domain.Warehouse{}
-	err := row.Scan(&w.ID, &w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.LocalityId)
+	w, err := scanWarehouse(row)
 	if err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			return domain.Warehouse{}, ErrNotFound
@@ -87,14 +154,16 @@ func (r *repository) Save(ctx context.Context, w domain.Warehouse) (int, error)
 	return int(id), nil
 }
 
+// Update stores the warehouse only if it is still at w.Version, bumping its
+// version. A warehouse changed since it was read returns ErrVersionMismatch.
 func (r *repository) Update(ctx context.Context, w domain.Warehouse) error {
-	query := "UPDATE warehouses SET address=?, telephone=?, warehouse_code=?, minimum_capacity=?, minimum_temperature=?, locality_id=? WHERE id=?"
+	query := "UPDATE warehouses SET address=?, telephone=?, warehouse_code=?, minimum_capacity=?, minimum_temperature=?, locality_id=?, version=version+1 WHERE id=? AND version=?"
 	stmt, err := r.db.Prepare(query)
 	if err != nil {
 		return err
 	}
 
-	res, err := stmt.Exec(&w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.LocalityId, &w.ID)
+	res, err := stmt.Exec(&w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.LocalityId, &w.ID, &w.Version)
 	if err != nil {
 		return err
 	}
@@ -104,7 +173,10 @@ func (r *repository) Update(ctx context.Context, w domain.Warehouse) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return ErrNotFound
+		if _, err := r.Get(ctx, w.ID); err != nil {
+			return err
+		}
+		return ErrVersionMismatch
 	}
 
 	return nil
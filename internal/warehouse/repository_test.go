@@ -10,6 +10,7 @@ import (
 	"github.com/DATA-DOG/go-txdb"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -20,8 +21,8 @@ var db = InitDatabase()
 var warehouseExpected = domain.Warehouse{
 	Address:            "Rua Pedro Dias",
 	Telephone:          "3712291281",
-	MinimumCapacity:    10,
-	MinimumTemperature: 10.0,
+	MinimumCapacity:    testutil.Int(10),
+	MinimumTemperature: testutil.Float64(10.0),
 	LocalityId:         1,
 }
 
@@ -124,6 +125,7 @@ func TestUpdateWarehousesRepository(t *testing.T) {
 
 		warehouseExpected.ID = result
 		warehouseExpected.Address = "Rua Maria"
+		warehouseExpected.Version = 1
 
 		err = repository.Update(ctx, warehouseExpected)
 		assert.NoError(t, err)
@@ -132,6 +134,10 @@ func TestUpdateWarehousesRepository(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, getResult)
 		assert.Equal(t, warehouseExpected.WarehouseCode, getResult.WarehouseCode)
+		assert.Equal(t, 2, getResult.Version)
+
+		err = repository.Update(ctx, warehouseExpected)
+		assert.ErrorIs(t, err, warehouse.ErrVersionMismatch)
 	})
 	t.Run("Should return error when there is not exists in database", func(t *testing.T) {
 		expectedMessage := warehouse.ErrNotFound.Error()
@@ -156,8 +162,8 @@ func TestDeleteWarehousesRepository(t *testing.T) {
 			Address:            "Rua Pedro Dias",
 			Telephone:          "3712291281",
 			WarehouseCode:      "BASD",
-			MinimumCapacity:    10,
-			MinimumTemperature: 10.0,
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10.0),
 			LocalityId:         1,
 		}
 
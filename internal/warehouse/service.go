@@ -3,34 +3,50 @@ package warehouse
 import (
 	"context"
 	"errors"
+	"sort"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	productbatch "github.com/extmatperez/meli_bootcamp_go_w2-3/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mergepatch"
 )
 
 var (
-	ErrNotFound     = errors.New("warehouse not found")
-	ErrInvalidId    = errors.New("invalid id")
-	ErrInvalidBody  = errors.New("invalid body")
-	ErrTryAgain     = errors.New("error, try again %s")
-	ErrAlredyExists = errors.New("warehouse already exists")
-	ErrInvalidJSON  = errors.New("invalid json")
+	ErrNotFound        = errors.New("warehouse not found")
+	ErrInvalidId       = errors.New("invalid id")
+	ErrInvalidBody     = errors.New("invalid body")
+	ErrTryAgain        = errors.New("error, try again %s")
+	ErrAlredyExists    = errors.New("warehouse already exists")
+	ErrInvalidJSON     = errors.New("invalid json")
+	ErrFutureAsOf      = errors.New("as_of cannot be in the future")
+	ErrVersionMismatch = errors.New("warehouse was modified by another request")
 )
 
 type Service interface {
 	Save(ctx context.Context, d domain.Warehouse) (domain.Warehouse, error)
 	GetAll(ctx context.Context) ([]domain.Warehouse, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Warehouse, error)
+	GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Warehouse, error)
+	ForEach(ctx context.Context, fn func(domain.Warehouse) error) error
 	Get(ctx context.Context, id int) (domain.Warehouse, error)
 	Delete(ctx context.Context, id int) error
-	Update(ctx context.Context, d domain.Warehouse, id int) (domain.Warehouse, error)
+	Update(ctx context.Context, id int, p domain.Patch) (domain.Warehouse, error)
+	Inventory(ctx context.Context, id int, asOf datetime.Time) (domain.WarehouseInventory, error)
 }
 
 type WarehouseService struct {
-	repository Repository
+	repository        Repository
+	sectionRepository section.Repository
+	batchRepository   productbatch.Repository
 }
 
-func NewService(r Repository) Service {
+func NewService(r Repository, sr section.Repository, br productbatch.Repository) Service {
 	return &WarehouseService{
-		repository: r,
+		repository:        r,
+		sectionRepository: sr,
+		batchRepository:   br,
 	}
 }
 
@@ -51,6 +67,20 @@ func (w *WarehouseService) GetAll(ctx context.Context) ([]domain.Warehouse, erro
 	return warehouses, err
 }
 
+// GetByIDs returns the warehouses with the given ids.
+func (w *WarehouseService) GetByIDs(ctx context.Context, ids []int) ([]domain.Warehouse, error) {
+	return w.repository.GetByIDs(ctx, ids)
+}
+
+// GetByLocalities returns the warehouses of the given localities.
+func (w *WarehouseService) GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Warehouse, error) {
+	return w.repository.GetByLocalities(ctx, localityIDs)
+}
+
+func (w *WarehouseService) ForEach(ctx context.Context, fn func(domain.Warehouse) error) error {
+	return w.repository.ForEach(ctx, fn)
+}
+
 func (w *WarehouseService) Get(ctx context.Context, id int) (domain.Warehouse, error) {
 	warehouse, err := w.repository.Get(ctx, id)
 	return warehouse, err
@@ -61,36 +91,150 @@ func (w *WarehouseService) Delete(ctx context.Context, id int) error {
 	return err
 }
 
-func (w *WarehouseService) Update(ctx context.Context, d domain.Warehouse, id int) (domain.Warehouse, error) {
+// Update applies a merge patch to the warehouse. Its fields can be set to
+// zero, but the ones identifying it and its locality cannot be cleared.
+func (w *WarehouseService) Update(ctx context.Context, id int, p domain.Patch) (domain.Warehouse, error) {
 	warehouseDomain, err := w.Get(ctx, id)
 	if err != nil {
 		return domain.Warehouse{}, ErrNotFound
 	}
-
-	if d.Address != "" {
-		warehouseDomain.Address = d.Address
+	if p.Version != 0 && p.Version != warehouseDomain.Version {
+		return domain.Warehouse{}, ErrVersionMismatch
 	}
-	if d.Telephone != "" {
-		warehouseDomain.Telephone = d.Telephone
-	}
-	if d.WarehouseCode != "" {
-		exists := w.repository.Exists(ctx, d.WarehouseCode)
-		if exists && warehouseDomain.WarehouseCode != d.WarehouseCode {
-			return domain.Warehouse{}, ErrAlredyExists
-		}
-		warehouseDomain.WarehouseCode = d.WarehouseCode
+
+	warehouseCode := warehouseDomain.WarehouseCode
+	if err := mergepatch.Apply(&warehouseDomain, p.Document); err != nil {
+		return domain.Warehouse{}, ErrInvalidBody
 	}
-	if d.MinimumCapacity != 0 {
-		warehouseDomain.MinimumCapacity = d.MinimumCapacity
+	warehouseDomain.ID = id
+	if warehouseDomain.Address == "" || warehouseDomain.Telephone == "" || warehouseDomain.WarehouseCode == "" ||
+		(warehouseDomain.MinimumCapacity != nil && *warehouseDomain.MinimumCapacity < 0) || warehouseDomain.LocalityId == 0 {
+		return domain.Warehouse{}, ErrInvalidBody
 	}
-	if d.MinimumTemperature != 0 {
-		warehouseDomain.MinimumTemperature = d.MinimumTemperature
+	if warehouseDomain.WarehouseCode != warehouseCode && w.repository.Exists(ctx, warehouseDomain.WarehouseCode) {
+		return domain.Warehouse{}, ErrAlredyExists
 	}
-	
+
 	err = w.repository.Update(ctx, warehouseDomain)
 	if err != nil {
 		return domain.Warehouse{}, err
 	}
+	warehouseDomain.Version++
 
 	return warehouseDomain, nil
 }
+
+// Inventory returns the stock of a warehouse. With an unset asOf it reads the
+// current quantity of every batch. Otherwise it rebuilds the stock at asOf
+// from the batches received by then, minus the units shipped by then, taken
+// from the oldest batches of each product first.
+func (w *WarehouseService) Inventory(ctx context.Context, id int, asOf datetime.Time) (domain.WarehouseInventory, error) {
+	now := datetime.Now()
+	if asOf.After(now.Time) {
+		return domain.WarehouseInventory{}, ErrFutureAsOf
+	}
+
+	warehouseDomain, err := w.repository.Get(ctx, id)
+	if err != nil {
+		return domain.WarehouseInventory{}, err
+	}
+	sections, err := w.sectionRepository.GetByWarehouse(ctx, id)
+	if err != nil {
+		return domain.WarehouseInventory{}, err
+	}
+	batches, err := w.batchRepository.GetByWarehouse(ctx, id)
+	if err != nil {
+		return domain.WarehouseInventory{}, err
+	}
+
+	reference := now
+	if !asOf.IsZero() {
+		reference = asOf
+		batches, err = w.rebuild(ctx, id, asOf, batches)
+		if err != nil {
+			return domain.WarehouseInventory{}, err
+		}
+	}
+
+	inventory := domain.WarehouseInventory{
+		WarehouseID:     warehouseDomain.ID,
+		WarehouseCode:   warehouseDomain.WarehouseCode,
+		AsOf:            reference,
+		MinimumCapacity: warehouseDomain.MinimumCapacity,
+		Products:        []domain.InventoryProduct{},
+		Sections:        []domain.InventorySection{},
+	}
+
+	sectionIndex := map[int]int{}
+	for _, s := range sections {
+		sectionIndex[s.ID] = len(inventory.Sections)
+		inventory.Sections = append(inventory.Sections, domain.InventorySection{
+			SectionID:     s.ID,
+			SectionNumber: s.SectionNumber,
+			Batches:       []domain.InventoryBatch{},
+		})
+	}
+
+	productIndex := map[int]int{}
+	for _, b := range batches {
+		b.AgeDays = int(reference.Sub(b.ManufacturingDate.Time) / (24 * time.Hour))
+
+		i, ok := productIndex[b.ProductID]
+		if !ok {
+			i = len(inventory.Products)
+			productIndex[b.ProductID] = i
+			inventory.Products = append(inventory.Products, domain.InventoryProduct{ProductID: b.ProductID, Description: b.ProductDescription})
+		}
+		inventory.Products[i].Quantity += b.Quantity
+
+		if j, ok := sectionIndex[b.SectionID]; ok {
+			inventory.Sections[j].Quantity += b.Quantity
+			inventory.Sections[j].Batches = append(inventory.Sections[j].Batches, b)
+		}
+		inventory.TotalQuantity += b.Quantity
+	}
+	inventory.BelowMinimum = inventory.MinimumCapacity != nil && inventory.TotalQuantity < *inventory.MinimumCapacity
+
+	return inventory, nil
+}
+
+// rebuild keeps the batches received by asOf and sets their quantity to what
+// was left of them at that time, once the units split off them by transfers
+// received by then are moved out.
+func (w *WarehouseService) rebuild(ctx context.Context, id int, asOf datetime.Time, batches []domain.InventoryBatch) ([]domain.InventoryBatch, error) {
+	consumed, err := w.batchRepository.Consumed(ctx, id, asOf)
+	if err != nil {
+		return nil, err
+	}
+	splitOff, err := w.batchRepository.SplitOff(ctx, id, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	received := []domain.InventoryBatch{}
+	for _, b := range batches {
+		if !b.ReceivedAt.IsZero() && !b.ReceivedAt.After(asOf.Time) {
+			received = append(received, b)
+		}
+	}
+	sort.SliceStable(received, func(i, j int) bool {
+		if received[i].ReceivedAt.Equal(received[j].ReceivedAt.Time) {
+			return received[i].ID < received[j].ID
+		}
+		return received[i].ReceivedAt.Before(received[j].ReceivedAt.Time)
+	})
+
+	for i := range received {
+		b := &received[i]
+		b.Quantity = b.InitialQuantity - splitOff[b.ID]
+		taken := consumed[b.ProductID]
+		if taken > b.Quantity {
+			taken = b.Quantity
+		}
+		b.Quantity -= taken
+		consumed[b.ProductID] -= taken
+	}
+
+	sort.SliceStable(received, func(i, j int) bool { return received[i].ID < received[j].ID })
+	return received, nil
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/testutil"
+	batchmocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/product_batch"
+	sectionmocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/section"
 	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/warehouse"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -20,8 +25,8 @@ func TestGetAllWarehouses(t *testing.T) {
 				Address:            "Rua Pedro Dias",
 				Telephone:          "3712291281",
 				WarehouseCode:      "DAE",
-				MinimumCapacity:    10,
-				MinimumTemperature: 10.0,
+				MinimumCapacity:    testutil.Int(10),
+				MinimumTemperature: testutil.Float64(10.0),
 				LocalityId:         1,
 			},
 			{
@@ -29,8 +34,8 @@ func TestGetAllWarehouses(t *testing.T) {
 				Address:            "Rua Maria das Dores",
 				Telephone:          "1722919394",
 				WarehouseCode:      "EWQ",
-				MinimumCapacity:    10,
-				MinimumTemperature: 10.0,
+				MinimumCapacity:    testutil.Int(10),
+				MinimumTemperature: testutil.Float64(10.0),
 				LocalityId:         1,
 			},
 		}
@@ -52,8 +57,8 @@ func TestCreateWarehouses(t *testing.T) {
 			Address:            "Rua Pedro Dias",
 			Telephone:          "3712291281",
 			WarehouseCode:      "AEX",
-			MinimumCapacity:    10,
-			MinimumTemperature: 10.0,
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10.0),
 			LocalityId:         1,
 		}
 
@@ -66,8 +71,8 @@ func TestCreateWarehouses(t *testing.T) {
 		assert.Equal(t, "Rua Pedro Dias", warehouse.Address)
 		assert.Equal(t, "3712291281", warehouse.Telephone)
 		assert.Equal(t, "AEX", warehouse.WarehouseCode)
-		assert.Equal(t, 10, warehouse.MinimumCapacity)
-		assert.Equal(t, 10.0, warehouse.MinimumTemperature)
+		assert.Equal(t, testutil.Int(10), warehouse.MinimumCapacity)
+		assert.Equal(t, testutil.Float64(10.0), warehouse.MinimumTemperature)
 		assert.Equal(t, 1, warehouse.LocalityId)
 		assert.Equal(t, 4, warehouse.ID)
 
@@ -107,8 +112,8 @@ func TestGetByIdWarehouses(t *testing.T) {
 			Address:            "Rua Pedro Dias",
 			Telephone:          "3712291281",
 			WarehouseCode:      "AEX",
-			MinimumCapacity:    10,
-			MinimumTemperature: 10.0,
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10.0),
 			LocalityId:         1,
 		}
 
@@ -151,8 +156,8 @@ func TestDeleteWarehouses(t *testing.T) {
 			Address:            "Rua Pedro Dias",
 			Telephone:          "3712291281",
 			WarehouseCode:      "AEX",
-			MinimumCapacity:    10,
-			MinimumTemperature: 10.0,
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10.0),
 			LocalityId:         1,
 		}
 
@@ -194,29 +199,48 @@ func TestUpdateWarehouses(t *testing.T) {
 			Address:            "Rua Antonio",
 			Telephone:          "37122911",
 			WarehouseCode:      "AEX",
-			MinimumCapacity:    10,
-			MinimumTemperature: 10.0,
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10.0),
 			LocalityId:         1,
 		}
 
+		storedWarehouse := expectedWarehouse
+		storedWarehouse.MinimumTemperature = testutil.Float64(-5)
+
 		repository, service := InitServerWithWarehousesRepository(t)
-		repository.On("Get", mock.Anything, expectedWarehouse.ID).Return(expectedWarehouse, nil)
-		repository.On("Exists", mock.Anything, expectedWarehouse.WarehouseCode).Return(false)
+		repository.On("Get", mock.Anything, expectedWarehouse.ID).Return(storedWarehouse, nil)
 		repository.On("Update", mock.Anything, expectedWarehouse).Return(nil)
 
-		updatedWarehouse, err := service.Update(context.TODO(), expectedWarehouse, expectedWarehouse.ID)
+		updatedWarehouse, err := service.Update(context.TODO(), expectedWarehouse.ID, domain.Patch{Document: []byte(`{"minimum_temperature":10}`)})
 
 		assert.NoError(t, err)
+		expectedWarehouse.Version = 1
 		assert.Equal(t, expectedWarehouse, updatedWarehouse)
 	})
+	t.Run("Should return error when the warehouse changed since the expected version", func(t *testing.T) {
+		storedWarehouse := domain.Warehouse{
+			ID:            4,
+			Address:       "Rua Antonio",
+			WarehouseCode: "AEX",
+			Version:       3,
+		}
+
+		repository, service := InitServerWithWarehousesRepository(t)
+		repository.On("Get", mock.Anything, storedWarehouse.ID).Return(storedWarehouse, nil)
+
+		_, err := service.Update(context.TODO(), storedWarehouse.ID, domain.Patch{Document: []byte(`{"address":"Rua Maria"}`), Version: 2})
+
+		assert.ErrorIs(t, err, warehouse.ErrVersionMismatch)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
 	t.Run("Should return error when there is not exists in database", func(t *testing.T) {
 		expectedWarehouse := domain.Warehouse{
 			ID:                 4,
 			Address:            "Rua Antonio",
 			Telephone:          "37122911",
 			WarehouseCode:      "AEX",
-			MinimumCapacity:    10,
-			MinimumTemperature: 10.0,
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10.0),
 			LocalityId:         1,
 		}
 
@@ -225,7 +249,7 @@ func TestUpdateWarehouses(t *testing.T) {
 		expectedError := errors.New("warehouse not found")
 		repository.On("Get", mock.Anything, expectedWarehouse.ID).Return(domain.Warehouse{}, expectedError)
 
-		updatedWarehouse, err := service.Update(context.TODO(), expectedWarehouse, expectedWarehouse.ID)
+		updatedWarehouse, err := service.Update(context.TODO(), expectedWarehouse.ID, domain.Patch{Document: []byte(`{}`)})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
@@ -237,35 +261,112 @@ func TestUpdateWarehouses(t *testing.T) {
 			Address:            "Rua Antonio",
 			Telephone:          "37122911",
 			WarehouseCode:      "AEX",
-			MinimumCapacity:    10,
-			MinimumTemperature: 10.0,
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10.0),
 			LocalityId:         1,
 		}
 
-		updateWarehouse := domain.Warehouse{
-			WarehouseCode: "ADA",
-		}
-
 		repository, service := InitServerWithWarehousesRepository(t)
 
 		expectedError := errors.New("warehouse already exists")
 		repository.On("Get", mock.Anything, domainWarehouse.ID).Return(domainWarehouse, nil)
-		repository.On("Exists", mock.Anything, updateWarehouse.WarehouseCode).Return(true)
+		repository.On("Exists", mock.Anything, "ADA").Return(true)
 
-		updatedWarehouse, err := service.Update(context.TODO(), updateWarehouse, 4)
+		updatedWarehouse, err := service.Update(context.TODO(), 4, domain.Patch{Document: []byte(`{"warehouse_code":"ADA"}`)})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
 		assert.Equal(t, domain.Warehouse{}, updatedWarehouse)
 	})
+	t.Run("Should set the minimum temperature to zero when given explicitly", func(t *testing.T) {
+		storedWarehouse := domain.Warehouse{
+			ID:                 4,
+			Address:            "Rua Antonio",
+			Telephone:          "37122911",
+			WarehouseCode:      "AEX",
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10.0),
+			LocalityId:         1,
+		}
+		expectedWarehouse := storedWarehouse
+		expectedWarehouse.MinimumTemperature = testutil.Float64(0)
+
+		repository, service := InitServerWithWarehousesRepository(t)
+		repository.On("Get", mock.Anything, storedWarehouse.ID).Return(storedWarehouse, nil)
+		repository.On("Update", mock.Anything, expectedWarehouse).Return(nil)
+
+		updatedWarehouse, err := service.Update(context.TODO(), storedWarehouse.ID, domain.Patch{Document: []byte(`{"minimum_temperature":0}`)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, testutil.Float64(0), updatedWarehouse.MinimumTemperature)
+	})
+	t.Run("Should unset the minimums given as null", func(t *testing.T) {
+		storedWarehouse := domain.Warehouse{
+			ID:                 4,
+			Address:            "Rua Antonio",
+			Telephone:          "37122911",
+			WarehouseCode:      "AEX",
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10.0),
+			LocalityId:         1,
+		}
+		expectedWarehouse := storedWarehouse
+		expectedWarehouse.MinimumCapacity = nil
+		expectedWarehouse.MinimumTemperature = nil
+
+		repository, service := InitServerWithWarehousesRepository(t)
+		repository.On("Get", mock.Anything, storedWarehouse.ID).Return(storedWarehouse, nil)
+		repository.On("Update", mock.Anything, expectedWarehouse).Return(nil)
+
+		updatedWarehouse, err := service.Update(context.TODO(), storedWarehouse.ID, domain.Patch{Document: []byte(`{"minimum_capacity":null,"minimum_temperature":null}`)})
+
+		assert.NoError(t, err)
+		assert.Nil(t, updatedWarehouse.MinimumCapacity)
+		assert.Nil(t, updatedWarehouse.MinimumTemperature)
+	})
+	t.Run("Should return error when a required field is cleared", func(t *testing.T) {
+		storedWarehouse := domain.Warehouse{
+			ID:            4,
+			Address:       "Rua Antonio",
+			Telephone:     "37122911",
+			WarehouseCode: "AEX",
+			LocalityId:    1,
+		}
+
+		repository, service := InitServerWithWarehousesRepository(t)
+		repository.On("Get", mock.Anything, storedWarehouse.ID).Return(storedWarehouse, nil)
+
+		for _, document := range []string{`{"address":null}`, `{"locality_id":null}`} {
+			_, err := service.Update(context.TODO(), storedWarehouse.ID, domain.Patch{Document: []byte(document)})
+
+			assert.ErrorIs(t, err, warehouse.ErrInvalidBody, document)
+		}
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+	t.Run("Should return error when a field has the wrong type", func(t *testing.T) {
+		storedWarehouse := domain.Warehouse{
+			ID:            4,
+			Address:       "Rua Antonio",
+			Telephone:     "37122911",
+			WarehouseCode: "AEX",
+			LocalityId:    1,
+		}
+
+		repository, service := InitServerWithWarehousesRepository(t)
+		repository.On("Get", mock.Anything, storedWarehouse.ID).Return(storedWarehouse, nil)
+
+		_, err := service.Update(context.TODO(), storedWarehouse.ID, domain.Patch{Document: []byte(`{"minimum_capacity":"ten"}`)})
+
+		assert.ErrorIs(t, err, warehouse.ErrInvalidBody)
+	})
 	t.Run("Should return error when there is an update repository error", func(t *testing.T) {
 		expectedWarehouse := domain.Warehouse{
 			ID:                 4,
 			Address:            "Rua Antonio",
 			Telephone:          "37122911",
 			WarehouseCode:      "AEX",
-			MinimumCapacity:    10,
-			MinimumTemperature: 10.0,
+			MinimumCapacity:    testutil.Int(10),
+			MinimumTemperature: testutil.Float64(10.0),
 			LocalityId:         1,
 		}
 
@@ -273,19 +374,118 @@ func TestUpdateWarehouses(t *testing.T) {
 
 		expectedError := errors.New("some error")
 		repository.On("Get", mock.Anything, expectedWarehouse.ID).Return(expectedWarehouse, nil)
-		repository.On("Exists", mock.Anything, expectedWarehouse.WarehouseCode).Return(false)
 		repository.On("Update", mock.Anything, expectedWarehouse).Return(expectedError)
 
-		_, err := service.Update(context.TODO(), expectedWarehouse, expectedWarehouse.ID)
+		_, err := service.Update(context.TODO(), expectedWarehouse.ID, domain.Patch{Document: []byte(`{}`)})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
 	})
 }
 
+func TestInventoryWarehouses(t *testing.T) {
+	warehouseDomain := domain.Warehouse{ID: 1, WarehouseCode: "W001", MinimumCapacity: testutil.Int(50)}
+	sections := []domain.Section{{ID: 1, SectionNumber: 10, WarehouseID: 1}, {ID: 2, SectionNumber: 20, WarehouseID: 1}}
+	batches := []domain.InventoryBatch{
+		{ID: 1, ProductID: 1, SectionID: 1, InitialQuantity: 20, Quantity: 5, ManufacturingDate: datetime.MustParse("2023-01-01"), ReceivedAt: datetime.MustParse("2023-01-02")},
+		{ID: 2, ProductID: 1, SectionID: 2, InitialQuantity: 10, Quantity: 10, ManufacturingDate: datetime.MustParse("2023-02-01"), ReceivedAt: datetime.MustParse("2023-02-02")},
+		{ID: 3, ProductID: 2, SectionID: 2, InitialQuantity: 30, Quantity: 30, ManufacturingDate: datetime.MustParse("2023-03-01"), ReceivedAt: datetime.MustParse("2023-03-02")},
+	}
+
+	t.Run("Should return the current stock per product and per section", func(t *testing.T) {
+		repository, sectionRepository, batchRepository, service := InitWarehouseInventoryService(t)
+		repository.On("Get", mock.Anything, 1).Return(warehouseDomain, nil)
+		sectionRepository.On("GetByWarehouse", mock.Anything, 1).Return(sections, nil)
+		batchRepository.On("GetByWarehouse", mock.Anything, 1).Return(batches, nil)
+
+		inventory, err := service.Inventory(context.TODO(), 1, datetime.Time{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 45, inventory.TotalQuantity)
+		assert.True(t, inventory.BelowMinimum)
+		assert.Equal(t, []domain.InventoryProduct{{ProductID: 1, Quantity: 15}, {ProductID: 2, Quantity: 30}}, inventory.Products)
+		assert.Equal(t, 5, inventory.Sections[0].Quantity)
+		assert.Equal(t, 40, inventory.Sections[1].Quantity)
+		assert.Len(t, inventory.Sections[1].Batches, 2)
+		batchRepository.AssertNotCalled(t, "Consumed", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should rebuild the stock at as_of from received batches and consumption", func(t *testing.T) {
+		asOf := datetime.MustParse("2023-02-15")
+		repository, sectionRepository, batchRepository, service := InitWarehouseInventoryService(t)
+		repository.On("Get", mock.Anything, 1).Return(warehouseDomain, nil)
+		sectionRepository.On("GetByWarehouse", mock.Anything, 1).Return(sections, nil)
+		batchRepository.On("GetByWarehouse", mock.Anything, 1).Return(batches, nil)
+		batchRepository.On("Consumed", mock.Anything, 1, asOf).Return(map[int]int{1: 25}, nil)
+		batchRepository.On("SplitOff", mock.Anything, 1, asOf).Return(map[int]int{}, nil)
+
+		inventory, err := service.Inventory(context.TODO(), 1, asOf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, asOf, inventory.AsOf)
+		assert.Equal(t, 5, inventory.TotalQuantity)
+		assert.Equal(t, []domain.InventoryProduct{{ProductID: 1, Quantity: 5}}, inventory.Products)
+		assert.Equal(t, 0, inventory.Sections[0].Quantity)
+		assert.Equal(t, 5, inventory.Sections[1].Quantity)
+		assert.Equal(t, 14, inventory.Sections[1].Batches[0].AgeDays)
+	})
+
+	t.Run("Should move the units split off a batch at as_of only once their transfer is received", func(t *testing.T) {
+		split := append(batches[:1:1],
+			domain.InventoryBatch{ID: 4, ProductID: 1, SectionID: 2, InitialQuantity: 8, Quantity: 8, ManufacturingDate: datetime.MustParse("2023-01-01"), ReceivedAt: datetime.MustParse("2023-03-10")},
+			domain.InventoryBatch{ID: 5, ProductID: 1, SectionID: 1, InitialQuantity: 4, Quantity: 4, ManufacturingDate: datetime.MustParse("2023-01-01")},
+		)
+		before, after := datetime.MustParse("2023-03-01"), datetime.MustParse("2023-03-15")
+		repository, sectionRepository, batchRepository, service := InitWarehouseInventoryService(t)
+		repository.On("Get", mock.Anything, 1).Return(warehouseDomain, nil)
+		sectionRepository.On("GetByWarehouse", mock.Anything, 1).Return(sections, nil)
+		batchRepository.On("GetByWarehouse", mock.Anything, 1).Return(split, nil)
+		batchRepository.On("Consumed", mock.Anything, 1, mock.Anything).Return(map[int]int{}, nil)
+		batchRepository.On("SplitOff", mock.Anything, 1, before).Return(map[int]int{}, nil)
+		batchRepository.On("SplitOff", mock.Anything, 1, after).Return(map[int]int{1: 8}, nil)
+
+		inventoryBefore, err := service.Inventory(context.TODO(), 1, before)
+		assert.NoError(t, err)
+		inventoryAfter, err := service.Inventory(context.TODO(), 1, after)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 20, inventoryBefore.TotalQuantity)
+		assert.Equal(t, 20, inventoryBefore.Sections[0].Quantity)
+		assert.Empty(t, inventoryBefore.Sections[1].Batches)
+		assert.Equal(t, 20, inventoryAfter.TotalQuantity)
+		assert.Equal(t, 12, inventoryAfter.Sections[0].Quantity)
+		assert.Equal(t, 8, inventoryAfter.Sections[1].Quantity)
+	})
+
+	t.Run("Should return error when the warehouse does not exist", func(t *testing.T) {
+		repository, _, _, service := InitWarehouseInventoryService(t)
+		repository.On("Get", mock.Anything, 1).Return(domain.Warehouse{}, warehouse.ErrNotFound)
+
+		_, err := service.Inventory(context.TODO(), 1, datetime.Time{})
+
+		assert.ErrorIs(t, err, warehouse.ErrNotFound)
+	})
+
+	t.Run("Should return error when as_of is in the future", func(t *testing.T) {
+		_, _, _, service := InitWarehouseInventoryService(t)
+
+		_, err := service.Inventory(context.TODO(), 1, datetime.New(time.Now().Add(time.Hour)))
+
+		assert.ErrorIs(t, err, warehouse.ErrFutureAsOf)
+	})
+}
+
 func InitServerWithWarehousesRepository(t *testing.T) (*mocks.WarehouseRepositoryMock, warehouse.Service) {
 	t.Helper()
-	mockRepository := &mocks.WarehouseRepositoryMock{}
-	mockService := warehouse.NewService(mockRepository)
+	mockRepository, _, _, mockService := InitWarehouseInventoryService(t)
 	return mockRepository, mockService
 }
+
+func InitWarehouseInventoryService(t *testing.T) (*mocks.WarehouseRepositoryMock, *sectionmocks.SectionRepositoryMock, *batchmocks.ProductBatchRepositoryMock, warehouse.Service) {
+	t.Helper()
+	mockRepository := &mocks.WarehouseRepositoryMock{}
+	mockSectionRepository := &sectionmocks.SectionRepositoryMock{}
+	mockBatchRepository := &batchmocks.ProductBatchRepositoryMock{}
+	mockService := warehouse.NewService(mockRepository, mockSectionRepository, mockBatchRepository)
+	return mockRepository, mockSectionRepository, mockBatchRepository, mockService
+}
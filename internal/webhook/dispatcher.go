@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+)
+
+// Defaults of a Dispatcher. With them a delivery is tried for a little over
+// a day before it is dead-lettered: the waits between its attempts add up to
+// about eight and a half hours until the backoff reaches its cap, and the
+// last three are six hours each.
+const (
+	DefaultBatchSize   = 50
+	DefaultMaxAttempts = 14
+	DefaultLease       = time.Minute
+	DefaultInterval    = 5 * time.Second
+	baseBackoff        = 30 * time.Second
+	maxBackoff         = 6 * time.Hour
+	maxErrorLength     = 255
+)
+
+// Dispatcher sends the queued deliveries. Failed attempts are retried with
+// exponential backoff until MaxAttempts, after which the delivery is dead.
+type Dispatcher struct {
+	BatchSize   int
+	MaxAttempts int
+	Lease       time.Duration
+
+	repository Repository
+	sender     *sender
+}
+
+func NewDispatcher(r Repository, client *http.Client) *Dispatcher {
+	return &Dispatcher{
+		BatchSize:   DefaultBatchSize,
+		MaxAttempts: DefaultMaxAttempts,
+		Lease:       DefaultLease,
+		repository:  r,
+		sender:      newSender(client),
+	}
+}
+
+// Backoff returns how long to wait before the attempt that follows the given
+// number of failed ones: 30s, 1m, 2m and so on, capped at six hours.
+func Backoff(attempts int) time.Duration {
+	wait := baseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return wait
+}
+
+// Run delivers due deliveries every interval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Errors are left for the next tick: the deliveries stay queued.
+		_, _ = d.DeliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue sends one batch of due deliveries and records the outcome of
+// each. It returns how many were attempted.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := d.repository.Claim(ctx, datetime.Now(), d.Lease, d.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, delivery := range deliveries {
+		if err := d.repository.UpdateDelivery(ctx, d.attempt(ctx, delivery)); err != nil {
+			return 0, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt sends a delivery and returns it updated with the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, delivery domain.WebhookDelivery) domain.WebhookDelivery {
+	status, err := d.sender.send(ctx, delivery)
+	now := datetime.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+
+	if err == nil {
+		delivery.Status = domain.DeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = now
+		return delivery
+	}
+
+	delivery.LastError = err.Error()
+	if len(delivery.LastError) > maxErrorLength {
+		delivery.LastError = delivery.LastError[:maxErrorLength]
+	}
+	if delivery.Attempts >= d.MaxAttempts {
+		delivery.Status = domain.DeliveryDead
+		return delivery
+	}
+	delivery.Status = domain.DeliveryPending
+	delivery.NextAttemptAt = datetime.New(now.Add(Backoff(delivery.Attempts)))
+	return delivery
+}
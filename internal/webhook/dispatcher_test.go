@@ -0,0 +1,265 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/webhook"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// receiver is an httptest server that answers with the given statuses in
+// turn and checks the signature of every request.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+	valid    []bool
+}
+
+func newReceiver(secret string, statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		signature := webhook.Sign(secret, req.Header.Get(webhook.HeaderTimestamp), body)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.bodies = append(r.bodies, string(body))
+		r.valid = append(r.valid, signature == req.Header.Get(webhook.HeaderSignature))
+		status := r.statuses[0]
+		if len(r.statuses) > 1 {
+			r.statuses = r.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	return r
+}
+
+func TestBackoff(t *testing.T) {
+	t.Run("Should double the wait after every failed attempt", func(t *testing.T) {
+		assert.Equal(t, 30*time.Second, webhook.Backoff(1))
+		assert.Equal(t, time.Minute, webhook.Backoff(2))
+		assert.Equal(t, 4*time.Minute, webhook.Backoff(4))
+		assert.Equal(t, 6*time.Hour, webhook.Backoff(30))
+	})
+
+	t.Run("Should retry a delivery for a little over a day by default", func(t *testing.T) {
+		var total time.Duration
+		for attempts := 1; attempts < webhook.DefaultMaxAttempts; attempts++ {
+			total += webhook.Backoff(attempts)
+		}
+
+		assert.Equal(t, 6*time.Hour, webhook.Backoff(webhook.DefaultMaxAttempts-1))
+		assert.Greater(t, total, 24*time.Hour)
+		assert.Less(t, total, 30*time.Hour)
+	})
+}
+
+func TestDeliverDue(t *testing.T) {
+	t.Run("Should deliver a signed payload", func(t *testing.T) {
+		r := newReceiver("secret", http.StatusOK)
+		defer r.Close()
+
+		repository, dispatcher := InitDispatcher(t)
+		delivery := domain.WebhookDelivery{ID: 1, URL: r.URL, Secret: "secret", Event: domain.EventSellerUpdated,
+			Payload: []byte(`{"type":"seller.updated"}`), Status: domain.DeliveryPending}
+		repository.On("Claim", mock.Anything, mock.Anything, webhook.DefaultLease, webhook.DefaultBatchSize).Return([]domain.WebhookDelivery{delivery}, nil)
+		repository.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return d.Status == domain.DeliveryDelivered && d.Attempts == 1 && d.ResponseStatus == http.StatusOK && !d.DeliveredAt.IsZero()
+		})).Return(nil)
+
+		n, err := dispatcher.DeliverDue(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []string{`{"type":"seller.updated"}`}, r.bodies)
+		assert.Equal(t, []bool{true}, r.valid)
+		repository.AssertExpectations(t)
+	})
+	t.Run("Should retry a failed attempt later", func(t *testing.T) {
+		r := newReceiver("secret", http.StatusInternalServerError)
+		defer r.Close()
+
+		repository, dispatcher := InitDispatcher(t)
+		delivery := domain.WebhookDelivery{ID: 1, URL: r.URL, Secret: "secret", Payload: []byte(`{}`), Attempts: 2, Status: domain.DeliveryPending}
+		repository.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.WebhookDelivery{delivery}, nil)
+
+		before := datetime.Now()
+		var updated domain.WebhookDelivery
+		repository.On("UpdateDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updated = args.Get(1).(domain.WebhookDelivery)
+		}).Return(nil)
+
+		_, err := dispatcher.DeliverDue(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DeliveryPending, updated.Status)
+		assert.Equal(t, 3, updated.Attempts)
+		assert.Equal(t, http.StatusInternalServerError, updated.ResponseStatus)
+		assert.NotEmpty(t, updated.LastError)
+		assert.False(t, updated.NextAttemptAt.Before(before.Add(webhook.Backoff(3))))
+	})
+	t.Run("Should dead-letter the last failed attempt", func(t *testing.T) {
+		repository, dispatcher := InitDispatcher(t)
+		dispatcher.MaxAttempts = 3
+		delivery := domain.WebhookDelivery{ID: 1, URL: "http://127.0.0.1:1", Secret: "secret", Payload: []byte(`{}`), Attempts: 2, Status: domain.DeliveryPending}
+		repository.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.WebhookDelivery{delivery}, nil)
+		repository.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return d.Status == domain.DeliveryDead && d.Attempts == 3 && d.LastError != ""
+		})).Return(nil)
+
+		_, err := dispatcher.DeliverDue(context.TODO())
+
+		assert.NoError(t, err)
+		repository.AssertExpectations(t)
+	})
+}
+
+func TestWebhookEndToEnd(t *testing.T) {
+	t.Run("Should deliver a published event after failed attempts", func(t *testing.T) {
+		repository := newMemoryRepository()
+		service := webhook.NewService(repository, nil)
+		dispatcher := webhook.NewDispatcher(repository, nil)
+
+		created, err := service.Create(context.TODO(), domain.WebhookRequest{URL: "http://placeholder", Events: []string{domain.EventSellerUpdated}})
+		assert.NoError(t, err)
+		r := newReceiver(created.Secret, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusAccepted)
+		defer r.Close()
+		repository.webhooks[0].URL = r.URL
+
+		assert.NoError(t, service.Publish(context.TODO(), domain.Event{ID: 1, Type: domain.EventSellerUpdated, Data: []byte(`{"id":7}`)}))
+		assert.NoError(t, service.Publish(context.TODO(), domain.Event{ID: 2, Type: domain.EventPurchaseOrderCreated, Data: []byte(`{"id":1}`)}))
+		for i := 0; i < 4; i++ {
+			_, err := dispatcher.DeliverDue(context.TODO())
+			assert.NoError(t, err)
+		}
+
+		if assert.Len(t, repository.deliveries, 1) {
+			assert.Equal(t, domain.DeliveryDelivered, repository.deliveries[0].Status)
+			assert.Equal(t, 3, repository.deliveries[0].Attempts)
+		}
+		assert.Len(t, r.bodies, 3)
+		assert.Equal(t, []bool{true, true, true}, r.valid)
+		assert.Contains(t, r.bodies[2], `"type":"seller.updated"`)
+	})
+	t.Run("Should dead-letter an event and queue it again on retry", func(t *testing.T) {
+		repository := newMemoryRepository()
+		service := webhook.NewService(repository, nil)
+		dispatcher := webhook.NewDispatcher(repository, nil)
+		dispatcher.MaxAttempts = 2
+
+		created, err := service.Create(context.TODO(), domain.WebhookRequest{URL: "http://placeholder", Events: []string{domain.EventInboundOrderCreated}})
+		assert.NoError(t, err)
+		r := newReceiver(created.Secret, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK)
+		defer r.Close()
+		repository.webhooks[0].URL = r.URL
+
+		assert.NoError(t, service.Publish(context.TODO(), domain.Event{ID: 1, Type: domain.EventInboundOrderCreated, Data: []byte(`{"id":1}`)}))
+		for i := 0; i < 3; i++ {
+			_, err := dispatcher.DeliverDue(context.TODO())
+			assert.NoError(t, err)
+		}
+
+		dead, err := service.DeadLetters(context.TODO())
+		assert.NoError(t, err)
+		if assert.Len(t, dead, 1) {
+			assert.Equal(t, 2, dead[0].Attempts)
+			assert.Equal(t, http.StatusInternalServerError, dead[0].ResponseStatus)
+
+			assert.NoError(t, service.Retry(context.TODO(), dead[0].ID))
+			_, err = dispatcher.DeliverDue(context.TODO())
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, domain.DeliveryDelivered, repository.deliveries[0].Status)
+		assert.Len(t, r.bodies, 3)
+	})
+}
+
+// memoryRepository keeps webhooks and deliveries in memory. Every pending
+// delivery is due, so retries happen on the next dispatch instead of after
+// the backoff.
+type memoryRepository struct {
+	webhook.Repository
+	webhooks   []domain.Webhook
+	deliveries []domain.WebhookDelivery
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{}
+}
+
+func (m *memoryRepository) Save(ctx context.Context, w domain.Webhook) (int, error) {
+	w.ID = len(m.webhooks) + 1
+	m.webhooks = append(m.webhooks, w)
+	return w.ID, nil
+}
+
+func (m *memoryRepository) Enqueue(ctx context.Context, event string, payload []byte, at datetime.Time) (int, error) {
+	queued := 0
+	for _, w := range m.webhooks {
+		for _, e := range w.Events {
+			if e == event {
+				m.deliveries = append(m.deliveries, domain.WebhookDelivery{
+					ID: len(m.deliveries) + 1, WebhookID: w.ID, Event: event, Payload: payload,
+					Status: domain.DeliveryPending, NextAttemptAt: at, CreatedAt: at,
+				})
+				queued++
+			}
+		}
+	}
+	return queued, nil
+}
+
+func (m *memoryRepository) Claim(ctx context.Context, now datetime.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	due := []domain.WebhookDelivery{}
+	for _, d := range m.deliveries {
+		if d.Status == domain.DeliveryPending && len(due) < limit {
+			w := m.webhooks[d.WebhookID-1]
+			d.URL, d.Secret = w.URL, w.Secret
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryRepository) UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	m.deliveries[d.ID-1] = d
+	return nil
+}
+
+func (m *memoryRepository) Dead(ctx context.Context) ([]domain.WebhookDelivery, error) {
+	dead := []domain.WebhookDelivery{}
+	for _, d := range m.deliveries {
+		if d.Status == domain.DeliveryDead {
+			dead = append(dead, d)
+		}
+	}
+	return dead, nil
+}
+
+func (m *memoryRepository) Retry(ctx context.Context, id int, at datetime.Time) error {
+	if id < 1 || id > len(m.deliveries) || m.deliveries[id-1].Status != domain.DeliveryDead {
+		return webhook.ErrDeliveryNotFound
+	}
+	m.deliveries[id-1].Status = domain.DeliveryPending
+	m.deliveries[id-1].Attempts = 0
+	m.deliveries[id-1].NextAttemptAt = at
+	return nil
+}
+
+func InitDispatcher(t *testing.T) (*mocks.WebhookRepositoryMock, *webhook.Dispatcher) {
+	t.Helper()
+	repository := &mocks.WebhookRepositoryMock{}
+	return repository, webhook.NewDispatcher(repository, nil)
+}
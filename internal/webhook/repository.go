@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
+)
+
+const (
+	SaveQuery       = "INSERT INTO webhooks (url, secret, created_at) VALUES (?, ?, ?)"
+	SaveEventQuery  = "INSERT INTO webhook_events (webhook_id, event_type) VALUES (?, ?)"
+	GetAllQuery     = "SELECT id, url, secret, created_at FROM webhooks ORDER BY id"
+	GetQuery        = "SELECT id, url, secret, created_at FROM webhooks WHERE id = ?"
+	EventTypesQuery = "SELECT webhook_id, event_type FROM webhook_events ORDER BY webhook_id, event_type"
+	EventsQuery     = "SELECT event_type FROM webhook_events WHERE webhook_id = ? ORDER BY event_type"
+	DeleteQuery     = "DELETE FROM webhooks WHERE id = ?"
+	EnqueueQuery    = "INSERT INTO webhook_deliveries (webhook_id, event_type, payload, status, attempts, next_attempt_at, created_at) " +
+		"SELECT webhook_id, event_type, ?, ?, 0, ?, ? FROM webhook_events WHERE event_type = ?"
+	DueQuery = "SELECT d.id, d.webhook_id, w.url, w.secret, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, " +
+		"COALESCE(d.last_error, ''), COALESCE(d.response_status, 0), d.created_at, d.delivered_at " +
+		"FROM webhook_deliveries d JOIN webhooks w ON d.webhook_id = w.id " +
+		"WHERE d.status = ? AND d.next_attempt_at <= ? ORDER BY d.next_attempt_at, d.id LIMIT ? FOR UPDATE SKIP LOCKED"
+	LeaseQuery          = "UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ?"
+	UpdateDeliveryQuery = "UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_error = ?, " +
+		"response_status = ?, delivered_at = ? WHERE id = ?"
+	DeadQuery = "SELECT d.id, d.webhook_id, w.url, w.secret, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, " +
+		"COALESCE(d.last_error, ''), COALESCE(d.response_status, 0), d.created_at, d.delivered_at " +
+		"FROM webhook_deliveries d JOIN webhooks w ON d.webhook_id = w.id WHERE d.status = ? ORDER BY d.id"
+	RetryQuery = "UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ?, last_error = NULL, response_status = NULL " +
+		"WHERE id = ? AND status = ?"
+)
+
+// Repository encapsulates the storage of webhooks and of the delivery queue.
+// Claim leases the deliveries it returns, so several dispatchers can share
+// the queue without sending the same delivery twice at the same time.
+type Repository interface {
+	Save(ctx context.Context, w domain.Webhook) (int, error)
+	GetAll(ctx context.Context) ([]domain.Webhook, error)
+	Get(ctx context.Context, id int) (domain.Webhook, error)
+	Delete(ctx context.Context, id int) error
+	Enqueue(ctx context.Context, event string, payload []byte, at datetime.Time) (int, error)
+	Claim(ctx context.Context, now datetime.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error
+	Dead(ctx context.Context) ([]domain.WebhookDelivery, error)
+	Retry(ctx context.Context, id int, at datetime.Time) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// Save stores the webhook and its event types in a single transaction.
+func (r *repository) Save(ctx context.Context, w domain.Webhook) (int, error) {
+	var id int
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, SaveQuery, w.URL, w.Secret, w.CreatedAt)
+		if err != nil {
+			return err
+		}
+		insertedID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		id = int(insertedID)
+
+		for _, event := range w.Events {
+			if _, err := tx.ExecContext(ctx, SaveEventQuery, id, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]domain.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, GetAllQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []domain.Webhook{}
+	for rows.Next() {
+		w := domain.Webhook{Events: []string{}}
+		if err := rows.Scan(&w.ID, &w.URL, &w.Secret, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	events, err := r.eventTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		if types, ok := events[webhooks[i].ID]; ok {
+			webhooks[i].Events = types
+		}
+	}
+	return webhooks, nil
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.Webhook, error) {
+	w := domain.Webhook{Events: []string{}}
+	err := r.db.QueryRowContext(ctx, GetQuery, id).Scan(&w.ID, &w.URL, &w.Secret, &w.CreatedAt)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return domain.Webhook{}, ErrNotFound
+		}
+		return domain.Webhook{}, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, EventsQuery, id)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event string
+		if err := rows.Scan(&event); err != nil {
+			return domain.Webhook{}, err
+		}
+		w.Events = append(w.Events, event)
+	}
+	return w, rows.Err()
+}
+
+func (r *repository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, DeleteQuery, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Enqueue queues a pending delivery of the payload for every webhook
+// subscribed to the event and returns how many were queued.
+func (r *repository) Enqueue(ctx context.Context, event string, payload []byte, at datetime.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, EnqueueQuery, string(payload), domain.DeliveryPending, at, at, event)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
+
+// Claim returns up to limit pending deliveries due at now and pushes their
+// next attempt lease further away. A delivery whose dispatcher dies before
+// recording the outcome is picked up again once the lease expires.
+func (r *repository) Claim(ctx context.Context, now datetime.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := sqltx.Run(ctx, r.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, DueQuery, domain.DeliveryPending, now, limit)
+		if err != nil {
+			return err
+		}
+		deliveries, err = scanDeliveries(rows)
+		if err != nil {
+			return err
+		}
+
+		until := datetime.New(now.Add(lease))
+		for _, d := range deliveries {
+			if _, err := tx.ExecContext(ctx, LeaseQuery, until, d.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery records the outcome of an attempt.
+func (r *repository) UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx, UpdateDeliveryQuery, d.Status, d.Attempts, d.NextAttemptAt, mysqlutil.NullableString(d.LastError),
+		mysqlutil.NullableInt(d.ResponseStatus), d.DeliveredAt, d.ID)
+	return err
+}
+
+// Dead lists the deliveries that ran out of attempts.
+func (r *repository) Dead(ctx context.Context) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, DeadQuery, domain.DeliveryDead)
+	if err != nil {
+		return nil, err
+	}
+	return scanDeliveries(rows)
+}
+
+// Retry queues a dead delivery again, with a fresh set of attempts.
+func (r *repository) Retry(ctx context.Context, id int, at datetime.Time) error {
+	result, err := r.db.ExecContext(ctx, RetryQuery, domain.DeliveryPending, at, id, domain.DeliveryDead)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrDeliveryNotFound
+	}
+	return nil
+}
+
+// eventTypes returns the event types of every webhook, keyed by webhook.
+func (r *repository) eventTypes(ctx context.Context) (map[int][]string, error) {
+	rows, err := r.db.QueryContext(ctx, EventTypesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := map[int][]string{}
+	for rows.Next() {
+		var id int
+		var event string
+		if err := rows.Scan(&id, &event); err != nil {
+			return nil, err
+		}
+		events[id] = append(events[id], event)
+	}
+	return events, rows.Err()
+}
+
+func scanDeliveries(rows *sql.Rows) ([]domain.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		d := domain.WebhookDelivery{}
+		var payload string
+		err := rows.Scan(&d.ID, &d.WebhookID, &d.URL, &d.Secret, &d.Event, &payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.LastError, &d.ResponseStatus, &d.CreatedAt, &d.DeliveredAt)
+		if err != nil {
+			return nil, err
+		}
+		d.Payload = []byte(payload)
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+)
+
+var (
+	ErrNotFound         = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("dead delivery not found")
+	ErrInvalidURL       = errors.New("url must be an absolute http or https url")
+	ErrInvalidEvents    = errors.New("events must list at least one known event type")
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+)
+
+// Events lists the event types a webhook can subscribe to.
+var Events = []string{
+	domain.EventProductCreated,
+	domain.EventProductUpdated,
+	domain.EventProductDeleted,
+	domain.EventProductBatchCreated,
+	domain.EventPurchaseOrderCreated,
+	domain.EventPurchaseOrderAssigned,
+	domain.EventPurchaseOrderStatusChanged,
+	domain.EventInboundOrderCreated,
+	domain.EventTransferCreated,
+	domain.EventTransferDispatched,
+	domain.EventTransferReceived,
+	domain.EventSectionUpdated,
+	domain.EventSellerCreated,
+	domain.EventSellerUpdated,
+	domain.EventSellerDeleted,
+}
+
+// Service manages webhooks. It is an outbox publisher: the relay hands it
+// every domain event and Publish queues it for the subscribed webhooks.
+type Service interface {
+	Publish(ctx context.Context, e domain.Event) error
+	Create(ctx context.Context, req domain.WebhookRequest) (domain.Webhook, error)
+	GetAll(ctx context.Context) ([]domain.Webhook, error)
+	Get(ctx context.Context, id int) (domain.Webhook, error)
+	Delete(ctx context.Context, id int) error
+	Test(ctx context.Context, id int) (domain.WebhookTest, error)
+	DeadLetters(ctx context.Context) ([]domain.WebhookDelivery, error)
+	Retry(ctx context.Context, id int) error
+}
+
+type service struct {
+	repository Repository
+	sender     *sender
+}
+
+// NewService returns a service that sends test pings with client, or with a
+// client bounded by DefaultTimeout when it is nil.
+func NewService(r Repository, client *http.Client) Service {
+	return &service{
+		repository: r,
+		sender:     newSender(client),
+	}
+}
+
+// Create subscribes a URL to the requested events and returns it with the
+// secret its deliveries are signed with.
+func (s *service) Create(ctx context.Context, req domain.WebhookRequest) (domain.Webhook, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.Webhook{}, ErrInvalidURL
+	}
+	events, err := checkEvents(req.Events)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	w := domain.Webhook{
+		URL:       req.URL,
+		Events:    events,
+		Secret:    secret,
+		CreatedAt: datetime.Now(),
+	}
+	id, err := s.repository.Save(ctx, w)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	w.ID = id
+	return w, nil
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.Webhook, error) {
+	webhooks, err := s.repository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.Webhook, error) {
+	w, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	w.Secret = ""
+	return w, nil
+}
+
+func (s *service) Delete(ctx context.Context, id int) error {
+	return s.repository.Delete(ctx, id)
+}
+
+// Publish queues a delivery of the event for every webhook subscribed to it.
+// The deliveries are sent by the Dispatcher.
+func (s *service) Publish(ctx context.Context, e domain.Event) error {
+	payload, err := json.Marshal(domain.WebhookEvent{ID: e.ID, Type: e.Type, OccurredAt: e.OccurredAt, Data: e.Data})
+	if err != nil {
+		return err
+	}
+	_, err = s.repository.Enqueue(ctx, e.Type, payload, datetime.Now())
+	return err
+}
+
+// Test sends a signed ping to the webhook right away, without queueing it,
+// and reports how the receiver answered.
+func (s *service) Test(ctx context.Context, id int) (domain.WebhookTest, error) {
+	w, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return domain.WebhookTest{}, err
+	}
+	payload, err := json.Marshal(domain.WebhookEvent{
+		Type:       domain.EventPing,
+		OccurredAt: datetime.Now(),
+		Data:       map[string]int{"webhook_id": w.ID},
+	})
+	if err != nil {
+		return domain.WebhookTest{}, err
+	}
+
+	status, err := s.sender.send(ctx, domain.WebhookDelivery{
+		WebhookID: w.ID,
+		URL:       w.URL,
+		Secret:    w.Secret,
+		Event:     domain.EventPing,
+		Payload:   payload,
+	})
+	result := domain.WebhookTest{WebhookID: w.ID, Delivered: err == nil, ResponseStatus: status}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+func (s *service) DeadLetters(ctx context.Context) ([]domain.WebhookDelivery, error) {
+	return s.repository.Dead(ctx)
+}
+
+// Retry queues a dead delivery again, to be sent on the next dispatch.
+func (s *service) Retry(ctx context.Context, id int) error {
+	return s.repository.Retry(ctx, id, datetime.Now())
+}
+
+// checkEvents returns the requested events without duplicates, or
+// ErrInvalidEvents if one is unknown or none is given.
+func checkEvents(requested []string) ([]string, error) {
+	known := map[string]bool{}
+	for _, event := range Events {
+		known[event] = true
+	}
+
+	events := []string{}
+	seen := map[string]bool{}
+	for _, event := range requested {
+		if !known[event] {
+			return nil, ErrInvalidEvents
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		return nil, ErrInvalidEvents
+	}
+	return events, nil
+}
+
+// newSecret returns a random 32-byte secret, hex encoded.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,161 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/webhook"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	mocks "github.com/extmatperez/meli_bootcamp_go_w2-3/tests/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	t.Run("Should store the webhook with a new secret", func(t *testing.T) {
+		repository, service := InitWebhookService(t)
+		repository.On("Save", mock.Anything, mock.MatchedBy(func(w domain.Webhook) bool {
+			return len(w.Secret) == 64 && !w.CreatedAt.IsZero()
+		})).Return(4, nil)
+
+		result, err := service.Create(context.TODO(), domain.WebhookRequest{
+			URL:    "https://erp.example.com/hooks",
+			Events: []string{domain.EventSellerUpdated, domain.EventPurchaseOrderCreated, domain.EventSellerUpdated},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result.ID)
+		assert.Equal(t, []string{domain.EventSellerUpdated, domain.EventPurchaseOrderCreated}, result.Events)
+		assert.Len(t, result.Secret, 64)
+	})
+	t.Run("Should reject an invalid url", func(t *testing.T) {
+		_, service := InitWebhookService(t)
+		for _, url := range []string{"", "erp.example.com/hooks", "ftp://erp.example.com", "https://"} {
+			_, err := service.Create(context.TODO(), domain.WebhookRequest{URL: url, Events: []string{domain.EventSellerUpdated}})
+			assert.ErrorIs(t, err, webhook.ErrInvalidURL, url)
+		}
+	})
+	t.Run("Should reject unknown or missing events", func(t *testing.T) {
+		_, service := InitWebhookService(t)
+		for _, events := range [][]string{nil, {"seller.archived"}, {domain.EventPing}} {
+			_, err := service.Create(context.TODO(), domain.WebhookRequest{URL: "https://erp.example.com/hooks", Events: events})
+			assert.ErrorIs(t, err, webhook.ErrInvalidEvents)
+		}
+	})
+}
+
+func TestGetWebhook(t *testing.T) {
+	t.Run("Should not show the secret", func(t *testing.T) {
+		repository, service := InitWebhookService(t)
+		repository.On("GetAll", mock.Anything).Return([]domain.Webhook{{ID: 1, Secret: "secret"}}, nil)
+		repository.On("Get", mock.Anything, 1).Return(domain.Webhook{ID: 1, Secret: "secret"}, nil)
+
+		webhooks, err := service.GetAll(context.TODO())
+		assert.NoError(t, err)
+		assert.Empty(t, webhooks[0].Secret)
+
+		w, err := service.Get(context.TODO(), 1)
+		assert.NoError(t, err)
+		assert.Empty(t, w.Secret)
+	})
+}
+
+func TestPublishWebhook(t *testing.T) {
+	t.Run("Should queue the event with its id and data", func(t *testing.T) {
+		repository, service := InitWebhookService(t)
+		var payload []byte
+		repository.On("Enqueue", mock.Anything, domain.EventSellerUpdated, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			payload = args.Get(2).([]byte)
+		}).Return(2, nil)
+
+		err := service.Publish(context.TODO(), domain.Event{
+			ID:          12,
+			AggregateID: 7,
+			Type:        domain.EventSellerUpdated,
+			Data:        []byte(`{"id":7,"cid":70}`),
+			OccurredAt:  datetime.MustParse("2023-07-03 10:00:00"),
+		})
+
+		assert.NoError(t, err)
+		event := struct {
+			ID         int64         `json:"id"`
+			Type       string        `json:"type"`
+			OccurredAt datetime.Time `json:"occurred_at"`
+			Data       domain.Seller `json:"data"`
+		}{}
+		assert.NoError(t, json.Unmarshal(payload, &event))
+		assert.Equal(t, int64(12), event.ID)
+		assert.Equal(t, domain.EventSellerUpdated, event.Type)
+		assert.Equal(t, datetime.MustParse("2023-07-03 10:00:00"), event.OccurredAt)
+		assert.Equal(t, 70, event.Data.CID)
+	})
+}
+
+func TestTestWebhook(t *testing.T) {
+	t.Run("Should send a signed ping to the receiver", func(t *testing.T) {
+		var signature, timestamp, event string
+		var body []byte
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			signature = r.Header.Get(webhook.HeaderSignature)
+			timestamp = r.Header.Get(webhook.HeaderTimestamp)
+			event = r.Header.Get(webhook.HeaderEvent)
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer receiver.Close()
+
+		repository, service := InitWebhookService(t)
+		repository.On("Get", mock.Anything, 1).Return(domain.Webhook{ID: 1, URL: receiver.URL, Secret: "secret"}, nil)
+
+		result, err := service.Test(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.WebhookTest{WebhookID: 1, Delivered: true, ResponseStatus: http.StatusNoContent}, result)
+		assert.Equal(t, domain.EventPing, event)
+		assert.Equal(t, webhook.Sign("secret", timestamp, body), signature)
+	})
+	t.Run("Should report a failing receiver", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer receiver.Close()
+
+		repository, service := InitWebhookService(t)
+		repository.On("Get", mock.Anything, 1).Return(domain.Webhook{ID: 1, URL: receiver.URL, Secret: "secret"}, nil)
+
+		result, err := service.Test(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.False(t, result.Delivered)
+		assert.Equal(t, http.StatusBadGateway, result.ResponseStatus)
+		assert.NotEmpty(t, result.Error)
+	})
+	t.Run("Should return not found for an unknown webhook", func(t *testing.T) {
+		repository, service := InitWebhookService(t)
+		repository.On("Get", mock.Anything, 1).Return(domain.Webhook{}, webhook.ErrNotFound)
+
+		_, err := service.Test(context.TODO(), 1)
+
+		assert.ErrorIs(t, err, webhook.ErrNotFound)
+	})
+}
+
+func TestRetryWebhookDelivery(t *testing.T) {
+	t.Run("Should requeue the dead delivery", func(t *testing.T) {
+		repository, service := InitWebhookService(t)
+		repository.On("Retry", mock.Anything, 3, mock.Anything).Return(webhook.ErrDeliveryNotFound)
+
+		assert.ErrorIs(t, service.Retry(context.TODO(), 3), webhook.ErrDeliveryNotFound)
+	})
+}
+
+func InitWebhookService(t *testing.T) (*mocks.WebhookRepositoryMock, webhook.Service) {
+	t.Helper()
+	repository := &mocks.WebhookRepositoryMock{}
+	return repository, webhook.NewService(repository, nil)
+}
@@ -0,0 +1,142 @@
+// Package datetime provides the timestamp type used by the domain models. It
+// reads RFC 3339 and date-only input, stores every value in UTC and converts
+// to and from DATETIME columns whether or not the MySQL driver is configured
+// with parseTime.
+package datetime
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DateLayout is the layout of date-only values.
+const DateLayout = "2006-01-02"
+
+// sqlLayout is the layout MySQL uses for DATETIME columns when the driver
+// returns them as text. Fractional seconds are accepted when parsing.
+const sqlLayout = "2006-01-02 15:04:05"
+
+var layouts = []string{time.RFC3339Nano, sqlLayout, DateLayout}
+
+var ErrInvalid = errors.New("invalid date")
+
+// Time is a point in time normalised to UTC. The zero value means unset.
+type Time struct {
+	time.Time
+}
+
+// New returns t in UTC.
+func New(t time.Time) Time {
+	if t.IsZero() {
+		return Time{}
+	}
+	return Time{t.UTC()}
+}
+
+// Now returns the current time in UTC.
+func Now() Time {
+	return New(time.Now())
+}
+
+// Parse reads an RFC 3339 timestamp, a "2006-01-02 15:04:05" date-time or a
+// "2006-01-02" date. Values without an offset are read as UTC.
+func Parse(s string) (Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return New(t), nil
+		}
+	}
+	return Time{}, fmt.Errorf("%w: %q", ErrInvalid, s)
+}
+
+// MustParse is like Parse but panics on error. It is meant for constants and
+// tests.
+func MustParse(s string) Time {
+	t, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Date returns the calendar date of t as "2006-01-02".
+func (t Time) Date() string {
+	return t.Format(DateLayout)
+}
+
+func (t Time) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// MarshalJSON writes t as an RFC 3339 string, or null when unset.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Format(time.RFC3339Nano))
+}
+
+// UnmarshalJSON reads any format accepted by Parse, so malformed dates are
+// rejected when the request is bound.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*t = Time{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalid, data)
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Value writes t in UTC. An unset value is written as NULL.
+func (t Time) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return t.UTC(), nil
+}
+
+// Scan reads a DATETIME column, either as a time.Time when the driver parses
+// times or as text when it does not.
+func (t *Time) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = Time{}
+		return nil
+	case time.Time:
+		*t = New(v)
+		return nil
+	case []byte:
+		return t.scanString(string(v))
+	case string:
+		return t.scanString(v)
+	}
+	return fmt.Errorf("datetime: cannot scan %T", src)
+}
+
+func (t *Time) scanString(s string) error {
+	if s == "" || s == "0000-00-00" || s == "0000-00-00 00:00:00" {
+		*t = Time{}
+		return nil
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
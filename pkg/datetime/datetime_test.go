@@ -0,0 +1,83 @@
+package datetime_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Should accept RFC 3339 and date-only input in UTC", func(t *testing.T) {
+		inputs := map[string]time.Time{
+			"2023-07-05":                time.Date(2023, 7, 5, 0, 0, 0, 0, time.UTC),
+			"2023-07-05 10:00:00":       time.Date(2023, 7, 5, 10, 0, 0, 0, time.UTC),
+			"2023-07-05T10:00:00-03:00": time.Date(2023, 7, 5, 13, 0, 0, 0, time.UTC),
+		}
+		for input, expected := range inputs {
+			parsed, err := datetime.Parse(input)
+
+			assert.NoError(t, err, input)
+			assert.Equal(t, expected, parsed.Time, input)
+			assert.Equal(t, time.UTC, parsed.Location(), input)
+		}
+	})
+
+	t.Run("Should reject malformed dates", func(t *testing.T) {
+		for _, input := range []string{"", "01/01/01", "2023-13-01", "yesterday"} {
+			_, err := datetime.Parse(input)
+
+			assert.True(t, errors.Is(err, datetime.ErrInvalid), input)
+		}
+	})
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("Should round trip through JSON", func(t *testing.T) {
+		var v struct {
+			At datetime.Time `json:"at"`
+		}
+
+		assert.NoError(t, json.Unmarshal([]byte(`{"at":"2023-07-05"}`), &v))
+		b, err := json.Marshal(v)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"at":"2023-07-05T00:00:00Z"}`, string(b))
+	})
+
+	t.Run("Should fail to bind an invalid date", func(t *testing.T) {
+		var v struct {
+			At datetime.Time `json:"at"`
+		}
+
+		err := json.Unmarshal([]byte(`{"at":"05/07/2023"}`), &v)
+
+		assert.True(t, errors.Is(err, datetime.ErrInvalid))
+	})
+}
+
+func TestScan(t *testing.T) {
+	t.Run("Should scan text and parsed DATETIME(6) values", func(t *testing.T) {
+		expected := time.Date(2023, 7, 5, 10, 0, 0, 123456000, time.UTC)
+		sources := []interface{}{
+			[]byte("2023-07-05 10:00:00.123456"),
+			time.Date(2023, 7, 5, 7, 0, 0, 123456000, time.FixedZone("", -3*60*60)),
+		}
+		for _, src := range sources {
+			var v datetime.Time
+
+			assert.NoError(t, v.Scan(src))
+			assert.Equal(t, expected, v.Time)
+		}
+	})
+
+	t.Run("Should write an unset time as NULL", func(t *testing.T) {
+		value, err := datetime.Time{}.Value()
+
+		assert.NoError(t, err)
+		assert.Nil(t, value)
+	})
+}
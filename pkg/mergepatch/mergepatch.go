@@ -0,0 +1,181 @@
+// Package mergepatch applies JSON merge patches (RFC 7396) to structs: members
+// absent from the patch are left unchanged, null members are cleared and any
+// other value, including 0 and "", replaces the current one. Only nullable
+// fields, pointers, slices and maps, can be cleared, so that a null member is
+// never mistaken for a zero value.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrInvalidPatch = errors.New("invalid merge patch")
+	ErrNotNullable  = errors.New("member cannot be null")
+)
+
+// Valid reports whether patch is a JSON object, the only kind of patch that
+// can be applied to a struct.
+func Valid(patch []byte) bool {
+	_, err := decodeObject(patch)
+	return err == nil
+}
+
+// Apply applies patch to the JSON encoding of target, a pointer to a struct,
+// and decodes the result back into it. Fields skipped by the encoding keep
+// their value. A patch that is not an object or whose values do not fit the
+// fields of target returns ErrInvalidPatch, and a patch with a null member
+// for a field that is not nullable returns ErrNotNullable. Either way target
+// is left unchanged.
+func Apply(target interface{}, patch []byte) error {
+	changes, err := decodeObject(patch)
+	if err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return ErrInvalidPatch
+	}
+	if err := checkNulls(value.Elem().Type(), changes); err != nil {
+		return err
+	}
+
+	original, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	document, err := decodeObject(original)
+	if err != nil {
+		return err
+	}
+	merged, err := json.Marshal(merge(document, changes))
+	if err != nil {
+		return err
+	}
+
+	result := reflect.New(value.Elem().Type())
+	result.Elem().Set(value.Elem())
+	clearEncoded(result.Elem())
+	if err := json.Unmarshal(merged, result.Interface()); err != nil {
+		return ErrInvalidPatch
+	}
+	value.Elem().Set(result.Elem())
+	return nil
+}
+
+// merge is the MergePatch function of RFC 7396.
+func merge(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = merge(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// checkNulls reports whether every null member of patch, at any depth, is
+// for a nullable field of t. Members matching no field are ignored, as
+// decoding ignores them.
+func checkNulls(t reflect.Type, patch map[string]interface{}) error {
+	for name, value := range patch {
+		field, ok := fieldByName(t, name)
+		if !ok {
+			continue
+		}
+		fieldType := field.Type
+		switch value := value.(type) {
+		case nil:
+			switch fieldType.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			default:
+				return ErrNotNullable
+			}
+		case map[string]interface{}:
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				if err := checkNulls(fieldType, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fieldByName returns the field of t that the JSON member name decodes into,
+// looking into embedded structs and matching names without regard to case as
+// encoding/json does.
+func fieldByName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			if embedded, ok := fieldByName(field.Type, name); ok {
+				return embedded, true
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		if strings.EqualFold(tag, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// decodeObject decodes a JSON object keeping numbers as written, so large
+// integers survive the round trip.
+func decodeObject(data []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil || object == nil {
+		return nil, ErrInvalidPatch
+	}
+	if decoder.More() {
+		return nil, ErrInvalidPatch
+	}
+	return object, nil
+}
+
+// clearEncoded zeroes the fields of v that appear in its JSON encoding, so
+// members removed by the patch end up cleared.
+func clearEncoded(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if strings.Split(field.Tag.Get("json"), ",")[0] == "-" {
+			continue
+		}
+		v.Field(i).Set(reflect.Zero(field.Type))
+	}
+}
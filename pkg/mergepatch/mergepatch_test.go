@@ -0,0 +1,129 @@
+package mergepatch_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mergepatch"
+	"github.com/stretchr/testify/assert"
+)
+
+type location struct {
+	City string  `json:"city"`
+	Zip  *string `json:"zip,omitempty"`
+}
+
+type resource struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Temperature float64  `json:"temperature"`
+	Capacity    *int     `json:"capacity,omitempty"`
+	Tags        []string `json:"tags"`
+	Location    location `json:"location"`
+	Version     int      `json:"-"`
+}
+
+func stored() resource {
+	capacity, zip := 40, "5000"
+	return resource{
+		ID:          1,
+		Name:        "north",
+		Temperature: -18,
+		Capacity:    &capacity,
+		Tags:        []string{"frozen"},
+		Location:    location{City: "Córdoba", Zip: &zip},
+		Version:     3,
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Run("Should leave absent members unchanged", func(t *testing.T) {
+		r := stored()
+
+		err := mergepatch.Apply(&r, []byte(`{"name":"south"}`))
+
+		expected := stored()
+		expected.Name = "south"
+		assert.NoError(t, err)
+		assert.Equal(t, expected, r)
+	})
+	t.Run("Should set zero values given explicitly", func(t *testing.T) {
+		r := stored()
+
+		err := mergepatch.Apply(&r, []byte(`{"temperature":0,"capacity":0,"name":""}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, r.Temperature)
+		assert.Equal(t, 0, *r.Capacity)
+		assert.Equal(t, "", r.Name)
+	})
+	t.Run("Should clear null members", func(t *testing.T) {
+		r := stored()
+
+		err := mergepatch.Apply(&r, []byte(`{"tags":null,"capacity":null}`))
+
+		assert.NoError(t, err)
+		assert.Nil(t, r.Tags)
+		assert.Nil(t, r.Capacity)
+		assert.Equal(t, "north", r.Name)
+	})
+	t.Run("Should reject null members for fields that are not nullable", func(t *testing.T) {
+		for _, patch := range []string{`{"name":null}`, `{"temperature":null,"tags":null}`, `{"location":{"city":null}}`, `{"location":null}`} {
+			r := stored()
+
+			err := mergepatch.Apply(&r, []byte(patch))
+
+			assert.True(t, errors.Is(err, mergepatch.ErrNotNullable), patch)
+			assert.Equal(t, stored(), r, patch)
+		}
+	})
+	t.Run("Should ignore null members matching no field", func(t *testing.T) {
+		r := stored()
+
+		err := mergepatch.Apply(&r, []byte(`{"unknown":null,"version":null}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, stored(), r)
+	})
+	t.Run("Should merge nested objects and replace arrays", func(t *testing.T) {
+		r := stored()
+
+		err := mergepatch.Apply(&r, []byte(`{"location":{"zip":null},"tags":["chilled","dry"]}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, location{City: "Córdoba"}, r.Location)
+		assert.Equal(t, []string{"chilled", "dry"}, r.Tags)
+	})
+	t.Run("Should keep fields hidden from JSON", func(t *testing.T) {
+		r := stored()
+
+		err := mergepatch.Apply(&r, []byte(`{"version":9}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, r.Version)
+	})
+	t.Run("Should reject patches that are not objects", func(t *testing.T) {
+		for _, patch := range []string{``, `null`, `[]`, `"name"`, `{"name":`, `{} {}`} {
+			r := stored()
+
+			err := mergepatch.Apply(&r, []byte(patch))
+
+			assert.True(t, errors.Is(err, mergepatch.ErrInvalidPatch), patch)
+			assert.Equal(t, stored(), r, patch)
+		}
+	})
+	t.Run("Should reject values that do not fit the fields without changing them", func(t *testing.T) {
+		r := stored()
+
+		err := mergepatch.Apply(&r, []byte(`{"name":"south","capacity":"full"}`))
+
+		assert.True(t, errors.Is(err, mergepatch.ErrInvalidPatch))
+		assert.Equal(t, stored(), r)
+	})
+}
+
+func TestValid(t *testing.T) {
+	assert.True(t, mergepatch.Valid([]byte(`{"name":null}`)))
+	assert.False(t, mergepatch.Valid([]byte(`["name"]`)))
+	assert.False(t, mergepatch.Valid([]byte(`not json`)))
+}
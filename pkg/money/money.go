@@ -0,0 +1,298 @@
+// Package money represents monetary amounts exactly, as a whole number of
+// cents and a currency code, matching the DECIMAL(19, 2) columns used to
+// store them.
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places kept for every amount.
+const Scale = 2
+
+// DefaultCurrency is used for amounts read or decoded without a currency.
+const DefaultCurrency = "USD"
+
+var (
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrInvalidCurrency  = errors.New("invalid currency code")
+	ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+	ErrUnknownRate      = errors.New("no conversion rate for currency")
+)
+
+var decimalPattern = regexp.MustCompile(`^[-+]?[0-9]+(\.[0-9]+)?$`)
+
+// Money is an exact amount in a currency. The zero value is zero in
+// DefaultCurrency.
+type Money struct {
+	cents    int64
+	currency string
+}
+
+// New returns cents hundredths of currency.
+func New(cents int64, currency string) Money {
+	return Money{cents: cents, currency: strings.ToUpper(currency)}
+}
+
+// Parse reads a decimal amount such as "10", "-3.5" or "1234.56". Amounts with
+// more than Scale decimal places are rejected instead of being rounded.
+func Parse(amount, currency string) (Money, error) {
+	cents, err := parseCents(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if !validCurrency(currency) {
+		return Money{}, ErrInvalidCurrency
+	}
+	return New(cents, currency), nil
+}
+
+// MustParse is like Parse but panics on error. It is meant for constants and
+// tests.
+func MustParse(amount, currency string) Money {
+	m, err := Parse(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Cents returns the amount in hundredths of the currency.
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+// Currency returns the ISO 4217 code of the amount.
+func (m Money) Currency() string {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+// In returns the same amount labelled with another currency. It does not
+// convert; use Rates.Convert for that.
+func (m Money) In(currency string) Money {
+	return New(m.cents, currency)
+}
+
+// Amount returns the decimal representation of the amount, always with Scale
+// decimal places.
+func (m Money) Amount() string {
+	cents := m.cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func (m Money) String() string {
+	return m.Amount() + " " + m.Currency()
+}
+
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the amount.
+func (m Money) Sign() int {
+	switch {
+	case m.cents < 0:
+		return -1
+	case m.cents > 0:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency() != o.Currency() {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return New(m.cents+o.cents, m.Currency()), nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency() != o.Currency() {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return New(m.cents-o.cents, m.Currency()), nil
+}
+
+// Cmp compares two amounts in the same currency and returns -1, 0 or +1.
+func (m Money) Cmp(o Money) (int, error) {
+	diff, err := m.Sub(o)
+	if err != nil {
+		return 0, err
+	}
+	return diff.Sign(), nil
+}
+
+// Percent returns m as a percentage of total rounded to two decimals, or 0
+// when total is zero.
+func (m Money) Percent(total Money) float64 {
+	if total.cents == 0 {
+		return 0
+	}
+	ratio := new(big.Rat).SetFrac64(m.cents*100, total.cents)
+	f, _ := new(big.Rat).SetInt(roundRat(ratio, 2)).Float64()
+	return f / 100
+}
+
+// Value stores the amount as a decimal string so the database keeps every cent.
+func (m Money) Value() (driver.Value, error) {
+	return m.Amount(), nil
+}
+
+// Scan reads a DECIMAL column. The currency is kept if it was already set,
+// since it is stored in its own column.
+func (m *Money) Scan(src interface{}) error {
+	var cents int64
+	var err error
+	switch v := src.(type) {
+	case nil:
+		cents = 0
+	case []byte:
+		cents, err = parseCents(string(v))
+	case string:
+		cents, err = parseCents(v)
+	case int64:
+		cents = v * 100
+	case float64:
+		cents, err = parseCents(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+	if err != nil {
+		return err
+	}
+	m.cents = cents
+	return nil
+}
+
+type jsonMoney struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON writes {"amount":"10.50","currency":"USD"}. The amount is a
+// string so clients parsing numbers as floats do not lose precision.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Amount(), m.Currency()})
+}
+
+// UnmarshalJSON accepts the object written by MarshalJSON, with the amount as
+// a string or a number, as well as a bare number or string in DefaultCurrency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	currency := ""
+	raw := data
+	if len(data) > 0 && data[0] == '{' {
+		var v jsonMoney
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		currency = v.Currency
+		raw = v.Amount
+	}
+
+	amount, err := decodeAmount(raw)
+	if err != nil {
+		return err
+	}
+	parsed, err := Parse(amount, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// decodeAmount returns the literal text of a JSON number or string, without
+// going through float64.
+func decodeAmount(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", ErrInvalidAmount
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return "", ErrInvalidAmount
+	}
+	return n.String(), nil
+}
+
+func parseCents(amount string) (int64, error) {
+	amount = strings.TrimSpace(amount)
+	if !decimalPattern.MatchString(amount) {
+		return 0, ErrInvalidAmount
+	}
+	r, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return 0, ErrInvalidAmount
+	}
+	r.Mul(r, big.NewRat(100, 1))
+	if !r.IsInt() {
+		return 0, ErrInvalidAmount
+	}
+	if !r.Num().IsInt64() {
+		return 0, ErrInvalidAmount
+	}
+	return r.Num().Int64(), nil
+}
+
+func validCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range strings.ToUpper(code) {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// roundRat rounds r to the given number of decimals, half away from zero,
+// and returns it scaled by 10^decimals.
+func roundRat(r *big.Rat, decimals int) *big.Int {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(scale))
+
+	num := new(big.Int).Abs(scaled.Num())
+	den := scaled.Denom()
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if scaled.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q
+}
@@ -0,0 +1,98 @@
+package money_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Should keep every cent", func(t *testing.T) {
+		m, err := money.Parse("1234.56", "usd")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(123456), m.Cents())
+		assert.Equal(t, "1234.56", m.Amount())
+		assert.Equal(t, "USD", m.Currency())
+	})
+
+	t.Run("Should reject amounts with more than two decimals", func(t *testing.T) {
+		_, err := money.Parse("10.005", "USD")
+
+		assert.ErrorIs(t, err, money.ErrInvalidAmount)
+	})
+
+	t.Run("Should reject malformed amounts and currencies", func(t *testing.T) {
+		for _, amount := range []string{"", "1e3", "1/2", "0x10", "ten"} {
+			_, err := money.Parse(amount, "USD")
+			assert.ErrorIs(t, err, money.ErrInvalidAmount, amount)
+		}
+
+		_, err := money.Parse("1", "US")
+		assert.ErrorIs(t, err, money.ErrInvalidCurrency)
+	})
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("Should write the amount as an exact string", func(t *testing.T) {
+		b, err := json.Marshal(money.MustParse("-0.5", "EUR"))
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"amount":"-0.50","currency":"EUR"}`, string(b))
+	})
+
+	t.Run("Should read objects, numbers and strings", func(t *testing.T) {
+		inputs := map[string]money.Money{
+			`{"amount":"10.10","currency":"BRL"}`: money.MustParse("10.1", "BRL"),
+			`{"amount":10.1,"currency":"BRL"}`:    money.MustParse("10.1", "BRL"),
+			`0.29`:                                money.MustParse("0.29", "USD"),
+			`"7"`:                                 money.MustParse("7", "USD"),
+		}
+		for input, expected := range inputs {
+			var m money.Money
+			assert.NoError(t, json.Unmarshal([]byte(input), &m), input)
+			assert.Equal(t, expected, m, input)
+		}
+	})
+}
+
+func TestScan(t *testing.T) {
+	var m money.Money
+
+	assert.NoError(t, m.Scan([]byte("19.99")))
+	assert.Equal(t, int64(1999), m.Cents())
+
+	v, err := m.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "19.99", v)
+}
+
+func TestConvert(t *testing.T) {
+	rates, err := money.ParseRates("USD", "EUR=1.08, BRL=0.2")
+	assert.NoError(t, err)
+
+	t.Run("Should convert through the base currency and round to the cent", func(t *testing.T) {
+		converted, err := rates.Convert(money.MustParse("10", "EUR"), "BRL")
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("54", "BRL"), converted)
+
+		converted, err = rates.Convert(money.MustParse("1", "USD"), "EUR")
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("0.93", "EUR"), converted)
+	})
+
+	t.Run("Should return an error for unknown currencies", func(t *testing.T) {
+		_, err := rates.Convert(money.MustParse("1", "USD"), "ARS")
+
+		assert.ErrorIs(t, err, money.ErrUnknownRate)
+	})
+}
+
+func TestPercent(t *testing.T) {
+	assert.Equal(t, 33.33, money.MustParse("1", "USD").Percent(money.MustParse("3", "USD")))
+	assert.Equal(t, 0.0, money.MustParse("1", "USD").Percent(money.Money{}))
+}
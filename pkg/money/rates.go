@@ -0,0 +1,94 @@
+package money
+
+import (
+	"math/big"
+	"os"
+	"strings"
+)
+
+// RatesEnv is the environment variable read by RatesFromEnv.
+const RatesEnv = "MONEY_RATES"
+
+// Rates is a local table of conversion rates, each expressed as the value of
+// one unit of a currency in the base currency.
+type Rates struct {
+	base  string
+	rates map[string]*big.Rat
+}
+
+// NewRates builds a table from decimal rates keyed by currency code. The base
+// currency always has a rate of 1.
+func NewRates(base string, rates map[string]string) (*Rates, error) {
+	base = strings.ToUpper(base)
+	if !validCurrency(base) {
+		return nil, ErrInvalidCurrency
+	}
+
+	table := &Rates{base: base, rates: map[string]*big.Rat{base: big.NewRat(1, 1)}}
+	for code, value := range rates {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !validCurrency(code) {
+			return nil, ErrInvalidCurrency
+		}
+		rate, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+		if !ok || rate.Sign() <= 0 {
+			return nil, ErrInvalidAmount
+		}
+		table.rates[code] = rate
+	}
+	return table, nil
+}
+
+// ParseRates reads a table written as "EUR=1.08,BRL=0.2" relative to base.
+func ParseRates(base, spec string) (*Rates, error) {
+	rates := map[string]string{}
+	for _, entry := range strings.Split(spec, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, ErrInvalidAmount
+		}
+		rates[parts[0]] = parts[1]
+	}
+	return NewRates(base, rates)
+}
+
+// RatesFromEnv reads the table from MONEY_RATES, relative to DefaultCurrency.
+// An unset variable yields a table that only knows DefaultCurrency.
+func RatesFromEnv() (*Rates, error) {
+	return ParseRates(DefaultCurrency, os.Getenv(RatesEnv))
+}
+
+// Base returns the currency every rate is expressed in.
+func (r *Rates) Base() string {
+	return r.base
+}
+
+// Convert returns m in the given currency, rounded half away from zero to the
+// cent.
+func (r *Rates) Convert(m Money, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if currency == m.Currency() {
+		return m, nil
+	}
+	from, ok := r.rates[m.Currency()]
+	if !ok {
+		return Money{}, ErrUnknownRate
+	}
+	to, ok := r.rates[currency]
+	if !ok {
+		return Money{}, ErrUnknownRate
+	}
+
+	amount := new(big.Rat).SetFrac64(m.cents, 100)
+	amount.Mul(amount, from)
+	amount.Quo(amount, to)
+
+	cents := roundRat(amount, Scale)
+	if !cents.IsInt64() {
+		return Money{}, ErrInvalidAmount
+	}
+	return New(cents.Int64(), currency), nil
+}
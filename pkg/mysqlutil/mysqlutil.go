@@ -0,0 +1,69 @@
+// Package mysqlutil tells the MySQL errors repositories report apart and
+// builds query arguments: IN lists, and NULL for unset values.
+package mysqlutil
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL error numbers.
+const (
+	// ErrDuplicateEntry is a unique index violation.
+	ErrDuplicateEntry = 1062
+	// ErrRowReferenced is the deletion of a row other rows still reference.
+	ErrRowReferenced = 1451
+	// ErrNoReferencedRow is a foreign key pointing to a missing row.
+	ErrNoReferencedRow = 1452
+)
+
+// is reports whether err is a MySQL error with the given number.
+func is(err error, number uint16) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == number
+}
+
+// IsDuplicateEntry reports whether err is a unique index violation.
+func IsDuplicateEntry(err error) bool {
+	return is(err, ErrDuplicateEntry)
+}
+
+// IsRowReferenced reports whether err is the deletion of a row other rows
+// still reference.
+func IsRowReferenced(err error) bool {
+	return is(err, ErrRowReferenced)
+}
+
+// IsNoReferencedRow reports whether err is a foreign key pointing to a
+// missing row.
+func IsNoReferencedRow(err error) bool {
+	return is(err, ErrNoReferencedRow)
+}
+
+// NullableInt stores 0, such as an unset reference, as NULL.
+func NullableInt(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+// NullableString stores a blank string as NULL.
+func NullableString(s string) interface{} {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return s
+}
+
+// In returns the placeholders of an IN list of ids, such as "(?,?,?)", and
+// the ids as query arguments. ids must not be empty.
+func In(ids []int) (string, []interface{}) {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return "(?" + strings.Repeat(",?", len(ids)-1) + ")", args
+}
@@ -0,0 +1,59 @@
+package mysqlutil_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mysqlutil"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	t.Run("Should tell the errors apart by their number", func(t *testing.T) {
+		duplicate := &mysql.MySQLError{Number: mysqlutil.ErrDuplicateEntry}
+		referenced := &mysql.MySQLError{Number: mysqlutil.ErrRowReferenced}
+		missing := &mysql.MySQLError{Number: mysqlutil.ErrNoReferencedRow}
+
+		assert.True(t, mysqlutil.IsDuplicateEntry(duplicate))
+		assert.False(t, mysqlutil.IsDuplicateEntry(missing))
+		assert.True(t, mysqlutil.IsRowReferenced(referenced))
+		assert.False(t, mysqlutil.IsRowReferenced(duplicate))
+		assert.True(t, mysqlutil.IsNoReferencedRow(missing))
+		assert.False(t, mysqlutil.IsNoReferencedRow(referenced))
+	})
+
+	t.Run("Should find the error when it is wrapped", func(t *testing.T) {
+		err := fmt.Errorf("saving: %w", &mysql.MySQLError{Number: mysqlutil.ErrDuplicateEntry})
+
+		assert.True(t, mysqlutil.IsDuplicateEntry(err))
+	})
+
+	t.Run("Should not match other errors", func(t *testing.T) {
+		assert.False(t, mysqlutil.IsDuplicateEntry(errors.New("Duplicate entry")))
+		assert.False(t, mysqlutil.IsDuplicateEntry(nil))
+	})
+}
+
+func TestNullable(t *testing.T) {
+	t.Run("Should store unset values as NULL", func(t *testing.T) {
+		assert.Nil(t, mysqlutil.NullableInt(0))
+		assert.Nil(t, mysqlutil.NullableString(""))
+		assert.Nil(t, mysqlutil.NullableString("  "))
+	})
+
+	t.Run("Should keep set values", func(t *testing.T) {
+		assert.Equal(t, 7, mysqlutil.NullableInt(7))
+		assert.Equal(t, "timeout", mysqlutil.NullableString("timeout"))
+	})
+}
+
+func TestIn(t *testing.T) {
+	t.Run("Should build a placeholder per id", func(t *testing.T) {
+		in, args := mysqlutil.In([]int{4, 8, 15})
+
+		assert.Equal(t, "(?,?,?)", in)
+		assert.Equal(t, []interface{}{4, 8, 15}, args)
+	})
+}
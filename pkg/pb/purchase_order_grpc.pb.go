@@ -0,0 +1,222 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: purchase_order.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PurchaseOrderService_GetPurchaseOrder_FullMethodName    = "/melisprint.v1.PurchaseOrderService/GetPurchaseOrder"
+	PurchaseOrderService_ListPurchaseOrders_FullMethodName  = "/melisprint.v1.PurchaseOrderService/ListPurchaseOrders"
+	PurchaseOrderService_CreatePurchaseOrder_FullMethodName = "/melisprint.v1.PurchaseOrderService/CreatePurchaseOrder"
+	PurchaseOrderService_AssignPurchaseOrder_FullMethodName = "/melisprint.v1.PurchaseOrderService/AssignPurchaseOrder"
+)
+
+// PurchaseOrderServiceClient is the client API for PurchaseOrderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PurchaseOrderServiceClient interface {
+	GetPurchaseOrder(ctx context.Context, in *GetPurchaseOrderRequest, opts ...grpc.CallOption) (*PurchaseOrder, error)
+	ListPurchaseOrders(ctx context.Context, in *ListPurchaseOrdersRequest, opts ...grpc.CallOption) (*ListPurchaseOrdersResponse, error)
+	CreatePurchaseOrder(ctx context.Context, in *CreatePurchaseOrderRequest, opts ...grpc.CallOption) (*PurchaseOrder, error)
+	// AssignPurchaseOrder picks the warehouse and the carrier of an order.
+	AssignPurchaseOrder(ctx context.Context, in *AssignPurchaseOrderRequest, opts ...grpc.CallOption) (*PurchaseOrderAssignment, error)
+}
+
+type purchaseOrderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPurchaseOrderServiceClient(cc grpc.ClientConnInterface) PurchaseOrderServiceClient {
+	return &purchaseOrderServiceClient{cc}
+}
+
+func (c *purchaseOrderServiceClient) GetPurchaseOrder(ctx context.Context, in *GetPurchaseOrderRequest, opts ...grpc.CallOption) (*PurchaseOrder, error) {
+	out := new(PurchaseOrder)
+	err := c.cc.Invoke(ctx, PurchaseOrderService_GetPurchaseOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *purchaseOrderServiceClient) ListPurchaseOrders(ctx context.Context, in *ListPurchaseOrdersRequest, opts ...grpc.CallOption) (*ListPurchaseOrdersResponse, error) {
+	out := new(ListPurchaseOrdersResponse)
+	err := c.cc.Invoke(ctx, PurchaseOrderService_ListPurchaseOrders_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *purchaseOrderServiceClient) CreatePurchaseOrder(ctx context.Context, in *CreatePurchaseOrderRequest, opts ...grpc.CallOption) (*PurchaseOrder, error) {
+	out := new(PurchaseOrder)
+	err := c.cc.Invoke(ctx, PurchaseOrderService_CreatePurchaseOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *purchaseOrderServiceClient) AssignPurchaseOrder(ctx context.Context, in *AssignPurchaseOrderRequest, opts ...grpc.CallOption) (*PurchaseOrderAssignment, error) {
+	out := new(PurchaseOrderAssignment)
+	err := c.cc.Invoke(ctx, PurchaseOrderService_AssignPurchaseOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PurchaseOrderServiceServer is the server API for PurchaseOrderService service.
+// All implementations must embed UnimplementedPurchaseOrderServiceServer
+// for forward compatibility
+type PurchaseOrderServiceServer interface {
+	GetPurchaseOrder(context.Context, *GetPurchaseOrderRequest) (*PurchaseOrder, error)
+	ListPurchaseOrders(context.Context, *ListPurchaseOrdersRequest) (*ListPurchaseOrdersResponse, error)
+	CreatePurchaseOrder(context.Context, *CreatePurchaseOrderRequest) (*PurchaseOrder, error)
+	// AssignPurchaseOrder picks the warehouse and the carrier of an order.
+	AssignPurchaseOrder(context.Context, *AssignPurchaseOrderRequest) (*PurchaseOrderAssignment, error)
+	mustEmbedUnimplementedPurchaseOrderServiceServer()
+}
+
+// UnimplementedPurchaseOrderServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPurchaseOrderServiceServer struct {
+}
+
+func (UnimplementedPurchaseOrderServiceServer) GetPurchaseOrder(context.Context, *GetPurchaseOrderRequest) (*PurchaseOrder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPurchaseOrder not implemented")
+}
+func (UnimplementedPurchaseOrderServiceServer) ListPurchaseOrders(context.Context, *ListPurchaseOrdersRequest) (*ListPurchaseOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPurchaseOrders not implemented")
+}
+func (UnimplementedPurchaseOrderServiceServer) CreatePurchaseOrder(context.Context, *CreatePurchaseOrderRequest) (*PurchaseOrder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePurchaseOrder not implemented")
+}
+func (UnimplementedPurchaseOrderServiceServer) AssignPurchaseOrder(context.Context, *AssignPurchaseOrderRequest) (*PurchaseOrderAssignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignPurchaseOrder not implemented")
+}
+func (UnimplementedPurchaseOrderServiceServer) mustEmbedUnimplementedPurchaseOrderServiceServer() {}
+
+// UnsafePurchaseOrderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PurchaseOrderServiceServer will
+// result in compilation errors.
+type UnsafePurchaseOrderServiceServer interface {
+	mustEmbedUnimplementedPurchaseOrderServiceServer()
+}
+
+func RegisterPurchaseOrderServiceServer(s grpc.ServiceRegistrar, srv PurchaseOrderServiceServer) {
+	s.RegisterService(&PurchaseOrderService_ServiceDesc, srv)
+}
+
+func _PurchaseOrderService_GetPurchaseOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPurchaseOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurchaseOrderServiceServer).GetPurchaseOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PurchaseOrderService_GetPurchaseOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurchaseOrderServiceServer).GetPurchaseOrder(ctx, req.(*GetPurchaseOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PurchaseOrderService_ListPurchaseOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPurchaseOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurchaseOrderServiceServer).ListPurchaseOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PurchaseOrderService_ListPurchaseOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurchaseOrderServiceServer).ListPurchaseOrders(ctx, req.(*ListPurchaseOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PurchaseOrderService_CreatePurchaseOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePurchaseOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurchaseOrderServiceServer).CreatePurchaseOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PurchaseOrderService_CreatePurchaseOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurchaseOrderServiceServer).CreatePurchaseOrder(ctx, req.(*CreatePurchaseOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PurchaseOrderService_AssignPurchaseOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignPurchaseOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurchaseOrderServiceServer).AssignPurchaseOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PurchaseOrderService_AssignPurchaseOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurchaseOrderServiceServer).AssignPurchaseOrder(ctx, req.(*AssignPurchaseOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PurchaseOrderService_ServiceDesc is the grpc.ServiceDesc for PurchaseOrderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PurchaseOrderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "melisprint.v1.PurchaseOrderService",
+	HandlerType: (*PurchaseOrderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPurchaseOrder",
+			Handler:    _PurchaseOrderService_GetPurchaseOrder_Handler,
+		},
+		{
+			MethodName: "ListPurchaseOrders",
+			Handler:    _PurchaseOrderService_ListPurchaseOrders_Handler,
+		},
+		{
+			MethodName: "CreatePurchaseOrder",
+			Handler:    _PurchaseOrderService_CreatePurchaseOrder_Handler,
+		},
+		{
+			MethodName: "AssignPurchaseOrder",
+			Handler:    _PurchaseOrderService_AssignPurchaseOrder_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "purchase_order.proto",
+}
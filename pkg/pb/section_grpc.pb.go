@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: section.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	SectionService_GetSection_FullMethodName    = "/melisprint.v1.SectionService/GetSection"
+	SectionService_ListSections_FullMethodName  = "/melisprint.v1.SectionService/ListSections"
+	SectionService_CreateSection_FullMethodName = "/melisprint.v1.SectionService/CreateSection"
+	SectionService_UpdateSection_FullMethodName = "/melisprint.v1.SectionService/UpdateSection"
+	SectionService_DeleteSection_FullMethodName = "/melisprint.v1.SectionService/DeleteSection"
+)
+
+// SectionServiceClient is the client API for SectionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SectionServiceClient interface {
+	GetSection(ctx context.Context, in *GetSectionRequest, opts ...grpc.CallOption) (*Section, error)
+	ListSections(ctx context.Context, in *ListSectionsRequest, opts ...grpc.CallOption) (*ListSectionsResponse, error)
+	CreateSection(ctx context.Context, in *CreateSectionRequest, opts ...grpc.CallOption) (*Section, error)
+	// UpdateSection replaces every field of the section.
+	UpdateSection(ctx context.Context, in *UpdateSectionRequest, opts ...grpc.CallOption) (*Section, error)
+	DeleteSection(ctx context.Context, in *DeleteSectionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type sectionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSectionServiceClient(cc grpc.ClientConnInterface) SectionServiceClient {
+	return &sectionServiceClient{cc}
+}
+
+func (c *sectionServiceClient) GetSection(ctx context.Context, in *GetSectionRequest, opts ...grpc.CallOption) (*Section, error) {
+	out := new(Section)
+	err := c.cc.Invoke(ctx, SectionService_GetSection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sectionServiceClient) ListSections(ctx context.Context, in *ListSectionsRequest, opts ...grpc.CallOption) (*ListSectionsResponse, error) {
+	out := new(ListSectionsResponse)
+	err := c.cc.Invoke(ctx, SectionService_ListSections_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sectionServiceClient) CreateSection(ctx context.Context, in *CreateSectionRequest, opts ...grpc.CallOption) (*Section, error) {
+	out := new(Section)
+	err := c.cc.Invoke(ctx, SectionService_CreateSection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sectionServiceClient) UpdateSection(ctx context.Context, in *UpdateSectionRequest, opts ...grpc.CallOption) (*Section, error) {
+	out := new(Section)
+	err := c.cc.Invoke(ctx, SectionService_UpdateSection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sectionServiceClient) DeleteSection(ctx context.Context, in *DeleteSectionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, SectionService_DeleteSection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SectionServiceServer is the server API for SectionService service.
+// All implementations must embed UnimplementedSectionServiceServer
+// for forward compatibility
+type SectionServiceServer interface {
+	GetSection(context.Context, *GetSectionRequest) (*Section, error)
+	ListSections(context.Context, *ListSectionsRequest) (*ListSectionsResponse, error)
+	CreateSection(context.Context, *CreateSectionRequest) (*Section, error)
+	// UpdateSection replaces every field of the section.
+	UpdateSection(context.Context, *UpdateSectionRequest) (*Section, error)
+	DeleteSection(context.Context, *DeleteSectionRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedSectionServiceServer()
+}
+
+// UnimplementedSectionServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedSectionServiceServer struct {
+}
+
+func (UnimplementedSectionServiceServer) GetSection(context.Context, *GetSectionRequest) (*Section, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSection not implemented")
+}
+func (UnimplementedSectionServiceServer) ListSections(context.Context, *ListSectionsRequest) (*ListSectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSections not implemented")
+}
+func (UnimplementedSectionServiceServer) CreateSection(context.Context, *CreateSectionRequest) (*Section, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSection not implemented")
+}
+func (UnimplementedSectionServiceServer) UpdateSection(context.Context, *UpdateSectionRequest) (*Section, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSection not implemented")
+}
+func (UnimplementedSectionServiceServer) DeleteSection(context.Context, *DeleteSectionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSection not implemented")
+}
+func (UnimplementedSectionServiceServer) mustEmbedUnimplementedSectionServiceServer() {}
+
+// UnsafeSectionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SectionServiceServer will
+// result in compilation errors.
+type UnsafeSectionServiceServer interface {
+	mustEmbedUnimplementedSectionServiceServer()
+}
+
+func RegisterSectionServiceServer(s grpc.ServiceRegistrar, srv SectionServiceServer) {
+	s.RegisterService(&SectionService_ServiceDesc, srv)
+}
+
+func _SectionService_GetSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SectionServiceServer).GetSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SectionService_GetSection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SectionServiceServer).GetSection(ctx, req.(*GetSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SectionService_ListSections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SectionServiceServer).ListSections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SectionService_ListSections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SectionServiceServer).ListSections(ctx, req.(*ListSectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SectionService_CreateSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SectionServiceServer).CreateSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SectionService_CreateSection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SectionServiceServer).CreateSection(ctx, req.(*CreateSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SectionService_UpdateSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SectionServiceServer).UpdateSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SectionService_UpdateSection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SectionServiceServer).UpdateSection(ctx, req.(*UpdateSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SectionService_DeleteSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SectionServiceServer).DeleteSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SectionService_DeleteSection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SectionServiceServer).DeleteSection(ctx, req.(*DeleteSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SectionService_ServiceDesc is the grpc.ServiceDesc for SectionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SectionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "melisprint.v1.SectionService",
+	HandlerType: (*SectionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSection",
+			Handler:    _SectionService_GetSection_Handler,
+		},
+		{
+			MethodName: "ListSections",
+			Handler:    _SectionService_ListSections_Handler,
+		},
+		{
+			MethodName: "CreateSection",
+			Handler:    _SectionService_CreateSection_Handler,
+		},
+		{
+			MethodName: "UpdateSection",
+			Handler:    _SectionService_UpdateSection_Handler,
+		},
+		{
+			MethodName: "DeleteSection",
+			Handler:    _SectionService_DeleteSection_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "section.proto",
+}
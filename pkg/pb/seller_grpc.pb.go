@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: seller.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	SellerService_GetSeller_FullMethodName    = "/melisprint.v1.SellerService/GetSeller"
+	SellerService_ListSellers_FullMethodName  = "/melisprint.v1.SellerService/ListSellers"
+	SellerService_CreateSeller_FullMethodName = "/melisprint.v1.SellerService/CreateSeller"
+	SellerService_UpdateSeller_FullMethodName = "/melisprint.v1.SellerService/UpdateSeller"
+	SellerService_DeleteSeller_FullMethodName = "/melisprint.v1.SellerService/DeleteSeller"
+)
+
+// SellerServiceClient is the client API for SellerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SellerServiceClient interface {
+	GetSeller(ctx context.Context, in *GetSellerRequest, opts ...grpc.CallOption) (*Seller, error)
+	ListSellers(ctx context.Context, in *ListSellersRequest, opts ...grpc.CallOption) (*ListSellersResponse, error)
+	CreateSeller(ctx context.Context, in *CreateSellerRequest, opts ...grpc.CallOption) (*Seller, error)
+	// UpdateSeller changes the fields that are set and keeps the others.
+	UpdateSeller(ctx context.Context, in *UpdateSellerRequest, opts ...grpc.CallOption) (*Seller, error)
+	DeleteSeller(ctx context.Context, in *DeleteSellerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type sellerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSellerServiceClient(cc grpc.ClientConnInterface) SellerServiceClient {
+	return &sellerServiceClient{cc}
+}
+
+func (c *sellerServiceClient) GetSeller(ctx context.Context, in *GetSellerRequest, opts ...grpc.CallOption) (*Seller, error) {
+	out := new(Seller)
+	err := c.cc.Invoke(ctx, SellerService_GetSeller_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sellerServiceClient) ListSellers(ctx context.Context, in *ListSellersRequest, opts ...grpc.CallOption) (*ListSellersResponse, error) {
+	out := new(ListSellersResponse)
+	err := c.cc.Invoke(ctx, SellerService_ListSellers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sellerServiceClient) CreateSeller(ctx context.Context, in *CreateSellerRequest, opts ...grpc.CallOption) (*Seller, error) {
+	out := new(Seller)
+	err := c.cc.Invoke(ctx, SellerService_CreateSeller_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sellerServiceClient) UpdateSeller(ctx context.Context, in *UpdateSellerRequest, opts ...grpc.CallOption) (*Seller, error) {
+	out := new(Seller)
+	err := c.cc.Invoke(ctx, SellerService_UpdateSeller_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sellerServiceClient) DeleteSeller(ctx context.Context, in *DeleteSellerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, SellerService_DeleteSeller_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SellerServiceServer is the server API for SellerService service.
+// All implementations must embed UnimplementedSellerServiceServer
+// for forward compatibility
+type SellerServiceServer interface {
+	GetSeller(context.Context, *GetSellerRequest) (*Seller, error)
+	ListSellers(context.Context, *ListSellersRequest) (*ListSellersResponse, error)
+	CreateSeller(context.Context, *CreateSellerRequest) (*Seller, error)
+	// UpdateSeller changes the fields that are set and keeps the others.
+	UpdateSeller(context.Context, *UpdateSellerRequest) (*Seller, error)
+	DeleteSeller(context.Context, *DeleteSellerRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedSellerServiceServer()
+}
+
+// UnimplementedSellerServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedSellerServiceServer struct {
+}
+
+func (UnimplementedSellerServiceServer) GetSeller(context.Context, *GetSellerRequest) (*Seller, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSeller not implemented")
+}
+func (UnimplementedSellerServiceServer) ListSellers(context.Context, *ListSellersRequest) (*ListSellersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSellers not implemented")
+}
+func (UnimplementedSellerServiceServer) CreateSeller(context.Context, *CreateSellerRequest) (*Seller, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSeller not implemented")
+}
+func (UnimplementedSellerServiceServer) UpdateSeller(context.Context, *UpdateSellerRequest) (*Seller, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSeller not implemented")
+}
+func (UnimplementedSellerServiceServer) DeleteSeller(context.Context, *DeleteSellerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSeller not implemented")
+}
+func (UnimplementedSellerServiceServer) mustEmbedUnimplementedSellerServiceServer() {}
+
+// UnsafeSellerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SellerServiceServer will
+// result in compilation errors.
+type UnsafeSellerServiceServer interface {
+	mustEmbedUnimplementedSellerServiceServer()
+}
+
+func RegisterSellerServiceServer(s grpc.ServiceRegistrar, srv SellerServiceServer) {
+	s.RegisterService(&SellerService_ServiceDesc, srv)
+}
+
+func _SellerService_GetSeller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSellerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SellerServiceServer).GetSeller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SellerService_GetSeller_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SellerServiceServer).GetSeller(ctx, req.(*GetSellerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SellerService_ListSellers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSellersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SellerServiceServer).ListSellers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SellerService_ListSellers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SellerServiceServer).ListSellers(ctx, req.(*ListSellersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SellerService_CreateSeller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSellerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SellerServiceServer).CreateSeller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SellerService_CreateSeller_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SellerServiceServer).CreateSeller(ctx, req.(*CreateSellerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SellerService_UpdateSeller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSellerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SellerServiceServer).UpdateSeller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SellerService_UpdateSeller_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SellerServiceServer).UpdateSeller(ctx, req.(*UpdateSellerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SellerService_DeleteSeller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSellerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SellerServiceServer).DeleteSeller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SellerService_DeleteSeller_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SellerServiceServer).DeleteSeller(ctx, req.(*DeleteSellerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SellerService_ServiceDesc is the grpc.ServiceDesc for SellerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SellerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "melisprint.v1.SellerService",
+	HandlerType: (*SellerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSeller",
+			Handler:    _SellerService_GetSeller_Handler,
+		},
+		{
+			MethodName: "ListSellers",
+			Handler:    _SellerService_ListSellers_Handler,
+		},
+		{
+			MethodName: "CreateSeller",
+			Handler:    _SellerService_CreateSeller_Handler,
+		},
+		{
+			MethodName: "UpdateSeller",
+			Handler:    _SellerService_UpdateSeller_Handler,
+		},
+		{
+			MethodName: "DeleteSeller",
+			Handler:    _SellerService_DeleteSeller_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "seller.proto",
+}
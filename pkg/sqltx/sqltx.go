@@ -0,0 +1,21 @@
+// Package sqltx runs functions in database transactions.
+package sqltx
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Run runs fn in a transaction of db, committing if it succeeds and rolling
+// back otherwise.
+func Run(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,63 @@
+package sqltx_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/sqltx"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is a database driver that only records how its transactions end.
+type recorder struct {
+	commits, rollbacks int
+}
+
+func (r *recorder) Open(name string) (driver.Conn, error) { return conn{r}, nil }
+
+type conn struct{ r *recorder }
+
+func (c conn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c conn) Close() error                              { return nil }
+func (c conn) Begin() (driver.Tx, error)                 { return tx(c), nil }
+
+type tx conn
+
+func (t tx) Commit() error   { t.r.commits++; return nil }
+func (t tx) Rollback() error { t.r.rollbacks++; return nil }
+
+func open(t *testing.T, name string) (*recorder, *sql.DB) {
+	t.Helper()
+	r := &recorder{}
+	sql.Register(name, r)
+	db, err := sql.Open(name, "")
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return r, db
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Should commit when fn succeeds", func(t *testing.T) {
+		r, db := open(t, "sqltx-commit")
+
+		err := sqltx.Run(context.Background(), db, func(tx *sql.Tx) error { return nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, r.commits)
+		assert.Equal(t, 0, r.rollbacks)
+	})
+
+	t.Run("Should roll back and return the error of fn", func(t *testing.T) {
+		r, db := open(t, "sqltx-rollback")
+		failed := errors.New("failed")
+
+		err := sqltx.Run(context.Background(), db, func(tx *sql.Tx) error { return failed })
+
+		assert.ErrorIs(t, err, failed)
+		assert.Equal(t, 0, r.commits)
+		assert.Equal(t, 1, r.rollbacks)
+	})
+}
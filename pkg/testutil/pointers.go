@@ -0,0 +1,11 @@
+package testutil
+
+// Int returns a pointer to v, for the optional fields of test fixtures.
+func Int(v int) *int {
+	return &v
+}
+
+// Float64 returns a pointer to v, for the optional fields of test fixtures.
+func Float64(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,81 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnyVersion is returned by IfMatch when the client accepts any version of
+// the resource.
+const AnyVersion = 0
+
+var (
+	ErrIfMatchRequired = errors.New("If-Match header is required")
+	ErrInvalidIfMatch  = errors.New("If-Match header does not match any version")
+)
+
+// ETag formats the version of a resource as a strong entity tag.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// SetETag writes the entity tag of the resource version to the response.
+func SetETag(c *gin.Context, version int) {
+	c.Header("ETag", ETag(version))
+}
+
+// NotModified writes the entity tag of the resource version and, when it
+// matches the If-None-Match header, answers 304 Not Modified. The caller must
+// not write a body when it returns true.
+func NotModified(c *gin.Context, version int) bool {
+	SetETag(c, version)
+
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	current := ETag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// IfMatch returns the resource version required by the If-Match header, or
+// AnyVersion for "*". Entity tags are compared strongly, so weak tags and
+// values this API never issued are rejected with ErrInvalidIfMatch.
+func IfMatch(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, ErrIfMatchRequired
+	}
+	if header == "*" {
+		return AnyVersion, nil
+	}
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, ErrInvalidIfMatch
+	}
+	version, err := strconv.Atoi(header[1 : len(header)-1])
+	if err != nil || version <= 0 {
+		return 0, ErrInvalidIfMatch
+	}
+	return version, nil
+}
+
+// PreconditionError answers a request whose If-Match header is missing or
+// unusable.
+func PreconditionError(c *gin.Context, err error) {
+	if errors.Is(err, ErrIfMatchRequired) {
+		Error(c, http.StatusPreconditionRequired, err.Error())
+		return
+	}
+	Error(c, http.StatusPreconditionFailed, err.Error())
+}
@@ -0,0 +1,356 @@
+package web
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Format is a response media type negotiated from the Accept header.
+type Format string
+
+const (
+	FormatJSON   Format = "application/json"
+	FormatCSV    Format = "text/csv"
+	FormatNDJSON Format = "application/x-ndjson"
+	FormatXLSX   Format = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// flushEvery is the number of rows written between two flushes of the
+// underlying connection while exporting.
+const flushEvery = 100
+
+var ErrUnsupportedExport = errors.New("data cannot be exported")
+
+var extensions = map[Format]string{
+	FormatCSV:    "csv",
+	FormatNDJSON: "ndjson",
+	FormatXLSX:   "xlsx",
+}
+
+// Negotiate returns the format the client weighs highest in its Accept
+// header, or FormatJSON when it does not prefer any export format. Ranges
+// with the same weight are preferred in the order they are listed, and a
+// wildcard range stands for FormatJSON.
+func Negotiate(c *gin.Context) Format {
+	best, bestWeight := FormatJSON, 0.0
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		weight := 1.0
+		if q, ok := params["q"]; ok {
+			weight, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		format := Format(mediaType)
+		if _, ok := extensions[format]; !ok {
+			if format != FormatJSON && mediaType != "*/*" && mediaType != "application/*" {
+				continue
+			}
+			format = FormatJSON
+		}
+		if weight > bestWeight {
+			best, bestWeight = format, weight
+		}
+	}
+	return best
+}
+
+// ExportFormat reports whether the client asked for an export format.
+func ExportFormat(c *gin.Context) (Format, bool) {
+	format := Negotiate(c)
+	return format, format != FormatJSON
+}
+
+// Stream writes the rows produced by fill in the given export format. Nothing
+// is written before the first row is emitted, so an error returned by fill
+// before that point can still be reported as a regular JSON error.
+func Stream(c *gin.Context, format Format, row interface{}, fill func(emit func(interface{}) error) error) error {
+	exporter, err := NewExporter(c, format, row)
+	if err != nil {
+		return err
+	}
+	if err := fill(exporter.Write); err != nil {
+		if exporter.started {
+			_ = exporter.Close()
+		}
+		return err
+	}
+	return exporter.Close()
+}
+
+// Export writes data, either a single struct or a slice of structs, in the
+// given export format.
+func Export(c *gin.Context, format Format, data interface{}) error {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Slice {
+		return Stream(c, format, data, func(emit func(interface{}) error) error {
+			return emit(data)
+		})
+	}
+
+	row := reflect.Zero(value.Type().Elem()).Interface()
+	return Stream(c, format, row, func(emit func(interface{}) error) error {
+		for i := 0; i < value.Len(); i++ {
+			if err := emit(value.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Exporter encodes rows of a single struct type to the response as they are
+// written, without buffering the whole table.
+type Exporter struct {
+	c       *gin.Context
+	format  Format
+	columns []column
+	encoder rowEncoder
+	rows    int
+	started bool
+}
+
+type column struct {
+	name  string
+	index int
+}
+
+type rowEncoder interface {
+	header(names []string) error
+	row(values []interface{}) error
+	close() error
+}
+
+// NewExporter prepares an exporter whose columns are taken from the json tags
+// of row, which must be a struct.
+func NewExporter(c *gin.Context, format Format, row interface{}) (*Exporter, error) {
+	if _, ok := extensions[format]; !ok {
+		return nil, ErrUnsupportedExport
+	}
+	columns, err := columnsOf(reflect.TypeOf(row))
+	if err != nil {
+		return nil, err
+	}
+	return &Exporter{c: c, format: format, columns: columns}, nil
+}
+
+// Write encodes one row, sending the response headers on the first call.
+func (e *Exporter) Write(row interface{}) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(row))
+	values := make([]interface{}, len(e.columns))
+	for i, col := range e.columns {
+		field := value.Field(col.index)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+		values[i] = field.Interface()
+	}
+	if err := e.encoder.row(values); err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%flushEvery == 0 {
+		e.c.Writer.Flush()
+	}
+	return nil
+}
+
+// Close finishes the document. An empty export still carries its header row.
+func (e *Exporter) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	if err := e.encoder.close(); err != nil {
+		return err
+	}
+	e.c.Writer.Flush()
+	return nil
+}
+
+func (e *Exporter) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	name := path.Base(e.c.FullPath())
+	if name == "." || name == "/" || strings.HasPrefix(name, ":") {
+		name = "export"
+	}
+
+	e.c.Header("Content-Type", string(e.format))
+	e.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+extensions[e.format]))
+	e.c.Status(http.StatusOK)
+
+	switch e.format {
+	case FormatCSV:
+		e.encoder = &csvEncoder{w: csv.NewWriter(e.c.Writer)}
+	case FormatNDJSON:
+		e.encoder = &ndjsonEncoder{w: e.c.Writer}
+	case FormatXLSX:
+		e.encoder = &xlsxEncoder{zw: zip.NewWriter(e.c.Writer)}
+	}
+
+	names := make([]string, len(e.columns))
+	for i, col := range e.columns {
+		names[i] = col.name
+	}
+	return e.encoder.header(names)
+}
+
+func columnsOf(t reflect.Type) ([]column, error) {
+	if t == nil {
+		return nil, ErrUnsupportedExport
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, ErrUnsupportedExport
+	}
+
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, column{name: name, index: i})
+	}
+	return columns, nil
+}
+
+// cell renders a value the way it would appear in the JSON response, a nil
+// value as an empty cell.
+func cell(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Marshaler:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		var s string
+		if json.Unmarshal(b, &s) == nil {
+			return s
+		}
+		return string(b)
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) header(names []string) error {
+	return e.w.Write(names)
+}
+
+func (e *csvEncoder) row(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = cell(v)
+	}
+	if err := e.w.Write(record); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonEncoder struct {
+	w     io.Writer
+	names []string
+}
+
+func (e *ndjsonEncoder) header(names []string) error {
+	e.names = names
+	return nil
+}
+
+func (e *ndjsonEncoder) row(values []interface{}) error {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(e.names[i])
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *ndjsonEncoder) close() error {
+	return nil
+}
@@ -0,0 +1,22 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/mergepatch"
+	"github.com/gin-gonic/gin"
+)
+
+var ErrInvalidMergePatch = errors.New("body must be a JSON merge patch object")
+
+// MergePatch reads the request body as a JSON merge patch (RFC 7396), which
+// must be an object. It is accepted as application/merge-patch+json as well
+// as application/json.
+func MergePatch(c *gin.Context) (json.RawMessage, error) {
+	body, err := c.GetRawData()
+	if err != nil || !mergepatch.Valid(body) {
+		return nil, ErrInvalidMergePatch
+	}
+	return body, nil
+}
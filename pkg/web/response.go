@@ -22,19 +22,32 @@ func Response(c *gin.Context, status int, data interface{}) {
 	c.JSON(status, data)
 }
 
+// Success writes data wrapped in the standard envelope. When the client asks
+// for an export format in the Accept header, successful responses are
+// written in that format instead.
 func Success(c *gin.Context, status int, data interface{}) {
+	if format, ok := ExportFormat(c); ok && status == http.StatusOK {
+		if err := Export(c, format, data); err != ErrUnsupportedExport {
+			return
+		}
+	}
 	Response(c, status, response{Data: data})
 }
 
 // NewErrorf creates a new error with the given status code and the message
 // formatted according to args and format.
 func Error(c *gin.Context, status int, format string, args ...interface{}) {
+	ErrorWithCode(c, status, strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_"), format, args...)
+}
+
+// ErrorWithCode is like Error but with an explicit code, so clients can tell
+// apart errors that share a status.
+func ErrorWithCode(c *gin.Context, status int, code string, format string, args ...interface{}) {
 	err := ErrorResponse{
-		Code:    strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_"),
+		Code:    code,
 		Message: fmt.Sprintf(format, args...),
 		Status:  status,
 	}
 
 	Response(c, status, err)
-
 }
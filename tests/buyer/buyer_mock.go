@@ -25,8 +25,8 @@ func (m *BuyerServiceMock) Get(ctx context.Context, id int) (domain.Buyer, error
 	return args.Get(0).(domain.Buyer), args.Error(1)
 }
 
-func (m *BuyerServiceMock) Update(ctx context.Context, b domain.Buyer, id int) (domain.Buyer, error) {
-	args := m.Called(ctx, b, id)
+func (m *BuyerServiceMock) Update(ctx context.Context, id int, p domain.Patch) (domain.Buyer, error) {
+	args := m.Called(ctx, id, p)
 	return args.Get(0).(domain.Buyer), args.Error(1)
 }
 
@@ -99,3 +99,38 @@ func (m *BuyerRepositoryMock) Save(ctx context.Context, b domain.Buyer) (int, er
 	args := m.Called(ctx, b)
 	return args.Get(0).(int), args.Error(1)
 }
+
+func (m *BuyerServiceMock) Orders(ctx context.Context, id int) ([]domain.BuyerOrder, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]domain.BuyerOrder), args.Error(1)
+}
+
+func (m *BuyerServiceMock) Summary(ctx context.Context, id int) (domain.BuyerSummary, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.BuyerSummary), args.Error(1)
+}
+
+func (m *BuyerRepositoryMock) Orders(ctx context.Context, id int) ([]domain.BuyerOrder, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]domain.BuyerOrder), args.Error(1)
+}
+
+func (m *BuyerServiceMock) UpdateCardNumber(ctx context.Context, id int, cardNumberID string) (domain.Buyer, error) {
+	args := m.Called(ctx, id, cardNumberID)
+	return args.Get(0).(domain.Buyer), args.Error(1)
+}
+
+func (m *BuyerRepositoryMock) UpdateCardNumber(ctx context.Context, id int, cardNumberID string) error {
+	args := m.Called(ctx, id, cardNumberID)
+	return args.Error(0)
+}
+
+func (m *BuyerServiceMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Buyer, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Buyer), args.Error(1)
+}
+
+func (m *BuyerRepositoryMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Buyer, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Buyer), args.Error(1)
+}
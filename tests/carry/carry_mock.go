@@ -54,3 +54,93 @@ func (m *CarryRepositoryMock) ExistsByCidCarry(ctx context.Context, cid string)
 	args := m.Called(ctx, cid)
 	return args.Get(0).(bool)
 }
+
+func (m *CarryServiceMock) GetAll(ctx context.Context) ([]domain.Carry, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Carry), args.Error(1)
+}
+
+func (m *CarryRepositoryMock) GetAll(ctx context.Context) ([]domain.Carry, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Carry), args.Error(1)
+}
+
+func (m *CarryServiceMock) Update(ctx context.Context, c domain.Carry, id int) (domain.Carry, error) {
+	args := m.Called(ctx, c, id)
+	return args.Get(0).(domain.Carry), args.Error(1)
+}
+
+func (m *CarryRepositoryMock) Update(ctx context.Context, c domain.Carry) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *CarryServiceMock) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *CarryRepositoryMock) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *CarryServiceMock) Coverage(ctx context.Context, id int) (domain.CarrierCoverage, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.CarrierCoverage), args.Error(1)
+}
+
+func (m *CarryRepositoryMock) Coverage(ctx context.Context, id int) ([]int, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *CarryServiceMock) SetCoverage(ctx context.Context, id int, localityIDs []int) (domain.CarrierCoverage, error) {
+	args := m.Called(ctx, id, localityIDs)
+	return args.Get(0).(domain.CarrierCoverage), args.Error(1)
+}
+
+func (m *CarryRepositoryMock) SetCoverage(ctx context.Context, id int, localityIDs []int) error {
+	args := m.Called(ctx, id, localityIDs)
+	return args.Error(0)
+}
+
+func (m *CarryServiceMock) ByLocality(ctx context.Context, localityID int) ([]domain.Carry, error) {
+	args := m.Called(ctx, localityID)
+	return args.Get(0).([]domain.Carry), args.Error(1)
+}
+
+func (m *CarryRepositoryMock) GetByLocality(ctx context.Context, localityID int) ([]domain.Carry, error) {
+	args := m.Called(ctx, localityID)
+	return args.Get(0).([]domain.Carry), args.Error(1)
+}
+
+func (m *CarryServiceMock) IssueCredential(ctx context.Context, id int) (domain.CarrierCredential, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.CarrierCredential), args.Error(1)
+}
+
+func (m *CarryRepositoryMock) SetAPIKeyHash(ctx context.Context, id int, hash string) error {
+	args := m.Called(ctx, id, hash)
+	return args.Error(0)
+}
+
+func (m *CarryServiceMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Carry, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Carry), args.Error(1)
+}
+
+func (m *CarryServiceMock) GetBasedIn(ctx context.Context, localityIDs []int) ([]domain.Carry, error) {
+	args := m.Called(ctx, localityIDs)
+	return args.Get(0).([]domain.Carry), args.Error(1)
+}
+
+func (m *CarryRepositoryMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Carry, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Carry), args.Error(1)
+}
+
+func (m *CarryRepositoryMock) GetBasedIn(ctx context.Context, localityIDs []int) ([]domain.Carry, error) {
+	args := m.Called(ctx, localityIDs)
+	return args.Get(0).([]domain.Carry), args.Error(1)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -55,8 +56,8 @@ func (m *EmployeeRepositoryMock) Get(ctx context.Context, id int) (domain.Employ
 	return args.Get(0).(domain.Employee), args.Error(1)
 }
 
-func (m *EmployeeServiceMock) Update(ctx context.Context, e domain.Employee, id int) (domain.Employee, error) {
-	args := m.Called(ctx, e, id)
+func (m *EmployeeServiceMock) Update(ctx context.Context, id int, p domain.Patch) (domain.Employee, error) {
+	args := m.Called(ctx, id, p)
 	return args.Get(0).(domain.Employee), args.Error(1)
 }
 
@@ -69,3 +70,63 @@ func (m *EmployeeRepositoryMock) Exists(ctx context.Context, warehouseCode strin
 	args := m.Called(ctx, warehouseCode)
 	return args.Get(0).(bool)
 }
+
+func (m *EmployeeServiceMock) ForEach(ctx context.Context, fn func(domain.Employee) error) error {
+	args := m.Called()
+	for _, e := range args.Get(0).([]domain.Employee) {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *EmployeeRepositoryMock) ForEach(ctx context.Context, fn func(domain.Employee) error) error {
+	args := m.Called()
+	for _, e := range args.Get(0).([]domain.Employee) {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *EmployeeServiceMock) Assignments(ctx context.Context, id int) ([]domain.EmployeeAssignment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]domain.EmployeeAssignment), args.Error(1)
+}
+
+func (m *EmployeeRepositoryMock) Assignments(ctx context.Context, id int) ([]domain.EmployeeAssignment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]domain.EmployeeAssignment), args.Error(1)
+}
+
+func (m *EmployeeServiceMock) CreateShift(ctx context.Context, id int, req domain.ShiftRequest) (domain.Shift, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(domain.Shift), args.Error(1)
+}
+
+func (m *EmployeeRepositoryMock) SaveShift(ctx context.Context, s domain.Shift) (int, error) {
+	args := m.Called(ctx, s)
+	return args.Get(0).(int), args.Error(1)
+}
+
+func (m *EmployeeServiceMock) Roster(ctx context.Context, warehouseID int, date datetime.Time) (domain.Roster, error) {
+	args := m.Called(ctx, warehouseID, date)
+	return args.Get(0).(domain.Roster), args.Error(1)
+}
+
+func (m *EmployeeRepositoryMock) Roster(ctx context.Context, warehouseID int, from, to datetime.Time) ([]domain.RosterShift, error) {
+	args := m.Called(ctx, warehouseID, from, to)
+	return args.Get(0).([]domain.RosterShift), args.Error(1)
+}
+
+func (m *EmployeeServiceMock) GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Employee, error) {
+	args := m.Called(ctx, warehouseIDs)
+	return args.Get(0).([]domain.Employee), args.Error(1)
+}
+
+func (m *EmployeeRepositoryMock) GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Employee, error) {
+	args := m.Called(ctx, warehouseIDs)
+	return args.Get(0).([]domain.Employee), args.Error(1)
+}
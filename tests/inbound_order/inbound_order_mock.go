@@ -15,6 +15,16 @@ type InboundOrderRepositoryMock struct {
 	mock.Mock
 }
 
+func (m *InboundOrderServiceMock) GetAll(ctx context.Context, filter domain.InboundOrderFilter) ([]domain.InboundOrders, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.InboundOrders), args.Error(1)
+}
+
+func (m *InboundOrderRepositoryMock) GetAll(ctx context.Context, filter domain.InboundOrderFilter) ([]domain.InboundOrders, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.InboundOrders), args.Error(1)
+}
+
 func (m *InboundOrderServiceMock) Create(ctx context.Context, c domain.InboundOrders) (domain.InboundOrders, error) {
 	args := m.Called(ctx, c)
 	return args.Get(0).(domain.InboundOrders), args.Error(1)
@@ -40,22 +50,22 @@ func (m *InboundOrderRepositoryMock) Exists(ctx context.Context, orderNumber str
 	return args.Get(0).(bool)
 }
 
-func (m *InboundOrderServiceMock) ReportByAll(ctx context.Context) ([]domain.InboundOrdersReport, error) {
-	args := m.Called(ctx)
+func (m *InboundOrderServiceMock) ReportByAll(ctx context.Context, filter domain.InboundOrdersReportFilter) ([]domain.InboundOrdersReport, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]domain.InboundOrdersReport), args.Error(1)
 }
 
-func (m *InboundOrderRepositoryMock) ReportByAll(ctx context.Context) ([]domain.InboundOrdersReport, error) {
-	args := m.Called(ctx)
+func (m *InboundOrderRepositoryMock) ReportByAll(ctx context.Context, filter domain.InboundOrdersReportFilter) ([]domain.InboundOrdersReport, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]domain.InboundOrdersReport), args.Error(1)
 }
 
-func (m *InboundOrderServiceMock) ReportByOne(ctx context.Context, id int) (domain.InboundOrdersReport, error) {
-	args := m.Called(ctx, id)
+func (m *InboundOrderServiceMock) ReportByOne(ctx context.Context, id int, filter domain.InboundOrdersReportFilter) (domain.InboundOrdersReport, error) {
+	args := m.Called(ctx, id, filter)
 	return args.Get(0).(domain.InboundOrdersReport), args.Error(1)
 }
 
-func (m *InboundOrderRepositoryMock) ReportByOne(ctx context.Context, id int) (domain.InboundOrdersReport, error) {
-	args := m.Called(ctx, id)
+func (m *InboundOrderRepositoryMock) ReportByOne(ctx context.Context, id int, filter domain.InboundOrdersReportFilter) (domain.InboundOrdersReport, error) {
+	args := m.Called(ctx, id, filter)
 	return args.Get(0).(domain.InboundOrdersReport), args.Error(1)
 }
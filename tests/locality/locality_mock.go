@@ -53,3 +53,33 @@ func (m *LocalityRepositoryMock) ReportLocalityId(ctx context.Context, id int) (
 	args := m.Called(ctx, id)
 	return args.Get(0).(domain.LocalityReport), args.Error(1)
 }
+
+func (m *LocalityServiceMock) GetAll(ctx context.Context) ([]domain.Locality, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Locality), args.Error(1)
+}
+
+func (m *LocalityServiceMock) Get(ctx context.Context, id int) (domain.Locality, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Locality), args.Error(1)
+}
+
+func (m *LocalityRepositoryMock) GetAll(ctx context.Context) ([]domain.Locality, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Locality), args.Error(1)
+}
+
+func (m *LocalityRepositoryMock) Get(ctx context.Context, id int) (domain.Locality, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Locality), args.Error(1)
+}
+
+func (m *LocalityServiceMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Locality, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Locality), args.Error(1)
+}
+
+func (m *LocalityRepositoryMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Locality, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Locality), args.Error(1)
+}
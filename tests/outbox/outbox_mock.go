@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/stretchr/testify/mock"
+)
+
+type OutboxRepositoryMock struct {
+	mock.Mock
+	Unlocked bool
+}
+
+type PublisherMock struct {
+	mock.Mock
+}
+
+// Lock returns an unlock func that records it was called in Unlocked.
+func (m *OutboxRepositoryMock) Lock(ctx context.Context) (func(), bool, error) {
+	args := m.Called(ctx)
+	return func() { m.Unlocked = true }, args.Bool(0), args.Error(1)
+}
+
+func (m *OutboxRepositoryMock) Pending(ctx context.Context, now datetime.Time, limit int) ([]domain.Event, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
+
+func (m *OutboxRepositoryMock) MarkPublished(ctx context.Context, id int64, at datetime.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) MarkFailed(ctx context.Context, id int64, reason string, retryAt datetime.Time) error {
+	args := m.Called(ctx, id, reason, retryAt)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) MarkDead(ctx context.Context, id int64, reason string, at datetime.Time) error {
+	args := m.Called(ctx, id, reason, at)
+	return args.Error(0)
+}
+
+func (m *PublisherMock) Publish(ctx context.Context, e domain.Event) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
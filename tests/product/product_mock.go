@@ -67,10 +67,9 @@ func (p *ProductRepositoryMock) Get(ctx context.Context, id int) (domain.Product
 	return args.Get(0).(domain.Product), args.Error(1)
 }
 
-func (p *ProductServiceMock) Update(ctx context.Context, d domain.Product) error {
-	args := p.Called(ctx, d)
-	return args.Error(0)
-
+func (p *ProductServiceMock) Update(ctx context.Context, id int, patch domain.Patch) (domain.Product, error) {
+	args := p.Called(ctx, id, patch)
+	return args.Get(0).(domain.Product), args.Error(1)
 }
 
 func (p *ProductRepositoryMock) Update(ctx context.Context, d domain.Product) error {
@@ -83,3 +82,33 @@ func (p *ProductRepositoryMock) Exists(ctx context.Context, productCode string)
 	args := p.Called(ctx, productCode)
 	return args.Get(0).(bool)
 }
+
+func (p *ProductServiceMock) Search(ctx context.Context, search domain.ProductSearch) (domain.ProductSearchResult, error) {
+	args := p.Called(ctx, search)
+	return args.Get(0).(domain.ProductSearchResult), args.Error(1)
+}
+
+func (p *ProductRepositoryMock) Search(ctx context.Context, s domain.ProductSearch, limit int) (domain.ProductSearchResult, error) {
+	args := p.Called(ctx, s, limit)
+	return args.Get(0).(domain.ProductSearchResult), args.Error(1)
+}
+
+func (p *ProductServiceMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Product, error) {
+	args := p.Called(ids)
+	return args.Get(0).([]domain.Product), args.Error(1)
+}
+
+func (p *ProductServiceMock) GetBySellers(ctx context.Context, sellerIDs []int) ([]domain.Product, error) {
+	args := p.Called(sellerIDs)
+	return args.Get(0).([]domain.Product), args.Error(1)
+}
+
+func (p *ProductRepositoryMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Product, error) {
+	args := p.Called(ids)
+	return args.Get(0).([]domain.Product), args.Error(1)
+}
+
+func (p *ProductRepositoryMock) GetBySellers(ctx context.Context, sellerIDs []int) ([]domain.Product, error) {
+	args := p.Called(sellerIDs)
+	return args.Get(0).([]domain.Product), args.Error(1)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -20,7 +21,72 @@ func (m *ProductBatchServiceMock) Save(ctx context.Context, p domain.ProductBatc
 	return args.Int(0), args.Error(1)
 }
 
-func (m *ProductBatchRepositoryMock) Save(produsctBatch domain.ProductBatch) (int, error) {
-	args := m.Called(produsctBatch)
+func (m *ProductBatchRepositoryMock) Save(ctx context.Context, produsctBatch domain.ProductBatch) (int, error) {
+	args := m.Called(ctx, produsctBatch)
 	return args.Int(0), args.Error(1)
 }
+
+func (m *ProductBatchRepositoryMock) Get(ctx context.Context, id int) (domain.ProductBatch, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.ProductBatch), args.Error(1)
+}
+
+func (m *ProductBatchServiceMock) GetAll(ctx context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.ProductBatch), args.Error(1)
+}
+
+func (m *ProductBatchServiceMock) ForEach(ctx context.Context, filter domain.ProductBatchFilter, fn func(domain.ProductBatch) error) error {
+	args := m.Called(ctx, filter)
+	for _, p := range args.Get(0).([]domain.ProductBatch) {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *ProductBatchRepositoryMock) ForEach(ctx context.Context, filter domain.ProductBatchFilter, fn func(domain.ProductBatch) error) error {
+	args := m.Called(ctx, filter)
+	for _, p := range args.Get(0).([]domain.ProductBatch) {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *ProductBatchRepositoryMock) GetByWarehouse(ctx context.Context, warehouseID int) ([]domain.InventoryBatch, error) {
+	args := m.Called(ctx, warehouseID)
+	return args.Get(0).([]domain.InventoryBatch), args.Error(1)
+}
+
+func (m *ProductBatchRepositoryMock) Consumed(ctx context.Context, warehouseID int, until datetime.Time) (map[int]int, error) {
+	args := m.Called(ctx, warehouseID, until)
+	return args.Get(0).(map[int]int), args.Error(1)
+}
+
+func (m *ProductBatchRepositoryMock) SplitOff(ctx context.Context, warehouseID int, until datetime.Time) (map[int]int, error) {
+	args := m.Called(ctx, warehouseID, until)
+	return args.Get(0).(map[int]int), args.Error(1)
+}
+
+func (m *ProductBatchServiceMock) GetBySections(ctx context.Context, sectionIDs []int) ([]domain.ProductBatch, error) {
+	args := m.Called(ctx, sectionIDs)
+	return args.Get(0).([]domain.ProductBatch), args.Error(1)
+}
+
+func (m *ProductBatchServiceMock) GetByProducts(ctx context.Context, productIDs []int) ([]domain.ProductBatch, error) {
+	args := m.Called(ctx, productIDs)
+	return args.Get(0).([]domain.ProductBatch), args.Error(1)
+}
+
+func (m *ProductBatchRepositoryMock) GetBySections(ctx context.Context, sectionIDs []int) ([]domain.ProductBatch, error) {
+	args := m.Called(ctx, sectionIDs)
+	return args.Get(0).([]domain.ProductBatch), args.Error(1)
+}
+
+func (m *ProductBatchRepositoryMock) GetByProducts(ctx context.Context, productIDs []int) ([]domain.ProductBatch, error) {
+	args := m.Called(ctx, productIDs)
+	return args.Get(0).([]domain.ProductBatch), args.Error(1)
+}
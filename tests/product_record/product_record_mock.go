@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -44,3 +45,23 @@ func (p *ProductRecordRepositoryMock) RecordsByOneProductReport(ctx context.Cont
 	args := p.Called(id)
 	return args.Get(0).(domain.ProductRecordReport), args.Error(1)
 }
+
+func (p *ProductRecordServiceMock) PriceHistory(ctx context.Context, productID int, currency string) ([]domain.ProductRecord, error) {
+	args := p.Called(productID, currency)
+	return args.Get(0).([]domain.ProductRecord), args.Error(1)
+}
+
+func (p *ProductRecordServiceMock) PriceAt(ctx context.Context, productID int, at datetime.Time, currency string) (domain.ProductPrice, error) {
+	args := p.Called(productID, at, currency)
+	return args.Get(0).(domain.ProductPrice), args.Error(1)
+}
+
+func (p *ProductRecordRepositoryMock) GetByProduct(ctx context.Context, productID int) ([]domain.ProductRecord, error) {
+	args := p.Called(productID)
+	return args.Get(0).([]domain.ProductRecord), args.Error(1)
+}
+
+func (p *ProductRecordRepositoryMock) GetEffective(ctx context.Context, productID int, at datetime.Time) (domain.ProductRecord, error) {
+	args := p.Called(productID, at)
+	return args.Get(0).(domain.ProductRecord), args.Error(1)
+}
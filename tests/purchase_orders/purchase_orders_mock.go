@@ -25,7 +25,72 @@ func (m *PurchaseOrdersRepositoryMock) ExistsOrder(ctx context.Context, ordernum
 	return args.Get(0).(bool)
 }
 
-func (m *PurchaseOrdersRepositoryMock) Save(ctx context.Context, o domain.PurchaseOrders) error {
+func (m *PurchaseOrdersRepositoryMock) Save(ctx context.Context, o domain.PurchaseOrders) (int, error) {
 	args := m.Called(ctx, o)
-	return args.Error(1)
+	return args.Get(0).(int), args.Error(1)
+}
+
+func (m *PurchaseOrdersServiceMock) Assign(ctx context.Context, id int, req domain.PurchaseOrderAssignmentRequest) (domain.PurchaseOrderAssignment, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(domain.PurchaseOrderAssignment), args.Error(1)
+}
+
+func (m *PurchaseOrdersRepositoryMock) Demand(ctx context.Context, id int) (domain.OrderDemand, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.OrderDemand), args.Error(1)
+}
+
+func (m *PurchaseOrdersRepositoryMock) Warehouses(ctx context.Context, id int) ([]domain.AssignmentWarehouse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]domain.AssignmentWarehouse), args.Error(1)
+}
+
+func (m *PurchaseOrdersRepositoryMock) Carriers(ctx context.Context, destination domain.Place) ([]domain.AssignmentCarrier, error) {
+	args := m.Called(ctx, destination)
+	return args.Get(0).([]domain.AssignmentCarrier), args.Error(1)
+}
+
+func (m *PurchaseOrdersRepositoryMock) Assign(ctx context.Context, id, warehouseID, carrierID int) error {
+	args := m.Called(ctx, id, warehouseID, carrierID)
+	return args.Error(0)
+}
+
+func (m *PurchaseOrdersServiceMock) GetAll(ctx context.Context) ([]domain.PurchaseOrders, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.PurchaseOrders), args.Error(1)
+}
+
+func (m *PurchaseOrdersServiceMock) Get(ctx context.Context, id int) (domain.PurchaseOrders, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.PurchaseOrders), args.Error(1)
+}
+
+func (m *PurchaseOrdersRepositoryMock) GetAll(ctx context.Context) ([]domain.PurchaseOrders, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.PurchaseOrders), args.Error(1)
+}
+
+func (m *PurchaseOrdersRepositoryMock) Get(ctx context.Context, id int) (domain.PurchaseOrders, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.PurchaseOrders), args.Error(1)
+}
+
+func (m *PurchaseOrdersServiceMock) GetByBuyers(ctx context.Context, buyerIDs []int) ([]domain.PurchaseOrders, error) {
+	args := m.Called(ctx, buyerIDs)
+	return args.Get(0).([]domain.PurchaseOrders), args.Error(1)
+}
+
+func (m *PurchaseOrdersServiceMock) GetByCarriers(ctx context.Context, carrierIDs []int) ([]domain.PurchaseOrders, error) {
+	args := m.Called(ctx, carrierIDs)
+	return args.Get(0).([]domain.PurchaseOrders), args.Error(1)
+}
+
+func (m *PurchaseOrdersRepositoryMock) GetByBuyers(ctx context.Context, buyerIDs []int) ([]domain.PurchaseOrders, error) {
+	args := m.Called(ctx, buyerIDs)
+	return args.Get(0).([]domain.PurchaseOrders), args.Error(1)
+}
+
+func (m *PurchaseOrdersRepositoryMock) GetByCarriers(ctx context.Context, carrierIDs []int) ([]domain.PurchaseOrders, error) {
+	args := m.Called(ctx, carrierIDs)
+	return args.Get(0).([]domain.PurchaseOrders), args.Error(1)
 }
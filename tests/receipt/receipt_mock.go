@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type ReceiptServiceMock struct {
+	mock.Mock
+}
+
+type ReceiptRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ReceiptServiceMock) Create(ctx context.Context, warehouseID int, req domain.ReceiptRequest) (domain.Receipt, error) {
+	args := m.Called(ctx, warehouseID, req)
+	return args.Get(0).(domain.Receipt), args.Error(1)
+}
+
+func (m *ReceiptRepositoryMock) Create(ctx context.Context, r domain.Receipt) (domain.Receipt, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(domain.Receipt), args.Error(1)
+}
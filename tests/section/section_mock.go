@@ -58,10 +58,11 @@ func (m *SectionRepositoryMock) Get(ctx context.Context, id int) (domain.Section
 	return args.Get(0).(domain.Section), args.Error(1)
 }
 
-func (m *SectionServiceMock) Update(ctx context.Context, s domain.Section) error {
-	args := m.Called(ctx, s)
-	return args.Error(0)
+func (m *SectionServiceMock) Update(ctx context.Context, id int, p domain.Patch) (domain.Section, error) {
+	args := m.Called(ctx, id, p)
+	return args.Get(0).(domain.Section), args.Error(1)
 }
+
 func (m *SectionRepositoryMock) Update(ctx context.Context, s domain.Section) error {
 	args := m.Called(ctx, s)
 	return args.Error(0)
@@ -99,3 +100,53 @@ func (m *SectionRepositoryMock) SectionProductsReportsBySection(id int) (domain.
 	args := m.Called(id)
 	return args.Get(0).(domain.ProductBySection), args.Error(1)
 }
+
+func (m *SectionServiceMock) ForEach(ctx context.Context, fn func(domain.Section) error) error {
+	args := m.Called()
+	for _, s := range args.Get(0).([]domain.Section) {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *SectionRepositoryMock) ForEach(ctx context.Context, fn func(domain.Section) error) error {
+	args := m.Called()
+	for _, s := range args.Get(0).([]domain.Section) {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *SectionRepositoryMock) GetByWarehouse(ctx context.Context, warehouseID int) ([]domain.Section, error) {
+	args := m.Called(ctx, warehouseID)
+	return args.Get(0).([]domain.Section), args.Error(1)
+}
+
+func (m *SectionServiceMock) Fit(ctx context.Context, id int, productID int) (domain.SectionFit, error) {
+	args := m.Called(id, productID)
+	return args.Get(0).(domain.SectionFit), args.Error(1)
+}
+
+func (m *SectionServiceMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Section, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Section), args.Error(1)
+}
+
+func (m *SectionServiceMock) GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Section, error) {
+	args := m.Called(ctx, warehouseIDs)
+	return args.Get(0).([]domain.Section), args.Error(1)
+}
+
+func (m *SectionRepositoryMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Section, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Section), args.Error(1)
+}
+
+func (m *SectionRepositoryMock) GetByWarehouses(ctx context.Context, warehouseIDs []int) ([]domain.Section, error) {
+	args := m.Called(ctx, warehouseIDs)
+	return args.Get(0).([]domain.Section), args.Error(1)
+}
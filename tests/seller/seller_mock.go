@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -64,8 +65,8 @@ func (s *SellerRepositoryMock) Update(ctx context.Context, d domain.Seller) erro
 	return args.Error(0)
 }
 
-func (s *SellerServiceMock) Update(ctx context.Context, id int, d domain.Seller) (domain.Seller, error) {
-	args := s.Called(ctx, d, id)
+func (s *SellerServiceMock) Update(ctx context.Context, id int, p domain.Patch) (domain.Seller, error) {
+	args := s.Called(ctx, id, p)
 	return args.Get(0).(domain.Seller), args.Error(1)
 }
 
@@ -84,7 +85,52 @@ func (l *LocalityServiceMock) ReportSellersByLocality(ctx context.Context, id in
 	return args.Get(0).([]domain.LocalityReport), args.Error(1)
 }
 
+func (l *LocalityServiceMock) GetAll(ctx context.Context) ([]domain.Locality, error) {
+	args := l.Called(ctx)
+	return args.Get(0).([]domain.Locality), args.Error(1)
+}
+
+func (l *LocalityServiceMock) Get(ctx context.Context, id int) (domain.Locality, error) {
+	args := l.Called(ctx, id)
+	return args.Get(0).(domain.Locality), args.Error(1)
+}
+
 func (l *LocalityServiceMock) Save(ctx context.Context, d domain.Locality) (domain.LocalityInput, error) {
 	args := l.Called(ctx, d)
 	return args.Get(0).(domain.LocalityInput), args.Error(1)
 }
+
+func (s *SellerServiceMock) Dashboard(ctx context.Context, id int, from, to datetime.Time) (domain.SellerDashboard, error) {
+	args := s.Called(ctx, id, from, to)
+	return args.Get(0).(domain.SellerDashboard), args.Error(1)
+}
+
+func (s *SellerRepositoryMock) Dashboard(ctx context.Context, id int, from, to datetime.Time) (domain.SellerDashboard, error) {
+	args := s.Called(ctx, id, from, to)
+	return args.Get(0).(domain.SellerDashboard), args.Error(1)
+}
+
+func (s *SellerServiceMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Seller, error) {
+	args := s.Called(ids)
+	return args.Get(0).([]domain.Seller), args.Error(1)
+}
+
+func (s *SellerServiceMock) GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Seller, error) {
+	args := s.Called(localityIDs)
+	return args.Get(0).([]domain.Seller), args.Error(1)
+}
+
+func (s *SellerRepositoryMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Seller, error) {
+	args := s.Called(ids)
+	return args.Get(0).([]domain.Seller), args.Error(1)
+}
+
+func (s *SellerRepositoryMock) GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Seller, error) {
+	args := s.Called(localityIDs)
+	return args.Get(0).([]domain.Seller), args.Error(1)
+}
+
+func (l *LocalityServiceMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Locality, error) {
+	args := l.Called(ctx, ids)
+	return args.Get(0).([]domain.Locality), args.Error(1)
+}
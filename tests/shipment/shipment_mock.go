@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type ShipmentServiceMock struct {
+	mock.Mock
+}
+
+type ShipmentRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ShipmentServiceMock) Get(ctx context.Context, trackingCode string) (domain.Shipment, error) {
+	args := m.Called(ctx, trackingCode)
+	return args.Get(0).(domain.Shipment), args.Error(1)
+}
+
+func (m *ShipmentServiceMock) AddEvent(ctx context.Context, trackingCode, apiKey string, req domain.ShipmentEventRequest) (domain.ShipmentEvent, error) {
+	args := m.Called(ctx, trackingCode, apiKey, req)
+	return args.Get(0).(domain.ShipmentEvent), args.Error(1)
+}
+
+func (m *ShipmentRepositoryMock) GetOrder(ctx context.Context, trackingCode string) (domain.ShipmentOrder, error) {
+	args := m.Called(ctx, trackingCode)
+	return args.Get(0).(domain.ShipmentOrder), args.Error(1)
+}
+
+func (m *ShipmentRepositoryMock) Events(ctx context.Context, orderID int) ([]domain.ShipmentEvent, error) {
+	args := m.Called(ctx, orderID)
+	return args.Get(0).([]domain.ShipmentEvent), args.Error(1)
+}
+
+func (m *ShipmentRepositoryMock) CarrierByKey(ctx context.Context, hash string) (int, error) {
+	args := m.Called(ctx, hash)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ShipmentRepositoryMock) AddEvent(ctx context.Context, order domain.ShipmentOrder, e domain.ShipmentEvent) (int, error) {
+	args := m.Called(ctx, order, e)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,16 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type StreamRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *StreamRepositoryMock) Warehouse(ctx context.Context, sectionID int) (int, error) {
+	args := m.Called(ctx, sectionID)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/stretchr/testify/mock"
+)
+
+type TransferServiceMock struct {
+	mock.Mock
+}
+
+type TransferRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *TransferServiceMock) Create(ctx context.Context, req domain.TransferRequest) (domain.Transfer, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(domain.Transfer), args.Error(1)
+}
+
+func (m *TransferServiceMock) Get(ctx context.Context, id int) (domain.Transfer, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Transfer), args.Error(1)
+}
+
+func (m *TransferServiceMock) Dispatch(ctx context.Context, id int) (domain.Transfer, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Transfer), args.Error(1)
+}
+
+func (m *TransferServiceMock) Receive(ctx context.Context, id int) (domain.Transfer, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Transfer), args.Error(1)
+}
+
+func (m *TransferRepositoryMock) Get(ctx context.Context, id int) (domain.Transfer, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Transfer), args.Error(1)
+}
+
+func (m *TransferRepositoryMock) GetBatch(ctx context.Context, id int) (domain.TransferBatch, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.TransferBatch), args.Error(1)
+}
+
+func (m *TransferRepositoryMock) GetSection(ctx context.Context, id int) (domain.TransferSection, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.TransferSection), args.Error(1)
+}
+
+func (m *TransferRepositoryMock) Save(ctx context.Context, t domain.Transfer) (int, error) {
+	args := m.Called(ctx, t)
+	return args.Get(0).(int), args.Error(1)
+}
+
+func (m *TransferRepositoryMock) Dispatch(ctx context.Context, id int, at datetime.Time) (domain.Transfer, error) {
+	args := m.Called(ctx, id, at)
+	return args.Get(0).(domain.Transfer), args.Error(1)
+}
+
+func (m *TransferRepositoryMock) Receive(ctx context.Context, id int, at datetime.Time) (domain.Transfer, error) {
+	args := m.Called(ctx, id, at)
+	return args.Get(0).(domain.Transfer), args.Error(1)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -55,8 +56,8 @@ func (m *WarehouseRepositoryMock) Get(ctx context.Context, id int) (domain.Wareh
 	return args.Get(0).(domain.Warehouse), args.Error(1)
 }
 
-func (m *WarehouseServiceMock) Update(ctx context.Context, d domain.Warehouse, id int) (domain.Warehouse, error) {
-	args := m.Called(ctx, d, id)
+func (m *WarehouseServiceMock) Update(ctx context.Context, id int, p domain.Patch) (domain.Warehouse, error) {
+	args := m.Called(ctx, id, p)
 	return args.Get(0).(domain.Warehouse), args.Error(1)
 }
 
@@ -69,3 +70,48 @@ func (m *WarehouseRepositoryMock) Exists(ctx context.Context, warehouseCode stri
 	args := m.Called(ctx, warehouseCode)
 	return args.Get(0).(bool)
 }
+
+func (m *WarehouseServiceMock) ForEach(ctx context.Context, fn func(domain.Warehouse) error) error {
+	args := m.Called(ctx, fn)
+	for _, w := range args.Get(0).([]domain.Warehouse) {
+		if err := fn(w); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *WarehouseRepositoryMock) ForEach(ctx context.Context, fn func(domain.Warehouse) error) error {
+	args := m.Called(ctx, fn)
+	for _, w := range args.Get(0).([]domain.Warehouse) {
+		if err := fn(w); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *WarehouseServiceMock) Inventory(ctx context.Context, id int, asOf datetime.Time) (domain.WarehouseInventory, error) {
+	args := m.Called(ctx, id, asOf)
+	return args.Get(0).(domain.WarehouseInventory), args.Error(1)
+}
+
+func (m *WarehouseServiceMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Warehouse, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Warehouse), args.Error(1)
+}
+
+func (m *WarehouseServiceMock) GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Warehouse, error) {
+	args := m.Called(ctx, localityIDs)
+	return args.Get(0).([]domain.Warehouse), args.Error(1)
+}
+
+func (m *WarehouseRepositoryMock) GetByIDs(ctx context.Context, ids []int) ([]domain.Warehouse, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Warehouse), args.Error(1)
+}
+
+func (m *WarehouseRepositoryMock) GetByLocalities(ctx context.Context, localityIDs []int) ([]domain.Warehouse, error) {
+	args := m.Called(ctx, localityIDs)
+	return args.Get(0).([]domain.Warehouse), args.Error(1)
+}
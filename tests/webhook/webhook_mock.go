@@ -0,0 +1,103 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-3/pkg/datetime"
+	"github.com/stretchr/testify/mock"
+)
+
+type WebhookServiceMock struct {
+	mock.Mock
+}
+
+type WebhookRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *WebhookServiceMock) Publish(ctx context.Context, e domain.Event) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *WebhookServiceMock) Create(ctx context.Context, req domain.WebhookRequest) (domain.Webhook, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(domain.Webhook), args.Error(1)
+}
+
+func (m *WebhookServiceMock) GetAll(ctx context.Context) ([]domain.Webhook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Get(ctx context.Context, id int) (domain.Webhook, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Webhook), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *WebhookServiceMock) Test(ctx context.Context, id int) (domain.WebhookTest, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.WebhookTest), args.Error(1)
+}
+
+func (m *WebhookServiceMock) DeadLetters(ctx context.Context) ([]domain.WebhookDelivery, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Retry(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) Save(ctx context.Context, w domain.Webhook) (int, error) {
+	args := m.Called(ctx, w)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) GetAll(ctx context.Context) ([]domain.Webhook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) Get(ctx context.Context, id int) (domain.Webhook, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Webhook), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) Enqueue(ctx context.Context, event string, payload []byte, at datetime.Time) (int, error) {
+	args := m.Called(ctx, event, payload, at)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) Claim(ctx context.Context, now datetime.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	args := m.Called(ctx, now, lease, limit)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) Dead(ctx context.Context) ([]domain.WebhookDelivery, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) Retry(ctx context.Context, id int, at datetime.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}